@@ -0,0 +1,164 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/badge"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Executes the "badge" command.
+func runBadge(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "badge", "Write SVG badges of the test results to a directory: status.svg, tests.svg, "+
+		"pass-rate.svg and,\nwhen the code coverage is known, coverage.svg.\n\n"+
+		"The badges are read from the result files, or from a run of the history store (with --history).")
+	output := fs.String("output", "badges", "Write the badges to `directory`.")
+	dir := fs.String("history", "", "Read the results (and the coverage) of the newest run of the history store in "+
+		"`directory`,\ninstead of result files.")
+	runID := fs.String("run", "", "Read the run with this `ID` of the history store, instead of the newest one "+
+		"(with --history).")
+	coverageFiles := make([]string, 0)
+
+	fs.Func("coverage", "Show the line coverage in `file` (in the Cobertura or OpenCover XML format, the JSON format "+
+		"of coverlet, or the LCOV format), which can be repeated to merge the coverage of sharded runs.",
+		func(v string) error {
+			coverageFiles = append(coverageFiles, v)
+
+			return nil
+		})
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var stats xunit.Stats
+	var lines coverage.Counter
+
+	switch {
+	case *dir != "" && fs.NArg() > 0:
+		return &usageError{msg: "--history doesn't accept input files"}
+	case *dir != "" && len(coverageFiles) > 0:
+		return &usageError{msg: "--history can't be combined with --coverage"}
+	case *dir == "" && *runID != "":
+		return &usageError{msg: "--run requires --history"}
+	case *dir != "":
+		run, err := historyRun(*dir, *runID)
+
+		if err != nil {
+			return err
+		}
+
+		stats, lines = run.Stats(), run.LineCoverage()
+	default:
+		testRun, err := loadFiles(ctx, env, fs.Args())
+
+		if err != nil {
+			return err
+		}
+
+		stats = testRun.Stats()
+
+		if len(coverageFiles) > 0 {
+			cov, err := loadCoverage(env, coverageFiles)
+
+			if err != nil {
+				return err
+			}
+
+			lines = cov.LineCoverage()
+		}
+	}
+
+	badges := map[string]badge.Badge{
+		"status.svg":    badge.Status(stats),
+		"tests.svg":     badge.Tests(stats),
+		"pass-rate.svg": badge.PassRate(stats),
+	}
+
+	if lines.Total > 0 {
+		badges["coverage.svg"] = badge.Coverage(lines)
+	}
+
+	return writeBadges(env, *output, badges)
+}
+
+// Returns the run with the given ID of the history store in dir, or its newest run if id is empty.
+func historyRun(dir, id string) (history.Run, error) {
+	store, err := history.Open(dir)
+
+	if err != nil {
+		return history.Run{}, err
+	}
+
+	if id != "" {
+		run, err := store.Run(id)
+
+		if err != nil {
+			return history.Run{}, &inputError{err: fmt.Errorf("%s: %w", id, err)}
+		}
+
+		return run, nil
+	}
+
+	runs, err := store.Runs()
+
+	if err != nil {
+		return history.Run{}, &inputError{err: err}
+	}
+
+	if len(runs) == 0 {
+		return history.Run{}, &inputError{err: fmt.Errorf("%s: the history store doesn't contain any runs", dir)}
+	}
+
+	return runs[len(runs)-1], nil
+}
+
+// Writes each badge of badges, by the name of its file, as an SVG image to the directory dir.
+func writeBadges(env *env, dir string, badges map[string]badge.Badge) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, name := range maps.SortedKeys(badges) {
+		path := filepath.Join(dir, name)
+
+		if err := withOutput(env, path, badges[name].WriteSVG); err != nil {
+			return err
+		}
+
+		env.log.Info("Wrote the badge", "file", path, "message", badges[name].Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// UT: Execute `dtvisual badge`.
+func TestRunBadge(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	coveragePath := writeFile(t, "lcov.info", "SF:Calc.cs\nDA:1,1\nDA:2,1\nDA:3,1\nDA:4,0\nend_of_record\n")
+	dir := t.TempDir()
+	store, _ := history.Open(dir)
+
+	store.Add(history.Run{
+		ID:        "run1",
+		Timestamp: time.Date(2023, 7, 10, 20, 0, 0, 0, time.UTC),
+		Tests:     []history.Test{{Assembly: "App.dll", Name: "NS.Calc.Adds", Result: "Fail"}},
+	})
+	store.Add(history.Run{
+		ID:        "run2",
+		Timestamp: time.Date(2023, 7, 10, 21, 0, 0, 0, time.UTC),
+		Tests:     []history.Test{{Assembly: "App.dll", Name: "NS.Calc.Adds", Result: "Pass"}},
+	})
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     map[string]string // The (partial) content of the badges, by the name of their file.
+	}{
+		{
+			args:     []string{"badge", "--coverage", coveragePath, path},
+			wantCode: exitOK,
+			want: map[string]string{
+				"status.svg":    "tests: failing",
+				"tests.svg":     "tests: 1 passed, 1 failed",
+				"pass-rate.svg": "pass rate: 50%",
+				"coverage.svg":  "coverage: 75%",
+			},
+		},
+		{
+			args:     []string{"badge", "--history", dir},
+			wantCode: exitOK,
+			want: map[string]string{
+				"status.svg":    "tests: passing",
+				"tests.svg":     "tests: 1 passed",
+				"pass-rate.svg": "pass rate: 100%",
+			},
+		},
+		{
+			args:     []string{"badge", "--history", dir, "--run", "run1"},
+			wantCode: exitOK,
+			want:     map[string]string{"status.svg": "tests: failing"},
+		},
+		{
+			args:     []string{"badge", "--history", t.TempDir()},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"badge", "--history", dir, path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"badge", "--run", "run1", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ARRANGE.
+		output := filepath.Join(t.TempDir(), "badges")
+
+		// ACT.
+		code, _, stderr := execute(append([]string{tc.args[0], "--output", output}, tc.args[1:]...)...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual badge`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		for name, want := range tc.want {
+			data, _ := os.ReadFile(filepath.Join(output, name))
+
+			assert.Equal(t, strings.Contains(string(data), "<title>"+want+"</title>"), true, "", "\n\n"+
+				"UT Name:    Execute `dtvisual badge`.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   %s with the title %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.args, name, want, data)
+		}
+
+		if _, err := os.Stat(filepath.Join(output, "coverage.svg")); tc.want != nil && tc.want["coverage.svg"] == "" {
+			assert.Equal(t, os.IsNotExist(err), true, "", "\n\n"+
+				"UT Name:    Execute `dtvisual badge`.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   No coverage.svg\033[0m\n"+
+				"\033[31mActual:     %v\033[0m\n\n", tc.args, err)
+		}
+	}
+}
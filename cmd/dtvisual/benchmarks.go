@@ -0,0 +1,71 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/benchmark"
+)
+
+// Returns the benchmarks of all the BenchmarkDotNet exports at paths, compared to the benchmarks of the exports at
+// baselinePaths.
+func loadBenchmarks(env *env, paths, baselinePaths []string) ([]benchmark.Comparison, error) {
+	var reports [2]benchmark.Report
+
+	for i, files := range [][]string{paths, baselinePaths} {
+		for _, path := range files {
+			report, err := loadBenchmarkFile(path)
+
+			if err != nil {
+				return nil, &inputError{err: fmt.Errorf("%s: %w", path, err)}
+			}
+
+			env.log.Info("Loaded the benchmark export", "file", path, "benchmarks", len(report.Benchmarks))
+
+			reports[i].Benchmarks = append(reports[i].Benchmarks, report.Benchmarks...)
+		}
+	}
+
+	return benchmark.Compare(reports[0], reports[1]), nil
+}
+
+// Returns the benchmarks in the export at path, which is either in the JSON or the CSV format of BenchmarkDotNet.
+func loadBenchmarkFile(path string) (benchmark.Report, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return benchmark.Report{}, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return benchmark.Load(bytes.NewReader(data))
+	}
+
+	return benchmark.LoadCSV(bytes.NewReader(data))
+}
@@ -0,0 +1,94 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/tui"
+)
+
+// Executes the "browse" command.
+func runBrowse(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "browse", "Browse the test results interactively in the terminal: expand and collapse the "+
+		"groups, show the\nfailure details of a test and filter the tests while typing.\n\n"+
+		"The details of the tests of a single result file are only loaded when they're shown.\n"+
+		"Both stdin and stdout must be connected to a terminal.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	in, isInTerminal := terminal(env.stdin)
+	out, isOutTerminal := terminal(env.stdout)
+
+	if !isInTerminal || !isOutTerminal {
+		return &usageError{msg: "browse requires a terminal (stdin and stdout)"}
+	}
+
+	stdin, err := readsStdin(fs.Args())
+
+	switch {
+	case err != nil:
+		return err
+	case stdin:
+		return &usageError{msg: "browse can't read the test results from stdin"}
+	}
+
+	if fs.NArg() != 1 {
+		testRun, err := loadFiles(ctx, env, fs.Args())
+
+		if err != nil {
+			return err
+		}
+
+		return tui.Run(in, out, testRun)
+	}
+
+	index, err := loadIndex(ctx, env, fs.Arg(0))
+
+	if err != nil {
+		return &inputError{err: err}
+	}
+
+	defer index.Close()
+
+	return tui.RunIndex(in, out, index)
+}
+
+// Returns v as a file, and true if it's a file connected to a terminal, false otherwise.
+func terminal(v any) (*os.File, bool) {
+	f, ok := v.(*os.File)
+
+	if !ok {
+		return nil, false
+	}
+
+	fi, err := f.Stat()
+
+	return f, err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
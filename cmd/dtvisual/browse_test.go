@@ -0,0 +1,66 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual browse` without a terminal.
+func TestRunBrowse(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"browse", path}, want: "browse requires a terminal (stdin and stdout)"},
+		{args: []string{"browse", "-"}, want: "browse requires a terminal (stdin and stdout)"},
+		{args: []string{"browse", "--bad-flag", path}, want: "flag provided but not defined: -bad-flag"},
+	} {
+		// ACT.
+		code, _, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, exitUsage, "", "\n\n"+
+			"UT Name:    Execute `dtvisual browse` without a terminal.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, exitUsage, code, stderr)
+
+		assert.Contains(t, stderr, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual browse` without a terminal.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stderr)
+	}
+}
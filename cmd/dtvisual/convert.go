@@ -0,0 +1,88 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/allure"
+	"github.com/kdeconinck/dtvisual/internal/pkg/junit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/sonar"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xlsx"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The functions writing the formats the test results can be converted to, by name.
+var convertFormats = map[string]func(w io.Writer, testRun xunit.TestRun) error{
+	"junit": junit.Write,
+	"sonar": sonar.Write,
+	"xlsx":  xlsx.Write,
+}
+
+// Executes the "convert" command.
+func runConvert(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "convert", "Convert the test results to another format.")
+	to := fs.String("to", "junit", "The output `format` (junit, sonar for the generic test execution format of "+
+		"SonarQube, xlsx for an Excel workbook, or allure for the results of an Allure report).")
+	output := fs.String("output", "", "Write the converted results to `file` instead of stdout (the directory of the "+
+		"results with --to allure).")
+	gates := addGateFlags(fs)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	write, ok := convertFormats[*to]
+
+	switch {
+	case *to == "allure" && *output == "":
+		return &usageError{msg: "--to allure requires --output"}
+	case !ok && *to != "allure":
+		return &usageError{msg: fmt.Sprintf("unknown format %q", *to)}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	if *to == "allure" {
+		if err := allure.Write(*output, testRun); err != nil {
+			return err
+		}
+
+		env.log.Info("Wrote the Allure results", "directory", *output)
+	} else if err := withOutput(env, *output, func(w io.Writer) error {
+		return write(w, testRun)
+	}); err != nil {
+		return err
+	}
+
+	return gates.check(testRun.Stats())
+}
@@ -0,0 +1,91 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual convert`.
+func TestRunConvert(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"convert", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<testsuite name=\"App.dll\" tests=\"2\" failures=\"1\" errors=\"0\" skipped=\"0\" time=\"1.500\" hostname=\"WIN11\">",
+		},
+		{
+			args:     []string{"convert", "--to", "xlsx", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "PK\x03\x04",
+		},
+		{
+			args:     []string{"convert", "--to", "sonar", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<testExecutions version=\"1\">",
+		},
+		{
+			args:     []string{"convert", "--to", "allure", "--output", filepath.Join(t.TempDir(), "allure"), path},
+			wantCode: exitTestsFailed,
+		},
+		{
+			args:     []string{"convert", "--to", "allure", path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"convert", "--to", "trx", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual convert`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual convert`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
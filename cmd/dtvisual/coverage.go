@@ -0,0 +1,100 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/cobertura"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/coverlet"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/lcov"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/opencover"
+)
+
+// The functions loading the coverage formats in XML, by the name of the root element of the document.
+var coverageFormats = map[string]func(rdr io.Reader) (coverage.Report, error){
+	"coverage":        cobertura.Load,
+	"CoverageSession": opencover.Load,
+}
+
+// Returns a single coverage report containing the coverage of all the files at paths (e.g. of sharded test runs).
+// Each file is in the Cobertura XML format, the OpenCover XML format, the JSON format of coverlet, or the LCOV format.
+func loadCoverage(env *env, paths []string) (coverage.Report, error) {
+	reports := make([]coverage.Report, 0, len(paths))
+
+	for _, path := range paths {
+		report, err := loadCoverageFile(path)
+
+		if err != nil {
+			return coverage.Report{}, &inputError{err: fmt.Errorf("%s: %w", path, err)}
+		}
+
+		lines := report.LineCoverage()
+		env.log.Info("Loaded the coverage file", "file", path, "assemblies", len(report.Assemblies), "lines", lines.Total)
+
+		reports = append(reports, report)
+	}
+
+	return coverage.Merge(reports...), nil
+}
+
+// Returns the coverage stored in the file at path, in the format detected from its content.
+func loadCoverageFile(path string) (coverage.Report, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return coverage.Report{}, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		return coverlet.Load(bytes.NewReader(data))
+	} else if bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")) {
+		// NOTE: The source files of a tracefile (without test name) are shown as an assembly named after the file.
+		name := filepath.Base(path)
+
+		return lcov.Load(bytes.NewReader(data), strings.TrimSuffix(name, filepath.Ext(name)))
+	}
+
+	root, err := rootElement(data)
+
+	if err != nil {
+		return coverage.Report{}, err
+	}
+
+	load, ok := coverageFormats[root]
+
+	if !ok {
+		return coverage.Report{}, fmt.Errorf("unrecognized coverage format (root element <%s>)", root)
+	}
+
+	return load(bytes.NewReader(data))
+}
@@ -0,0 +1,191 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/compare"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A testRunDiff contains the differences between two test runs, as reported by the "diff" command.
+type testRunDiff struct {
+	newlyFailing []compare.Change // The tests which failed in the new test run, but not in the old one.
+	newlyPassing []compare.Change // The tests which failed in the old test run, but passed in the new one.
+	added        []compare.Change // The tests which are only part of the new test run.
+	removed      []compare.Change // The tests which are only part of the old test run.
+	slower       []compare.Change // The tests which got significantly slower.
+}
+
+// A slowdown contains the thresholds above which a test is considered to be significantly slower.
+type slowdown struct {
+	percentage float64       // The minimum relative increase of the duration.
+	minimum    time.Duration // The minimum absolute increase of the duration.
+}
+
+// Executes the "diff" command.
+func runDiff(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "diff", "Compare two test runs, and report the tests which are newly failing, "+
+		"newly passing, added, removed or significantly slower.\n\n"+
+		"The first file is the old test run, the second file the new one.\n"+
+		"The command exits with code 1 if the new test run contains newly failing tests.")
+	slowerBy := fs.Float64("slower-by", 50, "Report tests whose duration increased by at least this `percentage`.")
+	minSlowdown := fs.Duration("min-slowdown", 100*time.Millisecond, "Ignore duration increases shorter than this "+
+		"`duration`.")
+	output := fs.String("output", "", "Write the differences to `file` instead of stdout.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return &usageError{msg: "expected exactly 2 input files (the old and the new test run)"}
+	}
+
+	if _, err := readsStdin(fs.Args()); err != nil {
+		return err
+	}
+
+	before, err := loadFiles(ctx, env, fs.Args()[:1])
+
+	if err != nil {
+		return err
+	}
+
+	after, err := loadFiles(ctx, env, fs.Args()[1:])
+
+	if err != nil {
+		return err
+	}
+
+	diff := diffTestRuns(before, after, slowdown{percentage: *slowerBy, minimum: *minSlowdown})
+
+	if err := withOutput(env, *output, func(w io.Writer) error {
+		return writeDiff(w, diff)
+	}); err != nil {
+		return err
+	}
+
+	if len(diff.newlyFailing) > 0 {
+		violation := fmt.Sprintf("contains %d newly failing test(s)", len(diff.newlyFailing))
+
+		return &testsFailedError{violations: []string{violation}}
+	}
+
+	return nil
+}
+
+// Returns the differences between the old and the new test run.
+func diffTestRuns(before, after xunit.TestRun, threshold slowdown) testRunDiff {
+	delta := compare.Runs(before, after)
+	diff := testRunDiff{added: delta.Added, removed: delta.Removed}
+
+	for _, change := range delta.StatusChanged {
+		switch {
+		case change.After.Result == "Fail":
+			diff.newlyFailing = append(diff.newlyFailing, change)
+		case change.After.Result == "Pass" && change.Before.Result == "Fail":
+			diff.newlyPassing = append(diff.newlyPassing, change)
+		}
+	}
+
+	for _, change := range delta.DurationChanged {
+		if threshold.exceededBy(change.Before.Duration, change.After.Duration) {
+			diff.slower = append(diff.slower, change)
+		}
+	}
+
+	return diff
+}
+
+// Returns true if the increase from the old to the new duration exceeds s, false otherwise.
+func (s slowdown) exceededBy(before, after time.Duration) bool {
+	if after <= before || after-before < s.minimum {
+		return false
+	}
+
+	return float64(after) >= float64(before)*(1+s.percentage/100)
+}
+
+// Writes diff to w, in a human-readable format.
+func writeDiff(w io.Writer, diff testRunDiff) error {
+	var sb strings.Builder
+
+	for _, section := range []struct {
+		title   string
+		icon    string
+		entries []compare.Change
+	}{
+		{title: "Newly failing", icon: "✘", entries: diff.newlyFailing},
+		{title: "Newly passing", icon: "✔", entries: diff.newlyPassing},
+		{title: "Added", icon: "+", entries: diff.added},
+		{title: "Removed", icon: "-", entries: diff.removed},
+		{title: "Slower", icon: "~", entries: diff.slower},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "%s (%d):\n", section.title, len(section.entries))
+
+		for _, entry := range section.entries {
+			fmt.Fprintf(&sb, "  %s %s\n", section.icon, changeName(entry))
+		}
+	}
+
+	if sb.Len() == 0 {
+		sb.WriteString("No differences found.\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// Returns the full name of the test which changed, followed by the change of its duration (if it's part of both test
+// runs).
+func changeName(change compare.Change) string {
+	if change.After == nil {
+		return fullName(change.Assembly, change.Path, change.Before.Name)
+	}
+
+	if change.Before == nil {
+		return fullName(change.Assembly, change.Path, change.After.Name)
+	}
+
+	// NOTE: The durations are rounded, since they are stored with a limited precision.
+	return fmt.Sprintf("%s (%v → %v)", fullName(change.Assembly, change.Path, change.After.Name),
+		change.Before.Duration.Round(time.Microsecond), change.After.Duration.Round(time.Microsecond))
+}
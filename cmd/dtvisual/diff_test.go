@@ -0,0 +1,132 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual diff`.
+func TestRunDiff(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	before := writeFile(t, "before.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"A\" result=\"Pass\" time=\"0.5\" />\n"+
+		"      <test name=\"B\" result=\"Fail\" time=\"0.5\" />\n"+
+		"      <test name=\"C\" result=\"Pass\" time=\"0.1\" />\n"+
+		"      <test name=\"D\" result=\"Pass\" time=\"0.1\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+	after := writeFile(t, "after.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"A\" result=\"Fail\" time=\"0.5\" />\n"+
+		"      <test name=\"B\" result=\"Pass\" time=\"0.5\" />\n"+
+		"      <test name=\"C\" result=\"Pass\" time=\"1\" />\n"+
+		"      <test name=\"E\" result=\"Pass\" time=\"0.1\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"diff", before, before},
+			wantCode: exitOK,
+			want:     "No differences found.\n",
+		},
+		{
+			args:     []string{"diff", before, after},
+			wantCode: exitTestsFailed,
+			want: "Newly failing (1):\n" +
+				"  ✘ App.dll › A (500ms → 500ms)\n" +
+				"\n" +
+				"Newly passing (1):\n" +
+				"  ✔ App.dll › B (500ms → 500ms)\n" +
+				"\n" +
+				"Added (1):\n" +
+				"  + App.dll › E\n" +
+				"\n" +
+				"Removed (1):\n" +
+				"  - App.dll › D\n" +
+				"\n" +
+				"Slower (1):\n" +
+				"  ~ App.dll › C (100ms → 1s)\n",
+		},
+		{
+			args:     []string{"diff", "--min-slowdown", "1s", after, before},
+			wantCode: exitTestsFailed,
+			want: "Newly failing (1):\n" +
+				"  ✘ App.dll › B (500ms → 500ms)\n" +
+				"\n" +
+				"Newly passing (1):\n" +
+				"  ✔ App.dll › A (500ms → 500ms)\n" +
+				"\n" +
+				"Added (1):\n" +
+				"  + App.dll › D\n" +
+				"\n" +
+				"Removed (1):\n" +
+				"  - App.dll › E\n",
+		},
+		{
+			args:     []string{"diff", before},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"diff", "-", "-"},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"diff", before, "missing.xml"},
+			wantCode: exitInput,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual diff`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Equal(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual diff`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
@@ -0,0 +1,207 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// A flaky test, as reported by the "flaky" command (in the JSON format).
+type flakyTestJSON struct {
+	Assembly string   `json:"assembly"`
+	Name     string   `json:"name"`
+	Score    float64  `json:"score"`
+	Flips    int      `json:"flips"`
+	Recent   []string `json:"recent"`
+	Traits   []string `json:"traits"`
+}
+
+// Executes the "flaky" command.
+func runFlaky(_ context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "flaky", "List the flakiest tests of the history store: the tests whose result flips "+
+		"between runs of\nthe same commit (or branch, for runs without a commit), with their score (the fraction of "+
+		"the\nconsecutive runs in which their result changed), their recent results and their traits.")
+	dir := fs.String("history", defaultHistoryDir, "The `directory` of the history store.")
+	top := fs.Int("top", 20, "List at most `N` tests (0 to list all of them).")
+	recent := fs.Int("recent", 10, "Show the results of each test in the last `N` runs.")
+	minScore := fs.Float64("min-score", 0, "Only list the tests with at least this `score` (0 - 1).")
+	format := fs.String("format", "table", "The output `format` (table or json).")
+	output := fs.String("output", "", "Write the tests to `file` instead of stdout.")
+	quarantine := fs.String("quarantine", "", "Write a suggestion to quarantine the listed tests to `file`, with a "+
+		"`dotnet test --filter`\nexpression excluding them.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		return &usageError{msg: "the flaky command doesn't accept input files"}
+	}
+
+	if *format != "table" && *format != "json" {
+		return &usageError{msg: fmt.Sprintf("unknown format %q", *format)}
+	}
+
+	store, err := history.Open(*dir)
+
+	if err != nil {
+		return err
+	}
+
+	runs, err := store.Runs()
+
+	if err != nil {
+		return err
+	}
+
+	tests := make([]analysis.FlakyTest, 0)
+
+	for _, test := range analysis.Flaky(runs, *recent) {
+		if test.Score >= *minScore && (*top <= 0 || len(tests) < *top) {
+			tests = append(tests, test)
+		}
+	}
+
+	if err := withOutput(env, *output, func(w io.Writer) error {
+		if *format == "json" {
+			return writeFlakyJSON(w, tests)
+		}
+
+		return writeFlaky(w, tests, len(runs))
+	}); err != nil {
+		return err
+	}
+
+	if *quarantine == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(*quarantine, []byte(quarantineFile(tests)), 0o644); err != nil {
+		return err
+	}
+
+	env.log.Info("Wrote the quarantine suggestion", "file", *quarantine, "tests", len(tests))
+
+	return nil
+}
+
+// Writes tests to w, as an aligned table, where runs is the number of runs in the history store.
+func writeFlaky(w io.Writer, tests []analysis.FlakyTest, runs int) error {
+	if len(tests) == 0 {
+		_, err := fmt.Fprintf(w, "No flaky tests in the %d run(s) of the history store.\n", runs)
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Score\tFlips\tRecent\tTest\tTraits\n")
+
+	for _, test := range tests {
+		traits := strings.Join(test.Traits, ", ")
+
+		if traits == "" {
+			traits = "-"
+		}
+
+		fmt.Fprintf(tw, "%.2f\t%d\t%s\t%s\t%s\n", test.Score, test.Flips, resultIcons(test.Results),
+			fullName(test.Assembly, nil, test.Name), traits)
+	}
+
+	return tw.Flush()
+}
+
+// Writes tests to w, in the JSON format.
+func writeFlakyJSON(w io.Writer, tests []analysis.FlakyTest) error {
+	resultSet := make([]flakyTestJSON, 0, len(tests))
+
+	for _, test := range tests {
+		resultSet = append(resultSet, flakyTestJSON{
+			Assembly: test.Assembly,
+			Name:     test.Name,
+			Score:    test.Score,
+			Flips:    test.Flips,
+			Recent:   test.Results,
+			Traits:   append(make([]string, 0, len(test.Traits)), test.Traits...),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(resultSet)
+}
+
+// Returns the content of the file suggesting to quarantine tests: the filter excluding them from `dotnet test`,
+// followed by a line per test (with its assembly and score).
+func quarantineFile(tests []analysis.FlakyTest) string {
+	var b strings.Builder
+
+	filters := make([]string, 0, len(tests))
+
+	for _, test := range tests {
+		filters = append(filters, "FullyQualifiedName!="+test.Name)
+	}
+
+	b.WriteString("# The flaky tests which are suggested to be quarantined, as listed by `dtvisual flaky`.\n")
+	b.WriteString("# Exclude them from a run (when they're named after their method) with:\n")
+	fmt.Fprintf(&b, "#   dotnet test --filter %q\n", strings.Join(filters, "&"))
+
+	for _, test := range tests {
+		fmt.Fprintf(&b, "%s\t%s\t%.2f\n", test.Assembly, test.Name, test.Score)
+	}
+
+	return b.String()
+}
+
+// Returns the icons representing results (e.g. "✔✘✔").
+func resultIcons(results []string) string {
+	var b strings.Builder
+
+	for _, result := range results {
+		switch result {
+		case "Pass":
+			b.WriteString("✔")
+		case "Fail":
+			b.WriteString("✘")
+		case "Skip":
+			b.WriteString("○")
+		default:
+			b.WriteString("?")
+		}
+	}
+
+	return b.String()
+}
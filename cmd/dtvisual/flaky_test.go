@@ -0,0 +1,113 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// UT: Execute `dtvisual flaky`.
+func TestRunFlaky(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	dir := t.TempDir()
+	store, _ := history.Open(dir)
+	quarantinePath := filepath.Join(t.TempDir(), "quarantine.txt")
+
+	for idx, results := range [][2]string{{"Pass", "Pass"}, {"Fail", "Pass"}, {"Pass", "Pass"}, {"Fail", "Skip"}} {
+		store.Add(history.Run{
+			ID:        "run" + string(rune('1'+idx)),
+			Commit:    "abc123",
+			Timestamp: time.Date(2023, 7, 10, 20, idx, 0, 0, time.UTC),
+			Tests: []history.Test{
+				{Assembly: "App.dll", Name: "NS.Calc.Adds", Result: results[0], Traits: []string{"Category - Unit"}},
+				{Assembly: "App.dll", Name: "NS.Calc.Subtracts", Result: results[1]},
+			},
+		})
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"flaky", "--history", dir, "--recent", "3"},
+			wantCode: exitOK,
+			want: "Score  Flips  Recent  Test                    Traits\n" +
+				"1.00   3      ✘✔✘     App.dll › NS.Calc.Adds  Category - Unit\n",
+		},
+		{
+			args:     []string{"flaky", "--history", dir, "--format", "json", "--quarantine", quarantinePath},
+			wantCode: exitOK,
+			want:     "\"name\": \"NS.Calc.Adds\",\n    \"score\": 1,\n    \"flips\": 3,\n",
+		},
+		{
+			args:     []string{"flaky", "--history", dir, "--min-score", "1.5"},
+			wantCode: exitOK,
+			want:     "No flaky tests in the 4 run(s) of the history store.\n",
+		},
+		{
+			args:     []string{"flaky", "--history", dir, "results.xml"},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"flaky", "--history", dir, "--format", "csv"},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual flaky`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual flaky`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+
+	got, _ := os.ReadFile(quarantinePath)
+	want := "#   dotnet test --filter \"FullyQualifiedName!=NS.Calc.Adds\"\nApp.dll\tNS.Calc.Adds\t1.00\n"
+
+	assert.Contains(t, string(got), want, "", "\n\n"+
+		"UT Name:    Execute `dtvisual flaky`.\n"+
+		"\033[32mExpected:   A quarantine file containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, got)
+}
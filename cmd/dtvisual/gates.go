@@ -0,0 +1,202 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/gate"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A condition which can be passed to the `--fail-on` flag.
+type failOnCondition struct {
+	name string           // The name of the condition.
+	rule func() gate.Rule // Returns the rule the condition corresponds to.
+}
+
+// The conditions which can be passed to the `--fail-on` flag.
+var failOnConditions = []failOnCondition{
+	{name: "failures", rule: gate.NoFailures},
+	{name: "errors", rule: gate.NoErrors},
+	{name: "skipped", rule: gate.NoSkipped},
+	{name: "not-run", rule: gate.NoNotRun},
+	{name: "none"},
+}
+
+// The gates, passed as flags, which a test run must satisfy.
+type gates struct {
+	failOn      failOn        // The value of the `--fail-on` flag.
+	minPassRate percentage    // The value of the `--fail-below-pass-rate` flag.
+	maxDuration time.Duration // The value of the `--fail-if-slower-than` flag.
+
+	minLineCoverage     percentage // The value of the `--min-line-coverage` flag.
+	minBranchCoverage   percentage // The value of the `--min-branch-coverage` flag.
+	coveragePerAssembly bool       // The value of the `--coverage-per-assembly` flag.
+}
+
+// A failOn is the set of conditions, passed to the `--fail-on` flag, which cause a command to fail.
+type failOn map[string]bool
+
+// A percentage is a value between 0 and 100.
+type percentage float64
+
+// A testsFailedError is returned when a test run doesn't satisfy the gates passed as flags.
+type testsFailedError struct {
+	violations []string
+}
+
+// Error returns the message of the error.
+func (e *testsFailedError) Error() string {
+	return "the test run " + strings.Join(e.violations, ", ")
+}
+
+// Adds the flags for the gates to fs, and returns their values.
+func addGateFlags(fs *flag.FlagSet) *gates {
+	g := &gates{failOn: failOn{"failures": true}}
+
+	fs.Var(g.failOn, "fail-on", "A comma-separated list of `conditions` which cause the command to exit with code 1 "+
+		"(failures, errors, skipped, not-run or none).")
+	fs.Var(&g.minPassRate, "fail-below-pass-rate", "Exit with code 1 if less than this `percentage` of the executed "+
+		"tests passed (e.g. 99.5).")
+	fs.DurationVar(&g.maxDuration, "fail-if-slower-than", 0, "Exit with code 1 if running the tests took longer than "+
+		"this `duration` (e.g. 10m).")
+
+	return g
+}
+
+// Adds the flags for the coverage gates to fs (for the commands which load code coverage).
+func (g *gates) addCoverageFlags(fs *flag.FlagSet) {
+	fs.Var(&g.minLineCoverage, "min-line-coverage", "Exit with code 1 if less than this `percentage` of the lines "+
+		"are covered (with --coverage).")
+	fs.Var(&g.minBranchCoverage, "min-branch-coverage", "Exit with code 1 if less than this `percentage` of the "+
+		"branches are covered (with --coverage).")
+	fs.BoolVar(&g.coveragePerAssembly, "coverage-per-assembly", false, "Apply --min-line-coverage and "+
+		"--min-branch-coverage to each assembly, instead of to the coverage of all the assemblies.")
+}
+
+// Returns true if any of the coverage gates is set.
+func (g *gates) hasCoverageGates() bool {
+	return g.minLineCoverage > 0 || g.minBranchCoverage > 0
+}
+
+// Returns a *testsFailedError if stats doesn't satisfy the gates, nil otherwise.
+func (g *gates) check(stats xunit.Stats) error {
+	return g.checkCoverage(stats, coverage.Report{})
+}
+
+// Returns a *testsFailedError if stats, or the code coverage in report, doesn't satisfy the gates, nil otherwise.
+func (g *gates) checkCoverage(stats xunit.Stats, report coverage.Report) error {
+	rules := make([]gate.Rule, 0, len(g.failOn)+2)
+
+	for _, c := range failOnConditions {
+		if g.failOn[c.name] {
+			rules = append(rules, c.rule())
+		}
+	}
+
+	if g.minPassRate > 0 {
+		rules = append(rules, gate.MinPassRate(float64(g.minPassRate)))
+	}
+
+	if g.maxDuration > 0 {
+		rules = append(rules, gate.MaxDuration(g.maxDuration))
+	}
+
+	covRules := make([]gate.CoverageRule, 0, 2)
+
+	if g.minLineCoverage > 0 {
+		covRules = append(covRules, gate.MinLineCoverage(float64(g.minLineCoverage), g.coveragePerAssembly))
+	}
+
+	if g.minBranchCoverage > 0 {
+		covRules = append(covRules, gate.MinBranchCoverage(float64(g.minBranchCoverage), g.coveragePerAssembly))
+	}
+
+	violations := append(gate.Evaluate(stats, rules...), gate.EvaluateCoverage(report, covRules...)...)
+
+	if len(violations) > 0 {
+		return &testsFailedError{violations: violations}
+	}
+
+	return nil
+}
+
+// String returns the conditions, separated by commas.
+func (f failOn) String() string {
+	conditions := make([]string, 0, len(f))
+
+	for _, c := range failOnConditions {
+		if f[c.name] {
+			conditions = append(conditions, c.name)
+		}
+	}
+
+	return strings.Join(conditions, ",")
+}
+
+// Set replaces the conditions by the comma-separated list of conditions in s.
+func (f failOn) Set(s string) error {
+	clear(f)
+
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+
+		if !slices.ContainsFunc(failOnConditions, func(fc failOnCondition) bool { return fc.name == c }) {
+			return fmt.Errorf("unknown condition %q", c)
+		}
+
+		if c != "none" {
+			f[c] = true
+		}
+	}
+
+	return nil
+}
+
+// String returns the percentage as a number.
+func (p *percentage) String() string {
+	return strconv.FormatFloat(float64(*p), 'f', -1, 64)
+}
+
+// Set parses s as a percentage.
+func (p *percentage) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+
+	if err != nil || v < 0 || v > 100 {
+		return fmt.Errorf("%q is not a percentage between 0 and 100", s)
+	}
+
+	*p = percentage(v)
+
+	return nil
+}
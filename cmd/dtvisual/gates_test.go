@@ -0,0 +1,209 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Check a test run against the gates passed as flags.
+func TestGatesCheck(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	stats := xunit.Stats{
+		PassedCount: 9, FailedCount: 1, ErrorCount: 2, SkippedCount: 3, NotRunCount: 4,
+		PassRate: 90, TotalDuration: 11 * time.Minute,
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		stats   xunit.Stats
+		wantErr string
+	}{
+		{
+			args:  []string{},
+			stats: xunit.Stats{PassedCount: 1, PassRate: 100},
+		},
+		{
+			args:  []string{"--fail-on", "none"},
+			stats: stats,
+		},
+		{
+			args:    []string{},
+			stats:   stats,
+			wantErr: "the test run contains 1 failed test(s)",
+		},
+		{
+			args:  []string{"--fail-on", "failures, errors,skipped,not-run"},
+			stats: stats,
+			wantErr: "the test run contains 1 failed test(s), contains 2 environmental error(s), " +
+				"contains 3 skipped test(s), contains 4 test(s) that weren't run",
+		},
+		{
+			args:    []string{"--fail-on", "none", "--fail-below-pass-rate", "99.5", "--fail-if-slower-than", "10m"},
+			stats:   stats,
+			wantErr: "the test run has a pass rate of 90.00%, which is below 99.50%, took 11m0s, which is longer than 10m0s",
+		},
+	} {
+		// ARRANGE.
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		g := addGateFlags(fs)
+
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatalf("Parse(%q) = %v, want <nil>", tc.args, err)
+		}
+
+		// ACT.
+		err := g.check(tc.stats)
+
+		// ASSERT.
+		got := ""
+
+		if err != nil {
+			got = err.Error()
+		}
+
+		assert.Equal(t, got, tc.wantErr, "", "\n\n"+
+			"UT Name:    Check a test run against the gates passed as flags.\n"+
+			"Input:      %q, %+v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.args, tc.stats, tc.wantErr, got)
+	}
+}
+
+// UT: Parse invalid gates.
+func TestGatesInvalid(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range [][]string{
+		{"--fail-on", "timeouts"},
+		{"--fail-below-pass-rate", "101"},
+		{"--fail-below-pass-rate", "high"},
+		{"--fail-if-slower-than", "10"},
+	} {
+		// ARRANGE.
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		addGateFlags(fs)
+
+		// ACT.
+		err := fs.Parse(tc)
+
+		// ASSERT.
+		assert.NotNil(t, err, "", "\n\n"+
+			"UT Name:    Parse invalid gates.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   Error, NOT <nil>\033[0m\n"+
+			"\033[31mActual:     Error, %v\033[0m\n\n", tc, err)
+	}
+}
+
+// UT: Get the conditions passed to `--fail-on`.
+func TestFailOnString(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		value string
+		want  string
+	}{
+		{value: "none", want: ""},
+		{value: "not-run,failures", want: "failures,not-run"},
+	} {
+		// ARRANGE.
+		f := failOn{}
+		f.Set(tc.value)
+
+		// ACT.
+		got := f.String()
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the conditions passed to `--fail-on`.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.value, tc.want, got)
+	}
+}
+
+// UT: Check the code coverage of a test run against the gates passed as flags.
+func TestGatesCheckCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	report := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {Hits: 1}}}}},
+		{Name: "Lib", Files: []coverage.File{{Path: "L.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {}}}}},
+	}}
+
+	for _, tc := range []struct {
+		args    []string
+		wantErr string
+	}{
+		{
+			args: []string{"--min-line-coverage", "75"},
+		},
+		{
+			args:    []string{"--min-line-coverage", "80"},
+			wantErr: "the test run has a line coverage of 75.00%, which is below 80.00%",
+		},
+		{
+			args:    []string{"--min-line-coverage", "75", "--coverage-per-assembly"},
+			wantErr: "the test run has a line coverage of 50.00% in Lib, which is below 75.00%",
+		},
+	} {
+		// ARRANGE.
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		g := addGateFlags(fs)
+		g.addCoverageFlags(fs)
+
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatalf("Parse(%q) = %v, want <nil>", tc.args, err)
+		}
+
+		// ACT.
+		err := g.checkCoverage(xunit.Stats{PassedCount: 1, PassRate: 100}, report)
+
+		// ASSERT.
+		got := ""
+
+		if err != nil {
+			got = err.Error()
+		}
+
+		assert.Equal(t, got, tc.wantErr, "", "\n\n"+
+			"UT Name:    Check the code coverage of a test run against the gates passed as flags.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.args, tc.wantErr, got)
+	}
+}
@@ -0,0 +1,284 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// The directory of the history store, unless specified otherwise.
+const defaultHistoryDir = ".dtvisual/history"
+
+// The characters which can't be part of the ID of an imported run.
+var unsafeIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// The subcommands of `dtvisual history`.
+var historyCommands = []command{
+	{name: "add", summary: "Add the test results to the history store.", run: runHistoryAdd},
+	{name: "prune", summary: "Remove the runs from the history store which aren't retained.", run: runHistoryPrune},
+	{name: "import", summary: "Add the result files in a directory to the history store.", run: runHistoryImport},
+}
+
+// Executes the "history" command, which dispatches to one of its subcommands.
+func runHistory(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printHistoryUsage(env.stderr)
+
+		if len(args) == 0 {
+			return &usageError{msg: "no subcommand"}
+		}
+
+		return nil
+	}
+
+	for _, c := range historyCommands {
+		if c.name == args[0] {
+			return c.run(ctx, env, args[1:])
+		}
+	}
+
+	return &usageError{msg: fmt.Sprintf("unknown subcommand %q", args[0])}
+}
+
+// Writes the usage of `dtvisual history` to w.
+func printHistoryUsage(w io.Writer) {
+	fmt.Fprint(w, "Manage the store containing the results of past test runs.\n\n"+
+		"Usage:\n\n"+
+		"  dtvisual history <subcommand> [flags] <file>...\n\n"+
+		"Subcommands:\n\n")
+
+	for _, c := range historyCommands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// Executes the "history add" command.
+func runHistoryAdd(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "history add", "Add the test results to the history store, as a single run.")
+	dir := fs.String("history", defaultHistoryDir, "The `directory` of the history store.")
+	commit := fs.String("commit", os.Getenv("GITHUB_SHA"), "The commit which was tested (defaults to $GITHUB_SHA).")
+	branch := fs.String("branch", os.Getenv("GITHUB_REF_NAME"), "The branch which was tested (defaults to "+
+		"$GITHUB_REF_NAME).")
+	baseline := fs.Bool("baseline", false, "Mark the run as the baseline, which later runs are compared with.")
+	coverageFiles := make([]string, 0)
+
+	fs.Func("coverage", "Store the code coverage in `file` (in any format of the report command) with the run, which "+
+		"can be repeated to merge the coverage of sharded runs.", func(v string) error {
+		coverageFiles = append(coverageFiles, v)
+
+		return nil
+	})
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	store, err := history.Open(*dir)
+
+	if err != nil {
+		return err
+	}
+
+	run := history.NewRun(testRun, *commit, *branch, time.Now())
+
+	if len(coverageFiles) > 0 {
+		cov, err := loadCoverage(env, coverageFiles)
+
+		if err != nil {
+			return err
+		}
+
+		run.Coverage = history.NewCoverage(cov)
+	}
+
+	if err := store.Add(run); err != nil {
+		return err
+	}
+
+	env.log.Info("Added the run to the history store", "id", run.ID, "tests", len(run.Tests))
+
+	if *baseline {
+		return store.SetBaseline(run.ID)
+	}
+
+	return nil
+}
+
+// Executes the "history prune" command.
+func runHistoryPrune(_ context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "history prune", "Remove the runs from the history store which aren't retained.\n\n"+
+		"The baseline run is always retained.")
+	dir := fs.String("history", defaultHistoryDir, "The `directory` of the history store.")
+	policy := history.Retention{}
+
+	fs.IntVar(&policy.KeepPerBranch, "keep-per-branch", 0, "Retain only this `number` of most recent runs per branch.")
+	fs.DurationVar(&policy.MaxAge, "max-age", 0, "Retain only the runs younger than this `duration` (e.g. 720h).")
+	fs.Var((*byteSize)(&policy.MaxSize), "max-size", "Remove the oldest runs until the history store is no larger "+
+		"than this `size` (e.g. 512K, 100M or 1G).")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		return &usageError{msg: "unexpected arguments"}
+	}
+
+	store, err := history.Open(*dir)
+
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Prune(policy, time.Now())
+
+	for _, id := range removed {
+		env.log.Info("Removed the run from the history store", "id", id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.stdout, "Removed %d run(s) from the history store.\n", len(removed))
+
+	return nil
+}
+
+// Executes the "history import" command.
+func runHistoryImport(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "history import", "Add the result files (*.xml) in a directory to the history store, as "+
+		"one run per file.\n\n"+
+		"The time of each run is taken from the file itself, from a date in its name (e.g. results-20230710.xml), "+
+		"or from its modification time.\n"+
+		"Importing the same file twice replaces the run.")
+	dir := fs.String("history", defaultHistoryDir, "The `directory` of the history store.")
+	branch := fs.String("branch", "", "The branch which was tested.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "expected exactly 1 directory"}
+	}
+
+	entries, err := os.ReadDir(fs.Arg(0))
+
+	if err != nil {
+		return &inputError{err: err}
+	}
+
+	store, err := history.Open(*dir)
+
+	if err != nil {
+		return err
+	}
+
+	count := 0
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".xml") {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return &inputError{err: err}
+		}
+
+		testRun, err := loadFiles(ctx, env, []string{filepath.Join(fs.Arg(0), entry.Name())})
+
+		if err != nil {
+			return err
+		}
+
+		timestamp, source := history.Timestamp(testRun, entry.Name(), info.ModTime())
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		run := history.NewRun(testRun, "", *branch, timestamp)
+		run.ID += "-" + unsafeIDChars.ReplaceAllString(name, "-")
+
+		if err := store.Add(run); err != nil {
+			return err
+		}
+
+		env.log.Debug("Imported the result file", "file", entry.Name(), "id", run.ID, "timestamp", timestamp,
+			"source", source)
+		count++
+	}
+
+	fmt.Fprintf(env.stdout, "Imported %d run(s) into the history store.\n", count)
+
+	return nil
+}
+
+// A byteSize is a number of bytes, which can be followed by a 1024-based unit (K, M or G).
+type byteSize int64
+
+// String returns the number of bytes.
+func (b *byteSize) String() string {
+	return strconv.FormatInt(int64(*b), 10)
+}
+
+// Set parses s as a number of bytes.
+func (b *byteSize) Set(s string) error {
+	number, multiplier := strings.ToUpper(s), int64(1)
+
+	for idx, unit := range []string{"K", "M", "G"} {
+		if trimmed, ok := strings.CutSuffix(number, unit); ok {
+			number, multiplier = trimmed, 1<<(10*(idx+1))
+
+			break
+		}
+	}
+
+	v, err := strconv.ParseInt(number, 10, 64)
+
+	if err != nil || v < 0 {
+		return fmt.Errorf("%q is not a size (e.g. 512K, 100M or 1G)", s)
+	}
+
+	*b = byteSize(v * multiplier)
+
+	return nil
+}
@@ -0,0 +1,284 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// UT: Execute `dtvisual history`.
+func TestRunHistory(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		args       []string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			args:       []string{"history"},
+			wantCode:   exitUsage,
+			wantStderr: "dtvisual history <subcommand> [flags] <file>...",
+		},
+		{
+			args:       []string{"history", "--help"},
+			wantCode:   exitOK,
+			wantStderr: "Subcommands:",
+		},
+		{
+			args:       []string{"history", "remove"},
+			wantCode:   exitUsage,
+			wantStderr: "unknown subcommand \"remove\"",
+		},
+		{
+			args:       []string{"history", "add", "--history", t.TempDir()},
+			wantCode:   exitUsage,
+			wantStderr: "no input files",
+		},
+	} {
+		// ACT.
+		code, _, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual history`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stderr, tc.wantStderr, "", "\n\n"+
+			"UT Name:    Execute `dtvisual history`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.wantStderr, stderr)
+	}
+}
+
+// UT: Triage the failures of `dtvisual report` against a baseline added with `dtvisual history add`.
+func TestRunReport_Baseline(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Join(t.TempDir(), "history")
+	baseline := writeFile(t, "baseline.xml", xmlData)
+	current := writeFile(t, "current.xml", strings.Replace(xmlData, "result=\"Pass\"", "result=\"Fail\"", 1))
+
+	_, _, stderrBefore := execute("report", "--history", dir, "--fail-on", "none", current)
+	code, _, stderr := execute("history", "add", "--history", dir, "--baseline", baseline)
+
+	if code != exitOK {
+		t.Fatalf("history add = %d (stderr: %s), want %d", code, stderr, exitOK)
+	}
+
+	// ACT.
+	_, stdout, _ := execute("report", "--history", dir, "--fail-on", "none", current)
+
+	// ASSERT.
+	assert.Contains(t, stderrBefore, "there's no baseline run", "", "\n\n"+
+		"UT Name:    Triage the failures of `dtvisual report` against a baseline.\n"+
+		"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", "there's no baseline run", stderrBefore)
+
+	want := "\nNew regressions (1):\n" +
+		"  ✘ App.dll › A passing test.\n" +
+		"\nPre-existing failures (1):\n" +
+		"  ✘ App.dll › A failing test.\n"
+
+	assert.Equal(t, strings.HasSuffix(stdout, want), true, "", "\n\n"+
+		"UT Name:    Triage the failures of `dtvisual report` against a baseline.\n"+
+		"\033[32mExpected:   Stdout ending with %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, stdout)
+}
+
+// UT: Report the tests of `dtvisual report` which are slower than usual.
+func TestRunReport_SlowerThanUsual(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Join(t.TempDir(), "history")
+	past := writeFile(t, "past.xml", strings.Replace(xmlData, "time=\"1\"", "time=\"0.25\"", 1))
+	current := writeFile(t, "current.xml", xmlData)
+
+	if code, _, stderr := execute("history", "add", "--history", dir, past); code != exitOK {
+		t.Fatalf("history add = %d (stderr: %s), want %d", code, stderr, exitOK)
+	}
+
+	// ACT.
+	_, stdout, _ := execute("report", "--history", dir, "--fail-on", "none", current)
+
+	// ASSERT.
+	want := "\nSlower than usual (1):\n" +
+		"  ~ App.dll › A failing test. (1s, median 250ms, 4.0x)\n"
+
+	assert.Equal(t, strings.HasSuffix(stdout, want), true, "", "\n\n"+
+		"UT Name:    Report the tests of `dtvisual report` which are slower than usual.\n"+
+		"\033[32mExpected:   Stdout ending with %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, stdout)
+}
+
+// UT: Compare the coverage of `dtvisual report` with the coverage of a baseline added with `dtvisual history add`.
+func TestRunReport_CoverageDiff(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Join(t.TempDir(), "history")
+	results := writeFile(t, "results.xml", xmlData)
+	before := writeFile(t, "before.info", "TN:App\nSF:src/A.cs\nDA:1,1\nDA:2,1\nend_of_record\n"+
+		"TN:App\nSF:src/B.cs\nDA:1,1\nend_of_record\n")
+	after := writeFile(t, "after.info", "TN:App\nSF:src/A.cs\nDA:1,1\nDA:2,0\nend_of_record\n"+
+		"TN:App\nSF:src/B.cs\nDA:1,1\nend_of_record\n")
+
+	if code, _, stderr := execute("history", "add", "--history", dir, "--baseline", "--coverage", before,
+		results); code != exitOK {
+		t.Fatalf("history add = %d (stderr: %s), want %d", code, stderr, exitOK)
+	}
+
+	// ACT.
+	code, stdout, stderr := execute("report", "--history", dir, "--coverage", after, "--fail-on", "none", results)
+
+	// ASSERT.
+	assert.Equal(t, code, exitOK, "", "\n\n"+
+		"UT Name:    Compare the coverage of `dtvisual report` with the coverage of a baseline.\n"+
+		"\033[32mExpected:   Exit code %d\033[0m\n"+
+		"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", exitOK, code, stderr)
+
+	want := "\nCoverage compared to the baseline: lines 66.67% (-33.33), branches -\n" +
+		"\nLess coverage than the baseline (1):\n" +
+		"  ▼ src/A.cs (App): lines 50.00% (-50.00), branches -\n"
+
+	assert.Equal(t, strings.HasSuffix(stdout, want), true, "", "\n\n"+
+		"UT Name:    Compare the coverage of `dtvisual report` with the coverage of a baseline.\n"+
+		"\033[32mExpected:   Stdout ending with %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, stdout)
+}
+
+// UT: Execute `dtvisual history prune`.
+func TestRunHistoryPrune(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Join(t.TempDir(), "history")
+	path := writeFile(t, "results.xml", xmlData)
+
+	for i := 0; i < 3; i++ {
+		if code, _, stderr := execute("history", "add", "--history", dir, "--branch", "main", path); code != exitOK {
+			t.Fatalf("history add = %d (stderr: %s), want %d", code, stderr, exitOK)
+		}
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"history", "prune", "--history", dir, "--max-size", "1T"},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"history", "prune", "--history", dir, path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"history", "prune", "--history", dir, "--keep-per-branch", "1"},
+			wantCode: exitOK,
+			want:     "Removed 2 run(s) from the history store.\n",
+		},
+		{
+			args:     []string{"history", "prune", "--history", dir, "--max-size", "1K"},
+			wantCode: exitOK,
+			want:     "Removed 0 run(s) from the history store.\n",
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual history prune`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Equal(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual history prune`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
+
+// UT: Execute `dtvisual history import`.
+func TestRunHistoryImport(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Join(t.TempDir(), "history")
+	archive := t.TempDir()
+
+	for _, name := range []string{"results-20230711.xml", "results-20230710.xml", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(archive, name), []byte(xmlData), 0o644); err != nil {
+			t.Fatalf("WriteFile() = %v, want <nil>", err)
+		}
+	}
+
+	// ACT.
+	code, stdout, stderr := execute("history", "import", "--history", dir, "--branch", "main", archive)
+	codeAgain, _, _ := execute("history", "import", "--history", dir, "--branch", "main", archive)
+
+	// ASSERT.
+	assert.Equal(t, code == exitOK && codeAgain == exitOK, true, "", "\n\n"+
+		"UT Name:    Execute `dtvisual history import`.\n"+
+		"\033[32mExpected:   Exit code %d\033[0m\n"+
+		"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", exitOK, code, stderr)
+
+	assert.Equal(t, stdout, "Imported 2 run(s) into the history store.\n", "", "\n\n"+
+		"UT Name:    Execute `dtvisual history import`.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", "Imported 2 run(s) into the history store.\n", stdout)
+
+	store, _ := history.Open(dir)
+	runs, _ := store.Runs()
+	got := make([]string, 0, len(runs))
+
+	for _, run := range runs {
+		got = append(got, run.ID)
+	}
+
+	want := []string{"20230710T000000.000000000Z-results-20230710", "20230711T000000.000000000Z-results-20230711"}
+
+	assert.Equal(t, strings.Join(got, ","), strings.Join(want, ","), "", "\n\n"+
+		"UT Name:    Execute `dtvisual history import`.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
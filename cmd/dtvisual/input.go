@@ -0,0 +1,268 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The path which refers to stdin.
+const stdinPath = "-"
+
+// The formats which can be recognized, by the name of the root element of the document.
+var formats = map[string]string{
+	"assemblies": "xunit",
+	"TestRun":    "trx",
+	"testsuites": "junit",
+	"testsuite":  "junit",
+}
+
+// Returns a single TestRun containing the assemblies of all the result files at paths.
+// The path "-" refers to the stdin of env, which can only be read once.
+// The information about the test run itself (computer, user, ...) is taken from the first file.
+func loadFiles(ctx context.Context, env *env, paths []string) (xunit.TestRun, error) {
+	if len(paths) == 0 {
+		return xunit.TestRun{}, &usageError{msg: "no input files"}
+	}
+
+	if _, err := readsStdin(paths); err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	env.log.Debug("Loading the test results", "files", paths)
+
+	testRuns := make([]xunit.TestRun, 0, len(paths))
+
+	for _, path := range paths {
+		testRun, err := loadFile(ctx, env, path)
+
+		if err != nil {
+			return xunit.TestRun{}, &inputError{err: err}
+		}
+
+		testRuns = append(testRuns, testRun)
+	}
+
+	return xunit.Merge(testRuns...), nil
+}
+
+// Returns true if paths refers to stdin, false otherwise.
+// It returns an error if stdin is referred to more than once.
+func readsStdin(paths []string) (bool, error) {
+	count := 0
+
+	for _, path := range paths {
+		if path == stdinPath {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return true, &usageError{msg: "stdin (\"-\") can only be used once"}
+	}
+
+	return count == 1, nil
+}
+
+// Returns the TestRun stored in the file at path (or read from stdin if path is "-").
+func loadFile(ctx context.Context, env *env, path string) (xunit.TestRun, error) {
+	var data []byte
+	var err error
+
+	if path == stdinPath {
+		path = "<stdin>"
+		data, err = io.ReadAll(env.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	env.log.Debug("Read the result file", "file", path, "bytes", len(data))
+
+	return parseFile(ctx, env, path, data)
+}
+
+// Returns the TestRun stored in data, which is the content of the result file at path.
+func parseFile(ctx context.Context, env *env, path string, data []byte) (xunit.TestRun, error) {
+	format, err := detectFormat(data)
+
+	if err != nil {
+		return xunit.TestRun{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	env.log.Debug("Detected the format of the result file", "file", path, "format", format)
+
+	if format != "xunit" {
+		return xunit.TestRun{}, fmt.Errorf("%s: the %s format is not supported", path, format)
+	}
+
+	start := time.Now()
+	testRun, err := xunit.LoadContext(ctx, bytes.NewReader(data), loadOptions(env))
+
+	if err != nil {
+		return xunit.TestRun{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	stats := testRun.Stats()
+	env.log.Info("Parsed the result file", "file", path, "assemblies", stats.AssemblyCount, "tests", stats.TotalCount,
+		"duration", time.Since(start))
+
+	if len(env.filters) > 0 {
+		testRun = testRun.Filter(env.filters...)
+		stats := testRun.Stats()
+		env.log.Info("Filtered the tests", "file", path, "filters", env.filters, "tests", stats.TotalCount)
+	}
+
+	for _, warning := range testRun.Warnings {
+		env.log.Warn(warning, "file", path)
+	}
+
+	for _, diag := range []struct {
+		msg, key string
+		counts   map[string]int
+	}{
+		{msg: "Missing attribute", key: "attribute", counts: testRun.Diagnostics.MissingAttributes},
+		{msg: "Unknown attribute", key: "attribute", counts: testRun.Diagnostics.UnknownAttributes},
+		{msg: "Skipped element", key: "element", counts: testRun.Diagnostics.SkippedElements},
+	} {
+		for _, name := range maps.SortedKeys(diag.counts) {
+			env.log.Debug(diag.msg, "file", path, diag.key, name, "count", diag.counts[name])
+		}
+	}
+
+	return testRun, nil
+}
+
+// Returns an Index of the result file at path (or read from stdin if path is "-"), whose tests are filtered by the
+// filters of env. The details of its tests are only loaded when they're needed.
+func loadIndex(ctx context.Context, env *env, path string) (*xunit.Index, error) {
+	rdr := env.stdin
+
+	if path == stdinPath {
+		path = "<stdin>"
+	} else {
+		f, err := os.Open(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer f.Close()
+
+		rdr = f
+	}
+
+	start := time.Now()
+	index, err := xunit.LoadIndexContext(ctx, rdr, loadOptions(env))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	stats := index.TestRun.Stats()
+	env.log.Info("Indexed the result file", "file", path, "assemblies", stats.AssemblyCount, "tests", stats.TotalCount,
+		"duration", time.Since(start))
+
+	if len(env.filters) > 0 {
+		index.TestRun = index.TestRun.Filter(env.filters...)
+	}
+
+	for _, warning := range index.TestRun.Warnings {
+		env.log.Warn(warning, "file", path)
+	}
+
+	return index, nil
+}
+
+// Returns the options for loading the result files, which are set by the shared flags of env.
+func loadOptions(env *env) xunit.Options {
+	opts := xunit.Options{
+		Partial:      env.partial,
+		Sanitize:     env.sanitize,
+		Namespaces:   env.namespaces,
+		GroupBy:      env.groupBy,
+		Delimiters:   env.delimiters,
+		Humanize:     env.humanize,
+		MaxDepth:     env.maxDepth,
+		Collapse:     env.collapse,
+		MemoryBudget: env.memoryBudget,
+	}
+
+	if env.displayNames != nil {
+		opts.DisplayName = env.displayNames.MatchString
+	}
+
+	return opts
+}
+
+// Returns the format of data, based on the name of its root element.
+func detectFormat(data []byte) (string, error) {
+	root, err := rootElement(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	if format, ok := formats[root]; ok {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unrecognized format (root element <%s>)", root)
+}
+
+// Returns the name of the root element of data (an XML document).
+func rootElement(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", errors.New("no XML document found")
+			}
+
+			return "", err
+		}
+
+		if el, ok := tok.(xml.StartElement); ok {
+			return el.Name.Local, nil
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Load multiple result files as a single test run.
+func TestLoadFiles(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	stdin := strings.NewReader("\ufeff<?xml version=\"1.0\"?>\n<!-- xUnit -->\n<assemblies computer=\"LINUX\">" +
+		"<assembly name=\"Other.dll\" /></assemblies>")
+
+	// ACT.
+	got, err := loadFiles(context.Background(), newEnv(stdin, io.Discard, io.Discard), []string{path, "-"})
+
+	// ASSERT.
+	assert.NoError(t, err, "loadFiles()")
+	assert.Equal(t, got.Computer, "WIN11", "loadFiles().Computer")
+	assert.Len(t, got.Assemblies, 2, "loadFiles().Assemblies")
+	assert.Equal(t, got.Assemblies[1].Name, "Other.dll", "loadFiles().Assemblies[1].Name")
+}
+
+// UT: Load result files in an unsupported format.
+func TestLoadFilesUnsupported(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		paths   []string
+		stdin   string
+		wantErr string
+	}{
+		{
+			paths:   []string{"-", "-"},
+			wantErr: "stdin (\"-\") can only be used once",
+		},
+		{
+			paths:   []string{"-"},
+			stdin:   "",
+			wantErr: "<stdin>: no XML document found",
+		},
+		{
+			paths:   []string{"-"},
+			stdin:   "<TestRun xmlns=\"http://microsoft.com/schemas/VisualStudio/TeamTest/2010\" />",
+			wantErr: "<stdin>: the trx format is not supported",
+		},
+		{
+			paths:   []string{"-"},
+			stdin:   "<testsuites />",
+			wantErr: "<stdin>: the junit format is not supported",
+		},
+		{
+			paths:   []string{"-"},
+			stdin:   "<html />",
+			wantErr: "<stdin>: unrecognized format (root element <html>)",
+		},
+	} {
+		// ACT.
+		_, err := loadFiles(context.Background(), newEnv(strings.NewReader(tc.stdin), io.Discard, io.Discard), tc.paths)
+
+		// ASSERT.
+		assert.NotNil(t, err, "loadFiles()")
+		assert.Equal(t, err.Error(), tc.wantErr, "", "\n\n"+
+			"UT Name:    Load result files in an unsupported format.\n"+
+			"Input:      %v (stdin: %q)\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.paths, tc.stdin, tc.wantErr, err)
+	}
+}
+
+// UT: Filter the tests of the result files (before rendering and gating them).
+func TestRunFilter(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"stats", "--filter", "result=Pass", path},
+			wantCode: exitOK,
+			want:     "Total       1\nPassed      1\nFailed      0\n",
+		},
+		{
+			args:     []string{"stats", "--filter", "name~passing", "--filter", "result!=Pass", path},
+			wantCode: exitOK,
+			want:     "Total       0\n",
+		},
+		{
+			args:     []string{"stats", "--filter", "name~failing", path},
+			wantCode: exitTestsFailed,
+			want:     "Total       1\nPassed      0\nFailed      1\n",
+		},
+		{
+			args:     []string{"stats", "--filter", "status=Fail", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Filter the tests of the result files (before rendering and gating them).\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Filter the tests of the result files (before rendering and gating them).\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
+
+// UT: Group the tests of the result files by result.
+func TestRunGroupBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"report", "--format", "text", "--group-by", "result", path},
+			wantCode: exitTestsFailed,
+			want:     "  Fail (1 failed)",
+		},
+		{
+			args:     []string{"report", "--format", "text", "--group-by", "result", "--filter", "result=Pass", path},
+			wantCode: exitOK,
+			want:     "  Pass (1 passed)",
+		},
+		{
+			args:     []string{"report", "--group-by", "class", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Group the tests of the result files by result.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Group the tests of the result files by result.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
@@ -0,0 +1,327 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package main implements the `dtvisual` command, which visualizes .NET test result(s) in xUnit's v2+ XML format.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"regexp"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The exit codes of the command.
+const (
+	exitOK          = 0 // The command succeeded.
+	exitTestsFailed = 1 // The test run doesn't satisfy the gates passed as flags (e.g. `--fail-on`).
+	exitUsage       = 2 // The command was invoked incorrectly.
+	exitInput       = 3 // The test results couldn't be read (or parsed).
+	exitError       = 4 // The command failed for another reason (e.g. the output couldn't be written).
+)
+
+// A command is a subcommand of `dtvisual`.
+type command struct {
+	name    string                                                   // The name of the command.
+	summary string                                                   // A one-line description of the command.
+	run     func(ctx context.Context, env *env, args []string) error // The implementation of the command.
+}
+
+// The environment in which a command is executed.
+type env struct {
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	log      *slog.Logger   // The logger writing diagnostic messages to stderr.
+	logLevel *slog.LevelVar // The minimum level of the messages written by log (set by `--quiet`, `--verbose`, ...).
+	partial  bool           // True to load the tests before the error of truncated result files (set by `--partial`).
+	sanitize xunit.Sanitize // How the control characters in the tests are handled (set by `--sanitize`).
+
+	// How the namespaces of nested tests are shown in their groups (set by `--namespaces`).
+	namespaces xunit.Namespaces
+
+	groupBy xunit.GroupBy // The top-level groups of the tests of each assembly (set by `--group-by`).
+
+	// The delimiters which separate the groups in the names of the tests (set by `--delimiter`).
+	delimiters []string
+
+	humanize bool // True to write the names of the methods of the tests as sentences (set by `--humanize`).
+	maxDepth int  // The maximum number of levels of groups inside the group of a trait (set by `--max-depth`).
+	collapse bool // True to merge the groups which only contain a single group with that group (set by `--collapse`).
+
+	// The names of the tests which are display names, rather than the names of methods (set by `--display-names`).
+	displayNames *regexp.Regexp
+
+	filters []xunit.Filter // The filters which select the tests of the result files (set by `--filter`).
+
+	// The maximum size of a result file which is kept in memory to load the details of its tests on demand (set by
+	// `--memory-budget`).
+	memoryBudget int64
+}
+
+// Returns a new environment, which writes its diagnostic messages (warnings by default) to stderr.
+func newEnv(stdin io.Reader, stdout, stderr io.Writer) *env {
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(slog.LevelWarn)
+
+	log := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			// NOTE: The time doesn't add anything to the messages of a short-lived command.
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}))
+
+	return &env{stdin: stdin, stdout: stdout, stderr: stderr, log: log, logLevel: logLevel}
+}
+
+// A usageError is returned when a command is invoked incorrectly.
+type usageError struct {
+	msg string
+}
+
+// Error returns the message of the error.
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// An inputError is returned when the test results can't be read (or parsed).
+type inputError struct {
+	err error
+}
+
+// Error returns the message of the error.
+func (e *inputError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// errBadFlags is returned when the flags of a command can't be parsed.
+// The error itself is already reported by the flag package.
+var errBadFlags = errors.New("bad flags")
+
+// The subcommands of `dtvisual`.
+var commands = []command{
+	{name: "report", summary: "Render the test results (as a tree in the terminal, or as an HTML page).", run: runReport},
+	{name: "summary", summary: "Write a GitHub Actions job summary of the test results.", run: runSummary},
+	{name: "convert", summary: "Convert the test results to another format.", run: runConvert},
+	{name: "stats", summary: "Print the statistics of the test results.", run: runStats},
+	{name: "validate", summary: "Check the result files for problems (e.g. invalid counts).", run: runValidate},
+	{name: "browse", summary: "Browse the test results interactively in the terminal.", run: runBrowse},
+	{name: "serve", summary: "Serve an interactive HTML report of the test results over HTTP.", run: runServe},
+	{name: "diff", summary: "Compare two test runs.", run: runDiff},
+	{name: "history", summary: "Manage the history of past test runs.", run: runHistory},
+	{name: "flaky", summary: "List the flakiest tests of the history of past test runs.", run: runFlaky},
+	{name: "badge", summary: "Write SVG badges of the test results (e.g. the pass rate) to a directory.", run: runBadge},
+	{name: "site", summary: "Write a static website of the result files in a directory.", run: runSite},
+	{name: "publish", summary: "Publish the test results to another service (e.g. Azure DevOps).", run: runPublish},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], newEnv(os.Stdin, os.Stdout, os.Stderr))
+
+	stop()
+	os.Exit(code)
+}
+
+// Executes the command described by args, and returns its exit code.
+// When the first argument isn't the name of a command, the "report" command is executed.
+func run(ctx context.Context, args []string, env *env) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printUsage(env.stderr)
+
+		if len(args) == 0 {
+			return exitUsage
+		}
+
+		return exitOK
+	}
+
+	cmd, cmdArgs := commands[0], args
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			cmd, cmdArgs = c, args[1:]
+		}
+	}
+
+	err := cmd.run(ctx, env, cmdArgs)
+
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	// NOTE: The flag package already reported the error.
+	if errors.Is(err, errBadFlags) {
+		return exitUsage
+	}
+
+	fmt.Fprintf(env.stderr, "dtvisual %s: %v\n", cmd.name, err)
+
+	return exitCode(err)
+}
+
+// Returns the exit code corresponding to err.
+func exitCode(err error) int {
+	var usageErr *usageError
+	var inputErr *inputError
+	var testsFailedErr *testsFailedError
+
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &inputErr):
+		return exitInput
+	case errors.As(err, &testsFailedErr):
+		return exitTestsFailed
+	default:
+		return exitError
+	}
+}
+
+// Writes the usage of `dtvisual` to w.
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, "dtvisual visualizes .NET test result(s) in xUnit's v2+ XML format.\n\n"+
+		"Usage:\n\n"+
+		"  dtvisual <command> [flags] <file>...\n\n"+
+		"Use \"-\" as file to read the test results from stdin.\n\n"+
+		"Commands:\n\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+
+	fmt.Fprint(w, "\nExit codes:\n\n"+
+		"  0          The command succeeded.\n"+
+		"  1          The test run doesn't satisfy the gates passed as flags (by default: it contains failed tests).\n"+
+		"  2          The command was invoked incorrectly.\n"+
+		"  3          The test results couldn't be read (or parsed).\n"+
+		"  4          The command failed for another reason.\n"+
+		"\nEach command accepts --quiet, --verbose and --debug to control the diagnostic messages written to stderr.\n"+
+		"\nRun \"dtvisual <command> -h\" for more information about a command.\n")
+}
+
+// Returns a FlagSet for the command name, which writes its usage (and errors) to the stderr of env.
+// The FlagSet contains the flags controlling the level of the diagnostic messages of env.
+func newFlagSet(env *env, name, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+
+	for _, v := range []struct {
+		name  string
+		level slog.Level
+		usage string
+	}{
+		{name: "quiet", level: slog.LevelError, usage: "Only log errors (no warnings)."},
+		{name: "verbose", level: slog.LevelInfo, usage: "Log the files being processed (and how long they take)."},
+		{name: "debug", level: slog.LevelDebug, usage: "Log everything (for debugging parsing problems)."},
+	} {
+		level := v.level
+
+		fs.BoolFunc(v.name, v.usage, func(string) error {
+			env.logLevel.Set(level)
+
+			return nil
+		})
+	}
+
+	fs.BoolVar(&env.partial, "partial", false,
+		"Load the tests of truncated (or malformed) result files up to the error, instead of failing.")
+	fs.TextVar(&env.sanitize, "sanitize", xunit.SanitizeStrip, "How the control characters (and ANSI escape "+
+		"sequences) in the names and failures of the tests are handled (strip, escape or none).")
+	fs.TextVar(&env.namespaces, "namespaces", xunit.NamespacesStrip, "How the namespaces of nested tests are shown "+
+		"in their groups: removed (strip), as a separate group (group), or in the group of the outer class (qualify).")
+	fs.TextVar(&env.groupBy, "group-by", xunit.GroupByTrait, "Group the tests of each assembly by trait, namespace, "+
+		"collection or result, or not at all (assembly), before grouping them by class.")
+	fs.BoolVar(&env.humanize, "humanize", false, "Write the names of the methods of the tests as sentences (e.g. "+
+		"Returns an error for Returns_An_Error).")
+	fs.IntVar(&env.maxDepth, "max-depth", 0, "Combine the groups of the tests nested deeper than `n` levels into a "+
+		"single group (0 for unlimited).")
+	fs.BoolVar(&env.collapse, "collapse", false, "Merge each group which only contains a single group (and no tests) "+
+		"with that group (e.g. Outer / Inner).")
+	fs.Func("delimiter", "Split the names of the tests into groups at each `delimiter` (e.g. :: or /), which can be "+
+		"repeated.", func(v string) error {
+		env.delimiters = append(env.delimiters, v)
+
+		return nil
+	})
+	fs.Func("display-names", "Treat the names of the tests matching `regexp` as display names, instead of splitting "+
+		"them into groups (by default, names with a space and without a + are display names).", func(v string) error {
+		re, err := regexp.Compile(v)
+		env.displayNames = re
+
+		return err
+	})
+
+	fs.Func("filter", "Only keep the tests matching `expression`, which can be repeated (all of them must match): "+
+		"trait:<name>=<value>, result=<result>, name~<regexp> or assembly=<name>, where = takes a comma-separated "+
+		"list of values, ~ a regular expression, and != or !~ negate.", func(v string) error {
+		f, err := xunit.ParseFilter(v)
+		env.filters = append(env.filters, f)
+
+		return err
+	})
+
+	fs.Int64Var(&env.memoryBudget, "memory-budget", 0, "Stream the result files larger than `bytes` to a temporary "+
+		"file, instead of keeping them in memory, when the details of their tests are loaded on demand (by browse and "+
+		"serve), 0 for unlimited.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "%s\n\nUsage:\n\n  dtvisual %s [flags] <file>...\n\nFlags:\n\n", description, name)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// Parses args using fs.
+// Any error, except flag.ErrHelp, is returned as errBadFlags.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return errBadFlags
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// The XML data used by the tests of this package.
+const xmlData = "<assemblies computer=\"WIN11\">\n" +
+	"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.5\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\">\n" +
+	"        <failure>\n" +
+	"          <message>Expected: 1</message>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// Writes data to a new file in a temporary directory, and returns its path.
+func writeFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile() = %v, want <nil>", err)
+	}
+
+	return path
+}
+
+// Executes `dtvisual` with args, and returns its exit code, stdout and stderr.
+func execute(args ...string) (int, string, string) {
+	return executeWithStdin("", args...)
+}
+
+// Executes `dtvisual` with args, reading stdin from the given string, and returns its exit code, stdout and stderr.
+func executeWithStdin(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr strings.Builder
+
+	code := run(context.Background(), args, newEnv(strings.NewReader(stdin), &stdout, &stderr))
+
+	return code, stdout.String(), stderr.String()
+}
+
+// UT: Execute `dtvisual`.
+func TestRun(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+
+	for _, tc := range []struct {
+		args       []string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			args:       []string{},
+			wantCode:   exitUsage,
+			wantStderr: "Usage:",
+		},
+		{
+			args:       []string{"help"},
+			wantCode:   exitOK,
+			wantStderr: "Commands:",
+		},
+		{
+			args:       []string{"report", "-h"},
+			wantCode:   exitOK,
+			wantStderr: "dtvisual report [flags] <file>...",
+		},
+		{
+			args:       []string{"report", "--unknown", path},
+			wantCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -unknown",
+		},
+		{
+			args:       []string{"report"},
+			wantCode:   exitUsage,
+			wantStderr: "dtvisual report: no input files",
+		},
+		{
+			args:       []string{"report", "missing.xml"},
+			wantCode:   exitInput,
+			wantStderr: "dtvisual report: open missing.xml:",
+		},
+		{
+			args:       []string{path},
+			wantCode:   exitTestsFailed,
+			wantStderr: "dtvisual report: the test run contains 1 failed test(s)",
+		},
+		{
+			args:     []string{"report", "--fail-on", "none", path},
+			wantCode: exitOK,
+		},
+		{
+			args:       []string{"report", "--fail-on", "timeouts", path},
+			wantCode:   exitUsage,
+			wantStderr: "unknown condition \"timeouts\"",
+		},
+	} {
+		// ACT.
+		code, _, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stderr, tc.wantStderr, "", "\n\n"+
+			"UT Name:    Execute `dtvisual`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.wantStderr, stderr)
+	}
+}
+
+// UT: Execute `dtvisual` with different verbosity levels.
+func TestRun_Verbosity(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", strings.Replace(xmlData, "<collection>", "<collection><property />", 1))
+
+	for _, tc := range []struct {
+		flag         string
+		wantStderr   []string
+		unwantStderr []string
+	}{
+		{
+			wantStderr:   []string{"level=WARN msg=\"unknown element <property> in <collection> ignored\""},
+			unwantStderr: []string{"level=INFO", "level=DEBUG", "time="},
+		},
+		{
+			flag:         "--quiet",
+			unwantStderr: []string{"level=WARN", "level=INFO", "level=DEBUG"},
+		},
+		{
+			flag:         "--verbose",
+			wantStderr:   []string{"level=WARN", "level=INFO msg=\"Parsed the result file\" file=" + path},
+			unwantStderr: []string{"level=DEBUG"},
+		},
+		{
+			flag: "--debug",
+			wantStderr: []string{
+				"level=WARN", "level=INFO", "level=DEBUG msg=\"Detected the format of the result file\"",
+				"level=DEBUG msg=\"Skipped element\" file=" + path + " element=collection/property count=1",
+			},
+		},
+	} {
+		// ARRANGE.
+		args := []string{"report"}
+
+		if tc.flag != "" {
+			args = append(args, tc.flag)
+		}
+
+		args = append(args, "--fail-on", "none", path)
+
+		// ACT.
+		_, _, stderr := execute(args...)
+
+		// ASSERT.
+		for _, want := range tc.wantStderr {
+			assert.Contains(t, stderr, want, "", "\n\n"+
+				"UT Name:    Execute `dtvisual` with different verbosity levels.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", args, want, stderr)
+		}
+
+		for _, unwant := range tc.unwantStderr {
+			assert.Equal(t, strings.Contains(stderr, unwant), false, "", "\n\n"+
+				"UT Name:    Execute `dtvisual` with different verbosity levels.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr not containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", args, unwant, stderr)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+)
+
+// Returns a single mutation report containing the results of all the Stryker.NET JSON reports at paths.
+func loadMutations(env *env, paths []string) (mutation.Report, error) {
+	reports := make([]mutation.Report, 0, len(paths))
+
+	for _, path := range paths {
+		report, err := loadMutationFile(path)
+
+		if err != nil {
+			return mutation.Report{}, &inputError{err: fmt.Errorf("%s: %w", path, err)}
+		}
+
+		env.log.Info("Loaded the mutation report", "file", path, "namespaces", len(report.Namespaces),
+			"score", report.Counts().Score())
+
+		reports = append(reports, report)
+	}
+
+	return mutation.Merge(reports...), nil
+}
+
+// Returns the results of mutation testing in the Stryker.NET JSON report at path.
+func loadMutationFile(path string) (mutation.Report, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return mutation.Report{}, err
+	}
+
+	defer f.Close()
+
+	return mutation.Load(f)
+}
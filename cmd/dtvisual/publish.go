@@ -0,0 +1,421 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/azure"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/issues"
+	"github.com/kdeconinck/dtvisual/internal/pkg/otlp"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/html"
+	"github.com/kdeconinck/dtvisual/internal/pkg/storage"
+)
+
+// The subcommands of `dtvisual publish`.
+var publishCommands = []command{
+	{name: "azure", summary: "Publish the test results to the test runs of Azure DevOps.", run: runPublishAzure},
+	{name: "otlp", summary: "Export the test results as a trace to an OpenTelemetry collector.", run: runPublishOTLP},
+	{name: "issues", summary: "Open an issue (on GitHub or in Jira) for each failing test.", run: runPublishIssues},
+	{name: "upload", summary: "Upload the reports to object storage (e.g. S3).", run: runPublishUpload},
+}
+
+// Executes the "publish" command, which dispatches to one of its subcommands.
+func runPublish(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printPublishUsage(env.stderr)
+
+		if len(args) == 0 {
+			return &usageError{msg: "no subcommand"}
+		}
+
+		return nil
+	}
+
+	for _, c := range publishCommands {
+		if c.name == args[0] {
+			return c.run(ctx, env, args[1:])
+		}
+	}
+
+	return &usageError{msg: fmt.Sprintf("unknown subcommand %q", args[0])}
+}
+
+// Writes the usage of `dtvisual publish` to w.
+func printPublishUsage(w io.Writer) {
+	fmt.Fprint(w, "Publish the test results to another service.\n\n"+
+		"Usage:\n\n"+
+		"  dtvisual publish <subcommand> [flags] <file>...\n\n"+
+		"Subcommands:\n\n")
+
+	for _, c := range publishCommands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// Executes the "publish azure" command.
+func runPublishAzure(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "publish azure", "Publish the test results as a test run of Azure DevOps (with the HTML "+
+		"report attached to it).\n\n"+
+		"In Azure Pipelines, the run belongs to the build which is running, and is shown on its \"Tests\" tab.\n"+
+		"The token is read from $SYSTEM_ACCESSTOKEN, which must be mapped explicitly in the pipeline:\n\n"+
+		"  env:\n"+
+		"    SYSTEM_ACCESSTOKEN: $(System.AccessToken)")
+	collectionURL := fs.String("collection-url", os.Getenv("SYSTEM_COLLECTIONURI"), "The `URL` of the organization "+
+		"(e.g. https://dev.azure.com/contoso, defaults to $SYSTEM_COLLECTIONURI).")
+	project := fs.String("project", os.Getenv("SYSTEM_TEAMPROJECT"), "The `name` of the project (defaults to "+
+		"$SYSTEM_TEAMPROJECT).")
+	buildID := fs.Int("build-id", envInt("BUILD_BUILDID"), "The `ID` of the build the run belongs to (defaults to "+
+		"$BUILD_BUILDID).")
+	name := fs.String("name", "dtvisual", "The `title` of the run.")
+	noReport := fs.Bool("no-report", false, "Don't attach the HTML report to the run.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *collectionURL == "" || *project == "" {
+		return &usageError{msg: "the organization (--collection-url) and the project (--project) are required"}
+	}
+
+	token := os.Getenv("SYSTEM_ACCESSTOKEN")
+
+	if token == "" {
+		return &usageError{msg: "no token ($SYSTEM_ACCESSTOKEN)"}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	run := azure.Run{Name: *name, BuildID: *buildID, TestRun: testRun}
+
+	if !*noReport {
+		var report bytes.Buffer
+
+		if err := html.Render(&report, testRun, html.Options{Title: *name, Interactive: true}); err != nil {
+			return err
+		}
+
+		run.Report = report.Bytes()
+	}
+
+	id, err := azure.New(*collectionURL, *project, token, nil).Publish(ctx, run)
+
+	if err != nil {
+		return err
+	}
+
+	env.log.Info("Published the test run to Azure DevOps", "id", id, "tests", testRun.Stats().TotalCount)
+
+	return nil
+}
+
+// Returns the value of the environment variable key as an integer (0 if it isn't set, or isn't an integer).
+func envInt(key string) int {
+	value, _ := strconv.Atoi(os.Getenv(key))
+
+	return value
+}
+
+// Executes the "publish otlp" command.
+func runPublishOTLP(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "publish otlp", "Export the test results as a trace to an OpenTelemetry collector (with "+
+		"OTLP/HTTP), so the test executions show up in tracing backends (e.g. Jaeger or Tempo).\n\n"+
+		"The trace consists of a span for the run, for each assembly, for each group of tests and for each test.\n"+
+		"The headers sent to the collector (e.g. an API key) are read from $OTEL_EXPORTER_OTLP_HEADERS\n"+
+		"(\"key1=value1,key2=value2\"). If $TRACEPARENT is set (e.g. by the instrumentation of the CI system),\n"+
+		"the run is part of that trace, next to the telemetry of the build.")
+	endpoint := fs.String("endpoint", envOr("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"), "The `URL` of "+
+		"the collector (defaults to $OTEL_EXPORTER_OTLP_ENDPOINT).")
+	serviceName := fs.String("service-name", envOr("OTEL_SERVICE_NAME", "dtvisual"), "The `name` of the service "+
+		"the spans belong to (defaults to $OTEL_SERVICE_NAME).")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	headers, err := parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+
+	if err != nil {
+		return &usageError{msg: fmt.Sprintf("invalid $OTEL_EXPORTER_OTLP_HEADERS: %v", err)}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Without a timestamp in the result file (or its name), the run is assumed to have ended just now.
+	start, _ := history.Timestamp(testRun, filepath.Base(fs.Arg(0)), time.Now().Add(-testRun.Stats().TotalDuration))
+	opts := otlp.Options{ServiceName: *serviceName, Start: start, Parent: os.Getenv("TRACEPARENT")}
+
+	if err := otlp.NewExporter(*endpoint, headers, nil).Export(ctx, testRun, opts); err != nil {
+		return err
+	}
+
+	env.log.Info("Exported the test run to the OpenTelemetry collector", "endpoint", *endpoint)
+
+	return nil
+}
+
+// Executes the "publish issues" command.
+func runPublishIssues(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "publish issues", "Open an issue (on GitHub or in Jira) for each failing test which doesn't "+
+		"have an open issue yet, and close the issues of the tests which pass again.\n\n"+
+		"The issues are labeled \"dtvisual\", and tied to their test by a stable ID (so a test never has more than\n"+
+		"one open issue). The issues of tests which are skipped, or which no longer exist, are left open.\n\n"+
+		"The token is read from $GITHUB_TOKEN (on GitHub), or from $JIRA_USER and $JIRA_TOKEN (in Jira).")
+	tracker := fs.String("tracker", "github", "The issue `tracker` (github or jira).")
+	repo := fs.String("repo", os.Getenv("GITHUB_REPOSITORY"), "The `repository` on GitHub (e.g. owner/name, "+
+		"defaults to $GITHUB_REPOSITORY).")
+	githubURL := fs.String("github-url", envOr("GITHUB_API_URL", "https://api.github.com"), "The `URL` of the API "+
+		"of GitHub (defaults to $GITHUB_API_URL).")
+	jiraURL := fs.String("jira-url", "", "The `URL` of the Jira site (e.g. https://contoso.atlassian.net).")
+	jiraProject := fs.String("jira-project", "", "The `key` of the project in Jira (e.g. APP).")
+	jiraIssueType := fs.String("jira-issue-type", "Bug", "The `type` of the issues opened in Jira.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var t issues.Tracker
+
+	switch *tracker {
+	case "github":
+		if *repo == "" || os.Getenv("GITHUB_TOKEN") == "" {
+			return &usageError{msg: "the repository (--repo) and the token ($GITHUB_TOKEN) are required"}
+		}
+
+		t = issues.NewGitHub(*githubURL, *repo, os.Getenv("GITHUB_TOKEN"), nil)
+	case "jira":
+		if *jiraURL == "" || *jiraProject == "" || os.Getenv("JIRA_USER") == "" || os.Getenv("JIRA_TOKEN") == "" {
+			return &usageError{msg: "the site (--jira-url), the project (--jira-project), the user ($JIRA_USER) and " +
+				"the token ($JIRA_TOKEN) are required"}
+		}
+
+		t = issues.NewJira(*jiraURL, *jiraProject, *jiraIssueType, os.Getenv("JIRA_USER"), os.Getenv("JIRA_TOKEN"), nil)
+	default:
+		return &usageError{msg: fmt.Sprintf("unknown tracker %q", *tracker)}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	result, err := issues.Sync(ctx, t, testRun)
+
+	// NOTE: Report the issues which have been opened (or closed) before the error, so they can be found.
+	for _, issue := range result.Opened {
+		fmt.Fprintf(env.stdout, "Opened issue %s: %s\n", issue.ID, fullName(issue.Assembly, nil, issue.Test.Name))
+	}
+
+	for _, issue := range result.Closed {
+		fmt.Fprintf(env.stdout, "Closed issue %s (the test passes again)\n", issue.ID)
+	}
+
+	return err
+}
+
+// Executes the "publish upload" command.
+func runPublishUpload(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "publish upload", "Upload the HTML report and the results (as JSON) to object storage, "+
+		"under <prefix>/<build>/, and print their URLs (so CI jobs can link to the reports).\n\n"+
+		"The credentials are read from $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and $AWS_SESSION_TOKEN (on S3,\n"+
+		"or the HMAC key on Google Cloud Storage), or from $AZURE_STORAGE_ACCOUNT and $AZURE_STORAGE_KEY (on\n"+
+		"Azure Blob Storage). Unless --expiry is 0, the printed URLs are presigned, so the bucket can stay private.")
+	provider := fs.String("provider", "s3", "The object storage `service` (s3, gcs or azure).")
+	bucket := fs.String("bucket", "", "The `name` of the bucket (or of the container, on Azure Blob Storage).")
+	region := fs.String("region", envOr("AWS_REGION", "us-east-1"), "The `region` of the bucket on S3 (defaults to "+
+		"$AWS_REGION).")
+	endpoint := fs.String("endpoint", "", "The `URL` of the service, for S3-compatible services (e.g. MinIO).")
+	prefix := fs.String("prefix", "dtvisual", "The `prefix` of the keys of the uploaded objects.")
+	build := fs.String("build", ciBuildID(), "The `ID` of the build (defaults to the ID of the CI build, or to the "+
+		"current time).")
+	expiry := fs.Duration("expiry", 24*time.Hour, "The `duration` the presigned URLs are valid (at most 168h, or 0 "+
+		"to print the public URLs).")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *bucket == "" {
+		return &usageError{msg: "the bucket (--bucket) is required"}
+	}
+
+	if *expiry < 0 || *expiry > storage.MaxExpiry {
+		return &usageError{msg: fmt.Sprintf("invalid expiry %v (at most %v)", *expiry, storage.MaxExpiry)}
+	}
+
+	var uploader storage.Uploader
+
+	switch *provider {
+	case "s3", "gcs":
+		cfg := storage.S3Config{
+			Endpoint:        *endpoint,
+			Region:          *region,
+			Bucket:          *bucket,
+			PathStyle:       *endpoint != "",
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+
+		// NOTE: Google Cloud Storage is used through its S3-compatible (XML) API, with an HMAC key.
+		if *provider == "gcs" {
+			cfg.Region, cfg.PathStyle = "auto", true
+
+			if cfg.Endpoint == "" {
+				cfg.Endpoint = "https://storage.googleapis.com"
+			}
+		}
+
+		if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return &usageError{msg: "the credentials ($AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY) are required"}
+		}
+
+		uploader = storage.NewS3(cfg, nil)
+	case "azure":
+		cfg := storage.AzureConfig{
+			Endpoint:  *endpoint,
+			Account:   os.Getenv("AZURE_STORAGE_ACCOUNT"),
+			Key:       os.Getenv("AZURE_STORAGE_KEY"),
+			Container: *bucket,
+		}
+
+		if cfg.Account == "" || cfg.Key == "" {
+			return &usageError{msg: "the credentials ($AZURE_STORAGE_ACCOUNT and $AZURE_STORAGE_KEY) are required"}
+		}
+
+		uploader = storage.NewAzure(cfg, nil)
+	default:
+		return &usageError{msg: fmt.Sprintf("unknown provider %q", *provider)}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	if *build == "" {
+		*build = time.Now().UTC().Format("20060102T150405Z")
+	}
+
+	var report, results bytes.Buffer
+
+	if err := html.Render(&report, testRun, html.Options{Interactive: true}); err != nil {
+		return err
+	}
+
+	if err := api.Encode(&results, []api.Run{{ID: *build, Name: filepath.Base(fs.Arg(0)), TestRun: testRun}}); err != nil {
+		return err
+	}
+
+	for _, object := range []struct {
+		name, contentType string
+		data              []byte
+	}{
+		{name: "report.html", contentType: "text/html; charset=utf-8", data: report.Bytes()},
+		{name: "results.json", contentType: "application/json", data: results.Bytes()},
+	} {
+		key := strings.Trim(*prefix, "/") + "/" + *build + "/" + object.name
+		objectURL, err := uploader.Upload(ctx, strings.TrimPrefix(key, "/"), object.contentType, object.data, *expiry)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(env.stdout, objectURL)
+	}
+
+	return nil
+}
+
+// Returns the ID of the CI build which is running (on GitHub Actions, Azure Pipelines, GitLab CI or Jenkins), or an
+// empty string outside of CI.
+func ciBuildID() string {
+	for _, key := range []string{"GITHUB_RUN_ID", "BUILD_BUILDID", "CI_PIPELINE_ID", "BUILD_NUMBER"} {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Returns the value of the environment variable key, or fallback if it isn't set.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// Returns the headers in value, in the format of $OTEL_EXPORTER_OTLP_HEADERS (e.g. "key1=value1,key2=value2", with
+// URL-encoded values).
+func parseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(pair, "=")
+
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%q isn't a key=value pair", pair)
+		}
+
+		val, err := url.QueryUnescape(strings.TrimSpace(val))
+
+		if err != nil {
+			return nil, err
+		}
+
+		headers[strings.TrimSpace(key)] = val
+	}
+
+	return headers, nil
+}
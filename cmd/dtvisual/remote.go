@@ -0,0 +1,184 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/client"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The environment variable holding the API token sent to a DTVisual server (see the --server flag of the "report"
+// command).
+const tokenEnv = "DTVISUAL_TOKEN"
+
+// Returns the runs with the given IDs (or all the runs if ids is empty) of the DTVisual server at serverURL, merged
+// into a single test run. The API token in $DTVISUAL_TOKEN (if any) is sent with each request.
+func loadRemote(ctx context.Context, env *env, serverURL string, ids []string) (xunit.TestRun, error) {
+	c := client.New(serverURL, nil)
+
+	if token := os.Getenv(tokenEnv); token != "" {
+		c = c.WithToken(token)
+	}
+
+	env.log.Debug("Loading the test results", "server", serverURL, "runs", ids)
+
+	var runs []client.Run
+
+	if len(ids) == 0 {
+		all, err := c.Runs(ctx)
+
+		if err != nil {
+			return xunit.TestRun{}, &inputError{err: err}
+		}
+
+		runs = all
+	}
+
+	for _, id := range ids {
+		run, err := c.Run(ctx, id)
+
+		if err != nil {
+			return xunit.TestRun{}, &inputError{err: err}
+		}
+
+		runs = append(runs, run)
+	}
+
+	testRuns := make([]xunit.TestRun, 0, len(runs))
+
+	for _, run := range runs {
+		start := time.Now()
+		testRun, err := loadRemoteRun(ctx, c, run)
+
+		if err != nil {
+			return xunit.TestRun{}, &inputError{err: err}
+		}
+
+		env.log.Info("Loaded the run", "server", serverURL, "run", run.ID, "duration", time.Since(start))
+		testRuns = append(testRuns, testRun)
+	}
+
+	return xunit.Merge(testRuns...), nil
+}
+
+// Returns run (of the server behind c) as a test run, whose tests are grouped by their path.
+// NOTE: The API doesn't expose the traits, the output and the collections of the tests, so they're missing.
+func loadRemoteRun(ctx context.Context, c *client.Client, run client.Run) (xunit.TestRun, error) {
+	assemblies, err := c.Assemblies(ctx, run.ID)
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	tests, err := c.Tests(ctx, run.ID, client.TestFilter{})
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	testRun := xunit.TestRun{Computer: run.Computer, User: run.User, Timestamp: run.Timestamp}
+
+	for _, a := range assemblies {
+		assembly := xunit.Assembly{
+			Name:         a.Name,
+			ErrorCount:   a.Errors,
+			PassedCount:  a.Passed,
+			FailedCount:  a.Failed,
+			SkippedCount: a.Skipped,
+			NotRunCount:  a.NotRun,
+			TotalCount:   a.Total,
+			Duration:     time.Duration(a.Duration),
+		}
+
+		index := 0
+
+		for _, test := range tests {
+			if test.Assembly == a.Name {
+				index++
+				addRemoteTest(&assembly, index, test)
+			}
+		}
+
+		testRun.Assemblies = append(testRun.Assemblies, assembly)
+	}
+
+	return testRun, nil
+}
+
+// Adds test (the index-th test of assembly) to the groups of assembly named after its path (the tests without a path
+// belong to an unnamed group).
+func addRemoteTest(assembly *xunit.Assembly, index int, test client.Test) {
+	tc := xunit.TestCase{
+		ID:         test.ID,
+		Name:       test.Name,
+		RawName:    test.Name,
+		Result:     test.Result,
+		Duration:   time.Duration(test.Duration),
+		SourceFile: test.SourceFile,
+		SourceLine: test.SourceLine,
+		Reason:     test.Reason,
+		Index:      index,
+	}
+
+	if test.Failure != nil {
+		tc.Failure = xunit.Failure{
+			ExceptionType: test.Failure.ExceptionType,
+			Message:       test.Failure.Message,
+			StackTrace:    test.Failure.StackTrace,
+		}
+	}
+
+	groups, group := &assembly.Tests, (*xunit.TestGroup)(nil)
+
+	if len(test.Path) == 0 {
+		group = findGroup(groups, "")
+	}
+
+	for _, name := range test.Path {
+		group = findGroup(groups, name)
+		groups = &group.Groups
+	}
+
+	group.Tests = append(group.Tests, tc)
+}
+
+// Returns the group named name in groups, which is appended to groups if it doesn't exist yet.
+func findGroup(groups *[]*xunit.TestGroup, name string) *xunit.TestGroup {
+	for _, group := range *groups {
+		if group.Name == name {
+			return group
+		}
+	}
+
+	group := &xunit.TestGroup{Name: name}
+	*groups = append(*groups, group)
+
+	return group
+}
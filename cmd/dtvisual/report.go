@@ -0,0 +1,466 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/custom"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/html"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/pipelines"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/plain"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/term"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Executes the "report" command.
+func runReport(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "report", "Render the test results (as a tree in the terminal, or as an HTML page).")
+	format := fs.String("format", "term", "The output `format` (term, html, or text for plain text without colors).")
+	width := fs.Int("width", plain.DefaultWidth, "The maximum number of `characters` of a line (format text only).")
+	noColor := fs.Bool("no-color", false, "Disable colored output (format term only).")
+	output := fs.String("output", "", "Write the report to `file` instead of stdout.")
+	title := fs.String("title", "", "The title of the report (format html or text).")
+	theme := fs.String("theme", "auto", "The color `theme` of the report: auto (following the preference of the "+
+		"browser), light or dark (format html only).")
+	templatePath := fs.String("template", "", "Render the report with the Go template in `file` instead of --format "+
+		"(as an html/template if its name ends with .html, .htm or .gohtml, optionally followed by .tmpl, or as a "+
+		"text/template otherwise).")
+	themeCSS := fs.String("theme-css", "", "Add the stylesheet in `file` to the report, e.g. to override the CSS "+
+		"variables of the theme (--pass, --fail, --text, --background, --link, ...) for a corporate branding (format "+
+		"html only).")
+	historyDir := fs.String("history", "", "Compare the test results with the history store in `directory`: triage "+
+		"the failures against the baseline run and report the tests slower than usual (format term or text), and "+
+		"compare the coverage with the coverage of the baseline run (with --coverage).")
+	slowdownFactor := fs.Float64("slowdown-factor", 2, "Report the tests taking this `factor` longer than their "+
+		"median duration (with --history).")
+	minSlowdown := fs.Duration("min-slowdown", 100*time.Millisecond, "Ignore duration increases shorter than this "+
+		"`duration` (with --history).")
+	coverageFiles := make([]string, 0)
+
+	fs.Func("coverage", "Show the code coverage in `file` (in the Cobertura or OpenCover XML format, the JSON format "+
+		"of coverlet, or the LCOV format) in the report, which can be repeated to merge the coverage of sharded runs "+
+		"(format html only).", func(v string) error {
+		coverageFiles = append(coverageFiles, v)
+
+		return nil
+	})
+
+	benchmarkFiles, baselineFiles := make([]string, 0), make([]string, 0)
+
+	fs.Func("benchmarks", "Show the benchmarks in the BenchmarkDotNet export `file` (in the JSON or CSV format) in "+
+		"the report, which can be repeated (format html only).", func(v string) error {
+		benchmarkFiles = append(benchmarkFiles, v)
+
+		return nil
+	})
+	fs.Func("benchmarks-baseline", "Compare the benchmarks with the ones in the BenchmarkDotNet export `file` (e.g. "+
+		"of the main branch), which can be repeated (with --benchmarks).", func(v string) error {
+		baselineFiles = append(baselineFiles, v)
+
+		return nil
+	})
+
+	mutationFiles := make([]string, 0)
+
+	fs.Func("mutations", "Show the mutation score of each namespace in the Stryker.NET JSON report `file` in the "+
+		"report, which can be repeated to merge the reports of multiple projects (format html only).",
+		func(v string) error {
+			mutationFiles = append(mutationFiles, v)
+
+			return nil
+		})
+
+	tracesDir := fs.String("traces", "", "Link the tests to their trace file in `directory` (named after the test, "+
+		"ending with .speedscope.json, or listed in a traces.json manifest) in the report (format html only).")
+	tracesURL := fs.String("traces-url", "", "Open the trace files in speedscope, from the `URL` the traces "+
+		"directory is served from (with --traces).")
+	azurePipelines := fs.Bool("azure-pipelines", false, "Report each failed test as an error of the Azure Pipelines "+
+		"run, and publish the HTML report as an attachment of the run (on stdout, which requires --output with format "+
+		"html).")
+	attachmentName := fs.String("attachment-name", "Test report", "The `name` of the attachment holding the HTML "+
+		"report (with --azure-pipelines).")
+	topSlow := fs.Int("top-slow", 0, "Append the `N` slowest tests (across all the result files) to the report, with "+
+		"their duration and groups (format term or text).")
+	slowOnly := fs.Bool("slow-only", false, "Only write the slowest tests, instead of the report (with --top-slow).")
+	server := fs.String("server", "", "Load the test results from the DTVisual server at `URL` (sending the API "+
+		"token in $"+tokenEnv+", if any) instead of result files, in which case the arguments are the IDs of the runs "+
+		"to report (all the runs of the server if there are none).")
+	gates := addGateFlags(fs)
+	gates.addCoverageFlags(fs)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *format != "term" && *format != "html" && *format != "text" {
+		return &usageError{msg: fmt.Sprintf("unknown format %q", *format)}
+	}
+
+	if !slices.Contains(html.Themes, *theme) {
+		return &usageError{msg: fmt.Sprintf("unknown theme %q", *theme)}
+	}
+
+	if *azurePipelines && *format == "html" && *output == "" {
+		return &usageError{msg: "--azure-pipelines requires --output with format html"}
+	}
+
+	switch {
+	case *topSlow < 0:
+		return &usageError{msg: "--top-slow must not be negative"}
+	case *topSlow > 0 && (*format == "html" || *templatePath != ""):
+		return &usageError{msg: "--top-slow requires format term or text"}
+	case *slowOnly && *topSlow == 0:
+		return &usageError{msg: "--slow-only requires --top-slow"}
+	}
+
+	var testRun xunit.TestRun
+	var err error
+
+	if *server != "" {
+		testRun, err = loadRemote(ctx, env, *server, fs.Args())
+	} else {
+		testRun, err = loadFiles(ctx, env, fs.Args())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if gates.hasCoverageGates() && len(coverageFiles) == 0 {
+		return &usageError{msg: "--min-line-coverage and --min-branch-coverage require --coverage"}
+	}
+
+	var cov coverage.Report
+
+	if len(coverageFiles) > 0 {
+		if cov, err = loadCoverage(env, coverageFiles); err != nil {
+			return err
+		}
+	}
+
+	benchmarks, err := loadBenchmarks(env, benchmarkFiles, baselineFiles)
+
+	if err != nil {
+		return err
+	}
+
+	var mutations mutation.Report
+
+	if len(mutationFiles) > 0 {
+		if mutations, err = loadMutations(env, mutationFiles); err != nil {
+			return err
+		}
+	}
+
+	var tmpl *custom.Template
+
+	if *templatePath != "" {
+		if tmpl, err = custom.ParseFile(*templatePath); err != nil {
+			return &inputError{err: err}
+		}
+	}
+
+	var customCSS []byte
+
+	if *themeCSS != "" {
+		if customCSS, err = os.ReadFile(*themeCSS); err != nil {
+			return &inputError{err: err}
+		}
+	}
+
+	var traceURL func(name string) string
+
+	if *tracesDir != "" {
+		if traceURL, err = traceURLs(env, *tracesDir, *tracesURL, *output); err != nil {
+			return err
+		}
+	}
+
+	var trends historyAnalysis
+
+	if *historyDir != "" {
+		threshold := analysis.Threshold{Factor: *slowdownFactor, Minimum: *minSlowdown}
+
+		if trends, err = analyzeHistory(env, *historyDir, testRun, cov, threshold); err != nil {
+			return err
+		}
+	}
+
+	if err := withOutput(env, *output, func(w io.Writer) error {
+		if tmpl != nil {
+			data := custom.Data{
+				Title:      *title,
+				Run:        testRun,
+				Stats:      testRun.Stats(),
+				Coverage:   cov,
+				Benchmarks: benchmarks,
+				Mutations:  mutations,
+			}
+
+			return tmpl.Execute(w, data)
+		}
+
+		if *format == "html" {
+			opts := html.Options{
+				Title:        *title,
+				Coverage:     cov,
+				Benchmarks:   benchmarks,
+				Mutations:    mutations,
+				TraceURL:     traceURL,
+				CoverageDiff: trends.coverage,
+				Theme:        *theme,
+				ThemeCSS:     string(customCSS),
+			}
+
+			return html.Render(w, testRun, opts)
+		}
+
+		slowest := analysis.TopSlowest(testRun, *topSlow)
+
+		if *slowOnly {
+			return writeSlowest(w, slowest, false)
+		}
+
+		if *format == "text" {
+			if err := plain.Render(w, testRun, plain.Options{Title: *title, Width: *width}); err != nil {
+				return err
+			}
+		} else {
+			f, isFile := w.(*os.File)
+
+			opts := term.Options{Color: !*noColor && isFile && term.ColorEnabled(f)}
+
+			if err := term.Render(w, testRun, opts); err != nil {
+				return err
+			}
+		}
+
+		if err := writeHistoryAnalysis(w, trends); err != nil {
+			return err
+		}
+
+		return writeSlowest(w, slowest, true)
+	}); err != nil {
+		return err
+	}
+
+	if *azurePipelines {
+		if err := writePipelinesCommands(env, testRun, *format, *output, *attachmentName); err != nil {
+			return err
+		}
+	}
+
+	return gates.checkCoverage(testRun.Stats(), cov)
+}
+
+// Writes the logging commands reporting the failures of testRun to Azure Pipelines, followed by the command publishing
+// the HTML report at output as an attachment (with format html).
+func writePipelinesCommands(env *env, testRun xunit.TestRun, format, output, attachmentName string) error {
+	if err := pipelines.LogIssues(env.stdout, testRun); err != nil {
+		return err
+	}
+
+	if format != "html" {
+		return nil
+	}
+
+	path, err := filepath.Abs(output)
+
+	if err != nil {
+		return err
+	}
+
+	return pipelines.AddReportAttachment(env.stdout, attachmentName, path)
+}
+
+// The analysis of a test run against the history store (see the `--history` flag of the "report" command).
+type historyAnalysis struct {
+	failures []analysis.TriagedFailure     // The failed tests, triaged against the baseline run.
+	slower   []analysis.DurationRegression // The tests which are slower than their median duration.
+	coverage *analysis.CoverageDiff        // The change of the coverage, compared to the baseline run (if known).
+}
+
+// Returns the analysis of testRun (with the coverage in cov) against the history store in dir.
+// When the store doesn't contain a baseline run, a warning is logged, and the failures aren't triaged. The coverage is
+// only compared when the baseline run contains coverage.
+func analyzeHistory(env *env, dir string, testRun xunit.TestRun, cov coverage.Report,
+	limit analysis.Threshold,
+) (historyAnalysis, error) {
+	store, err := history.Open(dir)
+
+	if err != nil {
+		return historyAnalysis{}, err
+	}
+
+	runs, err := store.Runs()
+
+	if err != nil {
+		return historyAnalysis{}, err
+	}
+
+	result := historyAnalysis{slower: analysis.DurationRegressions(testRun, runs, limit)}
+	baseline, err := store.Baseline()
+
+	if err != nil {
+		if !errors.Is(err, history.ErrNoBaseline) {
+			return historyAnalysis{}, err
+		}
+
+		env.log.Warn("The failures aren't triaged, since there's no baseline run", "history", dir)
+	} else {
+		env.log.Info("Triaging the failures", "baseline", baseline.ID)
+		result.failures = analysis.Triage(testRun, baseline)
+
+		switch {
+		case len(cov.Assemblies) == 0:
+		case len(baseline.Coverage) == 0:
+			env.log.Warn("The coverage isn't compared, since the baseline run doesn't contain coverage",
+				"baseline", baseline.ID)
+		default:
+			diff := analysis.DiffCoverage(cov, baseline)
+			result.coverage = &diff
+		}
+	}
+
+	return result, nil
+}
+
+// Writes a to w: the failures (split in new regressions and pre-existing failures), and the slower tests.
+func writeHistoryAnalysis(w io.Writer, a historyAnalysis) error {
+	var sb strings.Builder
+
+	for _, section := range []struct {
+		title      string
+		regression bool
+	}{
+		{title: "New regressions", regression: true},
+		{title: "Pre-existing failures", regression: false},
+	} {
+		names := make([]string, 0)
+
+		for _, failure := range a.failures {
+			if failure.Regression == section.regression {
+				names = append(names, fullName(failure.Assembly, failure.Path, failure.Test.Name))
+			}
+		}
+
+		if len(names) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "\n%s (%d):\n", section.title, len(names))
+
+		for _, name := range names {
+			fmt.Fprintf(&sb, "  ✘ %s\n", name)
+		}
+	}
+
+	if len(a.slower) > 0 {
+		fmt.Fprintf(&sb, "\nSlower than usual (%d):\n", len(a.slower))
+
+		for _, slow := range a.slower {
+			// NOTE: The durations are rounded, since they are stored with a limited precision.
+			fmt.Fprintf(&sb, "  ~ %s (%v, median %v, %.1fx)\n", fullName(slow.Assembly, slow.Path, slow.Test.Name),
+				slow.Test.Duration.Round(time.Microsecond), slow.Median.Round(time.Microsecond), slow.Factor)
+		}
+	}
+
+	if a.coverage != nil {
+		fmt.Fprintf(&sb, "\nCoverage compared to the baseline: lines %s, branches %s\n",
+			fmtCoverageChange(a.coverage.Line), fmtCoverageChange(a.coverage.Branch))
+
+		dropped := slices.DeleteFunc(slices.Clone(a.coverage.Files), func(f analysis.FileCoverageDiff) bool {
+			return !f.Dropped()
+		})
+
+		if len(dropped) > 0 {
+			fmt.Fprintf(&sb, "\nLess coverage than the baseline (%d):\n", len(dropped))
+
+			for _, f := range dropped {
+				fmt.Fprintf(&sb, "  ▼ %s (%s): lines %s, branches %s\n", f.Path, f.Assembly, fmtCoverageChange(f.Line),
+					fmtCoverageChange(f.Branch))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// Writes the slowest tests to w, with their duration (aligned to the right) and their groups. When the tests are
+// appended to a report, they're preceded by a blank line, and nothing is written if there are none.
+func writeSlowest(w io.Writer, tests []analysis.SlowTest, appended bool) error {
+	if appended && len(tests) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+
+	if appended {
+		sb.WriteString("\n")
+	}
+
+	durations, width := make([]string, 0, len(tests)), 0
+
+	for _, slow := range tests {
+		// NOTE: The durations are rounded, since they are stored with a limited precision.
+		durations = append(durations, slow.Test.Duration.Round(time.Microsecond).String())
+		width = max(width, len(durations[len(durations)-1]))
+	}
+
+	fmt.Fprintf(&sb, "Slowest tests (%d):\n", len(tests))
+
+	for idx, slow := range tests {
+		fmt.Fprintf(&sb, "  %*s  %s\n", width, durations[idx], fullName(slow.Assembly, slow.Path, slow.Test.Name))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// Returns c as the coverage rate, followed by its change in percentage points (e.g. "77.50% (-2.50)").
+func fmtCoverageChange(c analysis.CoverageChange) string {
+	if c.After.Total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.2f%% (%+.2f)", c.After.Rate(), c.Delta())
+}
@@ -0,0 +1,405 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/auth"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Execute `dtvisual report`.
+func TestRunReport(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+	coveragePath := writeFile(t, "coverage.xml", "<coverage><packages><package name=\"App\"><classes>"+
+		"<class filename=\"A.cs\"><lines><line number=\"1\" hits=\"1\" /></lines></class>"+
+		"</classes></package></packages></coverage>")
+	shardPath := writeFile(t, "coverage.json", `{"App.dll": {"A.cs": {"A": {"M": {"Lines": {"2": 0}}}}}}`)
+	openCoverPath := writeFile(t, "coverage.opencover.xml", "<CoverageSession><Modules><Module>"+
+		"<ModuleName>Lib</ModuleName><Files><File uid=\"1\" fullPath=\"L.cs\" /></Files></Module></Modules>"+
+		"</CoverageSession>")
+	lcovPath := writeFile(t, "frontend.info", "SF:src/index.ts\nDA:1,1\nend_of_record\n")
+	mutationPath := writeFile(t, "mutation-report.json", `{"files": {"A.cs": {"source": "namespace App;", `+
+		`"mutants": [{"status": "Killed"}, {"status": "Survived"}]}}}`)
+	tracesDir := filepath.Dir(writeFile(t, "traces.json", `{"A passing test.": "passing.speedscope.json"}`))
+	escapedDir := filepath.Dir(writeFile(t, "traces.json", `{"A passing test.": "a passing #1.speedscope.json"}`))
+	benchmarkPath := writeFile(t, "benchmarks.csv", "Method,Mean,Allocated\nParse,300 ns,-\n")
+	templatePath := writeFile(t, "report.tmpl", "{{.Title}}: {{.Stats.PassedCount}} of {{.Stats.TotalCount}} passed\n"+
+		"{{range failed .Run}}- {{.Test.Name}}: {{.Test.Failure.Message}}\n{{end}}")
+	themePath := writeFile(t, "theme.css", ":root { --link: #e20074; }")
+	baselinePath := writeFile(t, "baseline.json", `{"Benchmarks": [{"Method": "Parse", "Statistics": {"Mean": 200}}]}`)
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"report", path},
+			wantCode: exitTestsFailed,
+			want: "App.dll (1 passed, 1 failed) 1.5s\n" +
+				"├── ✔ A passing test. 500ms\n" +
+				"└── ✘ A failing test. 1s\n",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--title", "Nightly", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<title>Nightly</title>",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--coverage", coveragePath, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<td>App</td>",
+		},
+		{
+			args: []string{"report", "--format", "html", "--coverage", coveragePath, "--coverage", shardPath, "--fail-on",
+				"none", path},
+			wantCode: exitOK,
+			want:     "50.00% <span class=\"counts\">(1 / 2)</span>",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--coverage", openCoverPath, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<td>Lib</td>",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--coverage", lcovPath, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<td>frontend</td>",
+		},
+		{
+			args: []string{"report", "--format", "html", "--benchmarks", benchmarkPath, "--benchmarks-baseline",
+				baselinePath, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<td class=\"num\">1.50x</td>",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--traces", tracesDir, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<a class=\"trace\" href=\"" + filepath.ToSlash(filepath.Join(tracesDir, "passing.speedscope.json")),
+		},
+		{
+			args: []string{"report", "--format", "html", "--traces", tracesDir, "--traces-url", "https://ci.example/traces/",
+				"--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "#profileURL=https%3A%2F%2Fci.example%2Ftraces%2Fpassing.speedscope.json",
+		},
+		{
+			args: []string{"report", "--format", "html", "--traces", escapedDir, "--traces-url", "https://ci.example/",
+				"--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "#profileURL=https%3A%2F%2Fci.example%2Fa%2520passing%2520%25231.speedscope.json",
+		},
+		{
+			args:     []string{"report", "--traces", filepath.Join(tracesDir, "traces.json"), path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--format", "html", "--mutations", mutationPath, "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "<td>App</td>",
+		},
+		{
+			args:     []string{"report", "--mutations", path, path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--benchmarks", path, path},
+			wantCode: exitInput,
+		},
+		{
+			args: []string{"report", "--coverage", coveragePath, "--min-line-coverage", "100", "--fail-on", "none",
+				path},
+			wantCode: exitOK,
+		},
+		{
+			args: []string{"report", "--coverage", coveragePath, "--coverage", shardPath, "--min-line-coverage", "75",
+				"--coverage-per-assembly", "--fail-on", "none", path},
+			wantCode: exitTestsFailed,
+		},
+		{
+			args:     []string{"report", "--min-branch-coverage", "80", path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"report", "--coverage", path, path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--coverage", coveragePath + ".missing", path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--azure-pipelines", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "##vso[task.logissue type=error;]App.dll: A failing test.: Expected: 1\n",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--azure-pipelines", path},
+			wantCode: exitUsage,
+		},
+		{
+			args: []string{"report", "--format", "html", "--theme", "dark", "--theme-css", themePath, "--fail-on", "none",
+				path},
+			wantCode: exitOK,
+			want:     "<style>:root { --link: #e20074; }</style>",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--theme", "sepia", path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"report", "--format", "html", "--theme-css", themePath + ".missing", path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--template", templatePath, "--title", "Nightly", path},
+			wantCode: exitTestsFailed,
+			want:     "Nightly: 1 of 2 passed\n- A failing test.: Expected: 1\n",
+		},
+		{
+			args:     []string{"report", "--template", writeFile(t, "invalid.tmpl", "{{.Missing"), path},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--format", "text", "--width", "40", "--title", "Nightly", path},
+			wantCode: exitTestsFailed,
+			want: "Nightly\n=======\n\n" +
+				"2 tests in 1 assemblies: 1 passed, 1\n" +
+				"failed, 0 skipped, 0 not run (50.00%\n" +
+				"pass rate) in 1.5s.\n\n" +
+				"App.dll (1 passed, 1 failed)        1.5s\n" +
+				"  PASS  A passing test.            500ms\n" +
+				"  FAIL  A failing test.               1s\n\n" +
+				"Failures (1)\n------------\n\n" +
+				"1) App.dll > A failing test.\n" +
+				"  Expected: 1\n",
+		},
+		{
+			args:     []string{"report", "--top-slow", "1", path},
+			wantCode: exitTestsFailed,
+			want:     "└── ✘ A failing test. 1s\n\nSlowest tests (1):\n  1s  App.dll › A failing test.\n",
+		},
+		{
+			args:     []string{"report", "--top-slow", "5", "--slow-only", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "Slowest tests (2):\n     1s  App.dll › A failing test.\n  500ms  App.dll › A passing test.\n",
+		},
+		{
+			args:     []string{"report", "--format", "html", "--top-slow", "3", path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"report", "--slow-only", path},
+			wantCode: exitUsage,
+		},
+		{
+			args:     []string{"report", "--format", "pdf", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual report`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual report`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
+
+// UT: Execute `dtvisual report` with an output file.
+func TestRunReportWithOutput(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	output := filepath.Join(t.TempDir(), "report.html")
+
+	// ACT.
+	code, stdout, _ := execute("report", "--format", "html", "--output", output, "--fail-on", "none", path)
+
+	// ASSERT.
+	got, err := os.ReadFile(output)
+
+	assert.Equal(t, code, exitOK, "Exit code")
+	assert.NoError(t, err, "ReadFile()")
+	assert.Equal(t, stdout, "", "Stdout")
+	assert.Equal(t, strings.HasPrefix(string(got), "<!DOCTYPE html>"), true, "", "\n\n"+
+		"UT Name:    Execute `dtvisual report` with an output file.\n"+
+		"\033[32mExpected:   An HTML page\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", got)
+}
+
+// UT: Execute `dtvisual report`, publishing the HTML report as an attachment of an Azure Pipelines run.
+func TestRunReportAzurePipelines(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	output := filepath.Join(t.TempDir(), "report.html")
+	want := "##vso[task.logissue type=error;]App.dll: A failing test.: Expected: 1\n" +
+		"##vso[task.addattachment type=dtvisual.report;name=Nightly;]" + output + "\n"
+
+	// ACT.
+	code, stdout, _ := execute("report", "--format", "html", "--output", output, "--azure-pipelines",
+		"--attachment-name", "Nightly", "--fail-on", "none", path)
+
+	// ASSERT.
+	assert.Equal(t, code, exitOK, "Exit code")
+	assert.Equal(t, stdout, want, "", "\n\n"+
+		"UT Name:    Execute `dtvisual report`, publishing the HTML report as an attachment of an Azure Pipelines run.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, stdout)
+}
+
+// UT: Execute `dtvisual report`, reading the test results from stdin.
+func TestRunReportFromStdin(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ACT.
+	code, stdout, stderr := executeWithStdin(xmlData, "-")
+
+	// ASSERT.
+	assert.Equal(t, code, exitTestsFailed, "", "\n\n"+
+		"UT Name:    Execute `dtvisual report`, reading the test results from stdin.\n"+
+		"\033[32mExpected:   Exit code 1\033[0m\n"+
+		"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", code, stderr)
+
+	assert.Equal(t, strings.HasPrefix(stdout, "App.dll (1 passed, 1 failed) 1.5s\n"), true, "", "\n\n"+
+		"UT Name:    Execute `dtvisual report`, reading the test results from stdin.\n"+
+		"\033[32mExpected:   The report of the test results\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", stdout)
+}
+
+// Returns the output of `dtvisual` executed with args, which must succeed.
+func localReport(t *testing.T, args ...string) string {
+	code, stdout, stderr := execute(args...)
+
+	if code != exitOK {
+		t.Fatalf("execute(%v) = %d (stderr: %s), want %d", args, code, stderr, exitOK)
+	}
+
+	return stdout
+}
+
+// UT: Execute `dtvisual report`, loading the test results from a DTVisual server.
+func TestRunReportFromServer(t *testing.T) {
+	// ARRANGE.
+	nestedData := "<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" skipped=\"1\" time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Passes\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass.Skipped\" result=\"Skip\" time=\"0\"><reason>Flaky</reason></test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+	nestedPath := writeFile(t, "nested.xml", nestedData)
+	testRun, _ := xunit.Load(strings.NewReader(xmlData))
+	nestedRun, _ := xunit.Load(strings.NewReader(nestedData))
+	cfg := auth.Config{Tokens: []auth.Token{{Name: "CI", Token: "s3cr3t", Scopes: []auth.Scope{auth.ScopeRead}}}}
+	handler := api.Handler(func() ([]api.Run, error) {
+		return []api.Run{
+			{ID: "nightly", Name: "nightly.xml", TestRun: testRun},
+			{ID: "nested", Name: "nested.xml", TestRun: nestedRun},
+		}, nil
+	}, nil)
+	srv := httptest.NewServer(auth.Require(cfg, auth.MethodScope, handler))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		token    string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			token:    "s3cr3t",
+			args:     []string{"report", "--server", srv.URL},
+			wantCode: exitTestsFailed,
+			want: "App.dll (1 passed, 1 failed) 1.5s\n" +
+				"├── ✔ A passing test. 500ms\n" +
+				"└── ✘ A failing test. 1s\n",
+		},
+		{
+			token:    "s3cr3t",
+			args:     []string{"report", "--server", srv.URL, "--azure-pipelines", "--fail-on", "none", "nightly"},
+			wantCode: exitOK,
+			want:     "##vso[task.logissue type=error;]App.dll: A failing test.: Expected: 1\n",
+		},
+		{
+			token:    "s3cr3t",
+			args:     []string{"report", "--server", srv.URL, "--format", "text", "nested"},
+			wantCode: exitOK,
+			want:     localReport(t, "report", "--format", "text", nestedPath),
+		},
+		{
+			token:    "s3cr3t",
+			args:     []string{"report", "--server", srv.URL, "weekly"},
+			wantCode: exitInput,
+		},
+		{
+			args:     []string{"report", "--server", srv.URL},
+			wantCode: exitInput,
+		},
+	} {
+		t.Setenv(tokenEnv, tc.token)
+
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual report`, loading the test results from a DTVisual server.\n"+
+			"Input:      %v (token %q)\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.token, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual report`, loading the test results from a DTVisual server.\n"+
+			"Input:      %v (token %q)\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.token, tc.want, stdout)
+	}
+}
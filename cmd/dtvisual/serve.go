@@ -0,0 +1,543 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/auth"
+	"github.com/kdeconinck/dtvisual/internal/pkg/metrics"
+	"github.com/kdeconinck/dtvisual/internal/pkg/viewer"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The time the server waits for active connections to finish when shutting down.
+const shutdownTimeout = 5 * time.Second
+
+// Executes the "serve" command.
+func runServe(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "serve", "Serve an interactive HTML report of the test results over HTTP.\n\n"+
+		"The tests in the report can be searched by name, and filtered by result.\n"+
+		"The result files are loaded again on each request, so the report is always up-to-date (except when reading\n"+
+		"from stdin).\n\n"+
+		"The test results are also exposed as JSON (one run per file) on /api/runs, /api/runs/{id},\n"+
+		"/api/runs/{id}/assemblies and /api/runs/{id}/tests (filtered with ?result=Fail and/or ?assembly=App.dll).\n"+
+		"The API is described by the OpenAPI document on /api/openapi.json.\n"+
+		"The health of the tests of each project is exposed as Prometheus metrics on /metrics.\n\n"+
+		"With --watch, the open reports reload automatically whenever a result file changes (e.g. during a CI run or\n"+
+		"a local test loop). A changed file is indexed again as a whole, while the files which didn't change (based\n"+
+		"on their size and modification time) are reused. The updated runs are pushed (as on /api/runs) over the\n"+
+		"WebSocket on /api/live, which only accepts the reports served by the server itself, or by an origin passed\n"+
+		"with --allowed-origin.\n\n"+
+		"With --ingest, result files can be uploaded to the server (e.g. by CI pipelines), in which case the\n"+
+		"result files on the command line are optional. The latest 100 uploaded runs of each project are kept in\n"+
+		"memory, grouped by the project passed with &project=<name>, and the landing page is a dashboard of the latest\n"+
+		"status, the pass rate and the trend of each project. The report of each run is served on /runs/{id}. The\n"+
+		"commit and the branch of a run (passed with &commit=<sha> and &branch=<name>) are used to detect flaky\n"+
+		"tests.\n\n"+
+		"The liveness of the server is exposed on /healthz, and its readiness (whether the result files can be\n"+
+		"loaded) on /readyz, neither of which requires an API token. The number of runs, the time of the last upload\n"+
+		"and the number of result files which couldn't be parsed are exposed as JSON on /stats.\n\n"+
+		"With --tokens, each request must carry an API token, as a bearer token (\"Authorization: Bearer <token>\")\n"+
+		"or as the password of HTTP basic authentication (so browsers prompt for it). The file lists the tokens,\n"+
+		"and their scopes (\"read\" to read the test results, \"ingest\" to upload them):\n\n"+
+		"  {\"tokens\": [{\"name\": \"CI\", \"token\": \"<secret>\", \"scopes\": [\"ingest\"]}]}")
+	addr := fs.String("addr", "localhost:8080", "The `address` to listen on.")
+	watch := fs.Bool("watch", false, "Push an update to the open reports whenever a result file changes.")
+	ingest := fs.Bool("ingest", false, "Accept result files uploaded with `POST /api/runs?name=results.xml`.")
+	tokens := fs.String("tokens", "", "Require one of the API tokens configured in `file` (JSON) for each request.")
+
+	var origins []string
+
+	fs.Func("allowed-origin", "Accept the WebSocket of --watch from pages served on `origin` (e.g. "+
+		"https://example.com), besides the address of the server, which can be repeated.", func(v string) error {
+		origins = append(origins, v)
+
+		return nil
+	})
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	store, err := newRunStore(ctx, env, fs.Args(), *ingest)
+
+	if err != nil {
+		return err
+	}
+
+	defer store.close()
+
+	var live *watcher
+
+	if *watch {
+		live = newWatcher(fs.Args())
+	}
+
+	var cfg *auth.Config
+
+	if *tokens != "" {
+		c, err := loadTokens(*tokens)
+
+		if err != nil {
+			return err
+		}
+
+		cfg = &c
+	}
+
+	handler := newServeHandler(env, store, live, origins, cfg)
+
+	ln, err := net.Listen("tcp", *addr)
+
+	if err != nil {
+		return err
+	}
+
+	if live != nil {
+		go live.run(ctx, watchInterval)
+	}
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		// NOTE: The WebSocket connections aren't closed by Shutdown, so they're closed when ctx is done.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	fmt.Fprintf(env.stderr, "Serving the test results on http://%s (press Ctrl+C to stop).\n", ln.Addr())
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// Returns the API tokens configured in the file at path.
+func loadTokens(path string) (auth.Config, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return auth.Config{}, err
+	}
+
+	defer f.Close()
+
+	cfg, err := auth.Load(f)
+
+	if err != nil {
+		return auth.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// The maximum size of a result file uploaded to the server.
+const maxUploadSize = 64 << 20
+
+// The maximum number of uploaded runs kept for each project (the oldest ones are dropped first).
+const maxRunsPerProject = 100
+
+// The runs served by the "serve" command: the runs stored in result files (one run per file), which are loaded again
+// when they're requested after the file changed, and the runs uploaded to the server (if uploads are accepted).
+// Stdin can only be read once, so the run read from stdin is cached.
+type runStore struct {
+	env     *env
+	paths   []string        // The paths of the result files.
+	names   []string        // The names of the runs stored in the result files.
+	ids     []string        // The IDs of the runs stored in the result files.
+	uploads bool            // True if runs can be uploaded to the server.
+	ctx     context.Context // The context whose end stops loading the result files (when the server shuts down).
+
+	mu          sync.Mutex           // Guards the fields below.
+	stdinRun    *xunit.Index         // The index of the run read from stdin (if any).
+	used        map[string]bool      // The IDs of the runs.
+	uploaded    []api.Run            // The latest runs uploaded to the server, in the order they were uploaded.
+	lastIngest  time.Time            // The time the last run was uploaded (zero if none).
+	parseErrors int                  // The number of times a result file couldn't be read (or parsed).
+	loaded      map[string]loadedRun // The runs stored in the result files when they were last loaded, by path.
+}
+
+// A loadedRun is the index of the run stored in a result file, along with the state of the file when it was loaded.
+type loadedRun struct {
+	state fileState
+	index *xunit.Index
+}
+
+// The statistics of a runStore, as exposed on /stats.
+type storeStats struct {
+	Runs         int        `json:"runs"`
+	UploadedRuns int        `json:"uploadedRuns"`
+	LastIngest   *time.Time `json:"lastIngest,omitempty"`
+	ParseErrors  int        `json:"parseErrors"`
+}
+
+// Returns a store of the runs stored in the result files at paths, which also accepts uploads if uploads is true.
+// It returns an error if the files can't be loaded.
+func newRunStore(ctx context.Context, env *env, paths []string, uploads bool) (*runStore, error) {
+	if len(paths) == 0 && !uploads {
+		return nil, &usageError{msg: "no input files"}
+	}
+
+	if _, err := readsStdin(paths); err != nil {
+		return nil, err
+	}
+
+	s := &runStore{
+		ctx:     ctx,
+		env:     env,
+		paths:   paths,
+		uploads: uploads,
+		used:    make(map[string]bool),
+		loaded:  make(map[string]loadedRun),
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+
+		if path == stdinPath {
+			name = "<stdin>"
+		}
+
+		s.names, s.ids = append(s.names, name), append(s.ids, s.newID(name))
+	}
+
+	// NOTE: Load the files once, which reports problems before serving them (and caches the run read from stdin).
+	if _, err := s.runs(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Returns the runs of s: the runs stored in the result files, followed by the uploaded runs.
+func (s *runStore) runs() ([]api.Run, error) {
+	return s.loadRuns(true)
+}
+
+// Returns an error if the result files of s can't be loaded (or parsed).
+// Unlike runs, it doesn't count the failures as parse errors, so polling it (e.g. by a readiness probe) doesn't
+// change the statistics of s.
+func (s *runStore) ready() error {
+	_, err := s.loadRuns(false)
+
+	return err
+}
+
+// Returns the runs of s (see runs), and counts the result files which can't be loaded as parse errors if countErrors
+// is true.
+func (s *runStore) loadRuns(countErrors bool) ([]api.Run, error) {
+	runs := make([]api.Run, 0, len(s.paths))
+
+	for idx, path := range s.paths {
+		if path == stdinPath {
+			s.mu.Lock()
+			stdinRun := s.stdinRun
+			s.mu.Unlock()
+
+			if stdinRun != nil {
+				runs = append(runs, api.Run{
+					ID:      s.ids[idx],
+					Name:    s.names[idx],
+					TestRun: stdinRun.TestRun,
+					Index:   stdinRun,
+				})
+
+				continue
+			}
+		}
+
+		index, err := s.load(path)
+
+		if err != nil {
+			if countErrors {
+				s.parseFailed()
+			}
+
+			return nil, &inputError{err: err}
+		}
+
+		if path == stdinPath {
+			s.mu.Lock()
+			s.stdinRun = index
+			s.mu.Unlock()
+		}
+
+		runs = append(runs, api.Run{ID: s.ids[idx], Name: s.names[idx], TestRun: index.TestRun, Index: index})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(runs, s.uploaded...), nil
+}
+
+// Returns the index of the run stored in the result file at path, which is only loaded again if the file changed
+// (based on its size and modification time, like a watcher) since it was last loaded, so unchanged files don't slow
+// down watch mode. The details of the tests are only loaded from the index when they're shown.
+// NOTE: The cache is per file, not per assembly: a file which changed (e.g. because an assembly was appended to it)
+// is indexed again as a whole. Indexing skips the details of the tests, which keeps that pass cheap.
+func (s *runStore) load(path string) (*xunit.Index, error) {
+	state := statFile(path)
+
+	s.mu.Lock()
+	last, ok := s.loaded[path]
+	s.mu.Unlock()
+
+	if ok && state.exists && state == last.state {
+		s.env.log.Debug("Reused the run of the unchanged result file", "file", path)
+
+		return last.index, nil
+	}
+
+	index, err := loadIndex(s.ctx, s.env, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	prev, replaced := s.loaded[path]
+	s.loaded[path] = loadedRun{state: state, index: index}
+	s.mu.Unlock()
+
+	// NOTE: A request which still uses the previous index fails to load the details of its tests once it's closed.
+	if replaced {
+		prev.index.Close()
+	}
+
+	return index, nil
+}
+
+// Stores the result file read from r (described by upload) as a new run, and returns it.
+func (s *runStore) ingest(upload api.Upload, r io.Reader) (api.Run, error) {
+	name := upload.Name
+
+	if name == "" {
+		name = "upload"
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxUploadSize+1))
+
+	if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) || len(data) > maxUploadSize {
+		return api.Run{}, &api.TooLargeError{Name: name, Limit: maxUploadSize}
+	}
+
+	if err != nil {
+		return api.Run{}, err
+	}
+
+	testRun, err := parseFile(s.ctx, s.env, name, data)
+
+	if err != nil {
+		s.parseFailed()
+
+		return api.Run{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := name
+
+	if upload.Project != "" {
+		id = upload.Project + "-" + name
+	}
+
+	run := api.Run{
+		ID:      s.newID(id),
+		Name:    name,
+		Project: upload.Project,
+		Commit:  upload.Commit,
+		Branch:  upload.Branch,
+		TestRun: testRun,
+	}
+	s.uploaded, s.lastIngest = append(s.uploaded, run), time.Now()
+	s.evict(upload.Project)
+
+	s.env.log.Info("Stored an uploaded result file", "name", name, "project", upload.Project, "id", run.ID)
+
+	return run, nil
+}
+
+// Drops the oldest uploaded run of project if more than maxRunsPerProject of its runs are kept.
+// The caller must hold the lock.
+func (s *runStore) evict(project string) {
+	var idxs []int
+
+	for idx, run := range s.uploaded {
+		if run.Project == project {
+			idxs = append(idxs, idx)
+		}
+	}
+
+	if len(idxs) <= maxRunsPerProject {
+		return
+	}
+
+	oldest := idxs[0]
+	s.env.log.Info("Dropped the oldest uploaded run", "project", project, "id", s.uploaded[oldest].ID)
+
+	// NOTE: The runs are copied to a new array, so the dropped run can be garbage collected.
+	s.uploaded = append(s.uploaded[:oldest:oldest], s.uploaded[oldest+1:]...)
+}
+
+// Closes the indexes of the result files (including the one read from stdin), which removes their temporary files
+// (if any).
+func (s *runStore) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, loaded := range s.loaded {
+		loaded.index.Close()
+		delete(s.loaded, path)
+	}
+}
+
+// Records that a result file couldn't be read (or parsed).
+func (s *runStore) parseFailed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.parseErrors++
+}
+
+// Returns the statistics of s.
+func (s *runStore) stats() storeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := storeStats{Runs: len(s.paths) + len(s.uploaded), UploadedRuns: len(s.uploaded), ParseErrors: s.parseErrors}
+
+	if !s.lastIngest.IsZero() {
+		lastIngest := s.lastIngest.UTC()
+		stats.LastIngest = &lastIngest
+	}
+
+	return stats
+}
+
+// Returns a new ID for a run with the given name (the name without its extension, made URL-safe and unique).
+// The caller must hold the lock (once s is shared).
+func (s *runStore) newID(name string) string {
+	id := strings.Trim(unsafeIDChars.ReplaceAllString(strings.TrimSuffix(name, filepath.Ext(name)), "-"), "-")
+
+	if id == "" {
+		id = "run"
+	}
+
+	for base, n := id, 2; s.used[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+
+	s.used[id] = true
+
+	return id
+}
+
+// Returns the handler which serves the viewer of the runs of store (which accepts uploads if store does), their
+// metrics and the statistics of store. If live isn't nil, the runs are pushed to the open reports whenever live
+// notifies a change (over a WebSocket which can only be opened from the host of the server and from origins). If cfg
+// isn't nil, each request (except the health checks) requires one of its API tokens.
+func newServeHandler(env *env, store *runStore, live *watcher, origins []string, cfg *auth.Config) http.Handler {
+	mux := http.NewServeMux()
+	opts := viewer.Options{AllowedOrigins: origins, Logger: env.log}
+
+	if store.uploads {
+		opts.Ingest = store.ingest
+	}
+
+	if live != nil {
+		opts.Subscribe = live.subscribe
+	}
+
+	mux.Handle("/metrics", metrics.Handler(store.runs))
+	mux.Handle("/stats", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(store.stats())
+	}))
+	viewerHandler := viewer.NewHandler(store.runs, opts)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: The server stops reading an upload which is too large (instead of reading it to find out it's too large).
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		viewerHandler.ServeHTTP(w, r)
+	}))
+
+	var handler http.Handler = mux
+
+	if cfg != nil {
+		handler = auth.Require(*cfg, auth.MethodScope, mux)
+	}
+
+	// NOTE: The health checks don't require an API token, so they can be used by a load balancer (or Kubernetes).
+	probes := http.NewServeMux()
+	probes.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	probes.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: The probes don't require an API token, so the error (which names the result files) is only logged.
+		if err := store.ready(); err != nil {
+			env.log.Warn("The server isn't ready", "error", err)
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		fmt.Fprintln(w, "ok")
+	})
+	probes.Handle("/", handler)
+
+	return probes
+}
@@ -0,0 +1,557 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/auth"
+)
+
+// UT: Serve the HTML report (and the API) of result files.
+func TestServeHandler(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+	store, err := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path, path}, false)
+
+	if err != nil {
+		t.Fatalf("newRunStore() = %v, want <nil>", err)
+	}
+
+	handler := newServeHandler(newEnv(nil, io.Discard, io.Discard), store, nil, nil, nil)
+
+	for _, tc := range []struct {
+		target   string
+		wantCode int
+		want     string
+	}{
+		{
+			target:   "/",
+			wantCode: http.StatusOK,
+			want:     "<input id=\"search\" type=\"search\"",
+		},
+		{
+			target:   "/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			target:   "/metrics",
+			wantCode: http.StatusOK,
+			want:     "dtvisual_runs{project=\"default\"} 2\n",
+		},
+		{
+			target:   "/healthz",
+			wantCode: http.StatusOK,
+			want:     "ok",
+		},
+		{
+			target:   "/readyz",
+			wantCode: http.StatusOK,
+			want:     "ok",
+		},
+		{
+			target:   "/stats",
+			wantCode: http.StatusOK,
+			want:     "\"runs\": 2,\n  \"uploadedRuns\": 0,\n  \"parseErrors\": 0",
+		},
+		{
+			target:   "/runs/results-2",
+			wantCode: http.StatusOK,
+			want:     "<title>results.xml</title>",
+		},
+		{
+			target:   "/api/runs",
+			wantCode: http.StatusOK,
+			want:     "\"id\": \"results-2\",\n    \"name\": \"results.xml\"",
+		},
+		{
+			target:   "/runs/results-2",
+			wantCode: http.StatusOK,
+			want:     "Expected: 1",
+		},
+		{
+			target:   "/api/runs/results/tests?result=fail",
+			wantCode: http.StatusOK,
+			want:     "\"name\": \"A failing test.\"",
+		},
+		{
+			target:   "/api/runs/results/tests?result=fail",
+			wantCode: http.StatusOK,
+			want:     "\"message\": \"Expected: 1\"",
+		},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Serve the HTML report (and the API) of result files.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", tc.target, tc.wantCode, rec.Code)
+
+		assert.Contains(t, rec.Body.String(), tc.want, "", "\n\n"+
+			"UT Name:    Serve the HTML report (and the API) of result files.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Body containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.target, tc.want, rec.Body.String())
+	}
+}
+
+// UT: Serve result files which are larger than the memory budget.
+func TestServeHandler_MemoryBudget(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	env := newEnv(nil, io.Discard, io.Discard)
+
+	if err := parseFlags(newFlagSet(env, "serve", ""), []string{"--memory-budget", "16"}); err != nil {
+		t.Fatalf("parseFlags() = %v, want <nil>", err)
+	}
+
+	store, _ := newRunStore(context.Background(), env, []string{path}, false)
+	handler := newServeHandler(env, store, nil, nil, nil)
+	rec := httptest.NewRecorder()
+	want := "\"message\": \"Expected: 1\""
+
+	t.Cleanup(store.close)
+
+	// ACT.
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/runs/results/tests?result=Fail", nil))
+
+	// ASSERT.
+	assert.Contains(t, rec.Body.String(), want, "", "\n\n"+
+		"UT Name:    Serve result files which are larger than the memory budget.\n"+
+		"\033[32mExpected:   Body containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, rec.Body.String())
+}
+
+// UT: Serve result files which can no longer be read.
+func TestServeHandler_Missing(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	store, _ := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path}, false)
+	handler := newServeHandler(newEnv(nil, io.Discard, io.Discard), store, nil, nil, nil)
+	rec := httptest.NewRecorder()
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove() = %v, want <nil>", err)
+	}
+
+	// ACT.
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// ASSERT.
+	assert.Equal(t, rec.Code, http.StatusInternalServerError, "", "\n\n"+
+		"UT Name:    Serve result files which can no longer be read.\n"+
+		"\033[32mExpected:   Status code %d\033[0m\n"+
+		"\033[31mActual:     Status code %d\033[0m\n\n", http.StatusInternalServerError, rec.Code)
+}
+
+// UT: Check the health of a server whose result files can no longer be read.
+func TestServeHandler_Unready(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+	store, _ := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path}, false)
+	cfg := &auth.Config{Tokens: []auth.Token{{Name: "CI", Token: "s3cr3t", Scopes: []auth.Scope{auth.ScopeRead}}}}
+	handler := newServeHandler(newEnv(nil, io.Discard, io.Discard), store, nil, nil, cfg)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove() = %v, want <nil>", err)
+	}
+
+	for _, tc := range []struct {
+		target   string
+		wantCode int
+		want     string
+	}{
+		{target: "/healthz", wantCode: http.StatusOK, want: "ok\n"},
+		{target: "/readyz", wantCode: http.StatusServiceUnavailable, want: "not ready\n"},
+		{target: "/stats", wantCode: http.StatusUnauthorized, want: "Unauthorized\n"},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Check the health of a server whose result files can no longer be read.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", tc.target, tc.wantCode, rec.Code)
+
+		assert.Equal(t, rec.Body.String(), tc.want, "", "\n\n"+
+			"UT Name:    Check the health of a server whose result files can no longer be read.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Body %q\033[0m\n"+
+			"\033[31mActual:     Body %q\033[0m\n\n", tc.target, tc.want, rec.Body.String())
+	}
+}
+
+// UT: Poll the readiness of a server whose result files can no longer be parsed.
+func TestServeHandler_UnreadyStats(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	store, _ := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path}, false)
+	handler := newServeHandler(newEnv(nil, io.Discard, io.Discard), store, nil, nil, nil)
+
+	if err := os.WriteFile(path, []byte("<assemblies>\n  <assembly name=\"App.dll\" total=\"x\">"), 0o644); err != nil {
+		t.Fatalf("WriteFile() = %v, want <nil>", err)
+	}
+
+	// ACT.
+	for i := 0; i < 3; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	// ASSERT.
+	want := "\"parseErrors\": 0"
+
+	assert.Contains(t, rec.Body.String(), want, "", "\n\n"+
+		"UT Name:    Poll the readiness of a server whose result files can no longer be parsed.\n"+
+		"\033[32mExpected:   Body containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, rec.Body.String())
+}
+
+// UT: Push the runs to the open reports when a result file changes.
+func TestServeHandler_Live(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := writeFile(t, "results.xml", xmlData)
+	store, _ := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path}, false)
+	live := newWatcher([]string{path})
+	srv := httptest.NewServer(newServeHandler(newEnv(nil, io.Discard, io.Discard), store, live, nil, nil))
+	defer srv.Close()
+
+	go live.run(ctx, 10*time.Millisecond)
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+
+	if err != nil {
+		t.Fatalf("net.Dial() = %v, want <nil>", err)
+	}
+
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	io.WriteString(conn, "GET /api/live HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	r := bufio.NewReader(conn)
+
+	if _, err := http.ReadResponse(r, nil); err != nil {
+		t.Fatalf("http.ReadResponse() = %v, want <nil>", err)
+	}
+
+	// ACT.
+	data := strings.Replace(xmlData, "A failing test.", "A fixed test.", 1)
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile() = %v, want <nil>", err)
+	}
+
+	header := make([]byte, 4)
+	io.ReadFull(r, header)
+	payload := make([]byte, int(header[2])<<8|int(header[3]))
+	io.ReadFull(r, payload)
+
+	// ASSERT.
+	want := "\"id\": \"results\""
+
+	assert.Contains(t, string(payload), want, "", "\n\n"+
+		"UT Name:    Push the runs to the open reports when a result file changes.\n"+
+		"\033[32mExpected:   A message containing %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, payload)
+
+	rec := httptest.NewRecorder()
+	srv.Config.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Contains(t, rec.Body.String(), "A fixed test.", "", "\n\n"+
+		"UT Name:    Push the runs to the open reports when a result file changes.\n"+
+		"\033[32mExpected:   A report containing \"A fixed test.\"\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", rec.Body.String())
+}
+
+// UT: Upload result files to the server (grouped by project).
+func TestServeHandler_Ingest(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	store, err := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), nil, true)
+
+	if err != nil {
+		t.Fatalf("newRunStore() = %v, want <nil>", err)
+	}
+
+	handler := newServeHandler(newEnv(nil, io.Discard, io.Discard), store, nil, nil, nil)
+
+	for _, tc := range []struct {
+		method       string
+		target       string
+		body         string
+		wantCode     int
+		wantLocation string
+		want         string
+	}{
+		{
+			method:       http.MethodPost,
+			target:       "/api/runs?name=nightly.xml&project=api",
+			body:         xmlData,
+			wantCode:     http.StatusCreated,
+			wantLocation: "/api/runs/api-nightly",
+			want:         "\"project\": \"api\"",
+		},
+		{
+			method:       http.MethodPost,
+			target:       "/api/runs?name=nightly.xml",
+			body:         xmlData,
+			wantCode:     http.StatusCreated,
+			wantLocation: "/api/runs/nightly",
+		},
+		{
+			method:       http.MethodPost,
+			target:       "/api/runs?name=nightly.xml&project=api",
+			body:         xmlData,
+			wantCode:     http.StatusCreated,
+			wantLocation: "/api/runs/api-nightly-2",
+		},
+		{
+			method:   http.MethodPost,
+			target:   "/api/runs?name=results.trx",
+			body:     "<TestRun />",
+			wantCode: http.StatusBadRequest,
+			want:     "the trx format is not supported",
+		},
+		{
+			method:   http.MethodPost,
+			target:   "/api/runs?name=huge.xml",
+			body:     strings.Repeat(" ", maxUploadSize+1),
+			wantCode: http.StatusRequestEntityTooLarge,
+			want:     "huge.xml: the file is larger than 67108864 bytes",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/api/runs/nightly/tests?result=Fail",
+			wantCode: http.StatusOK,
+			want:     "\"name\": \"A failing test.\"",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/",
+			wantCode: http.StatusOK,
+			want:     "<td><a href=\"runs/api-nightly-2\">api</a></td>",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/",
+			wantCode: http.StatusOK,
+			want:     "<td><a href=\"runs/nightly\">default</a></td>",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/runs/api-nightly",
+			wantCode: http.StatusOK,
+			want:     "<a class=\"back\" href=\"../\">",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/runs/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/stats",
+			wantCode: http.StatusOK,
+			want:     "\"uploadedRuns\": 3,\n  \"lastIngest\": ",
+		},
+		{
+			method:   http.MethodGet,
+			target:   "/stats",
+			wantCode: http.StatusOK,
+			want:     "\"parseErrors\": 1",
+		},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Upload result files to the server (grouped by project).\n"+
+			"Input:      %s %s\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d (%s)\033[0m\n\n", tc.method, tc.target, tc.wantCode, rec.Code, rec.Body)
+
+		assert.Equal(t, rec.Header().Get("Location"), tc.wantLocation, "", "\n\n"+
+			"UT Name:    Upload result files to the server (grouped by project).\n"+
+			"Input:      %s %s\n"+
+			"\033[32mExpected:   Location: %s\033[0m\n"+
+			"\033[31mActual:     Location: %s\033[0m\n\n", tc.method, tc.target, tc.wantLocation,
+			rec.Header().Get("Location"))
+
+		assert.Contains(t, rec.Body.String(), tc.want, "", "\n\n"+
+			"UT Name:    Upload result files to the server (grouped by project).\n"+
+			"Input:      %s %s\n"+
+			"\033[32mExpected:   Body containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.method, tc.target, tc.want, rec.Body.String())
+	}
+}
+
+// UT: Drop the oldest uploaded runs of a project.
+func TestRunStore_Evict(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	store, err := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), nil, true)
+
+	if err != nil {
+		t.Fatalf("newRunStore() = %v, want <nil>", err)
+	}
+
+	store.ingest(api.Upload{Name: "web.xml", Project: "web"}, strings.NewReader(xmlData))
+
+	// ACT.
+	for idx := 0; idx < maxRunsPerProject+2; idx++ {
+		if _, err := store.ingest(api.Upload{Name: "api.xml", Project: "api"}, strings.NewReader(xmlData)); err != nil {
+			t.Fatalf("ingest() = %v, want <nil>", err)
+		}
+	}
+
+	// ASSERT.
+	runs, _ := store.runs()
+	got := []string{runs[0].ID, runs[1].ID, runs[len(runs)-1].ID}
+	want := []string{"web-web", "api-api-3", "api-api-" + strconv.Itoa(maxRunsPerProject+2)}
+
+	assert.Equal(t, len(runs), maxRunsPerProject+1, "", "\n\n"+
+		"UT Name:    Drop the oldest uploaded runs of a project.\n"+
+		"\033[32mExpected:   %d runs\033[0m\n"+
+		"\033[31mActual:     %d runs\033[0m\n\n", maxRunsPerProject+1, len(runs))
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Drop the oldest uploaded runs of a project.\n"+
+		"\033[32mExpected:   The IDs %v (first, second and last)\033[0m\n"+
+		"\033[31mActual:     The IDs %v\033[0m\n\n", want, got)
+}
+
+// UT: Reuse the runs of the result files which didn't change since they were loaded.
+func TestRunStore_Unchanged(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	path := writeFile(t, "results.xml", xmlData)
+	store, err := newRunStore(context.Background(), newEnv(nil, io.Discard, io.Discard), []string{path}, false)
+
+	if err != nil {
+		t.Fatalf("newRunStore() = %v, want <nil>", err)
+	}
+
+	info, _ := os.Stat(path)
+
+	// NOTE: The file keeps its size and its modification time, so the change isn't detected.
+	data := strings.Replace(xmlData, "A failing test.", "A pending test.", 1)
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile() = %v, want <nil>", err)
+	}
+
+	os.Chtimes(path, info.ModTime(), info.ModTime())
+
+	// ACT.
+	runs, err := store.runs()
+
+	// ASSERT.
+	if err != nil {
+		t.Fatalf("runs() = %v, want <nil>", err)
+	}
+
+	got := runs[0].TestRun.Assemblies[0].Tests[0].Tests[1].Name
+
+	assert.Equal(t, got, "A failing test.", "", "\n\n"+
+		"UT Name:    Reuse the runs of the result files which didn't change since they were loaded.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", "A failing test.", got)
+}
+
+// UT: Serve the test results read from stdin.
+func TestNewRunStore_Stdin(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	env := newEnv(strings.NewReader(xmlData), io.Discard, io.Discard)
+	store, err := newRunStore(context.Background(), env, []string{"-"}, false)
+
+	if err != nil {
+		t.Fatalf("newRunStore() = %v, want <nil>", err)
+	}
+
+	// ACT.
+	runs, err := store.runs()
+
+	// ASSERT.
+	got := err == nil && len(runs) == 1 && runs[0].ID == "stdin" && runs[0].Name == "<stdin>"
+
+	assert.Equal(t, got, true, "", "\n\n"+
+		"UT Name:    Serve the test results read from stdin.\n"+
+		"\033[32mExpected:   A single run (with ID \"stdin\")\033[0m\n"+
+		"\033[31mActual:     %v (error: %v)\033[0m\n\n", runs, err)
+}
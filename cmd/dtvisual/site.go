@@ -0,0 +1,79 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/site"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Executes the "site" command.
+func runSite(_ context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "site", "Write a static website of the result files in a directory (e.g. for publishing the "+
+		"history of a\ntest suite on GitHub Pages): an index page listing the runs (from the most recently modified "+
+		"file to the\nleast recently modified file), and an HTML report per run.\n\n"+
+		"The only argument is the directory containing the result files.")
+	output := fs.String("output", "site", "Write the site to `directory`.")
+	fs.StringVar(output, "o", "site", "Shorthand for --output.")
+	title := fs.String("title", "Test runs", "The `title` of the index page.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "site requires a single directory"}
+	}
+
+	dir := fs.Arg(0)
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		if err == nil {
+			err = fmt.Errorf("%s: not a directory", dir)
+		}
+
+		return &inputError{err: err}
+	}
+
+	if err := site.Generate(dir, *output, site.Options{Title: *title}); err != nil {
+		var parseErr *xunit.ParseError
+
+		if errors.As(err, &parseErr) {
+			return &inputError{err: err}
+		}
+
+		return err
+	}
+
+	env.log.Info("Wrote the site", "directory", *output)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual site`.
+func TestRunSite(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	dir := filepath.Dir(writeFile(t, "nightly.xml", xmlData))
+	malformed := filepath.Dir(writeFile(t, "malformed.xml", "<assemblies>\n  <assembly name=\"App.dll\" total=\"x\">"))
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{args: []string{"site", "-o", filepath.Join(t.TempDir(), "site"), dir}, wantCode: exitOK},
+		{args: []string{"site", "--output", filepath.Join(t.TempDir(), "site"), dir}, wantCode: exitOK},
+		{
+			args:     []string{"site", "-o", filepath.Join(t.TempDir(), "site"), malformed},
+			wantCode: exitInput,
+			want:     "malformed.xml: xunit: line 2 (offset 50), in <assembly>",
+		},
+		{args: []string{"site", "missing"}, wantCode: exitInput, want: "stat missing: no such file or directory"},
+		{args: []string{"site"}, wantCode: exitUsage, want: "site requires a single directory"},
+		{args: []string{"site", dir, dir}, wantCode: exitUsage, want: "site requires a single directory"},
+	} {
+		// ACT.
+		code, _, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual site`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stderr, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual site`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stderr)
+
+		if tc.wantCode != exitOK {
+			continue
+		}
+
+		for _, name := range []string{"index.html", "runs/nightly.html"} {
+			_, err := os.Stat(filepath.Join(tc.args[2], name))
+
+			assert.NoError(t, err, "Stat(%s)", name)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The statistics of a test run, as reported by the "stats" command (in the JSON format).
+type testRunStats struct {
+	Assemblies  int               `json:"assemblies"`
+	Total       int               `json:"total"`
+	Passed      int               `json:"passed"`
+	Failed      int               `json:"failed"`
+	Skipped     int               `json:"skipped"`
+	NotRun      int               `json:"notRun"`
+	Errors      int               `json:"errors"`
+	PassRate    float64           `json:"passRate"`
+	DurationMs  int64             `json:"durationMs"`
+	Durations   durationStats     `json:"durations"`
+	Traits      []traitStats      `json:"traits"`
+	Collections []collectionStats `json:"collections"`
+}
+
+// The distribution of the durations of the executed tests, in milliseconds.
+type durationStats struct {
+	Count int   `json:"count"`
+	P50   int64 `json:"p50Ms"`
+	P90   int64 `json:"p90Ms"`
+	P95   int64 `json:"p95Ms"`
+	P99   int64 `json:"p99Ms"`
+	Max   int64 `json:"maxMs"`
+}
+
+// The number of tests with a trait, per result.
+type traitStats struct {
+	Trait   string `json:"trait"`
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Skipped int    `json:"skipped"`
+	NotRun  int    `json:"notRun"`
+}
+
+// The number of tests of a test collection, per result, and the time spent running them.
+type collectionStats struct {
+	Assembly   string `json:"assembly"`
+	Name       string `json:"name"`
+	Total      int    `json:"total"`
+	Passed     int    `json:"passed"`
+	Failed     int    `json:"failed"`
+	Skipped    int    `json:"skipped"`
+	NotRun     int    `json:"notRun"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Executes the "stats" command.
+func runStats(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "stats", "Print the statistics of the test results: the totals, the pass rate, the "+
+		"percentiles of the\ndurations of the executed tests, the number of tests with each trait, and the tests of each "+
+		"collection\n(ordered by the time spent running them).")
+	format := fs.String("format", "table", "The output `format` (table or json).")
+	output := fs.String("output", "", "Write the statistics to `file` instead of stdout.")
+	gates := addGateFlags(fs)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *format != "table" && *format != "json" {
+		return &usageError{msg: fmt.Sprintf("unknown format %q", *format)}
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	stats := newTestRunStats(testRun)
+
+	if err := withOutput(env, *output, func(w io.Writer) error {
+		if *format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+
+			return enc.Encode(stats)
+		}
+
+		return writeStats(w, stats)
+	}); err != nil {
+		return err
+	}
+
+	return gates.check(testRun.Stats())
+}
+
+// Returns the statistics of testRun.
+func newTestRunStats(testRun xunit.TestRun) testRunStats {
+	s, p := testRun.Stats(), analysis.Percentiles(testRun)
+	stats := testRunStats{
+		Assemblies: s.AssemblyCount,
+		Total:      s.TotalCount,
+		Passed:     s.PassedCount,
+		Failed:     s.FailedCount,
+		Skipped:    s.SkippedCount,
+		NotRun:     s.NotRunCount,
+		Errors:     s.ErrorCount,
+		PassRate:   s.PassRate,
+		DurationMs: s.TotalDuration.Milliseconds(),
+		Durations: durationStats{
+			Count: p.Count,
+			P50:   p.P50.Milliseconds(),
+			P90:   p.P90.Milliseconds(),
+			P95:   p.P95.Milliseconds(),
+			P99:   p.P99.Milliseconds(),
+			Max:   p.Max.Milliseconds(),
+		},
+		Traits:      make([]traitStats, 0),
+		Collections: make([]collectionStats, 0),
+	}
+
+	for _, c := range analysis.CountByTrait(testRun) {
+		stats.Traits = append(stats.Traits, traitStats{
+			Trait:   c.Trait,
+			Total:   c.Total,
+			Passed:  c.Passed,
+			Failed:  c.Failed,
+			Skipped: c.Skipped,
+			NotRun:  c.NotRun,
+		})
+	}
+
+	for _, assembly := range testRun.Assemblies {
+		for _, c := range assembly.Collections {
+			stats.Collections = append(stats.Collections, collectionStats{
+				Assembly:   assembly.Name,
+				Name:       c.Name,
+				Total:      c.TotalCount,
+				Passed:     c.PassedCount,
+				Failed:     c.FailedCount,
+				Skipped:    c.SkippedCount,
+				NotRun:     c.NotRunCount,
+				DurationMs: c.Duration.Milliseconds(),
+			})
+		}
+	}
+
+	// NOTE: The collections which take the longest are the ones which limit how much the tests run in parallel.
+	slices.SortStableFunc(stats.Collections, func(a, b collectionStats) int {
+		return cmp.Compare(b.DurationMs, a.DurationMs)
+	})
+
+	return stats
+}
+
+// Writes stats to w, as aligned tables.
+func writeStats(w io.Writer, stats testRunStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	ms := func(n int64) string { return (time.Duration(n) * time.Millisecond).String() }
+
+	fmt.Fprintf(tw, "Assemblies\t%d\n", stats.Assemblies)
+	fmt.Fprintf(tw, "Total\t%d\n", stats.Total)
+	fmt.Fprintf(tw, "Passed\t%d\n", stats.Passed)
+	fmt.Fprintf(tw, "Failed\t%d\n", stats.Failed)
+	fmt.Fprintf(tw, "Skipped\t%d\n", stats.Skipped)
+	fmt.Fprintf(tw, "Not run\t%d\n", stats.NotRun)
+	fmt.Fprintf(tw, "Errors\t%d\n", stats.Errors)
+	fmt.Fprintf(tw, "Pass rate\t%.2f%%\n", stats.PassRate)
+	fmt.Fprintf(tw, "Duration\t%s\n", ms(stats.DurationMs))
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nDurations of the %d executed test(s):\n", stats.Durations.Count)
+	fmt.Fprintf(tw, "p50\tp90\tp95\tp99\tmax\n")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ms(stats.Durations.P50), ms(stats.Durations.P90),
+		ms(stats.Durations.P95), ms(stats.Durations.P99), ms(stats.Durations.Max))
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(stats.Traits) > 0 {
+		fmt.Fprintf(w, "\nTests per trait:\n")
+		fmt.Fprintf(tw, "Trait\tTotal\tPassed\tFailed\tSkipped\tNot run\n")
+
+		for _, t := range stats.Traits {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", t.Trait, t.Total, t.Passed, t.Failed, t.Skipped, t.NotRun)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(stats.Collections) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nTests per collection:\n")
+	fmt.Fprintf(tw, "Assembly\tCollection\tTotal\tPassed\tFailed\tSkipped\tNot run\tDuration\n")
+
+	for _, c := range stats.Collections {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n", c.Assembly, c.Name, c.Total, c.Passed, c.Failed, c.Skipped,
+			c.NotRun, ms(c.DurationMs))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,114 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual stats`.
+func TestRunStats(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	path := writeFile(t, "results.xml", xmlData)
+	collectionsPath := writeFile(t, "collections.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" total=\"3\" passed=\"3\" failed=\"0\" skipped=\"0\" time=\"3\">\n"+
+		"    <collection name=\"Fast\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\" time=\"0.5\">\n"+
+		"      <test name=\"NS.Fast.Test\" result=\"Pass\" time=\"0.5\" />\n"+
+		"    </collection>\n"+
+		"    <collection name=\"Slow\" total=\"2\" passed=\"2\" failed=\"0\" skipped=\"0\" time=\"2.5\">\n"+
+		"      <test name=\"NS.Slow.Test1\" result=\"Pass\" time=\"1\" />\n"+
+		"      <test name=\"NS.Slow.Test2\" result=\"Pass\" time=\"1.5\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"stats", path},
+			wantCode: exitTestsFailed,
+			want: "Assemblies  1\n" +
+				"Total       2\n" +
+				"Passed      1\n" +
+				"Failed      1\n" +
+				"Skipped     0\n" +
+				"Not run     0\n" +
+				"Errors      0\n" +
+				"Pass rate   50.00%\n" +
+				"Duration    1.5s\n\n" +
+				"Durations of the 2 executed test(s):\n" +
+				"p50    p90  p95  p99  max\n" +
+				"500ms  1s   1s   1s   1s\n",
+		},
+		{
+			args:     []string{"stats", "--format", "json", "--fail-on", "none", path},
+			wantCode: exitOK,
+			want:     "\"passRate\": 50,\n  \"durationMs\": 1500,\n  \"durations\": {\n    \"count\": 2,\n    \"p50Ms\": 500,",
+		},
+		{
+			args:     []string{"stats", collectionsPath},
+			wantCode: exitOK,
+			want: "Tests per collection:\n" +
+				"Assembly  Collection  Total  Passed  Failed  Skipped  Not run  Duration\n" +
+				"App.dll   Slow        2      2       0       0        0        2.5s\n" +
+				"App.dll   Fast        1      1       0       0        0        500ms\n",
+		},
+		{
+			args:     []string{"stats", "--format", "json", "--filter", "name~Test2", collectionsPath},
+			wantCode: exitOK,
+			want: "\"collections\": [\n    {\n      \"assembly\": \"App.dll\",\n      \"name\": \"Slow\",\n" +
+				"      \"total\": 1,\n      \"passed\": 1,\n      \"failed\": 0,\n      \"skipped\": 0,\n" +
+				"      \"notRun\": 0,\n      \"durationMs\": 1500\n    }\n  ]",
+		},
+		{
+			args:     []string{"stats", "--format", "csv", path},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual stats`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual stats`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
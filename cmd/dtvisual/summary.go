@@ -0,0 +1,70 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/github"
+)
+
+// Executes the "summary" command.
+func runSummary(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "summary", "Write a GitHub Actions job summary of the test results.\n\n"+
+		"The summary is appended to the file referred to by the GITHUB_STEP_SUMMARY environment variable, or written to\n"+
+		"stdout when it isn't set.")
+	annotations := fs.Bool("annotations", false, "Report each failed test as an error annotation (on stdout).")
+	gates := addGateFlags(fs)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	testRun, err := loadFiles(ctx, env, fs.Args())
+
+	if err != nil {
+		return err
+	}
+
+	if err := github.WriteStepSummary(testRun); err != nil {
+		if !errors.Is(err, github.ErrNoStepSummary) {
+			return err
+		}
+
+		if err := github.Summary(env.stdout, testRun); err != nil {
+			return err
+		}
+	}
+
+	if *annotations {
+		if err := github.Annotations(env.stdout, testRun); err != nil {
+			return err
+		}
+	}
+
+	return gates.check(testRun.Stats())
+}
@@ -0,0 +1,91 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual summary`.
+func TestRunSummary(t *testing.T) {
+	path := writeFile(t, "results.xml", xmlData)
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+
+	for _, tc := range []struct {
+		args        []string
+		summaryEnv  string
+		wantStdout  []string
+		wantSummary string
+	}{
+		{
+			args:       []string{"summary", path},
+			wantStdout: []string{"## Test results", "<summary>❌ App.dll: 1 passed, 1 failed, 0 skipped (1.5s)</summary>"},
+		},
+		{
+			args:        []string{"summary", "--annotations", path},
+			summaryEnv:  summaryPath,
+			wantStdout:  []string{"::error title=App.dll%3A A failing test.::Expected: 1\n"},
+			wantSummary: "## Test results",
+		},
+	} {
+		// ARRANGE.
+		t.Setenv("GITHUB_STEP_SUMMARY", tc.summaryEnv)
+
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, exitTestsFailed, "", "\n\n"+
+			"UT Name:    Execute `dtvisual summary`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code 1\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, code, stderr)
+
+		for _, want := range tc.wantStdout {
+			assert.Contains(t, stdout, want, "", "\n\n"+
+				"UT Name:    Execute `dtvisual summary`.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.args, want, stdout)
+		}
+
+		if tc.summaryEnv != "" {
+			got, _ := os.ReadFile(tc.summaryEnv)
+
+			assert.Equal(t, strings.HasPrefix(string(got), tc.wantSummary), true, "", "\n\n"+
+				"UT Name:    Execute `dtvisual summary`.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Summary starting with %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.wantSummary, got)
+		}
+	}
+}
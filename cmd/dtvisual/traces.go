@@ -0,0 +1,69 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/traces"
+)
+
+// Returns the function returning the URL of the trace file of a test, for the trace files in dir (see traces.Find).
+// When baseURL isn't empty, the trace files are expected to be served from it (so they can be opened in speedscope).
+// Otherwise, the trace files are linked relative to the directory of the report at output (or the working directory,
+// if the report is written to stdout).
+func traceURLs(env *env, dir, baseURL, output string) (func(name string) string, error) {
+	idx, err := traces.Find(dir)
+
+	if err != nil {
+		return nil, &inputError{err: err}
+	}
+
+	env.log.Info("Found the trace files", "directory", dir, "traces", idx.Len())
+
+	return func(name string) string {
+		path, ok := idx.Lookup(name)
+
+		switch {
+		case !ok:
+			return ""
+		case baseURL != "":
+			return strings.TrimSuffix(baseURL, "/") + "/" + (&url.URL{Path: path}).EscapedPath()
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(path))
+
+		if output != "" {
+			if rel, err := filepath.Rel(filepath.Dir(output), target); err == nil {
+				target = rel
+			}
+		}
+
+		return (&url.URL{Path: filepath.ToSlash(target)}).String()
+	}, nil
+}
@@ -0,0 +1,148 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Executes the "validate" command.
+func runValidate(ctx context.Context, env *env, args []string) error {
+	fs := newFlagSet(env, "validate", "Check the result files for problems: deviations from xUnit's v2+ XML format, "+
+		"counts which\ndon't match the tests, duplicate IDs and times which can't be parsed.\n\n"+
+		"Each problem is written to stdout as <file>:<line>: <problem>, and the command exits with code 3 if any\n"+
+		"file has a problem.")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	paths := fs.Args()
+
+	if len(paths) == 0 {
+		return &usageError{msg: "no input files"}
+	}
+
+	if _, err := readsStdin(paths); err != nil {
+		return err
+	}
+
+	problemCount, invalidCount := 0, 0
+
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := path
+
+		if path == stdinPath {
+			name = "<stdin>"
+		}
+
+		problems := validateFile(env, path)
+
+		if len(problems) == 0 {
+			fmt.Fprintf(env.stdout, "%s: no problems found\n", name)
+
+			continue
+		}
+
+		for _, p := range problems {
+			if p.Line > 0 {
+				fmt.Fprintf(env.stdout, "%s:%d: %s\n", name, p.Line, p)
+			} else {
+				fmt.Fprintf(env.stdout, "%s: %s\n", name, p)
+			}
+		}
+
+		problemCount += len(problems)
+		invalidCount++
+	}
+
+	if invalidCount > 0 {
+		return &inputError{err: fmt.Errorf("found %d problem(s) in %d of %d file(s)", problemCount, invalidCount,
+			len(paths))}
+	}
+
+	return nil
+}
+
+// Returns the problems of the result file at path (or read from stdin if path is "-"). A file which can't be read, or
+// which isn't a valid document in xUnit's v2+ XML format, has a single problem.
+func validateFile(env *env, path string) []xunit.Problem {
+	var data []byte
+	var err error
+
+	if path == stdinPath {
+		data, err = io.ReadAll(env.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return []xunit.Problem{{Message: err.Error()}}
+	}
+
+	format, err := detectFormat(data)
+
+	if err != nil {
+		return []xunit.Problem{{Message: err.Error()}}
+	}
+
+	if format != "xunit" {
+		return []xunit.Problem{{Message: fmt.Sprintf("the %s format is not supported", format)}}
+	}
+
+	env.log.Info("Validating the result file", "file", path)
+
+	problems, err := xunit.Validate(bytes.NewReader(data))
+
+	var parseErr *xunit.ParseError
+
+	if errors.As(err, &parseErr) {
+		p := xunit.Problem{Line: parseErr.Line, Message: parseErr.Err.Error()}
+
+		if parseErr.Element != "" {
+			p.Element = "<" + parseErr.Element + ">"
+		}
+
+		return []xunit.Problem{p}
+	}
+
+	if err != nil {
+		return []xunit.Problem{{Message: err.Error()}}
+	}
+
+	return problems
+}
@@ -0,0 +1,98 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the "dtvisual" command.
+package main
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+)
+
+// UT: Execute `dtvisual validate`.
+func TestRunValidate(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	valid := writeFile(t, "valid.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"1\" passed=\"1\" "+
+		"failed=\"0\" skipped=\"0\">\n"+
+		"    <collection name=\"C\" time=\"1\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\">\n"+
+		"      <test name=\"T\" type=\"NS.C\" method=\"T\" time=\"1\" result=\"Pass\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+	invalid := writeFile(t, "invalid.xml", xmlData)
+	malformed := writeFile(t, "malformed.xml", "<assemblies>\n  <assembly name=\"App.dll\" total=\"x\">")
+
+	for _, tc := range []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			args:     []string{"validate", valid},
+			wantCode: exitOK,
+			want:     valid + ": no problems found\n",
+		},
+		{
+			args:     []string{"validate", valid, invalid},
+			wantCode: exitInput,
+			want: invalid + ": 1 <assembly> element(s) don't have the required attribute \"run-date\"\n" +
+				invalid + ": 1 <assembly> element(s) don't have the required attribute \"run-time\"\n",
+		},
+		{
+			args:     []string{"validate", malformed},
+			wantCode: exitInput,
+			want:     malformed + ":2: <assembly>: strconv.ParseInt: parsing \"x\": invalid syntax\n",
+		},
+		{
+			args:     []string{"validate", "missing.xml"},
+			wantCode: exitInput,
+			want:     "missing.xml: open missing.xml: no such file or directory\n",
+		},
+		{
+			args:     []string{"validate"},
+			wantCode: exitUsage,
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := execute(tc.args...)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Execute `dtvisual validate`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %d\033[0m\n"+
+			"\033[31mActual:     Exit code %d (stderr: %s)\033[0m\n\n", tc.args, tc.wantCode, code, stderr)
+
+		assert.Contains(t, stdout, tc.want, "", "\n\n"+
+			"UT Name:    Execute `dtvisual validate`.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.args, tc.want, stdout)
+	}
+}
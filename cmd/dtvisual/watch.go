@@ -0,0 +1,138 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The interval at which the result files are checked for changes in watch mode.
+const watchInterval = time.Second
+
+// A watcher notifies its subscribers whenever one of the files it watches changes (based on their size and
+// modification time).
+type watcher struct {
+	paths       []string               // The paths of the watched files.
+	mu          sync.Mutex             // Guards subscribers.
+	subscribers map[chan struct{}]bool // The channels to notify.
+	last        []fileState            // The state of the watched files when they were last checked.
+}
+
+// The state of a watched file, which changes when the file is written.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// Returns a watcher for the files at paths (stdin can't be watched, so "-" is ignored).
+func newWatcher(paths []string) *watcher {
+	w := &watcher{subscribers: make(map[chan struct{}]bool)}
+
+	for _, path := range paths {
+		if path != stdinPath {
+			w.paths = append(w.paths, path)
+		}
+	}
+
+	w.last = w.states()
+
+	return w
+}
+
+// Checks the watched files every interval until ctx is done, and notifies the subscribers when they changed (since the
+// watcher was created, or since the previous check).
+func (w *watcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if next := w.states(); !slices.Equal(next, w.last) {
+				w.last = next
+				w.notify()
+			}
+		}
+	}
+}
+
+// Returns a channel on which a value is sent when the watched files change, and a function which cancels the
+// subscription. Changes are coalesced, so a slow subscriber receives a single notification for multiple changes.
+func (w *watcher) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	w.mu.Lock()
+	w.subscribers[ch] = true
+	w.mu.Unlock()
+
+	return ch, func() {
+		w.mu.Lock()
+		delete(w.subscribers, ch)
+		w.mu.Unlock()
+	}
+}
+
+// Notifies the subscribers that the watched files changed.
+func (w *watcher) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Returns the current state of the watched files.
+func (w *watcher) states() []fileState {
+	states := make([]fileState, 0, len(w.paths))
+
+	for _, path := range w.paths {
+		states = append(states, statFile(path))
+	}
+
+	return states
+}
+
+// Returns the current state of the file at path.
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return fileState{}
+	}
+
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
@@ -0,0 +1,193 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package allure contains functions for writing .NET test result(s) as Allure results, which can be turned into an
+// Allure report (e.g. with `allure generate`).
+// More information regarding this format can be found @ https://allurereport.org/docs/how-it-works-test-result-file/.
+package allure
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The suffix of the names of the result files.
+const resultSuffix = "-result.json"
+
+// The suffix of the names of the attachments containing the output of a test.
+const outputSuffix = "-attachment.txt"
+
+// The statuses of Allure, by the result of a test.
+var statuses = map[string]string{"Pass": "passed", "Fail": "failed", "Skip": "skipped", "NotRun": "skipped"}
+
+// A result is the content of a result file, which contains the result of a single test.
+type result struct {
+	UUID          string        `json:"uuid"`
+	HistoryID     string        `json:"historyId"`
+	TestCaseID    string        `json:"testCaseId"`
+	FullName      string        `json:"fullName"`
+	Name          string        `json:"name"`
+	Status        string        `json:"status"`
+	StatusDetails statusDetails `json:"statusDetails"`
+	Stage         string        `json:"stage"`
+	Start         int64         `json:"start"`
+	Stop          int64         `json:"stop"`
+	Labels        []label       `json:"labels"`
+	Attachments   []attachment  `json:"attachments"`
+}
+
+// The details of the status of a test: the reason it was skipped, or the details of its failure.
+type statusDetails struct {
+	Message string `json:"message,omitempty"`
+	Trace   string `json:"trace,omitempty"`
+}
+
+// A label of a test, which Allure uses to group the tests (e.g. in suites).
+type label struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// A file which is attached to a test.
+type attachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// Write writes testRun to dir as Allure results: a result file for each test, and an attachment with the output of
+// each test which wrote output. The directory is created if it doesn't exist.
+// The tests of an assembly are assumed to run one after the other (from the time the assembly started running, if
+// known), and the failures which aren't assertion failures are reported as broken tests. The IDs of the results are
+// derived from the tests, so writing the same test run twice replaces the results.
+func Write(dir string, testRun xunit.TestRun) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var err error
+
+	for _, assembly := range testRun.Assemblies {
+		start := assembly.StartTime
+		idx := 0
+
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			if err != nil {
+				return
+			}
+
+			res := newResult(testRun, assembly.Name, path, tc, idx, start)
+			start, idx = start.Add(tc.Duration), idx+1
+
+			if tc.Output != "" {
+				source := res.UUID + outputSuffix
+				res.Attachments = append(res.Attachments, attachment{Name: "Output", Source: source, Type: "text/plain"})
+
+				if err = os.WriteFile(filepath.Join(dir, source), []byte(tc.Output), 0o644); err != nil {
+					return
+				}
+			}
+
+			err = writeResult(filepath.Join(dir, res.UUID+resultSuffix), res)
+		})
+
+		if err != nil {
+			return fmt.Errorf("allure: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the result of tc, the test with the given index in the assembly with the given name (and in the given
+// path), which started running at start.
+func newResult(testRun xunit.TestRun, assemblyName string, path []string, tc xunit.TestCase, idx int,
+	start time.Time,
+) result {
+	key := assemblyName + "/" + tc.RawName
+	idHash, keyHash := sha1.Sum([]byte(fmt.Sprintf("%s#%d", key, idx))), md5.Sum([]byte(key))
+	res := result{
+		UUID:        fmt.Sprintf("%x-%x-%x-%x-%x", idHash[0:4], idHash[4:6], idHash[6:8], idHash[8:10], idHash[10:16]),
+		HistoryID:   hex.EncodeToString(keyHash[:]),
+		TestCaseID:  hex.EncodeToString(keyHash[:]),
+		FullName:    tc.RawName,
+		Name:        tc.Name,
+		Status:      statuses[tc.Result],
+		Stage:       "finished",
+		Labels:      []label{{Name: "parentSuite", Value: assemblyName}},
+		Attachments: make([]attachment, 0),
+	}
+
+	if !start.IsZero() {
+		res.Start, res.Stop = start.UnixMilli(), start.Add(tc.Duration).UnixMilli()
+	} else {
+		res.Stop = tc.Duration.Milliseconds()
+	}
+
+	if res.Status == "" {
+		res.Status = "unknown"
+	}
+
+	if len(path) > 0 {
+		res.Labels = append(res.Labels, label{Name: "suite", Value: strings.Join(path, ".")})
+	}
+
+	if testRun.Computer != "" {
+		res.Labels = append(res.Labels, label{Name: "host", Value: testRun.Computer})
+	}
+
+	switch tc.Result {
+	case "Fail":
+		res.StatusDetails = statusDetails{Message: tc.Failure.Message, Trace: tc.Failure.StackTrace}
+
+		if !tc.Failure.IsAssertion() {
+			res.Status = "broken"
+		}
+	case "Skip", "NotRun":
+		res.StatusDetails = statusDetails{Message: tc.Reason}
+	}
+
+	return res
+}
+
+// Writes res to the file at path.
+func writeResult(path string, res result) error {
+	data, err := json.Marshal(res)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
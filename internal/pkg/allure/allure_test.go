@@ -0,0 +1,121 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "allure" package.
+package allure_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/allure"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Write a test run as Allure results.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies computer=\"WIN11\">\n" +
+		"  <assembly name=\"~/App.dll\" start-rtf=\"2023-07-10T20:53:19.0000000+00:00\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.Calc+Math.Adds\" type=\"NS.Calc+Math\" result=\"Pass\" time=\"0.5\">\n" +
+		"        <output>Adding</output>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Calc+Math.Divides\" type=\"NS.Calc+Math\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n" +
+		"          <message>Expected: 1</message>\n" +
+		"          <stack-trace>at Divides()</stack-trace>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Calc+Math.Parses\" type=\"NS.Calc+Math\" result=\"Fail\" time=\"0\">\n" +
+		"        <failure exception-type=\"System.NullReferenceException\" />\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Calc+Math.Skips\" type=\"NS.Calc+Math\" result=\"Skip\" time=\"0\">\n" +
+		"        <reason>Not yet</reason>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	dir := filepath.Join(t.TempDir(), "allure-results")
+
+	// ACT.
+	err := allure.Write(dir, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Write()")
+
+	type label struct{ Name, Value string }
+	type result struct {
+		UUID, HistoryID, FullName, Status string
+		StatusDetails                     struct{ Message, Trace string }
+		Start, Stop                       int64
+		Labels                            []label
+		Attachments                       []struct{ Source string }
+	}
+
+	results := make(map[string]result)
+	outputs := make([]string, 0)
+	entries, _ := os.ReadDir(dir)
+
+	for _, entry := range entries {
+		data, _ := os.ReadFile(filepath.Join(dir, entry.Name()))
+
+		if !strings.HasSuffix(entry.Name(), "-result.json") {
+			outputs = append(outputs, string(data))
+
+			continue
+		}
+
+		var res result
+
+		assert.NoError(t, json.Unmarshal(data, &res), "Unmarshal()")
+		results[res.FullName] = res
+	}
+
+	adds, divides := results["NS.Calc+Math.Adds"], results["NS.Calc+Math.Divides"]
+	got := []any{
+		len(results), outputs, adds.Status, adds.Start, adds.Stop, adds.Labels, divides.Status, divides.StatusDetails,
+		divides.Start, results["NS.Calc+Math.Parses"].Status, results["NS.Calc+Math.Skips"].StatusDetails.Message,
+		len(adds.Attachments) == 1 && adds.Attachments[0].Source == adds.UUID+"-attachment.txt",
+		adds.HistoryID != divides.HistoryID,
+	}
+	want := []any{
+		4, []string{"Adding"}, "passed", int64(1689022399000), int64(1689022399500),
+		[]label{{Name: "parentSuite", Value: "App.dll"}, {Name: "suite", Value: "Calc.Math"}, {Name: "host", Value: "WIN11"}},
+		"failed", struct{ Message, Trace string }{Message: "Expected: 1", Trace: "at Divides()"}, int64(1689022399500),
+		"broken", "Not yet", true, true,
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Write a test run as Allure results.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
@@ -0,0 +1,63 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package analysis defines functions for analyzing .NET test result(s).
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// SlowTest contains information about a single test, and where it's located in a test run.
+type SlowTest struct {
+	Assembly string         // The name of the assembly the test belongs to.
+	Path     []string       // The names of the groups the test belongs to.
+	Test     xunit.TestCase // The test itself.
+}
+
+// TopSlowest returns the n slowest tests across all the assemblies of run, ordered from the slowest to the fastest.
+// Tests with the same duration keep the order in which they appear in run.
+func TopSlowest(run xunit.TestRun, n int) []SlowTest {
+	if n <= 0 {
+		return make([]SlowTest, 0)
+	}
+
+	resultSet := make([]SlowTest, 0)
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			resultSet = append(resultSet, SlowTest{Assembly: assembly.Name, Path: path, Test: tc})
+		})
+	}
+
+	slices.SortStableFunc(resultSet, func(a, b SlowTest) int {
+		return cmp.Compare(b.Test.Duration, a.Test.Duration)
+	})
+
+	return resultSet[:min(n, len(resultSet))]
+}
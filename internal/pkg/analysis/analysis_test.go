@@ -0,0 +1,176 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Get the N slowest tests of a test run.
+func TestTopSlowest(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"~/App1.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Fast test\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Result\" result=\"Pass\" time=\"3\" />\n" +
+		"      <test name=\"Slow test\" result=\"Fail\" time=\"2\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"  <assembly name=\"~/App2.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Other test\" result=\"Pass\" time=\"1\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	run, err := xunit.Load(strings.NewReader(xmlData))
+
+	assert.NoError(t, err, "", "\n\n"+
+		"UT Name:    Get the N slowest tests of a test run.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	for _, tc := range []struct {
+		n    int
+		want []analysis.SlowTest
+	}{
+		{
+			n:    0,
+			want: []analysis.SlowTest{},
+		},
+		{
+			n: 2,
+			want: []analysis.SlowTest{
+				{
+					Assembly: "App1.dll",
+					Path:     []string{"TestClass", "Method"},
+					Test: xunit.TestCase{
+						Name:     "NS.TestClass+Method.Result",
+						RawName:  "NS.TestClass+Method.Result",
+						Result:   "Pass",
+						Duration: 3 * time.Second,
+						Index:    2,
+					},
+				},
+				{
+					Assembly: "App1.dll",
+					Path:     []string{"Category - Unit"},
+					Test: xunit.TestCase{
+						Name:     "Slow test",
+						RawName:  "Slow test",
+						Result:   "Fail",
+						Duration: 2 * time.Second,
+						Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
+						Index:    3,
+					},
+				},
+			},
+		},
+		{
+			n: 10,
+			want: []analysis.SlowTest{
+				{
+					Assembly: "App1.dll",
+					Path:     []string{"TestClass", "Method"},
+					Test: xunit.TestCase{
+						Name:     "NS.TestClass+Method.Result",
+						RawName:  "NS.TestClass+Method.Result",
+						Result:   "Pass",
+						Duration: 3 * time.Second,
+						Index:    2,
+					},
+				},
+				{
+					Assembly: "App1.dll",
+					Path:     []string{"Category - Unit"},
+					Test: xunit.TestCase{
+						Name:     "Slow test",
+						RawName:  "Slow test",
+						Result:   "Fail",
+						Duration: 2 * time.Second,
+						Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
+						Index:    3,
+					},
+				},
+				{
+					Assembly: "App2.dll",
+					Test: xunit.TestCase{
+						Name:     "Other test",
+						RawName:  "Other test",
+						Result:   "Pass",
+						Duration: time.Second,
+						Index:    1,
+					},
+				},
+				{
+					Assembly: "App1.dll",
+					Test: xunit.TestCase{
+						Name:     "Fast test",
+						RawName:  "Fast test",
+						Result:   "Pass",
+						Duration: 500 * time.Millisecond,
+						Index:    1,
+					},
+				},
+			},
+		},
+	} {
+		// HELPER FUNCTIONS.
+		fmtValue := func(v []analysis.SlowTest) string {
+			b, _ := json.MarshalIndent(v, "", "  ")
+
+			return strings.Replace(string(b), "\n", "\n            ", -1)
+		}
+
+		// ACT.
+		got := analysis.TopSlowest(run, tc.n)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []analysis.SlowTest) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Get the N slowest tests of a test run.\n"+
+			"Input:      %d\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.n, fmtValue(tc.want), fmtValue(got))
+	}
+}
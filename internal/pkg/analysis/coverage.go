@@ -0,0 +1,219 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The suffixes of the names of the source files containing tests (e.g. CalculatorTests.cs for Calculator.cs).
+var testFileSuffixes = []string{"tests", "test", "specs", "spec"}
+
+// CoveredFile contains information about a source file of a coverage report, and the tests which exercise it.
+type CoveredFile struct {
+	Assembly string        // The name of the assembly (in the coverage report) the source file belongs to.
+	File     coverage.File // The source file itself.
+	Tests    []LocatedTest // The tests which exercise the source file, in the order in which they appear in the run.
+	Failed   int           // The number of tests in Tests which failed.
+}
+
+// LocatedTest contains information about a single test, and where it's located in a test run.
+type LocatedTest struct {
+	Assembly string         // The name of the assembly the test belongs to.
+	Path     []string       // The names of the groups the test belongs to.
+	Test     xunit.TestCase // The test itself.
+}
+
+// CoverageDiff contains the changes of the code coverage of a test run, compared to a baseline run.
+type CoverageDiff struct {
+	Line   CoverageChange     // The change of the line coverage of all the source files.
+	Branch CoverageChange     // The change of the branch coverage of all the source files.
+	Files  []FileCoverageDiff // The source files of which the coverage changed.
+}
+
+// FileCoverageDiff contains the changes of the code coverage of a single source file.
+type FileCoverageDiff struct {
+	Assembly string         // The name of the assembly the source file belongs to.
+	Path     string         // The path of the source file.
+	Line     CoverageChange // The change of the line coverage of the source file.
+	Branch   CoverageChange // The change of the branch coverage of the source file.
+}
+
+// CoverageChange contains the code coverage (of lines or branches) in a baseline run, and in the current run.
+type CoverageChange struct {
+	Before coverage.Counter // The coverage in the baseline run.
+	After  coverage.Counter // The coverage in the current run.
+}
+
+// Delta returns the change of the coverage rate, in percentage points (e.g. -2.5 if the coverage dropped from 80% to
+// 77.5%).
+func (c CoverageChange) Delta() float64 {
+	return c.After.Rate() - c.Before.Rate()
+}
+
+// Dropped returns true if the line or the branch coverage of f is lower than in the baseline run.
+func (f FileCoverageDiff) Dropped() bool {
+	return f.Line.Delta() < 0 || f.Branch.Delta() < 0
+}
+
+// DiffCoverage returns the changes of the code coverage in report, compared to the coverage of baseline.
+// The source files are compared when they're part of both runs (matching the assembly and the path), and only the
+// source files of which the coverage changed are returned: the files with the largest drop of their line coverage
+// first, followed by the files with the largest drop of their branch coverage.
+func DiffCoverage(report coverage.Report, baseline history.Run) CoverageDiff {
+	type fileKey struct{ assembly, path string }
+
+	before := make(map[fileKey]history.FileCoverage, len(baseline.Coverage))
+	diff := CoverageDiff{Line: CoverageChange{After: report.LineCoverage()}, Branch: CoverageChange{
+		After: report.BranchCoverage(),
+	}}
+
+	for _, f := range baseline.Coverage {
+		before[fileKey{assembly: f.Assembly, path: f.Path}] = f
+		diff.Line.Before = diff.Line.Before.Add(coverage.Counter{Covered: f.CoveredLines, Total: f.Lines})
+		diff.Branch.Before = diff.Branch.Before.Add(coverage.Counter{Covered: f.CoveredBranches, Total: f.Branches})
+	}
+
+	for _, a := range report.Assemblies {
+		for _, f := range a.Files {
+			old, ok := before[fileKey{assembly: a.Name, path: f.Path}]
+
+			if !ok {
+				continue
+			}
+
+			file := FileCoverageDiff{
+				Assembly: a.Name,
+				Path:     f.Path,
+				Line:     CoverageChange{Before: coverage.Counter{Covered: old.CoveredLines, Total: old.Lines}},
+				Branch:   CoverageChange{Before: coverage.Counter{Covered: old.CoveredBranches, Total: old.Branches}},
+			}
+			file.Line.After, file.Branch.After = f.LineCoverage(), f.BranchCoverage()
+
+			if file.Line.Delta() != 0 || file.Branch.Delta() != 0 {
+				diff.Files = append(diff.Files, file)
+			}
+		}
+	}
+
+	slices.SortStableFunc(diff.Files, func(a, b FileCoverageDiff) int {
+		if c := cmp.Compare(a.Line.Delta(), b.Line.Delta()); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Branch.Delta(), b.Branch.Delta())
+	})
+
+	return diff
+}
+
+// CorrelateCoverage returns the source files of report which are exercised by the tests of run, with the files
+// exercised by failed tests first (ordered by the number of failed tests), followed by the other files in the order
+// in which they appear in report.
+// Since the coverage isn't recorded per test, a test exercises a source file when the source file of the test is
+// that file, or when the name of the file is the name of the source file of the test without its suffix (e.g.
+// Calculator.cs for CalculatorTests.cs). The names are compared case-insensitively, and tests without a source file
+// are ignored.
+func CorrelateCoverage(run xunit.TestRun, report coverage.Report) []CoveredFile {
+	files := make([]CoveredFile, 0)
+	byPath, byName := make(map[string][]int), make(map[string][]int)
+
+	for _, assembly := range report.Assemblies {
+		for _, file := range assembly.Files {
+			idx := len(files)
+			files = append(files, CoveredFile{Assembly: assembly.Name, File: file})
+			byPath[normalizePath(file.Path)] = append(byPath[normalizePath(file.Path)], idx)
+			byName[baseName(file.Path)] = append(byName[baseName(file.Path)], idx)
+		}
+	}
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			if tc.SourceFile == "" {
+				return
+			}
+
+			matches := byPath[normalizePath(tc.SourceFile)]
+
+			if len(matches) == 0 {
+				matches = byName[subjectName(tc.SourceFile)]
+			}
+
+			for _, idx := range matches {
+				files[idx].Tests = append(files[idx].Tests, LocatedTest{Assembly: assembly.Name, Path: path, Test: tc})
+
+				if tc.Result == "Fail" {
+					files[idx].Failed++
+				}
+			}
+		})
+	}
+
+	resultSet := slices.DeleteFunc(files, func(f CoveredFile) bool { return len(f.Tests) == 0 })
+
+	slices.SortStableFunc(resultSet, func(a, b CoveredFile) int {
+		return cmp.Compare(b.Failed, a.Failed)
+	})
+
+	return resultSet
+}
+
+// Returns path with forward slashes, in lower case.
+func normalizePath(path string) string {
+	return strings.ToLower(strings.ReplaceAll(path, `\`, "/"))
+}
+
+// Returns the name of the file at path (on Unix or Windows), without its extension, in lower case.
+func baseName(path string) string {
+	name := normalizePath(path)
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	if idx := strings.LastIndex(name, "."); idx > 0 {
+		name = name[:idx]
+	}
+
+	return name
+}
+
+// Returns the name of the source file which is tested by the tests in the file at path (e.g. "calculator" for
+// CalculatorTests.cs).
+func subjectName(path string) string {
+	name := baseName(path)
+
+	for _, suffix := range testFileSuffixes {
+		if subject, ok := strings.CutSuffix(name, suffix); ok && subject != "" {
+			return strings.TrimRight(subject, "._-")
+		}
+	}
+
+	return name
+}
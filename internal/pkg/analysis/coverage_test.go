@@ -0,0 +1,168 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Correlate the tests of a test run with the source files they exercise.
+func TestCorrelateCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	run, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.Tests.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Adds\" result=\"Pass\" source-file=\"/src/App.Tests/CalculatorTests.cs\" />\n" +
+		"      <test name=\"Subtracts\" result=\"Fail\" source-file=\"/src/App.Tests/CalculatorTests.cs\" />\n" +
+		"      <test name=\"Parses\" result=\"Pass\" source-file=\"C:\\src\\App.Tests\\Parser.Tests.cs\" />\n" +
+		"      <test name=\"Helps\" result=\"Fail\" source-file=\"/src/App/Helper.cs\" />\n" +
+		"      <test name=\"Unknown\" result=\"Fail\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	report := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{
+			{Path: "/src/App/Logger.cs"},
+			{Path: "C:\\src\\App\\parser.cs"},
+			{Path: "/src/App/Calculator.cs"},
+			{Path: "/src/App/Helper.cs"},
+		}},
+	}}
+
+	// ACT.
+	files := analysis.CorrelateCoverage(run, report)
+
+	// ASSERT.
+	got := make([]string, 0, len(files))
+
+	for _, file := range files {
+		names := make([]string, 0, len(file.Tests))
+
+		for _, test := range file.Tests {
+			names = append(names, test.Test.Name)
+		}
+
+		got = append(got, file.File.Path+": "+strings.Join(names, ", "))
+	}
+
+	want := []string{
+		"/src/App/Calculator.cs: Adds, Subtracts",
+		"/src/App/Helper.cs: Helps",
+		"C:\\src\\App\\parser.cs: Parses",
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Correlate the tests of a test run with the source files they exercise.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+
+	assert.Equal(t, files[0].Failed, 1, "", "\n\n"+
+		"UT Name:    Correlate the tests of a test run with the source files they exercise.\n"+
+		"\033[32mExpected:   1 failed test\033[0m\n"+
+		"\033[31mActual:     %d failed test(s)\033[0m\n\n", files[0].Failed)
+}
+
+// UT: Compare the code coverage of a test run with the coverage of a baseline run.
+func TestDiffCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	report := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{
+			{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {Hits: 1}}},
+			{Path: "B.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {}}},
+			{Path: "C.cs", Lines: map[int]coverage.Line{1: {Hits: 1, Branches: 2, CoveredBranches: 1}}},
+			{Path: "D.cs", Lines: map[int]coverage.Line{1: {}}},
+			{Path: "New.cs", Lines: map[int]coverage.Line{1: {}}},
+		}},
+	}}
+	baseline := history.Run{Coverage: []history.FileCoverage{
+		{Assembly: "App", Path: "A.cs", Lines: 2, CoveredLines: 1},
+		{Assembly: "App", Path: "B.cs", Lines: 2, CoveredLines: 2},
+		{Assembly: "App", Path: "C.cs", Lines: 1, CoveredLines: 1, Branches: 2, CoveredBranches: 2},
+		{Assembly: "App", Path: "D.cs", Lines: 1},
+		{Assembly: "App", Path: "Removed.cs", Lines: 4, CoveredLines: 4},
+	}}
+
+	// ACT.
+	got := analysis.DiffCoverage(report, baseline)
+
+	// ASSERT.
+	want := analysis.CoverageDiff{
+		Line: analysis.CoverageChange{
+			Before: coverage.Counter{Covered: 8, Total: 10}, After: coverage.Counter{Covered: 4, Total: 7},
+		},
+		Branch: analysis.CoverageChange{
+			Before: coverage.Counter{Covered: 2, Total: 2}, After: coverage.Counter{Covered: 1, Total: 2},
+		},
+		Files: []analysis.FileCoverageDiff{
+			{
+				Assembly: "App", Path: "B.cs",
+				Line: analysis.CoverageChange{Before: coverage.Counter{Covered: 2, Total: 2}, After: coverage.Counter{
+					Covered: 1, Total: 2,
+				}},
+			},
+			{
+				Assembly: "App", Path: "C.cs",
+				Line: analysis.CoverageChange{Before: coverage.Counter{Covered: 1, Total: 1}, After: coverage.Counter{
+					Covered: 1, Total: 1,
+				}},
+				Branch: analysis.CoverageChange{Before: coverage.Counter{Covered: 2, Total: 2}, After: coverage.Counter{
+					Covered: 1, Total: 2,
+				}},
+			},
+			{
+				Assembly: "App", Path: "A.cs",
+				Line: analysis.CoverageChange{Before: coverage.Counter{Covered: 1, Total: 2}, After: coverage.Counter{
+					Covered: 2, Total: 2,
+				}},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Compare the code coverage of a test run with the coverage of a baseline run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+
+	dropped := []bool{got.Files[0].Dropped(), got.Files[1].Dropped(), got.Files[2].Dropped()}
+	wantDropped := []bool{true, true, false}
+
+	assert.DeepEqual(t, dropped, wantDropped, "", "\n\n"+
+		"UT Name:    Compare the code coverage of a test run with the coverage of a baseline run.\n"+
+		"\033[32mExpected:   Dropped %v\033[0m\n"+
+		"\033[31mActual:     Dropped %v\033[0m\n\n", wantDropped, dropped)
+}
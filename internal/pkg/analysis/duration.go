@@ -0,0 +1,117 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// DurationRegression contains information about a test which is significantly slower than it used to be.
+type DurationRegression struct {
+	Assembly string         // The name of the assembly the test belongs to.
+	Path     []string       // The names of the groups the test belongs to.
+	Test     xunit.TestCase // The test itself.
+	Median   time.Duration  // The median duration of the test in the past runs.
+	Factor   float64        // The duration of the test, relative to its median duration.
+}
+
+// Threshold contains the limits above which a test is considered to be significantly slower.
+type Threshold struct {
+	Factor  float64       // The minimum duration of a test, relative to its median duration (e.g. 2 for twice as slow).
+	Minimum time.Duration // The minimum increase of the duration, which avoids reporting noise on fast tests.
+}
+
+// DurationRegressions returns the executed tests of run which are slower than their median duration in past, beyond
+// threshold, ordered from the largest to the smallest factor.
+// Only the runs in past in which a test was executed (passed or failed) count towards its median duration.
+func DurationRegressions(run xunit.TestRun, past []history.Run, threshold Threshold) []DurationRegression {
+	durations := make(map[testKey][]time.Duration)
+
+	for _, pastRun := range past {
+		for _, test := range pastRun.Tests {
+			if test.Result == "Pass" || test.Result == "Fail" {
+				key := testKey{assembly: test.Assembly, name: test.Name}
+				durations[key] = append(durations[key], test.Duration)
+			}
+		}
+	}
+
+	resultSet := make([]DurationRegression, 0)
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			if tc.Result != "Pass" && tc.Result != "Fail" {
+				return
+			}
+
+			testDurations, ok := durations[testKey{assembly: assembly.Name, name: tc.RawName}]
+
+			if !ok {
+				return
+			}
+
+			med := median(testDurations)
+
+			if tc.Duration <= med || tc.Duration-med < threshold.Minimum ||
+				float64(tc.Duration) < float64(med)*threshold.Factor {
+				return
+			}
+
+			factor := float64(tc.Duration) / float64(max(med, 1))
+
+			resultSet = append(resultSet, DurationRegression{
+				Assembly: assembly.Name,
+				Path:     path,
+				Test:     tc,
+				Median:   med,
+				Factor:   factor,
+			})
+		})
+	}
+
+	slices.SortStableFunc(resultSet, func(a, b DurationRegression) int {
+		return cmp.Compare(b.Factor, a.Factor)
+	})
+
+	return resultSet
+}
+
+// Returns the median of durations, which must not be empty.
+func median(durations []time.Duration) time.Duration {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+
+	return (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+}
@@ -0,0 +1,104 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Get the tests which are slower than their median duration.
+func TestDurationRegressions(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	run, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Twice as slow\" result=\"Pass\" time=\"2\" />\n" +
+		"      <test name=\"Four times as slow\" result=\"Fail\" time=\"4\" />\n" +
+		"      <test name=\"Fast test\" result=\"Pass\" time=\"0.004\" />\n" +
+		"      <test name=\"Unchanged\" result=\"Pass\" time=\"1\" />\n" +
+		"      <test name=\"Skipped\" result=\"Skip\" time=\"4\" />\n" +
+		"      <test name=\"New test\" result=\"Pass\" time=\"4\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	// Returns the results of the tests in a past run, in which the slow tests took the given duration.
+	pastTests := func(d time.Duration, result string) []history.Test {
+		return []history.Test{
+			{Assembly: "App.dll", Name: "Twice as slow", Result: result, Duration: d},
+			{Assembly: "App.dll", Name: "Four times as slow", Result: result, Duration: d},
+			{Assembly: "App.dll", Name: "Unchanged", Result: result, Duration: d},
+			{Assembly: "App.dll", Name: "Skipped", Result: result, Duration: d},
+			{Assembly: "App.dll", Name: "Fast test", Result: result, Duration: time.Millisecond},
+		}
+	}
+
+	past := []history.Run{
+		{Tests: pastTests(500*time.Millisecond, "Pass")},
+		{Tests: pastTests(time.Second, "Pass")},
+		{Tests: pastTests(1500*time.Millisecond, "Fail")},
+		{Tests: pastTests(time.Hour, "Skip")},
+	}
+
+	// ACT.
+	got := analysis.DurationRegressions(run, past, analysis.Threshold{Factor: 2, Minimum: 100 * time.Millisecond})
+
+	// ASSERT.
+	type regression struct {
+		Name   string
+		Median time.Duration
+		Factor float64
+	}
+
+	gotRegressions := make([]regression, 0, len(got))
+
+	for _, r := range got {
+		gotRegressions = append(gotRegressions, regression{Name: r.Test.Name, Median: r.Median, Factor: r.Factor})
+	}
+
+	want := []regression{
+		{Name: "Four times as slow", Median: time.Second, Factor: 4},
+		{Name: "Twice as slow", Median: time.Second, Factor: 2},
+	}
+
+	assert.EqualFn(t, gotRegressions, want, func(got, want []regression) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Get the tests which are slower than their median duration.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, gotRegressions)
+}
@@ -0,0 +1,138 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// FlakyTest contains information about a test whose result flips between runs of the same revision.
+type FlakyTest struct {
+	Assembly string   // The name of the assembly the test belongs to.
+	Name     string   // The name of the test.
+	Flips    int      // The number of times the result changed between consecutive runs of the same revision.
+	Score    float64  // The fraction of the consecutive runs of the same revision in which the result changed (0-1).
+	Results  []string // The results of the test in the last runs, from the oldest to the newest (e.g. for a sparkline).
+	Traits   []string // The traits of the test in the newest run containing it (e.g. "Category - Unit").
+}
+
+// The key identifying a test across runs.
+type testKey struct {
+	assembly string
+	name     string
+}
+
+// The results of a test across runs, used to compute its flakiness.
+type flakyState struct {
+	last        map[string]string // The last executed result of the test, by revision.
+	flips       int               // The number of times the result changed between runs of the same revision.
+	transitions int               // The number of consecutive runs of the same revision which executed the test.
+	results     []string          // The results of the test in all the runs.
+	traits      []string          // The traits of the test in the newest run.
+}
+
+// Flaky returns the tests whose result flips between runs of the same commit (or branch, for runs without a commit),
+// ordered from the most to the least flaky. The runs must be ordered from the oldest to the newest. The results of
+// each test are limited to the last n runs.
+// Only executed tests (tests which passed or failed) are taken into account.
+func Flaky(runs []history.Run, n int) []FlakyTest {
+	states := make(map[testKey]*flakyState)
+
+	for _, run := range runs {
+		revision := "commit:" + run.Commit
+
+		if run.Commit == "" {
+			revision = "branch:" + run.Branch
+		}
+
+		for _, test := range run.Tests {
+			key := testKey{assembly: test.Assembly, name: test.Name}
+			state, ok := states[key]
+
+			if !ok {
+				state = &flakyState{last: make(map[string]string)}
+				states[key] = state
+			}
+
+			state.results = append(state.results, test.Result)
+			state.traits = test.Traits
+
+			if test.Result != "Pass" && test.Result != "Fail" {
+				continue
+			}
+
+			if last, ok := state.last[revision]; ok {
+				state.transitions++
+
+				if last != test.Result {
+					state.flips++
+				}
+			}
+
+			state.last[revision] = test.Result
+		}
+	}
+
+	resultSet := make([]FlakyTest, 0)
+
+	for key, state := range states {
+		if state.flips == 0 {
+			continue
+		}
+
+		keep := min(max(n, 0), len(state.results))
+
+		resultSet = append(resultSet, FlakyTest{
+			Assembly: key.assembly,
+			Name:     key.name,
+			Flips:    state.flips,
+			Score:    float64(state.flips) / float64(state.transitions),
+			Results:  state.results[len(state.results)-keep:],
+			Traits:   state.traits,
+		})
+	}
+
+	slices.SortFunc(resultSet, func(a, b FlakyTest) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(b.Flips, a.Flips); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(a.Assembly, b.Assembly); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return resultSet
+}
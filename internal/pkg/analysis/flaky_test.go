@@ -0,0 +1,121 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// UT: Get the flaky tests across runs.
+func TestFlaky(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Returns a run of commit, in which the tests have the given results.
+	newRun := func(commit, branch string, results map[string]string) history.Run {
+		run := history.Run{Commit: commit, Branch: branch}
+
+		for _, name := range []string{"A", "B", "C", "D"} {
+			if result, ok := results[name]; ok {
+				run.Tests = append(run.Tests, history.Test{Assembly: "App.dll", Name: name, Result: result})
+			}
+		}
+
+		return run
+	}
+
+	for _, tc := range []struct {
+		runs []history.Run
+		n    int
+		want []analysis.FlakyTest
+	}{
+		{
+			runs: []history.Run{},
+			n:    5,
+			want: []analysis.FlakyTest{},
+		},
+		{
+			runs: []history.Run{
+				newRun("c1", "main", map[string]string{"A": "Pass", "B": "Pass", "C": "Pass", "D": "Pass"}),
+				newRun("c1", "main", map[string]string{"A": "Fail", "B": "Pass", "C": "Skip", "D": "Pass"}),
+				newRun("c2", "main", map[string]string{"A": "Pass", "B": "Fail", "C": "Pass", "D": "Fail"}),
+				newRun("c2", "main", map[string]string{"A": "Pass", "B": "Pass", "C": "Pass", "D": "Fail"}),
+			},
+			n: 3,
+			want: []analysis.FlakyTest{
+				{Assembly: "App.dll", Name: "A", Flips: 1, Score: 0.5, Results: []string{"Fail", "Pass", "Pass"}},
+				{Assembly: "App.dll", Name: "B", Flips: 1, Score: 0.5, Results: []string{"Pass", "Fail", "Pass"}},
+			},
+		},
+		{
+			runs: []history.Run{
+				newRun("", "main", map[string]string{"A": "Pass"}),
+				newRun("", "feature", map[string]string{"A": "Fail"}),
+				newRun("", "main", map[string]string{"A": "Fail"}),
+			},
+			n: 0,
+			want: []analysis.FlakyTest{
+				{Assembly: "App.dll", Name: "A", Flips: 1, Score: 1, Results: []string{}},
+			},
+		},
+		{
+			runs: []history.Run{
+				{Commit: "c1", Tests: []history.Test{{Assembly: "App.dll", Name: "A", Result: "Pass"}}},
+				{Commit: "c1", Tests: []history.Test{
+					{Assembly: "App.dll", Name: "A", Result: "Fail", Traits: []string{"Category - Unit"}},
+				}},
+			},
+			n: 2,
+			want: []analysis.FlakyTest{
+				{
+					Assembly: "App.dll",
+					Name:     "A",
+					Flips:    1,
+					Score:    1,
+					Results:  []string{"Pass", "Fail"},
+					Traits:   []string{"Category - Unit"},
+				},
+			},
+		},
+	} {
+		// ACT.
+		got := analysis.Flaky(tc.runs, tc.n)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []analysis.FlakyTest) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Get the flaky tests across runs.\n"+
+			"Input:      %v (n: %d)\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.runs, tc.n, tc.want, got)
+	}
+}
@@ -0,0 +1,138 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package analysis
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// DurationPercentiles contains the distribution of the durations of the executed tests of a test run.
+type DurationPercentiles struct {
+	Count int           // The number of executed (passed or failed) tests.
+	P50   time.Duration // The median duration.
+	P90   time.Duration // The duration which 90% of the tests don't exceed.
+	P95   time.Duration // The duration which 95% of the tests don't exceed.
+	P99   time.Duration // The duration which 99% of the tests don't exceed.
+	Max   time.Duration // The duration of the slowest test.
+}
+
+// TraitCounts contains the number of tests with a trait, per result.
+type TraitCounts struct {
+	Trait   string // The friendly name of the trait (e.g. "Category - Unit").
+	Total   int    // The number of tests with the trait.
+	Passed  int    // The number of tests with the trait which passed.
+	Failed  int    // The number of tests with the trait which failed.
+	Skipped int    // The number of tests with the trait which were skipped.
+	NotRun  int    // The number of tests with the trait which weren't run.
+}
+
+// Percentiles returns the distribution of the durations of the executed (passed or failed) tests of run, using the
+// nearest-rank method. All the durations are 0 if no test was executed.
+func Percentiles(run xunit.TestRun) DurationPercentiles {
+	durations := make([]time.Duration, 0)
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			if tc.Result == "Pass" || tc.Result == "Fail" {
+				durations = append(durations, tc.Duration)
+			}
+		})
+	}
+
+	if len(durations) == 0 {
+		return DurationPercentiles{}
+	}
+
+	slices.Sort(durations)
+
+	rank := func(p float64) time.Duration {
+		return durations[max(int(math.Ceil(p/100*float64(len(durations))))-1, 0)]
+	}
+
+	return DurationPercentiles{
+		Count: len(durations),
+		P50:   rank(50),
+		P90:   rank(90),
+		P95:   rank(95),
+		P99:   rank(99),
+		Max:   durations[len(durations)-1],
+	}
+}
+
+// CountByTrait returns the number of tests of run with each trait, per result, ordered by trait.
+// A test with multiple traits is counted for each of them, and the tests without a trait aren't counted.
+func CountByTrait(run xunit.TestRun) []TraitCounts {
+	byTrait := make(map[string]*TraitCounts)
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			for _, trait := range tc.Traits {
+				name := trait.String()
+				c, ok := byTrait[name]
+
+				if !ok {
+					c = &TraitCounts{Trait: name}
+					byTrait[name] = c
+				}
+
+				c.add(tc.Result)
+			}
+		})
+	}
+
+	resultSet := make([]TraitCounts, 0, len(byTrait))
+
+	for _, c := range byTrait {
+		resultSet = append(resultSet, *c)
+	}
+
+	slices.SortFunc(resultSet, func(a, b TraitCounts) int {
+		return cmp.Compare(a.Trait, b.Trait)
+	})
+
+	return resultSet
+}
+
+// Adds a test with the given result to c.
+func (c *TraitCounts) add(result string) {
+	c.Total++
+
+	switch result {
+	case "Pass":
+		c.Passed++
+	case "Fail":
+		c.Failed++
+	case "Skip":
+		c.Skipped++
+	case "NotRun":
+		c.NotRun++
+	}
+}
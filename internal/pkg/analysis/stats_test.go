@@ -0,0 +1,133 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Get the distribution of the durations of the tests of a test run.
+func TestPercentiles(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    analysis.DurationPercentiles
+	}{
+		{
+			xmlData: "<assemblies><assembly name=\"~/App.dll\"><collection>" +
+				"<test name=\"Skipped\" result=\"Skip\" time=\"9\" />" +
+				"</collection></assembly></assemblies>",
+			want: analysis.DurationPercentiles{},
+		},
+		{
+			xmlData: "<assemblies><assembly name=\"~/App.dll\"><collection>" +
+				"<test name=\"T1\" result=\"Pass\" time=\"1\" />" +
+				"<test name=\"T2\" result=\"Fail\" time=\"0.125\" />" +
+				"<test name=\"T3\" result=\"Pass\" time=\"0.5\" />" +
+				"<test name=\"T4\" result=\"Skip\" time=\"9\" />" +
+				"<test name=\"T5\" result=\"Pass\" time=\"0.25\">" +
+				"<traits><trait name=\"Category\" value=\"A\" /><trait name=\"Category\" value=\"B\" /></traits>" +
+				"</test>" +
+				"</collection></assembly></assemblies>",
+			want: analysis.DurationPercentiles{
+				Count: 4,
+				P50:   250 * time.Millisecond,
+				P90:   time.Second,
+				P95:   time.Second,
+				P99:   time.Second,
+				Max:   time.Second,
+			},
+		},
+	} {
+		// ARRANGE.
+		run, _ := xunit.Load(strings.NewReader(tc.xmlData))
+
+		// ACT.
+		got := analysis.Percentiles(run)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the distribution of the durations of the tests of a test run.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.xmlData, tc.want, got)
+	}
+}
+
+// UT: Count the tests of a test run with each trait.
+func TestCountByTrait(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"~/App1.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Without traits\" result=\"Pass\" time=\"1\" />\n" +
+		"      <test name=\"NS.Calc+Math.Adds\" result=\"Fail\" time=\"1\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /><trait name=\"Timing\" value=\"Slow\" /></traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Calc+Math.Subtracts\" result=\"Skip\" time=\"1\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"  <assembly name=\"~/App2.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Other test\" result=\"Pass\" time=\"1\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+	want := []analysis.TraitCounts{
+		{Trait: "Category - Unit", Total: 3, Passed: 1, Failed: 1, Skipped: 1},
+		{Trait: "Timing - Slow", Total: 1, Failed: 1},
+	}
+
+	// NOTE: The counts don't depend on how the tests are grouped.
+	for _, groupBy := range []xunit.GroupBy{xunit.GroupByTrait, xunit.GroupByNamespace, xunit.GroupByResult} {
+		run, _ := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{GroupBy: groupBy})
+
+		// ACT.
+		got := analysis.CountByTrait(run)
+
+		// ASSERT.
+		assert.Equal(t, reflect.DeepEqual(got, want), true, "", "\n\n"+
+			"UT Name:    Count the tests of a test run with each trait.\n"+
+			"Input:      Grouped by %v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", groupBy, want, got)
+	}
+}
@@ -0,0 +1,72 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package analysis
+
+import (
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/set"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// TriagedFailure contains information about a failed test, and whether it already failed in the baseline run.
+type TriagedFailure struct {
+	Assembly   string         // The name of the assembly the test belongs to.
+	Path       []string       // The names of the groups the test belongs to.
+	Test       xunit.TestCase // The test itself.
+	Regression bool           // True if the test is a new regression, false if it also failed in the baseline run.
+}
+
+// Triage returns the failed tests of run, in the order in which they appear in run.
+// A failed test is a new regression unless it also failed in baseline (tests which aren't part of baseline are new
+// regressions too).
+func Triage(run xunit.TestRun, baseline history.Run) []TriagedFailure {
+	failedBefore := set.New[testKey]()
+
+	for _, test := range baseline.Tests {
+		if test.Result == "Fail" {
+			failedBefore.Add(testKey{assembly: test.Assembly, name: test.Name})
+		}
+	}
+
+	resultSet := make([]TriagedFailure, 0)
+
+	for _, assembly := range run.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			if tc.Result != "Fail" {
+				return
+			}
+
+			resultSet = append(resultSet, TriagedFailure{
+				Assembly:   assembly.Name,
+				Path:       path,
+				Test:       tc,
+				Regression: !failedBefore.Has(testKey{assembly: assembly.Name, name: tc.RawName}),
+			})
+		})
+	}
+
+	return resultSet
+}
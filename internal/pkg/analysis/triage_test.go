@@ -0,0 +1,77 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "analysis" package.
+package analysis_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Triage the failures of a test run against a baseline run.
+func TestTriage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	run, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Already failing\" result=\"Fail\" />\n" +
+		"      <test name=\"Newly failing\" result=\"Fail\" />\n" +
+		"      <test name=\"New test\" result=\"Fail\" />\n" +
+		"      <test name=\"Passing\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	baseline := history.Run{Tests: []history.Test{
+		{Assembly: "App.dll", Name: "Already failing", Result: "Fail"},
+		{Assembly: "App.dll", Name: "Newly failing", Result: "Pass"},
+		{Assembly: "App.dll", Name: "Passing", Result: "Fail"},
+	}}
+
+	// ACT.
+	got := make(map[string]bool)
+
+	for _, failure := range analysis.Triage(run, baseline) {
+		got[failure.Test.Name] = failure.Regression
+	}
+
+	// ASSERT.
+	want := map[string]bool{"Already failing": false, "Newly failing": true, "New test": true}
+
+	assert.EqualFn(t, got, want, func(got, want map[string]bool) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Triage the failures of a test run against a baseline run.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
@@ -0,0 +1,435 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package api defines an HTTP API, which exposes .NET test result(s) as JSON.
+//
+// The API consists of the following endpoints:
+//
+//	GET /api/runs                       The runs (with their statistics).
+//	GET /api/runs/{id}                  A single run (with its statistics).
+//	GET /api/runs/{id}/assemblies       The assemblies of a run.
+//	GET /api/runs/{id}/tests            The tests of a run (filtered with ?result=Fail and/or ?assembly=App.dll).
+//	POST /api/runs?name=results.xml     Upload a result file as a new run (if the handler accepts uploads), optionally
+//	                                    with its project, commit and branch (e.g. &project=api&branch=main).
+//	GET /api/openapi.json               The OpenAPI document describing the API.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The prefix of the path of the endpoints which expose runs.
+const runsPath = "/api/runs"
+
+// The path of the OpenAPI document.
+const specPath = "/api/openapi.json"
+
+// The OpenAPI (3.0) document describing the API.
+//
+//go:embed assets/openapi.json
+var spec []byte
+
+// Run is a test run, as exposed by the API.
+type Run struct {
+	ID      string        // The identifier of the run (unique among the runs returned by a Source).
+	Name    string        // The name of the run (e.g. the name of the file it was loaded from).
+	Project string        // The project (e.g. repository) the run belongs to.
+	Commit  string        // The commit the run tested (if known).
+	Branch  string        // The branch the run tested (if known).
+	TestRun xunit.TestRun // The test run itself.
+
+	// The index the details of the tests of TestRun are loaded from, when they're needed (nil if TestRun contains the
+	// details of its tests).
+	Index *xunit.Index
+}
+
+// WithDetails returns the test run of run, with the details of its tests (which are loaded from its index, if any).
+func (run Run) WithDetails() (xunit.TestRun, error) {
+	if run.Index == nil {
+		return run.TestRun, nil
+	}
+
+	return run.Index.WithDetails(run.TestRun)
+}
+
+// DefaultProject is the name of the project of the runs which don't belong to a project (e.g. on a dashboard).
+const DefaultProject = "default"
+
+// Upload describes a result file which is uploaded to the API.
+type Upload struct {
+	Name    string // The name of the result file.
+	Project string // The project (e.g. repository) the run belongs to.
+	Commit  string // The commit the run tested.
+	Branch  string // The branch the run tested.
+}
+
+// Source returns the runs exposed by the API.
+// It's called on each request, so the API always exposes the latest runs.
+type Source func() ([]Run, error)
+
+// Ingest stores the result file read from r (described by upload) as a new run, and returns it.
+// The run is expected to be returned by the Source of the API from then on.
+// It returns a *TooLargeError if the result file is too large.
+type Ingest func(upload Upload, r io.Reader) (Run, error)
+
+// TooLargeError is returned by an Ingest when the uploaded result file is larger than it accepts, which the API
+// responds to with 413 Request Entity Too Large (instead of 400 Bad Request).
+type TooLargeError struct {
+	Name  string // The name of the result file.
+	Limit int64  // The maximum size (in bytes) of a result file.
+}
+
+// Error returns the message of e.
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("%s: the file is larger than %d bytes", e.Name, e.Limit)
+}
+
+// The representation of a run.
+type runJSON struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Project   string    `json:"project"`
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch"`
+	Computer  string    `json:"computer"`
+	User      string    `json:"user"`
+	Timestamp string    `json:"timestamp"`
+	Stats     statsJSON `json:"stats"`
+}
+
+// The representation of the statistics of a run.
+type statsJSON struct {
+	Assemblies int     `json:"assemblies"`
+	Errors     int     `json:"errors"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	Skipped    int     `json:"skipped"`
+	NotRun     int     `json:"notRun"`
+	Total      int     `json:"total"`
+	PassRate   float64 `json:"passRate"`
+	Duration   float64 `json:"duration"` // In seconds.
+}
+
+// The representation of an assembly.
+type assemblyJSON struct {
+	Name     string  `json:"name"`
+	Errors   int     `json:"errors"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Skipped  int     `json:"skipped"`
+	NotRun   int     `json:"notRun"`
+	Total    int     `json:"total"`
+	Duration float64 `json:"duration"` // In seconds.
+}
+
+// The representation of a test.
+type testJSON struct {
+	ID         string       `json:"id,omitempty"`
+	Assembly   string       `json:"assembly"`
+	Path       []string     `json:"path"`
+	Name       string       `json:"name"`
+	Result     string       `json:"result"`
+	Duration   float64      `json:"duration"` // In seconds.
+	SourceFile string       `json:"sourceFile,omitempty"`
+	SourceLine int          `json:"sourceLine,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+	Failure    *failureJSON `json:"failure,omitempty"`
+}
+
+// The representation of the failure of a test.
+type failureJSON struct {
+	ExceptionType string `json:"exceptionType,omitempty"`
+	Message       string `json:"message,omitempty"`
+	StackTrace    string `json:"stackTrace,omitempty"`
+}
+
+// The representation of an error.
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the handler which serves the API, exposing the runs returned by source.
+// If ingest isn't nil, result files uploaded with POST /api/runs are passed to ingest.
+func Handler(source Source, ingest Ingest) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ingests := ingest != nil && (r.URL.Path == runsPath || r.URL.Path == runsPath+"/")
+
+		if ingests && r.Method == http.MethodPost {
+			query := r.URL.Query()
+			upload := Upload{
+				Name:    query.Get("name"),
+				Project: query.Get("project"),
+				Commit:  query.Get("commit"),
+				Branch:  query.Get("branch"),
+			}
+
+			run, err := ingest(upload, r.Body)
+
+			if err != nil {
+				status := http.StatusBadRequest
+
+				if errors.As(err, new(*TooLargeError)) {
+					status = http.StatusRequestEntityTooLarge
+				}
+
+				writeError(w, status, err.Error())
+
+				return
+			}
+
+			w.Header().Set("Location", runsPath+"/"+url.PathEscape(run.ID))
+			writeJSON(w, http.StatusCreated, newRunJSON(run))
+
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+
+			if ingests {
+				w.Header().Set("Allow", "GET, HEAD, POST")
+			}
+
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+
+			return
+		}
+
+		if r.URL.Path == specPath {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(spec)
+
+			return
+		}
+
+		rest, ok := strings.CutPrefix(r.URL.Path, runsPath)
+
+		if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+			writeError(w, http.StatusNotFound, "not found")
+
+			return
+		}
+
+		runs, err := source()
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+
+			return
+		}
+
+		if rest == "" || rest == "/" {
+			writeJSON(w, http.StatusOK, newRunsJSON(runs))
+
+			return
+		}
+
+		id, resource, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+		run, ok := findRun(runs, id)
+
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown run "+id)
+
+			return
+		}
+
+		switch resource {
+		case "":
+			writeJSON(w, http.StatusOK, newRunJSON(run))
+		case "assemblies":
+			writeJSON(w, http.StatusOK, assemblies(run.TestRun))
+		case "tests":
+			tests, err := tests(run, r.URL.Query().Get("assembly"), r.URL.Query().Get("result"))
+
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+
+				return
+			}
+
+			writeJSON(w, http.StatusOK, tests)
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	})
+}
+
+// Encode writes runs to w as JSON, in the same representation as GET /api/runs.
+func Encode(w io.Writer, runs []Run) error {
+	return newEncoder(w).Encode(newRunsJSON(runs))
+}
+
+// Returns the run with the given ID.
+func findRun(runs []Run, id string) (Run, bool) {
+	for _, run := range runs {
+		if run.ID == id {
+			return run, true
+		}
+	}
+
+	return Run{}, false
+}
+
+// Returns the representation of runs.
+func newRunsJSON(runs []Run) []runJSON {
+	resultSet := make([]runJSON, 0, len(runs))
+
+	for _, run := range runs {
+		resultSet = append(resultSet, newRunJSON(run))
+	}
+
+	return resultSet
+}
+
+// Returns the representation of run.
+func newRunJSON(run Run) runJSON {
+	stats := run.TestRun.Stats()
+
+	return runJSON{
+		ID:        run.ID,
+		Name:      run.Name,
+		Project:   run.Project,
+		Commit:    run.Commit,
+		Branch:    run.Branch,
+		Computer:  run.TestRun.Computer,
+		User:      run.TestRun.User,
+		Timestamp: run.TestRun.Timestamp,
+		Stats: statsJSON{
+			Assemblies: stats.AssemblyCount,
+			Errors:     stats.ErrorCount,
+			Passed:     stats.PassedCount,
+			Failed:     stats.FailedCount,
+			Skipped:    stats.SkippedCount,
+			NotRun:     stats.NotRunCount,
+			Total:      stats.TotalCount,
+			PassRate:   stats.PassRate,
+			Duration:   stats.TotalDuration.Seconds(),
+		},
+	}
+}
+
+// Returns the representation of the assemblies of testRun.
+func assemblies(testRun xunit.TestRun) []assemblyJSON {
+	resultSet := make([]assemblyJSON, 0, len(testRun.Assemblies))
+
+	for _, a := range testRun.Assemblies {
+		resultSet = append(resultSet, assemblyJSON{
+			Name:     a.Name,
+			Errors:   a.ErrorCount,
+			Passed:   a.PassedCount,
+			Failed:   a.FailedCount,
+			Skipped:  a.SkippedCount,
+			NotRun:   a.NotRunCount,
+			Total:    a.TotalCount,
+			Duration: a.Duration.Seconds(),
+		})
+	}
+
+	return resultSet
+}
+
+// Returns the representation of the tests of run, optionally filtered by assembly and result (case-insensitive).
+// The details of the tests are only loaded (from the index of run, if any) for the tests which are returned.
+func tests(run Run, assembly, result string) ([]testJSON, error) {
+	var err error
+
+	resultSet := make([]testJSON, 0)
+
+	for _, a := range run.TestRun.Assemblies {
+		if assembly != "" && a.Name != assembly {
+			continue
+		}
+
+		a.Walk(func(path []string, tc xunit.TestCase) {
+			if err != nil || (result != "" && !strings.EqualFold(tc.Result, result)) {
+				return
+			}
+
+			if run.Index != nil {
+				if tc, err = run.Index.Details(tc); err != nil {
+					return
+				}
+			}
+
+			test := testJSON{
+				ID:         tc.ID,
+				Assembly:   a.Name,
+				Path:       append(make([]string, 0, len(path)), path...),
+				Name:       tc.Name,
+				Result:     tc.Result,
+				Duration:   tc.Duration.Seconds(),
+				SourceFile: tc.SourceFile,
+				SourceLine: tc.SourceLine,
+				Reason:     tc.Reason,
+			}
+
+			if tc.Failure != (xunit.Failure{}) {
+				test.Failure = &failureJSON{
+					ExceptionType: tc.Failure.ExceptionType,
+					Message:       tc.Failure.Message,
+					StackTrace:    tc.Failure.StackTrace,
+				}
+			}
+
+			resultSet = append(resultSet, test)
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resultSet, nil
+}
+
+// Writes v to w as JSON, with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	newEncoder(w).Encode(v)
+}
+
+// Returns an encoder which writes indented JSON to w.
+func newEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc
+}
+
+// Writes msg to w as a JSON error, with the given status code.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, errorJSON{Error: msg})
+}
@@ -0,0 +1,274 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "api" package.
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Query the runs exposed by the API.
+func TestHandler(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies computer=\"WIN11\">\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Passes\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Fails\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"AssertException\">\n" +
+		"          <message>Expected: 1</message>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	handler := api.Handler(func() ([]api.Run, error) {
+		return []api.Run{{ID: "nightly", Name: "nightly.xml", TestRun: testRun}}, nil
+	}, nil)
+
+	for _, tc := range []struct {
+		method   string
+		target   string
+		wantCode int
+		want     string
+	}{
+		{
+			target:   "/api/runs",
+			wantCode: http.StatusOK,
+			want: "[\n" +
+				"  {\n" +
+				"    \"id\": \"nightly\",\n" +
+				"    \"name\": \"nightly.xml\",\n" +
+				"    \"project\": \"\",\n" +
+				"    \"commit\": \"\",\n" +
+				"    \"branch\": \"\",\n" +
+				"    \"computer\": \"WIN11\",\n" +
+				"    \"user\": \"\",\n" +
+				"    \"timestamp\": \"\",\n" +
+				"    \"stats\": {\n" +
+				"      \"assemblies\": 1,\n" +
+				"      \"errors\": 0,\n" +
+				"      \"passed\": 1,\n" +
+				"      \"failed\": 1,\n" +
+				"      \"skipped\": 0,\n" +
+				"      \"notRun\": 0,\n" +
+				"      \"total\": 2,\n" +
+				"      \"passRate\": 50,\n" +
+				"      \"duration\": 1.5\n" +
+				"    }\n" +
+				"  }\n" +
+				"]\n",
+		},
+		{
+			target:   "/api/runs/nightly",
+			wantCode: http.StatusOK,
+			want:     "\"id\": \"nightly\"",
+		},
+		{
+			target:   "/api/runs/nightly/assemblies",
+			wantCode: http.StatusOK,
+			want: "[\n" +
+				"  {\n" +
+				"    \"name\": \"App.dll\",\n" +
+				"    \"errors\": 0,\n" +
+				"    \"passed\": 1,\n" +
+				"    \"failed\": 1,\n" +
+				"    \"skipped\": 0,\n" +
+				"    \"notRun\": 0,\n" +
+				"    \"total\": 2,\n" +
+				"    \"duration\": 1.5\n" +
+				"  }\n" +
+				"]\n",
+		},
+		{
+			target:   "/api/runs/nightly/tests?result=Fail",
+			wantCode: http.StatusOK,
+			want: "[\n" +
+				"  {\n" +
+				"    \"assembly\": \"App.dll\",\n" +
+				"    \"path\": [\n" +
+				"      \"TestClass\",\n" +
+				"      \"Method\"\n" +
+				"    ],\n" +
+				"    \"name\": \"NS.TestClass+Method.Fails\",\n" +
+				"    \"result\": \"Fail\",\n" +
+				"    \"duration\": 1,\n" +
+				"    \"failure\": {\n" +
+				"      \"exceptionType\": \"AssertException\",\n" +
+				"      \"message\": \"Expected: 1\"\n" +
+				"    }\n" +
+				"  }\n" +
+				"]\n",
+		},
+		{
+			target:   "/api/runs/nightly/tests?assembly=Other.dll",
+			wantCode: http.StatusOK,
+			want:     "[]\n",
+		},
+		{
+			target:   "/api/runs/weekly/tests",
+			wantCode: http.StatusNotFound,
+			want:     "{\n  \"error\": \"unknown run weekly\"\n}\n",
+		},
+		{
+			target:   "/api/runs/nightly/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			target:   "/api/openapi.json",
+			wantCode: http.StatusOK,
+			want:     "\"openapi\": \"3.0.3\"",
+		},
+		{
+			target:   "/api/runsx",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			method:   http.MethodPost,
+			target:   "/api/runs",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+		method := tc.method
+
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(method, tc.target, nil))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Query the runs exposed by the API.\n"+
+			"Input:      %s %s\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", method, tc.target, tc.wantCode, rec.Code)
+
+		if tc.want != "" {
+			assert.Contains(t, rec.Body.String(), tc.want, "", "\n\n"+
+				"UT Name:    Query the runs exposed by the API.\n"+
+				"Input:      %s %s\n"+
+				"\033[32mExpected:   Body containing %q\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", method, tc.target, tc.want, rec.Body.String())
+		}
+	}
+}
+
+// UT: Query the API when the runs can't be loaded.
+func TestHandler_SourceError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	rec := httptest.NewRecorder()
+	handler := api.Handler(func() ([]api.Run, error) { return nil, errors.New("results.xml: EOF") }, nil)
+
+	// ACT.
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/runs", nil))
+
+	// ASSERT.
+	assert.Equal(t, rec.Code, http.StatusInternalServerError, "", "\n\n"+
+		"UT Name:    Query the API when the runs can't be loaded.\n"+
+		"\033[32mExpected:   Status code %d\033[0m\n"+
+		"\033[31mActual:     Status code %d\033[0m\n\n", http.StatusInternalServerError, rec.Code)
+}
+
+// UT: Upload result files which can't be stored.
+func TestHandler_IngestError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		err      error
+		wantCode int
+	}{
+		{err: errors.New("results.xml: EOF"), wantCode: http.StatusBadRequest},
+		{err: &api.TooLargeError{Name: "results.xml", Limit: 1024}, wantCode: http.StatusRequestEntityTooLarge},
+		{
+			err:      fmt.Errorf("upload: %w", &api.TooLargeError{Name: "results.xml", Limit: 1024}),
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+		handler := api.Handler(func() ([]api.Run, error) { return nil, nil }, func(api.Upload, io.Reader) (api.Run, error) {
+			return api.Run{}, tc.err
+		})
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/runs?name=results.xml", nil))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Upload result files which can't be stored.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", tc.err, tc.wantCode, rec.Code)
+	}
+}
+
+// UT: Query the tests of a run, whose details are loaded from an index.
+func TestHandler_Index(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	index, _ := xunit.LoadIndex(strings.NewReader("<assemblies>\n"+
+		"  <assembly name=\"App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"A passing test.\" result=\"Pass\"><output>Connecting...</output></test>\n"+
+		"      <test name=\"A failing test.\" result=\"Fail\">\n"+
+		"        <failure><message>Expected: 1</message></failure>\n"+
+		"      </test>\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>"), xunit.Options{})
+	handler := api.Handler(func() ([]api.Run, error) {
+		return []api.Run{{ID: "nightly", Name: "nightly.xml", TestRun: index.TestRun, Index: index}}, nil
+	}, nil)
+	rec := httptest.NewRecorder()
+	want := "\"message\": \"Expected: 1\""
+
+	// ACT.
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/runs/nightly/tests?result=Fail", nil))
+
+	// ASSERT.
+	assert.Contains(t, rec.Body.String(), want, "", "\n\n"+
+		"UT Name:    Query the tests of a run, whose details are loaded from an index.\n"+
+		"\033[32mExpected:   Body containing %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, rec.Body.String())
+}
@@ -26,7 +26,14 @@
 // Package assert defines functions for making assertions in Go's standard testing framework.
 package assert
 
-import "testing"
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 // Nil compares got against nil.
 // If they are NOT equal, t is marked as failed, and it's execution is terminated.
@@ -48,12 +55,39 @@ func NotNil(t testing.TB, got any, name string, msg ...any) {
 	}
 }
 
+// NoError checks that err is nil.
+// If it isn't, t is marked as failed, and it's execution is terminated.
+func NoError(t testing.TB, err error, name string, msg ...any) {
+	if err != nil {
+		t.Helper()
+
+		failT[any](t, err, nil, name, "%s = %v, want %v", msg...)
+	}
+}
+
+// Error checks that err is NOT nil.
+// If it is, t is marked as failed, and it's execution is terminated.
+func Error(t testing.TB, err error, name string, msg ...any) {
+	if err == nil {
+		t.Helper()
+
+		failT[any](t, err, "an error", name, "%s = %v, want %s", msg...)
+	}
+}
+
 // Equal compares got against want for equality.
 // If they are not equal, t is marked as failed, and it's execution is terminated.
 func Equal[V comparable](t testing.TB, got, want V, name string, msg ...any) {
 	if got != want {
 		t.Helper()
 
+		// NOTE: Multi-line strings are hard to compare by eye, so a (unified) diff is reported instead.
+		if isMultiline(got, want) {
+			failDiff(t, unifiedDiff(any(got).(string), any(want).(string)), name, msg...)
+
+			return
+		}
+
 		failT(t, got, want, name, "%s = %v, want %v", msg...)
 	}
 }
@@ -68,8 +102,174 @@ func EqualFn[V any](t testing.TB, got, want V, cmpFn func(got, want V) bool, nam
 	}
 }
 
+// DeepEqual compares got against want for deep equality (see reflect.DeepEqual).
+// If they are not equal, t is marked as failed, and it's execution is terminated. The message lists the differences
+// between got and want, field by field (they are appended to a custom message).
+func DeepEqual[V any](t testing.TB, got, want V, name string, msg ...any) {
+	if !reflect.DeepEqual(got, want) {
+		t.Helper()
+
+		failDiff(t, diff(got, want), name, msg...)
+	}
+}
+
+// Contains checks that got contains element: as a substring (if got is a string), as an element (if got is a slice or
+// an array), or as a key (if got is a map).
+// If it doesn't, t is marked as failed, and it's execution is terminated.
+func Contains(t testing.TB, got, element any, name string, msg ...any) {
+	if !contains(got, element) {
+		t.Helper()
+
+		failT(t, got, element, name, "%s = %v, want it to contain %v", msg...)
+	}
+}
+
+// Returns true if container contains element (see Contains).
+func contains(container, element any) bool {
+	v := reflect.ValueOf(container)
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := element.(string)
+
+		return ok && strings.Contains(v.String(), s)
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			if reflect.DeepEqual(v.Index(idx).Interface(), element) {
+				return true
+			}
+		}
+	case reflect.Map:
+		key := reflect.ValueOf(element)
+
+		return key.IsValid() && key.Type().AssignableTo(v.Type().Key()) && v.MapIndex(key).IsValid()
+	}
+
+	return false
+}
+
+// Len checks that got (a string, a slice, an array, a map or a channel) has the given length.
+// If it hasn't, t is marked as failed, and it's execution is terminated.
+func Len(t testing.TB, got any, want int, name string, msg ...any) {
+	if n, ok := length(got); !ok || n != want {
+		t.Helper()
+
+		failT[any](t, describeLen(got), fmt.Sprintf("len %d", want), name, "%s = %v, want %v", msg...)
+	}
+}
+
+// Empty checks that got (a string, a slice, an array, a map or a channel) is empty (or nil).
+// If it isn't, t is marked as failed, and it's execution is terminated.
+func Empty(t testing.TB, got any, name string, msg ...any) {
+	if n, ok := length(got); !ok || n != 0 {
+		t.Helper()
+
+		failT[any](t, describeLen(got), "empty", name, "%s = %v, want %v", msg...)
+	}
+}
+
+// Returns the length of v, and false if v doesn't have a length.
+func length(v any) (int, bool) {
+	if v == nil {
+		return 0, true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return rv.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+// Returns a description of v, which starts with its length (e.g. "len 2 [Pass Fail]").
+func describeLen(v any) string {
+	n, ok := length(v)
+
+	if !ok {
+		return fmt.Sprintf("%v (without a length)", v)
+	}
+
+	return fmt.Sprintf("len %d %v", n, v)
+}
+
+// Greater checks that got is greater than than.
+// If it isn't, t is marked as failed, and it's execution is terminated.
+func Greater[V cmp.Ordered](t testing.TB, got, than V, name string, msg ...any) {
+	if !(got > than) {
+		t.Helper()
+
+		failT(t, got, than, name, "%s = %v, want > %v", msg...)
+	}
+}
+
+// Less checks that got is less than than.
+// If it isn't, t is marked as failed, and it's execution is terminated.
+func Less[V cmp.Ordered](t testing.TB, got, than V, name string, msg ...any) {
+	if !(got < than) {
+		t.Helper()
+
+		failT(t, got, than, name, "%s = %v, want < %v", msg...)
+	}
+}
+
+// InDelta checks that got differs at most delta from want (e.g. for durations, or for floating-point numbers).
+// If it doesn't, t is marked as failed, and it's execution is terminated.
+func InDelta[V number](t testing.TB, got, want, delta V, name string, msg ...any) {
+	diff := got - want
+
+	if want > got {
+		diff = want - got
+	}
+
+	// NOTE: The negation also catches NaN, which can't be compared.
+	if !(diff <= delta) {
+		t.Helper()
+
+		failT[any](t, got, fmt.Sprintf("%v (±%v)", want, delta), name, "%s = %v, want %v", msg...)
+	}
+}
+
+// The numeric types.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Eventually checks that cond returns true within timeout, by calling it every interval (e.g. for a watcher or a server
+// which works in the background).
+// If it doesn't, t is marked as failed, and it's execution is terminated.
+func Eventually(t testing.TB, cond func() bool, timeout, interval time.Duration, name string, msg ...any) {
+	deadline := time.Now().Add(timeout)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Helper()
+
+			failT[any](t, fmt.Sprintf("false (after %v)", timeout), true, name, "%s = %v, want %v", msg...)
+
+			return
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// Marks t as failed (with the differences between the actual and the expected value) and terminates its execution.
+func failDiff(t testing.TB, diff, name string, msg ...any) {
+	t.Helper()
+
+	if name != "" {
+		t.Fatalf("%s differs from the expected value:\n%s", name, diff)
+	} else {
+		t.Fatalf(msg[0].(string)+"%s", append(msg[1:], diff)...)
+	}
+}
+
 // Marks t as failed and terminates its execution.
 func failT[V any](t testing.TB, got, want V, name, msgTemplate string, msg ...any) {
+	t.Helper()
+
 	if name != "" {
 		t.Fatalf(msgTemplate, name, got, want)
 	} else {
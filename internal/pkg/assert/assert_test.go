@@ -27,8 +27,12 @@
 package assert_test
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
 )
@@ -44,6 +48,11 @@ func (t *testableT) Fatalf(format string, args ...any) {
 	t.failureMsg = fmt.Sprintf(format, args...)
 }
 
+// Errorf formats args using fmt.Sprintf and stores the result in t.
+func (t *testableT) Errorf(format string, args ...any) {
+	t.failureMsg = fmt.Sprintf(format, args...)
+}
+
 // UT: Compare a value against nil.
 func TestNil(t *testing.T) {
 	t.Parallel() // Enable parallel execution.
@@ -167,6 +176,127 @@ func TestNotNilWithCustomMessage(t *testing.T) {
 	}
 }
 
+// UT: Check that an error is nil.
+func TestNoError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		err  error
+		name string
+		want string
+	}{
+		{
+			err:  errors.New("invalid XML"),
+			name: "Load()",
+			want: "Load() = invalid XML, want <nil>",
+		},
+		{
+			name: "Load()",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.NoError(testingT, tc.err, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that an error is nil (with a custom message).
+func TestNoErrorWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		err  error
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			err:  errors.New("invalid XML"),
+			name: "",
+			msg:  []any{"UT Failed: `Load()` - got %v, want <nil>.", errors.New("invalid XML")},
+			want: "UT Failed: `Load()` - got invalid XML, want <nil>.",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.NoError(testingT, tc.err, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that an error is NOT nil.
+func TestError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		err  error
+		name string
+		want string
+	}{
+		{
+			name: "Load()",
+			want: "Load() = <nil>, want an error",
+		},
+		{
+			err:  errors.New("invalid XML"),
+			name: "Load()",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Error(testingT, tc.err, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that an error is NOT nil (with a custom message).
+func TestErrorWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		err  error
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			name: "",
+			msg:  []any{"UT Failed: `Load()` - got <nil>, want an error."},
+			want: "UT Failed: `Load()` - got <nil>, want an error.",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Error(testingT, tc.err, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
 // UT: Compare 2 values for equality.
 func TestEqual(t *testing.T) {
 	t.Parallel() // Enable parallel execution.
@@ -290,3 +420,659 @@ func TestEqualFnWithCustomMessage(t *testing.T) {
 		}
 	}
 }
+
+// UT: Check that a string, a slice or a map contains an element.
+func TestContains(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, element any
+		name       string
+		want       string
+	}{
+		{
+			g: "A passing test.", element: "passing",
+			name: "Name()",
+		},
+		{
+			g: "A passing test.", element: "failing",
+			name: "Name()",
+			want: "Name() = A passing test., want it to contain failing",
+		},
+		{
+			g: []string{"Pass", "Fail"}, element: "Fail",
+			name: "Results()",
+		},
+		{
+			g: [2]int{1, 2}, element: 3,
+			name: "Counts()",
+			want: "Counts() = [1 2], want it to contain 3",
+		},
+		{
+			g: map[string]int{"Pass": 1}, element: "Pass",
+			name: "Counts()",
+		},
+		{
+			g: map[string]int{"Pass": 1}, element: 1,
+			name: "Counts()",
+			want: "Counts() = map[Pass:1], want it to contain 1",
+		},
+		{
+			g: 42, element: 4,
+			name: "Count()",
+			want: "Count() = 42, want it to contain 4",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Contains(testingT, tc.g, tc.element, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a string, a slice or a map contains an element (with a custom message).
+func TestContainsWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, element any
+		name       string
+		msg        []any
+		want       string
+	}{
+		{
+			g: []string{"Pass"}, element: "Fail",
+			name: "",
+			msg:  []any{"UT Failed: `Results()` - got %v, want it to contain %q.", []string{"Pass"}, "Fail"},
+			want: "UT Failed: `Results()` - got [Pass], want it to contain \"Fail\".",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Contains(testingT, tc.g, tc.element, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check the length of a string, a slice or a map.
+func TestLen(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g    any
+		len  int
+		name string
+		want string
+	}{
+		{
+			g: []string{"Pass", "Fail"}, len: 2,
+			name: "Results()",
+		},
+		{
+			g: []string{"Pass", "Fail", "Skip"}, len: 5,
+			name: "Results()",
+			want: "Results() = len 3 [Pass Fail Skip], want len 5",
+		},
+		{
+			g: map[string]int{"Pass": 1}, len: 1,
+			name: "Counts()",
+		},
+		{
+			g: "Pass", len: 3,
+			name: "Result()",
+			want: "Result() = len 4 Pass, want len 3",
+		},
+		{
+			g: 42, len: 2,
+			name: "Count()",
+			want: "Count() = 42 (without a length), want len 2",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Len(testingT, tc.g, tc.len, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check the length of a string, a slice or a map (with a custom message).
+func TestLenWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g    any
+		len  int
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			g: []string{"Pass"}, len: 2,
+			name: "",
+			msg:  []any{"UT Failed: `Results()` - got %v, want 2 results.", []string{"Pass"}},
+			want: "UT Failed: `Results()` - got [Pass], want 2 results.",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Len(testingT, tc.g, tc.len, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a string, a slice or a map is empty.
+func TestEmpty(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g    any
+		name string
+		want string
+	}{
+		{
+			g:    []string(nil),
+			name: "Results()",
+		},
+		{
+			g:    map[string]int{},
+			name: "Counts()",
+		},
+		{
+			g:    []string{"Fail"},
+			name: "Results()",
+			want: "Results() = len 1 [Fail], want empty",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Empty(testingT, tc.g, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a string, a slice or a map is empty (with a custom message).
+func TestEmptyWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g    any
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			g:    "Fail",
+			name: "",
+			msg:  []any{"UT Failed: `Result()` - got %q, want \"\".", "Fail"},
+			want: "UT Failed: `Result()` - got \"Fail\", want \"\".",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Empty(testingT, tc.g, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a value is greater than another value.
+func TestGreater(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, than int
+		name    string
+		want    string
+	}{
+		{
+			g: 2, than: 1,
+			name: "Count()",
+		},
+		{
+			g: 1, than: 1,
+			name: "Count()",
+			want: "Count() = 1, want > 1",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Greater(testingT, tc.g, tc.than, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a value is less than another value.
+func TestLess(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, than time.Duration
+		name    string
+		want    string
+	}{
+		{
+			g: time.Second, than: time.Minute,
+			name: "Duration()",
+		},
+		{
+			g: time.Minute, than: time.Second,
+			name: "Duration()",
+			want: "Duration() = 1m0s, want < 1s",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Less(testingT, tc.g, tc.than, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a value is within a delta of another value.
+func TestInDelta(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, w, delta float64
+		name        string
+		want        string
+	}{
+		{
+			g: 0.1 + 0.2, w: 0.3, delta: 1e-9,
+			name: "Ratio()",
+		},
+		{
+			g: 0.5, w: 0.3, delta: 0.1,
+			name: "Ratio()",
+			want: "Ratio() = 0.5, want 0.3 (±0.1)",
+		},
+		{
+			g: math.NaN(), w: 0.3, delta: 0.1,
+			name: "Ratio()",
+			want: "Ratio() = NaN, want 0.3 (±0.1)",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.InDelta(testingT, tc.g, tc.w, tc.delta, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a value is within a delta of another value (with a custom message).
+func TestInDeltaWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, w, delta time.Duration
+		name        string
+		msg         []any
+		want        string
+	}{
+		{
+			g: 2 * time.Second, w: time.Second, delta: 10 * time.Millisecond,
+			name: "",
+			msg:  []any{"UT Failed: `Duration()` - got %v, want ~%v.", 2 * time.Second, time.Second},
+			want: "UT Failed: `Duration()` - got 2s, want ~1s.",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.InDelta(testingT, tc.g, tc.w, tc.delta, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Compare 2 values for deep equality.
+func TestDeepEqual(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	type test struct {
+		Name   string
+		Traits map[string]string
+		Time   time.Duration
+		Skip   *string
+	}
+
+	type assembly struct {
+		Name     string
+		Tests    []test
+		Finished time.Time
+	}
+
+	reason := "Not supported."
+	want := assembly{
+		Name:     "App.dll",
+		Tests:    []test{{Name: "A passing test.", Traits: map[string]string{"Category": "Unit"}, Time: time.Second}},
+		Finished: time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
+	}
+
+	for _, tc := range []struct {
+		g    assembly
+		name string
+		want string
+	}{
+		{
+			g:    want,
+			name: "Load()",
+		},
+		{
+			g: assembly{
+				Name: "App.dll",
+				Tests: []test{
+					{Name: "A failing test.", Traits: map[string]string{"Owner": "Team A"}, Time: time.Second},
+					{Name: "A skipped test.", Skip: &reason},
+				},
+				Finished: time.Date(2023, 7, 10, 20, 53, 20, 0, time.UTC),
+			},
+			name: "Load()",
+			want: "Load() differs from the expected value:\n" +
+				"Tests[0].Name: got \"A failing test.\", want \"A passing test.\"\n" +
+				"Tests[0].Traits[\"Category\"]: got <missing>, want \"Unit\"\n" +
+				"Tests[0].Traits[\"Owner\"]: got \"Team A\", want <missing>\n" +
+				"Tests[1]: got {Name:A skipped test. Traits:map[] Time:0s Skip:" + fmt.Sprint(&reason) + "}, " +
+				"want <missing>\n" +
+				"Finished: got 2023-07-10 20:53:20 +0000 UTC, want 2023-07-10 20:53:19 +0000 UTC",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.DeepEqual(testingT, tc.g, want, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Compare 2 values for deep equality (with a custom message).
+func TestDeepEqualWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, w []string
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			g: []string{"Pass"}, w: nil,
+			name: "",
+			msg:  []any{"UT Failed: `Results()` - got %d results, want %d.\n", 1, 0},
+			want: "UT Failed: `Results()` - got 1 results, want 0.\n(root): got [Pass], want <nil>",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.DeepEqual(testingT, tc.g, tc.w, tc.name, tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a condition becomes true within a timeout.
+func TestEventually(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		after int32
+		name  string
+		want  string
+	}{
+		{
+			after: 3,
+			name:  "Ready()",
+		},
+		{
+			after: math.MaxInt32,
+			name:  "Ready()",
+			want:  "Ready() = false (after 20ms), want true",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		var calls atomic.Int32
+
+		// ACT.
+		assert.Eventually(testingT, func() bool { return calls.Add(1) >= tc.after }, 20*time.Millisecond,
+			time.Millisecond, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check that a condition becomes true within a timeout (with a custom message).
+func TestEventuallyWithCustomMessage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name string
+		msg  []any
+		want string
+	}{
+		{
+			name: "",
+			msg:  []any{"UT Failed: `Ready()` - not ready after %v.", 5 * time.Millisecond},
+			want: "UT Failed: `Ready()` - not ready after 5ms.",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Eventually(testingT, func() bool { return false }, 5*time.Millisecond, time.Millisecond, tc.name,
+			tc.msg...)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Check assertions without terminating the execution of the test.
+func TestCheck(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		check  func(t testing.TB) bool
+		wantOk bool
+		want   string
+	}{
+		{
+			check:  func(t testing.TB) bool { return assert.CheckNil(t, nil, "ValueOf(nil)") },
+			wantOk: true,
+		},
+		{
+			check: func(t testing.TB) bool { return assert.CheckNotNil(t, nil, "ValueOf(nil)") },
+			want:  "ValueOf(nil) = <nil>, want NOT <nil>",
+		},
+		{
+			check: func(t testing.TB) bool { return assert.CheckError(t, nil, "Load()") },
+			want:  "Load() = <nil>, want an error",
+		},
+		{
+			check: func(t testing.TB) bool { return assert.CheckEqual(t, 1, 2, "Count()") },
+			want:  "Count() = 1, want 2",
+		},
+		{
+			check: func(t testing.TB) bool { return assert.CheckLen(t, []int{1}, 2, "Counts()") },
+			want:  "Counts() = len 1 [1], want len 2",
+		},
+		{
+			check: func(t testing.TB) bool {
+				return assert.CheckInDelta(t, time.Second, time.Minute, time.Millisecond, "Duration()")
+			},
+			want: "Duration() = 1s, want 1m0s (±1ms)",
+		},
+		{
+			check: func(t testing.TB) bool {
+				return assert.CheckEventually(t, func() bool { return true }, time.Second, time.Millisecond, "Ready()")
+			},
+			wantOk: true,
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		ok := tc.check(testingT)
+
+		// ASSERT.
+		if ok != tc.wantOk || testingT.failureMsg != tc.want {
+			t.Fatalf("Check() = %t (failure message \"%s\"), want %t (failure message \"%s\")", ok,
+				testingT.failureMsg, tc.wantOk, tc.want)
+		}
+	}
+}
+
+// UT: Compare 2 multi-line strings for equality.
+func TestEqualMultiline(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		g, w string
+		name string
+		want string
+	}{
+		{
+			g:    "App.dll\n  A passing test.\n  A failing test.\n",
+			w:    "App.dll\n  A passing test.\n  A failing test.\n",
+			name: "Render()",
+		},
+		{
+			g:    "App.dll\n  1\n  2\n  3\n  4\n  A failing test.\n  5\n  6\n  7\n  8\n  9\n  10\n  11\n  Extra.",
+			w:    "App.dll\n  1\n  2\n  3\n  4\n  A passing test.\n  5\n  6\n  7\n  8\n  9\n  10\n  11",
+			name: "Render()",
+			want: "Render() differs from the expected value:\n" +
+				"--- want\n" +
+				"+++ got\n" +
+				"@@ -3,7 +3,7 @@\n" +
+				"   2\n" +
+				"   3\n" +
+				"   4\n" +
+				"\033[32m-  A passing test.\033[0m\n" +
+				"\033[31m+  A failing test.\033[0m\n" +
+				"   5\n" +
+				"   6\n" +
+				"   7\n" +
+				"@@ -11,3 +11,4 @@\n" +
+				"   9\n" +
+				"   10\n" +
+				"   11\n" +
+				"\033[31m+  Extra.\033[0m",
+		},
+		{
+			g:    "A\nB",
+			w:    "A",
+			name: "Render()",
+			want: "Render() differs from the expected value:\n--- want\n+++ got\n@@ -1,1 +1,2 @@\n A\n\033[31m+B\033[0m",
+		},
+	} {
+		// ARRANGE.
+		testingT := &testableT{TB: t}
+
+		// ACT.
+		assert.Equal(testingT, tc.g, tc.w, tc.name)
+
+		// ASSERT.
+		if testingT.failureMsg != tc.want {
+			t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, tc.want)
+		}
+	}
+}
+
+// UT: Compare 2 values, with multi-line strings, for deep equality.
+func TestDeepEqualMultiline(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	type failure struct {
+		Message    string
+		StackTrace string
+	}
+
+	// ARRANGE.
+	testingT := &testableT{TB: t}
+
+	// ACT.
+	assert.DeepEqual(testingT, failure{Message: "Expected: 1", StackTrace: "at A()\nat C()"},
+		failure{Message: "Expected: 1", StackTrace: "at A()\nat B()"}, "Failure()")
+
+	// ASSERT.
+	want := "Failure() differs from the expected value:\n" +
+		"StackTrace:\n--- want\n+++ got\n@@ -1,2 +1,2 @@\n at A()\n\033[32m-at B()\033[0m\n\033[31m+at C()\033[0m"
+
+	if testingT.failureMsg != want {
+		t.Fatalf("Failure message = \"%s\", want \"%s\"", testingT.failureMsg, want)
+	}
+}
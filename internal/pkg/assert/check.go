@@ -0,0 +1,157 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package assert
+
+import (
+	"cmp"
+	"testing"
+	"time"
+)
+
+// A testing.TB which reports fatal failures as errors, so the execution of the test continues.
+type nonFatal struct {
+	testing.TB
+	failed bool // True if an assertion failed.
+}
+
+// Fatalf formats args using fmt.Sprintf and reports the result as an error (see testing.TB.Errorf).
+func (t *nonFatal) Fatalf(format string, args ...any) {
+	t.TB.Helper()
+
+	t.failed = true
+	t.TB.Errorf(format, args...)
+}
+
+// Runs assert with a testing.TB which doesn't terminate the execution of t, and returns true if the assertion passed.
+func check(t testing.TB, assert func(t testing.TB)) bool {
+	t.Helper()
+
+	nf := &nonFatal{TB: t}
+	assert(nf)
+
+	return !nf.failed
+}
+
+// CheckNil is like Nil, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckNil(t testing.TB, got any, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Nil(t, got, name, msg...) })
+}
+
+// CheckNotNil is like NotNil, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckNotNil(t testing.TB, got any, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); NotNil(t, got, name, msg...) })
+}
+
+// CheckNoError is like NoError, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckNoError(t testing.TB, err error, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); NoError(t, err, name, msg...) })
+}
+
+// CheckError is like Error, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckError(t testing.TB, err error, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Error(t, err, name, msg...) })
+}
+
+// CheckEqual is like Equal, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckEqual[V comparable](t testing.TB, got, want V, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Equal(t, got, want, name, msg...) })
+}
+
+// CheckEqualFn is like EqualFn, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckEqualFn[V any](t testing.TB, got, want V, cmpFn func(got, want V) bool, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); EqualFn(t, got, want, cmpFn, name, msg...) })
+}
+
+// CheckDeepEqual is like DeepEqual, but it doesn't terminate the execution of t, and returns true if the assertion
+// passed.
+func CheckDeepEqual[V any](t testing.TB, got, want V, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); DeepEqual(t, got, want, name, msg...) })
+}
+
+// CheckContains is like Contains, but it doesn't terminate the execution of t, and returns true if the assertion
+// passed.
+func CheckContains(t testing.TB, got, element any, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Contains(t, got, element, name, msg...) })
+}
+
+// CheckLen is like Len, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckLen(t testing.TB, got any, want int, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Len(t, got, want, name, msg...) })
+}
+
+// CheckEmpty is like Empty, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckEmpty(t testing.TB, got any, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Empty(t, got, name, msg...) })
+}
+
+// CheckGreater is like Greater, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckGreater[V cmp.Ordered](t testing.TB, got, than V, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Greater(t, got, than, name, msg...) })
+}
+
+// CheckLess is like Less, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckLess[V cmp.Ordered](t testing.TB, got, than V, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Less(t, got, than, name, msg...) })
+}
+
+// CheckInDelta is like InDelta, but it doesn't terminate the execution of t, and returns true if the assertion passed.
+func CheckInDelta[V number](t testing.TB, got, want, delta V, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); InDelta(t, got, want, delta, name, msg...) })
+}
+
+// CheckEventually is like Eventually, but it doesn't terminate the execution of t, and returns true if the assertion
+// passed.
+func CheckEventually(t testing.TB, cond func() bool, timeout, interval time.Duration, name string, msg ...any) bool {
+	t.Helper()
+
+	return check(t, func(t testing.TB) { t.Helper(); Eventually(t, cond, timeout, interval, name, msg...) })
+}
@@ -0,0 +1,208 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// The depth after which values are compared (and reported) as a whole, which guards against cyclic values.
+const maxDiffDepth = 32
+
+// Returns the differences between got and want, one field (or element) per line, or an empty string if they're deeply
+// equal.
+func diff(got, want any) string {
+	var lines []string
+
+	diffValues(&lines, "", reflect.ValueOf(got), reflect.ValueOf(want), 0)
+
+	return strings.Join(lines, "\n")
+}
+
+// Appends the differences between got and want, at the given path (e.g. ".Assemblies[0].Name"), to lines.
+func diffValues(lines *[]string, path string, got, want reflect.Value, depth int) {
+	if !got.IsValid() || !want.IsValid() || got.Type() != want.Type() {
+		if got.IsValid() != want.IsValid() || got.IsValid() && got.Type() != want.Type() {
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+		}
+
+		return
+	}
+
+	if depth > maxDiffDepth {
+		if !deepEqual(got, want) {
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+		}
+
+		return
+	}
+
+	switch got.Kind() {
+	case reflect.Struct:
+		// NOTE: Structs without exported fields (e.g. time.Time) are opaque, so they're reported as a whole.
+		if !hasExportedFields(got.Type()) {
+			if !deepEqual(got, want) {
+				*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+			}
+
+			return
+		}
+
+		for idx := 0; idx < got.NumField(); idx++ {
+			diffValues(lines, path+"."+got.Type().Field(idx).Name, got.Field(idx), want.Field(idx), depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if got.Kind() == reflect.Slice && got.IsNil() != want.IsNil() {
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+
+			return
+		}
+
+		for idx := 0; idx < max(got.Len(), want.Len()); idx++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, idx)
+
+			switch {
+			case idx >= got.Len():
+				*lines = append(*lines, diffLine(elemPath, "<missing>", formatValue(want.Index(idx))))
+			case idx >= want.Len():
+				*lines = append(*lines, diffLine(elemPath, formatValue(got.Index(idx)), "<missing>"))
+			default:
+				diffValues(lines, elemPath, got.Index(idx), want.Index(idx), depth+1)
+			}
+		}
+	case reflect.Map:
+		if got.IsNil() != want.IsNil() {
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+
+			return
+		}
+
+		keys := append(got.MapKeys(), want.MapKeys()...)
+
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(formatValue(a), formatValue(b)) })
+
+		keys = slices.CompactFunc(keys, func(a, b reflect.Value) bool { return deepEqual(a, b) })
+
+		for _, key := range keys {
+			elemPath := fmt.Sprintf("%s[%s]", path, formatValue(key))
+			gotElem, wantElem := got.MapIndex(key), want.MapIndex(key)
+
+			switch {
+			case !gotElem.IsValid():
+				*lines = append(*lines, diffLine(elemPath, "<missing>", formatValue(wantElem)))
+			case !wantElem.IsValid():
+				*lines = append(*lines, diffLine(elemPath, formatValue(gotElem), "<missing>"))
+			default:
+				diffValues(lines, elemPath, gotElem, wantElem, depth+1)
+			}
+		}
+	case reflect.Pointer, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+			}
+
+			return
+		}
+
+		diffValues(lines, path, got.Elem(), want.Elem(), depth+1)
+	case reflect.String:
+		switch {
+		case got.String() == want.String():
+		case isMultiline(got.String(), want.String()):
+			*lines = append(*lines, pathName(path)+":\n"+
+				unifiedDiff(got.String(), want.String()))
+		default:
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+		}
+	default:
+		if !deepEqual(got, want) {
+			*lines = append(*lines, diffLine(path, formatValue(got), formatValue(want)))
+		}
+	}
+}
+
+// Returns true if a and b (of the same type) are deeply equal, even if they are (part of) unexported fields.
+func deepEqual(a, b reflect.Value) bool {
+	if a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	switch a.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return a.IsNil() && b.IsNil() || a.Pointer() == b.Pointer()
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32,
+		reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return a.Equal(b)
+	default:
+		// NOTE: Composite values in unexported fields are compared by their representation.
+		return formatValue(a) == formatValue(b)
+	}
+}
+
+// Returns true if t (a struct type) has at least one exported field.
+func hasExportedFields(t reflect.Type) bool {
+	for idx := 0; idx < t.NumField(); idx++ {
+		if t.Field(idx).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns a line describing a difference at the given path.
+func diffLine(path, got, want string) string {
+	return fmt.Sprintf("%s: got %s, want %s", pathName(path), got, want)
+}
+
+// Returns the name of the given path ("(root)" if it's empty).
+func pathName(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+
+	return strings.TrimPrefix(path, ".")
+}
+
+// Returns a representation of v ("<nil>" if it's invalid or nil, and quoted if it's a string).
+func formatValue(v reflect.Value) string {
+	switch {
+	case !v.IsValid():
+		return "<nil>"
+	case slices.Contains([]reflect.Kind{reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface}, v.Kind()) &&
+		v.IsNil():
+		return "<nil>"
+	case v.Kind() == reflect.String:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
@@ -0,0 +1,205 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package assert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The number of unchanged lines shown around each change in a unified diff.
+const diffContext = 3
+
+// The maximum number of cells in the table used to compute the longest common subsequence of 2 texts.
+// Larger (changed parts of) texts are reported as entirely removed and added.
+const maxDiffCells = 4_000_000
+
+// An edit which turns a line of the expected text into a line of the actual text.
+type edit struct {
+	op   byte   // ' ' (if the line is unchanged), '-' (if it's removed) or '+' (if it's added).
+	line string // The line itself.
+}
+
+// Returns true if s and t are strings (of which at least one spans multiple lines), so they're compared with a
+// unified diff.
+func isMultiline(s, t any) bool {
+	a, okA := s.(string)
+	b, okB := t.(string)
+
+	return okA && okB && (strings.Contains(a, "\n") || strings.Contains(b, "\n"))
+}
+
+// Returns a colored unified diff which turns want into got (removed lines in green, added lines in red).
+func unifiedDiff(got, want string) string {
+	edits := diffLines(strings.Split(want, "\n"), strings.Split(got, "\n"))
+
+	var sb strings.Builder
+
+	sb.WriteString("--- want\n+++ got\n")
+
+	for start := 0; start < len(edits); {
+		// Find the next change, and the end of the hunk containing it.
+		first := start
+
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+
+		if first == len(edits) {
+			break
+		}
+
+		last := first
+
+		for idx := first; idx < len(edits); idx++ {
+			if edits[idx].op != ' ' {
+				last = idx
+			} else if idx-last > 2*diffContext {
+				break
+			}
+		}
+
+		from, to := max(first-diffContext, start), min(last+diffContext+1, len(edits))
+		wantLine, gotLine := lineNumbers(edits[:from])
+		wantCount, gotCount := lineNumbers(edits[from:to])
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", wantLine+1, wantCount, gotLine+1, gotCount)
+
+		for _, e := range edits[from:to] {
+			switch e.op {
+			case '-':
+				sb.WriteString("\033[32m-" + e.line + "\033[0m\n")
+			case '+':
+				sb.WriteString("\033[31m+" + e.line + "\033[0m\n")
+			default:
+				sb.WriteString(" " + e.line + "\n")
+			}
+		}
+
+		start = to
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Returns the number of lines of the expected text, and of the actual text, which are covered by edits.
+func lineNumbers(edits []edit) (int, int) {
+	var want, got int
+
+	for _, e := range edits {
+		if e.op != '+' {
+			want++
+		}
+
+		if e.op != '-' {
+			got++
+		}
+	}
+
+	return want, got
+}
+
+// Returns the edits which turn the lines a into the lines b, based on their longest common subsequence.
+func diffLines(a, b []string) []edit {
+	// The common prefix and suffix are unchanged, which keeps the table small.
+	prefix := 0
+
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{op: ' ', line: line})
+	}
+
+	edits = append(edits, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{op: ' ', line: line})
+	}
+
+	return edits
+}
+
+// Returns the edits which turn the lines a into the lines b (which don't share a prefix or a suffix).
+func diffMiddle(a, b []string) []edit {
+	var edits []edit
+
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			edits = append(edits, edit{op: '-', line: line})
+		}
+
+		for _, line := range b {
+			edits = append(edits, edit{op: '+', line: line})
+		}
+
+		return edits
+	}
+
+	// NOTE: lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{op: ' ', line: a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{op: '-', line: a[i]})
+			i++
+		default:
+			edits = append(edits, edit{op: '+', line: b[j]})
+			j++
+		}
+	}
+
+	return edits
+}
@@ -0,0 +1,195 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package auth defines the authentication of the requests sent to a DTVisual server, with static API tokens.
+//
+// Each token is granted a set of scopes (e.g. read-only, or ingest-only for a CI pipeline which uploads its results).
+// A token is sent as a bearer token ("Authorization: Bearer <token>"), or as the password of HTTP basic authentication
+// (so browsers can prompt for it).
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// The realm of the HTTP basic authentication.
+const realm = "DTVisual"
+
+// Scope is a permission granted to a token.
+type Scope string
+
+// The scopes which can be granted to a token.
+const (
+	ScopeRead   Scope = "read"   // Read the test results (the report and the API).
+	ScopeIngest Scope = "ingest" // Upload test results.
+)
+
+// ErrNoTokens is returned when a configuration doesn't contain any token.
+var ErrNoTokens = errors.New("auth: no tokens configured")
+
+// Token is an API token, and the scopes granted to it.
+type Token struct {
+	Name   string  `json:"name"`   // The name of the token (e.g. who uses it), which is never sent by clients.
+	Token  string  `json:"token"`  // The secret value of the token.
+	Scopes []Scope `json:"scopes"` // The scopes granted to the token.
+}
+
+// Config contains the tokens which are accepted by a server.
+type Config struct {
+	Tokens []Token `json:"tokens"` // The accepted tokens.
+}
+
+// Load returns the configuration read (as JSON) from r.
+// It returns an error if the configuration contains no tokens, an empty or duplicate token, or an unknown scope.
+func Load(r io.Reader) (Config, error) {
+	var cfg Config
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("auth: %w", err)
+	}
+
+	if len(cfg.Tokens) == 0 {
+		return Config{}, ErrNoTokens
+	}
+
+	seen := make(map[string]bool, len(cfg.Tokens))
+
+	for idx, token := range cfg.Tokens {
+		if token.Token == "" {
+			return Config{}, fmt.Errorf("auth: token %d (%q) is empty", idx+1, token.Name)
+		}
+
+		if seen[token.Token] {
+			return Config{}, fmt.Errorf("auth: token %d (%q) is a duplicate", idx+1, token.Name)
+		}
+
+		seen[token.Token] = true
+
+		for _, scope := range token.Scopes {
+			if scope != ScopeRead && scope != ScopeIngest {
+				return Config{}, fmt.Errorf("auth: token %d (%q) has an unknown scope %q", idx+1, token.Name, scope)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// MethodScope returns the scope required by r, based on its method: reads (GET, HEAD, OPTIONS) require ScopeRead, and
+// changes (POST, PUT, PATCH, DELETE) require ScopeIngest. For any other method, the empty scope is returned, so the
+// request is rejected.
+func MethodScope(r *http.Request) Scope {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return ScopeIngest
+	default:
+		return ""
+	}
+}
+
+// Require returns a handler which passes the requests to next if they carry a token of cfg which is granted the scope
+// returned by scope. Requests without a (valid) token are rejected with "401 Unauthorized", requests for which scope
+// returns the empty scope with "405 Method Not Allowed", and requests with a token which isn't granted the scope with
+// "403 Forbidden".
+func Require(cfg Config, scope func(r *http.Request) Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := cfg.find(requestToken(r))
+
+		if !ok {
+			w.Header().Add("WWW-Authenticate", "Bearer realm=\""+realm+"\"")
+			w.Header().Add("WWW-Authenticate", "Basic realm=\""+realm+"\"")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		required := scope(r)
+
+		if required == "" {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if !token.allows(required) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Returns the token of cfg with the given value.
+func (cfg Config) find(value string) (Token, bool) {
+	if value == "" {
+		return Token{}, false
+	}
+
+	for _, token := range cfg.Tokens {
+		// NOTE: Compare in constant time, so the response time doesn't reveal (a part of) a token.
+		if subtle.ConstantTimeCompare([]byte(token.Token), []byte(value)) == 1 {
+			return token, true
+		}
+	}
+
+	return Token{}, false
+}
+
+// Returns true if t is granted scope, false otherwise.
+func (t Token) allows(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the token carried by r (as a bearer token, or as the password of basic authentication).
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+
+	return ""
+}
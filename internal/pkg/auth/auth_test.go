@@ -0,0 +1,144 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "auth" package.
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/auth"
+)
+
+// UT: Load the tokens accepted by a server.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input   string
+		wantErr bool
+	}{
+		{
+			input: "{\"tokens\": [{\"name\": \"CI\", \"token\": \"s3cr3t\", \"scopes\": [\"ingest\"]}]}",
+		},
+		{
+			input: "{\"tokens\": [{\"name\": \"Team\", \"token\": \"t34m\", \"scopes\": [\"read\", \"ingest\"]}]}",
+		},
+		{
+			input:   "{\"tokens\": []}",
+			wantErr: true,
+		},
+		{
+			input:   "{\"tokens\": [{\"name\": \"CI\", \"token\": \"\", \"scopes\": [\"ingest\"]}]}",
+			wantErr: true,
+		},
+		{
+			input: "{\"tokens\": [{\"name\": \"CI\", \"token\": \"s3cr3t\", \"scopes\": [\"ingest\"]}, " +
+				"{\"name\": \"Team\", \"token\": \"s3cr3t\", \"scopes\": [\"read\"]}]}",
+			wantErr: true,
+		},
+		{
+			input:   "{\"tokens\": [{\"name\": \"CI\", \"token\": \"s3cr3t\", \"scopes\": [\"admin\"]}]}",
+			wantErr: true,
+		},
+		{
+			input:   "{\"tokens\": [{\"name\": \"CI\", \"secret\": \"s3cr3t\"}]}",
+			wantErr: true,
+		},
+	} {
+		// ACT.
+		_, err := auth.Load(strings.NewReader(tc.input))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the tokens accepted by a server.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error: %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.wantErr, err)
+	}
+}
+
+// UT: Authenticate the requests sent to a server.
+func TestRequire(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	cfg, err := auth.Load(strings.NewReader("{\"tokens\": [" +
+		"{\"name\": \"CI\", \"token\": \"ingest-token\", \"scopes\": [\"ingest\"]}, " +
+		"{\"name\": \"Team\", \"token\": \"read-token\", \"scopes\": [\"read\"]}]}"))
+
+	if err != nil {
+		t.Fatalf("Load() = %v, want <nil>", err)
+	}
+
+	handler := auth.Require(cfg, auth.MethodScope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tc := range []struct {
+		method   string
+		token    string
+		basic    bool
+		wantCode int
+	}{
+		{method: http.MethodGet, wantCode: http.StatusUnauthorized},
+		{method: http.MethodGet, token: "wrong-token", wantCode: http.StatusUnauthorized},
+		{method: http.MethodGet, token: "read-token", wantCode: http.StatusNoContent},
+		{method: http.MethodGet, token: "read-token", basic: true, wantCode: http.StatusNoContent},
+		{method: http.MethodPost, token: "read-token", wantCode: http.StatusForbidden},
+		{method: http.MethodPost, token: "ingest-token", wantCode: http.StatusNoContent},
+		{method: http.MethodGet, token: "ingest-token", wantCode: http.StatusForbidden},
+		{method: http.MethodHead, token: "read-token", wantCode: http.StatusNoContent},
+		{method: http.MethodOptions, token: "read-token", wantCode: http.StatusNoContent},
+		{method: http.MethodDelete, token: "read-token", wantCode: http.StatusForbidden},
+		{method: http.MethodPatch, token: "ingest-token", wantCode: http.StatusNoContent},
+		{method: "PROPFIND", token: "read-token", wantCode: http.StatusMethodNotAllowed},
+		{method: "PROPFIND", token: "ingest-token", wantCode: http.StatusMethodNotAllowed},
+		{method: "PROPFIND", wantCode: http.StatusUnauthorized},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, "/api/runs", nil)
+
+		if tc.basic {
+			req.SetBasicAuth("user", tc.token)
+		} else if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+
+		// ACT.
+		handler.ServeHTTP(rec, req)
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Authenticate the requests sent to a server.\n"+
+			"Input:      %s with token %q (basic: %v)\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", tc.method, tc.token, tc.basic, tc.wantCode, rec.Code)
+	}
+}
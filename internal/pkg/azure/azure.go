@@ -0,0 +1,278 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package azure publishes .NET test result(s) to Azure DevOps, with the Test Runs REST API (as the test tasks of
+// Azure Pipelines do).
+//
+// A test run is published in four steps: the run is created, its results are added (in batches), the HTML report is
+// attached to it, and the run is completed. The published run shows up on the "Tests" tab of the build it belongs to.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The version of the REST API.
+const apiVersion = "7.1"
+
+// The version of the REST API used to attach files to a run (which is still in preview).
+const attachmentsAPIVersion = "7.1-preview.1"
+
+// The maximum number of results added to a run with a single request.
+const maxResultsPerRequest = 1000
+
+// The outcomes of the results in Azure DevOps, by result of a test.
+var outcomes = map[string]string{
+	"Pass":   "Passed",
+	"Fail":   "Failed",
+	"Skip":   "NotExecuted",
+	"NotRun": "NotExecuted",
+}
+
+// Run describes a test run which is published to Azure DevOps.
+type Run struct {
+	Name    string        // The title of the run.
+	BuildID int           // The ID of the build the run belongs to (0 if it doesn't belong to a build).
+	TestRun xunit.TestRun // The test run itself.
+	Report  []byte        // The HTML report attached to the run (nil to attach no report).
+}
+
+// Error is returned when Azure DevOps responds with an error.
+type Error struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The message of the error, as returned by Azure DevOps.
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return fmt.Sprintf("azure: %s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Publisher publishes test runs to a project in Azure DevOps.
+type Publisher struct {
+	baseURL    string       // The URL of the project (without a trailing slash).
+	token      string       // The token sent with each request.
+	httpClient *http.Client // The client which sends the requests.
+}
+
+// The representation of a test run which is created.
+type runJSON struct {
+	Name      string         `json:"name"`
+	Automated bool           `json:"automated"`
+	State     string         `json:"state"`
+	Build     *referenceJSON `json:"build,omitempty"`
+}
+
+// The representation of a reference to another resource (e.g. a build).
+type referenceJSON struct {
+	ID string `json:"id"`
+}
+
+// The representation of the result of a test.
+type resultJSON struct {
+	TestCaseTitle        string  `json:"testCaseTitle"`
+	AutomatedTestName    string  `json:"automatedTestName"`
+	AutomatedTestStorage string  `json:"automatedTestStorage"`
+	Outcome              string  `json:"outcome"`
+	State                string  `json:"state"`
+	DurationInMs         float64 `json:"durationInMs"`
+	ErrorMessage         string  `json:"errorMessage,omitempty"`
+	StackTrace           string  `json:"stackTrace,omitempty"`
+	Comment              string  `json:"comment,omitempty"`
+}
+
+// The representation of a file which is attached to a test run.
+type attachmentJSON struct {
+	AttachmentType string `json:"attachmentType"`
+	FileName       string `json:"fileName"`
+	Stream         string `json:"stream"` // The content of the file, in base64.
+	Comment        string `json:"comment"`
+}
+
+// New returns a publisher for the project in the organization (or collection) at collectionURL (e.g.
+// "https://dev.azure.com/contoso"), which authenticates with token (a personal access token, or the
+// $(System.AccessToken) of a pipeline) and sends its requests with httpClient (or http.DefaultClient if it's nil).
+func New(collectionURL, project, token string, httpClient *http.Client) *Publisher {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	baseURL := strings.TrimSuffix(collectionURL, "/") + "/" + url.PathEscape(project)
+
+	return &Publisher{baseURL: baseURL, token: token, httpClient: httpClient}
+}
+
+// Publish publishes run, and returns the ID it has been assigned by Azure DevOps.
+// If the results can't be published, the run is aborted (so it isn't left in progress).
+func (p *Publisher) Publish(ctx context.Context, run Run) (int, error) {
+	body := runJSON{Name: run.Name, Automated: true, State: "InProgress"}
+
+	if run.BuildID != 0 {
+		body.Build = &referenceJSON{ID: strconv.Itoa(run.BuildID)}
+	}
+
+	var created struct {
+		ID int `json:"id"`
+	}
+
+	if err := p.do(ctx, http.MethodPost, "/_apis/test/runs", apiVersion, body, &created); err != nil {
+		return 0, err
+	}
+
+	runPath := "/_apis/test/runs/" + strconv.Itoa(created.ID)
+
+	if err := p.publishResults(ctx, runPath, run); err != nil {
+		// NOTE: Aborting the run is a best effort, the original error is more relevant.
+		p.do(ctx, http.MethodPatch, runPath, apiVersion, map[string]string{"state": "Aborted"}, nil)
+
+		return 0, err
+	}
+
+	completed := map[string]string{"state": "Completed"}
+
+	if err := p.do(ctx, http.MethodPatch, runPath, apiVersion, completed, nil); err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// Adds the results of run (and its report) to the run at runPath.
+func (p *Publisher) publishResults(ctx context.Context, runPath string, run Run) error {
+	results := newResultsJSON(run.TestRun)
+
+	for len(results) > 0 {
+		batch := results[:min(len(results), maxResultsPerRequest)]
+		results = results[len(batch):]
+
+		if err := p.do(ctx, http.MethodPost, runPath+"/results", apiVersion, batch, nil); err != nil {
+			return err
+		}
+	}
+
+	if run.Report == nil {
+		return nil
+	}
+
+	attachment := attachmentJSON{
+		AttachmentType: "GeneralAttachment",
+		FileName:       "report.html",
+		Stream:         base64.StdEncoding.EncodeToString(run.Report),
+		Comment:        "The HTML report of the test run.",
+	}
+
+	return p.do(ctx, http.MethodPost, runPath+"/attachments", attachmentsAPIVersion, attachment, nil)
+}
+
+// Returns the representation of the results of the tests of testRun.
+func newResultsJSON(testRun xunit.TestRun) []resultJSON {
+	resultSet := make([]resultJSON, 0)
+
+	for _, a := range testRun.Assemblies {
+		a.Walk(func(_ []string, tc xunit.TestCase) {
+			outcome, ok := outcomes[tc.Result]
+
+			if !ok {
+				outcome = "None"
+			}
+
+			resultSet = append(resultSet, resultJSON{
+				TestCaseTitle:        tc.Name,
+				AutomatedTestName:    tc.Name,
+				AutomatedTestStorage: a.Name,
+				Outcome:              outcome,
+				State:                "Completed",
+				DurationInMs:         float64(tc.Duration.Microseconds()) / 1000,
+				ErrorMessage:         tc.Failure.Message,
+				StackTrace:           tc.Failure.StackTrace,
+				Comment:              tc.Reason,
+			})
+		})
+	}
+
+	return resultSet
+}
+
+// Sends a request for path (relative to the project) with body as JSON, and decodes the (JSON) response into v (if
+// v isn't nil).
+func (p *Publisher) do(ctx context.Context, method, path, version string, body, v any) error {
+	data, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	target := p.baseURL + path + "?api-version=" + version
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	// NOTE: Azure DevOps accepts both personal access tokens and the tokens of pipelines as the password.
+	req.SetBasicAuth("", p.token)
+
+	resp, err := p.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body struct {
+			Message string `json:"message"`
+		}
+
+		if json.NewDecoder(resp.Body).Decode(&body) != nil || body.Message == "" {
+			body.Message = "unexpected response"
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Message: body.Message}
+	}
+
+	if v == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
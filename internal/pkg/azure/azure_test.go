@@ -0,0 +1,223 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "azure" package.
+package azure_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/azure"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A request received by a fake Azure DevOps server.
+type request struct {
+	method string // The method of the request.
+	target string // The path (and query) of the request.
+	body   any    // The (decoded) JSON body of the request.
+}
+
+// Returns a fake Azure DevOps server which records the requests it receives, and responds with failCode to the
+// requests adding results (if failCode isn't 0).
+func newServer(t *testing.T, failCode int) (*httptest.Server, func() []request) {
+	var mu sync.Mutex
+	var requests []request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			t.Errorf("Decode() = %v, want <nil>", err)
+		}
+
+		if _, token, _ := r.BasicAuth(); token != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		mu.Lock()
+		requests = append(requests, request{method: r.Method, target: r.URL.RequestURI(), body: body})
+		mu.Unlock()
+
+		switch {
+		case failCode != 0 && strings.HasSuffix(r.URL.Path, "/results"):
+			w.WriteHeader(failCode)
+			io.WriteString(w, `{"message": "The results are invalid."}`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/_apis/test/runs"):
+			io.WriteString(w, `{"id": 42}`)
+		default:
+			io.WriteString(w, `{}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+// Returns a test run with a passed, a failed and a skipped test.
+func newTestRun() xunit.TestRun {
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" total=\"3\" passed=\"1\" failed=\"1\" skipped=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.Tests.Passes\" result=\"Pass\" time=\"0.25\" />\n" +
+		"      <test name=\"NS.Tests.Fails\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"AssertException\">\n" +
+		"          <message>Expected: 1</message>\n" +
+		"          <stack-trace>at NS.Tests.Fails()</stack-trace>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Tests.Skips\" result=\"Skip\" time=\"0\">\n" +
+		"        <reason>Not yet.</reason>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	return testRun
+}
+
+// UT: Publish a test run (with its report) to Azure DevOps.
+func TestPublisher_Publish(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv, requests := newServer(t, 0)
+	publisher := azure.New(srv.URL+"/contoso/", "My Project", "s3cr3t", srv.Client())
+	run := azure.Run{Name: "Unit tests", BuildID: 7, TestRun: newTestRun(), Report: []byte("<html></html>")}
+
+	// ACT.
+	id, err := publisher.Publish(context.Background(), run)
+
+	// ASSERT.
+	assert.NoError(t, err, "Publish()")
+
+	assert.Equal(t, id, 42, "", "\n\n"+
+		"UT Name:    Publish a test run (with its report) to Azure DevOps.\n"+
+		"\033[32mExpected:   %d\033[0m\n"+
+		"\033[31mActual:     %d\033[0m\n\n", 42, id)
+
+	want := []request{
+		{
+			method: http.MethodPost,
+			target: "/contoso/My%20Project/_apis/test/runs?api-version=7.1",
+			body: map[string]any{
+				"name": "Unit tests", "automated": true, "state": "InProgress", "build": map[string]any{"id": "7"},
+			},
+		},
+		{
+			method: http.MethodPost,
+			target: "/contoso/My%20Project/_apis/test/runs/42/results?api-version=7.1",
+			body: []any{
+				map[string]any{
+					"testCaseTitle": "NS.Tests.Passes", "automatedTestName": "NS.Tests.Passes",
+					"automatedTestStorage": "App.dll", "outcome": "Passed", "state": "Completed", "durationInMs": 250.0,
+				},
+				map[string]any{
+					"testCaseTitle": "NS.Tests.Fails", "automatedTestName": "NS.Tests.Fails",
+					"automatedTestStorage": "App.dll", "outcome": "Failed", "state": "Completed", "durationInMs": 1000.0,
+					"errorMessage": "Expected: 1", "stackTrace": "at NS.Tests.Fails()",
+				},
+				map[string]any{
+					"testCaseTitle": "NS.Tests.Skips", "automatedTestName": "NS.Tests.Skips",
+					"automatedTestStorage": "App.dll", "outcome": "NotExecuted", "state": "Completed", "durationInMs": 0.0,
+					"comment": "Not yet.",
+				},
+			},
+		},
+		{
+			method: http.MethodPost,
+			target: "/contoso/My%20Project/_apis/test/runs/42/attachments?api-version=7.1-preview.1",
+			body: map[string]any{
+				"attachmentType": "GeneralAttachment", "fileName": "report.html", "stream": "PGh0bWw+PC9odG1sPg==",
+				"comment": "The HTML report of the test run.",
+			},
+		},
+		{
+			method: http.MethodPatch,
+			target: "/contoso/My%20Project/_apis/test/runs/42?api-version=7.1",
+			body:   map[string]any{"state": "Completed"},
+		},
+	}
+
+	assert.EqualFn(t, requests(), want, func(got, want []request) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Publish a test run (with its report) to Azure DevOps.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, requests())
+}
+
+// UT: Publish a test run whose results are rejected by Azure DevOps.
+func TestPublisher_Publish_Rejected(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv, requests := newServer(t, http.StatusBadRequest)
+	publisher := azure.New(srv.URL+"/contoso", "App", "s3cr3t", srv.Client())
+
+	// ACT.
+	_, err := publisher.Publish(context.Background(), azure.Run{Name: "Unit tests", TestRun: newTestRun()})
+
+	// ASSERT.
+	var azureErr *azure.Error
+	rejected := errors.As(err, &azureErr) && azureErr.StatusCode == http.StatusBadRequest &&
+		azureErr.Message == "The results are invalid."
+
+	assert.Equal(t, rejected, true, "", "\n\n"+
+		"UT Name:    Publish a test run whose results are rejected by Azure DevOps.\n"+
+		"\033[32mExpected:   azure: The results are invalid. (400 Bad Request)\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+
+	got := requests()[len(requests())-1]
+	want := request{
+		method: http.MethodPatch,
+		target: "/contoso/App/_apis/test/runs/42?api-version=7.1",
+		body:   map[string]any{"state": "Aborted"},
+	}
+
+	assert.EqualFn(t, got, want, func(got, want request) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Publish a test run whose results are rejected by Azure DevOps.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
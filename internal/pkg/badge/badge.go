@@ -0,0 +1,193 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package badge contains functions for rendering status badges (in the style of https://shields.io) as SVG images.
+package badge
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The colors of a badge.
+const (
+	Green     = "#4c1"
+	Yellow    = "#dfb317"
+	Orange    = "#fe7d37"
+	Red       = "#e05d44"
+	LightGrey = "#9f9f9f"
+)
+
+// The horizontal padding (in pixels) around the texts of a badge.
+const padding = 10
+
+// Badge is a status badge, which consists of a label (on the left) and a message (on the right).
+type Badge struct {
+	Label   string // The text on the left side of the badge.
+	Message string // The text on the right side of the badge.
+	Color   string // The background color of the right side of the badge.
+}
+
+// Status returns a badge showing whether the tests in stats passed (e.g. "tests | passing"). The badge is green when
+// no test failed, and red otherwise.
+func Status(stats xunit.Stats) Badge {
+	switch {
+	case stats.TotalCount == 0:
+		return Badge{Label: "tests", Message: "no tests", Color: LightGrey}
+	case stats.FailedCount > 0 || stats.ErrorCount > 0:
+		return Badge{Label: "tests", Message: "failing", Color: Red}
+	default:
+		return Badge{Label: "tests", Message: "passing", Color: Green}
+	}
+}
+
+// Tests returns a badge showing the number of passed, failed and skipped tests in stats (e.g. "tests | 1234 passed,
+// 2 failed"). The badge is green when no test failed, and red otherwise.
+func Tests(stats xunit.Stats) Badge {
+	if stats.TotalCount == 0 {
+		return Badge{Label: "tests", Message: "no tests", Color: LightGrey}
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d passed", stats.PassedCount)
+
+	if stats.FailedCount > 0 {
+		fmt.Fprintf(&b, ", %d failed", stats.FailedCount)
+	}
+
+	if stats.SkippedCount > 0 {
+		fmt.Fprintf(&b, ", %d skipped", stats.SkippedCount)
+	}
+
+	if stats.FailedCount > 0 {
+		return Badge{Label: "tests", Message: b.String(), Color: Red}
+	}
+
+	return Badge{Label: "tests", Message: b.String(), Color: Green}
+}
+
+// PassRate returns a badge showing the pass rate of stats (e.g. "pass rate | 99.8%").
+// The color of the badge ranges from green (all tests passed) to red (less than 75% of the tests passed).
+func PassRate(stats xunit.Stats) Badge {
+	if stats.PassedCount+stats.FailedCount == 0 {
+		return Badge{Label: "pass rate", Message: "unknown", Color: LightGrey}
+	}
+
+	return Badge{Label: "pass rate", Message: fmtPercentage(stats.PassRate), Color: scaleColor(stats.PassRate)}
+}
+
+// Coverage returns a badge showing the coverage rate of c (e.g. "coverage | 87.5%").
+// The color of the badge ranges from green (at least 80% is covered) to red (less than 40% is covered).
+func Coverage(c coverage.Counter) Badge {
+	if c.Total == 0 {
+		return Badge{Label: "coverage", Message: "unknown", Color: LightGrey}
+	}
+
+	color := Red
+
+	switch rate := c.Rate(); {
+	case rate >= 80:
+		color = Green
+	case rate >= 60:
+		color = Yellow
+	case rate >= 40:
+		color = Orange
+	}
+
+	return Badge{Label: "coverage", Message: fmtPercentage(c.Rate()), Color: color}
+}
+
+// WriteSVG writes the badge to w as an SVG image.
+func (badge Badge) WriteSVG(w io.Writer) error {
+	lw, mw := textWidth(badge.Label)+2*padding, textWidth(badge.Message)+2*padding
+	label, msg := html.EscapeString(badge.Label), html.EscapeString(badge.Message)
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%">`+
+		`<stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/>`+
+		`</linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)">`+
+		`<rect width="%[2]d" height="20" fill="#555"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<rect width="%[1]d" height="20" fill="url(#s)"/>`+
+		`</g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>`+
+		`</g>`+
+		`</svg>`+"\n",
+		lw+mw, lw, mw, label, msg, html.EscapeString(badge.Color), lw/2, lw+mw/2)
+
+	return err
+}
+
+// Returns the color of a badge showing percentage p.
+func scaleColor(p float64) string {
+	switch {
+	case p >= 100:
+		return Green
+	case p >= 90:
+		return Yellow
+	case p >= 75:
+		return Orange
+	default:
+		return Red
+	}
+}
+
+// Returns p as a percentage, with at most one decimal.
+func fmtPercentage(p float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", p), ".0") + "%"
+}
+
+// Returns the (approximate) width, in pixels, of s when rendered in an 11px Verdana font.
+func textWidth(s string) int {
+	width := 0
+
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iIl.,:;'|!", r):
+			width += 4
+		case strings.ContainsRune(" ()[]ftjr", r):
+			width += 5
+		case strings.ContainsRune("mwMW%", r):
+			width += 11
+		case r >= 'A' && r <= 'Z':
+			width += 8
+		default:
+			width += 7
+		}
+	}
+
+	return width
+}
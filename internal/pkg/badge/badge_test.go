@@ -0,0 +1,233 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "badge" package.
+package badge_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/badge"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Get the badge showing the status of the tests.
+func TestStatus(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		stats xunit.Stats
+		want  badge.Badge
+	}{
+		{
+			stats: xunit.Stats{},
+			want:  badge.Badge{Label: "tests", Message: "no tests", Color: badge.LightGrey},
+		},
+		{
+			stats: xunit.Stats{TotalCount: 2, PassedCount: 1, SkippedCount: 1},
+			want:  badge.Badge{Label: "tests", Message: "passing", Color: badge.Green},
+		},
+		{
+			stats: xunit.Stats{TotalCount: 2, PassedCount: 1, FailedCount: 1},
+			want:  badge.Badge{Label: "tests", Message: "failing", Color: badge.Red},
+		},
+		{
+			stats: xunit.Stats{TotalCount: 1, PassedCount: 1, ErrorCount: 1},
+			want:  badge.Badge{Label: "tests", Message: "failing", Color: badge.Red},
+		},
+	} {
+		// ACT.
+		got := badge.Status(tc.stats)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the badge showing the status of the tests.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.stats, tc.want, got)
+	}
+}
+
+// UT: Get the badge showing the number of tests.
+func TestTests(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		stats xunit.Stats
+		want  badge.Badge
+	}{
+		{
+			stats: xunit.Stats{},
+			want:  badge.Badge{Label: "tests", Message: "no tests", Color: badge.LightGrey},
+		},
+		{
+			stats: xunit.Stats{TotalCount: 1234, PassedCount: 1234},
+			want:  badge.Badge{Label: "tests", Message: "1234 passed", Color: badge.Green},
+		},
+		{
+			stats: xunit.Stats{TotalCount: 1239, PassedCount: 1234, FailedCount: 2, SkippedCount: 3},
+			want:  badge.Badge{Label: "tests", Message: "1234 passed, 2 failed, 3 skipped", Color: badge.Red},
+		},
+	} {
+		// ACT.
+		got := badge.Tests(tc.stats)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the badge showing the number of tests.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.stats, tc.want, got)
+	}
+}
+
+// UT: Get the badge showing the pass rate.
+func TestPassRate(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		stats xunit.Stats
+		want  badge.Badge
+	}{
+		{
+			stats: xunit.Stats{},
+			want:  badge.Badge{Label: "pass rate", Message: "unknown", Color: badge.LightGrey},
+		},
+		{
+			stats: xunit.Stats{PassedCount: 10, PassRate: 100},
+			want:  badge.Badge{Label: "pass rate", Message: "100%", Color: badge.Green},
+		},
+		{
+			stats: xunit.Stats{PassedCount: 998, FailedCount: 2, PassRate: 99.8},
+			want:  badge.Badge{Label: "pass rate", Message: "99.8%", Color: badge.Yellow},
+		},
+		{
+			stats: xunit.Stats{PassedCount: 8, FailedCount: 2, PassRate: 80},
+			want:  badge.Badge{Label: "pass rate", Message: "80%", Color: badge.Orange},
+		},
+		{
+			stats: xunit.Stats{PassedCount: 1, FailedCount: 2, PassRate: 33.333},
+			want:  badge.Badge{Label: "pass rate", Message: "33.3%", Color: badge.Red},
+		},
+	} {
+		// ACT.
+		got := badge.PassRate(tc.stats)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the badge showing the pass rate.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.stats, tc.want, got)
+	}
+}
+
+// UT: Get the badge showing the code coverage.
+func TestCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		counter coverage.Counter
+		want    badge.Badge
+	}{
+		{
+			counter: coverage.Counter{},
+			want:    badge.Badge{Label: "coverage", Message: "unknown", Color: badge.LightGrey},
+		},
+		{
+			counter: coverage.Counter{Covered: 7, Total: 8},
+			want:    badge.Badge{Label: "coverage", Message: "87.5%", Color: badge.Green},
+		},
+		{
+			counter: coverage.Counter{Covered: 5, Total: 8},
+			want:    badge.Badge{Label: "coverage", Message: "62.5%", Color: badge.Yellow},
+		},
+		{
+			counter: coverage.Counter{Covered: 1, Total: 2},
+			want:    badge.Badge{Label: "coverage", Message: "50%", Color: badge.Orange},
+		},
+		{
+			counter: coverage.Counter{Covered: 1, Total: 8},
+			want:    badge.Badge{Label: "coverage", Message: "12.5%", Color: badge.Red},
+		},
+	} {
+		// ACT.
+		got := badge.Coverage(tc.counter)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the badge showing the code coverage.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.counter, tc.want, got)
+	}
+}
+
+// UT: Render a badge as an SVG image.
+func TestBadgeWriteSVG(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		badge badge.Badge
+		want  string
+	}{
+		{
+			badge: badge.Badge{Label: "a", Message: "<b>", Color: badge.Green},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="68" height="20" role="img" aria-label="a: &lt;b&gt;">` +
+				`<title>a: &lt;b&gt;</title>` +
+				`<linearGradient id="s" x2="0" y2="100%">` +
+				`<stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/>` +
+				`</linearGradient>` +
+				`<clipPath id="r"><rect width="68" height="20" rx="3" fill="#fff"/></clipPath>` +
+				`<g clip-path="url(#r)">` +
+				`<rect width="27" height="20" fill="#555"/>` +
+				`<rect x="27" width="41" height="20" fill="#4c1"/>` +
+				`<rect width="68" height="20" fill="url(#s)"/>` +
+				`</g>` +
+				`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+				`<text x="13" y="15" fill="#010101" fill-opacity=".3">a</text><text x="13" y="14">a</text>` +
+				`<text x="47" y="15" fill="#010101" fill-opacity=".3">&lt;b&gt;</text><text x="47" y="14">&lt;b&gt;</text>` +
+				`</g>` +
+				`</svg>` + "\n",
+		},
+	} {
+		// ARRANGE.
+		var sb strings.Builder
+
+		// ACT.
+		err := tc.badge.WriteSVG(&sb)
+
+		// ASSERT.
+		assert.NoError(t, err, "WriteSVG()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Render a badge as an SVG image.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.badge, tc.want, sb.String())
+	}
+}
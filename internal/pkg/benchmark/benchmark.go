@@ -0,0 +1,152 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package benchmark contains functions for loading (and comparing) the results of benchmarks, as exported by
+// BenchmarkDotNet (in its JSON or CSV format).
+// More information regarding BenchmarkDotNet can be found @ https://benchmarkdotnet.org.
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Report contains the results of the benchmarks of a single run.
+type Report struct {
+	Title      string      // The title of the run (e.g. the name of the class containing the benchmarks).
+	Benchmarks []Benchmark // The benchmarks, in the order of the export they're loaded from.
+}
+
+// Benchmark contains the result of a single benchmark.
+type Benchmark struct {
+	Type       string  // The name of the class containing the benchmark.
+	Method     string  // The name of the method of the benchmark.
+	Parameters string  // The values of the parameters of the benchmark (e.g. "N=10"), if any.
+	Mean       float64 // The mean duration of an operation, in nanoseconds.
+	StdDev     float64 // The standard deviation of the duration of an operation, in nanoseconds.
+	Allocated  int64   // The number of bytes allocated per operation, or -1 if the allocations weren't measured.
+}
+
+// Comparison contains the result of a benchmark, compared to its result in a baseline run.
+type Comparison struct {
+	Benchmark
+	Baseline *Benchmark // The result of the benchmark in the baseline run (or nil if it isn't part of that run).
+}
+
+// The document of the JSON export.
+type document struct {
+	Title      string `json:"Title"`
+	Benchmarks []struct {
+		Type       string `json:"Type"`
+		Method     string `json:"Method"`
+		Parameters string `json:"Parameters"`
+		Statistics *struct {
+			Mean              float64 `json:"Mean"`
+			StandardDeviation float64 `json:"StandardDeviation"`
+		} `json:"Statistics"`
+		Memory *struct {
+			BytesAllocatedPerOperation int64 `json:"BytesAllocatedPerOperation"`
+		} `json:"Memory"`
+	} `json:"Benchmarks"`
+}
+
+// Name returns the name of b: its type (if known) and method, followed by its parameters (if any).
+func (b Benchmark) Name() string {
+	name := b.Method
+
+	if b.Type != "" {
+		name = b.Type + "." + name
+	}
+
+	if b.Parameters != "" {
+		name += "(" + b.Parameters + ")"
+	}
+
+	return name
+}
+
+// Ratio returns the mean duration of c relative to its baseline (e.g. 2 for twice as slow), or 0 if c doesn't have a
+// baseline.
+func (c Comparison) Ratio() float64 {
+	if c.Baseline == nil || c.Baseline.Mean == 0 {
+		return 0
+	}
+
+	return c.Mean / c.Baseline.Mean
+}
+
+// Load reads a JSON export of BenchmarkDotNet (e.g. "*-report-full.json" or "*-report-brief.json") from rdr, and
+// returns the results it contains.
+// The benchmarks which didn't produce a result (e.g. because they failed) are omitted.
+func Load(rdr io.Reader) (Report, error) {
+	var doc document
+
+	if err := json.NewDecoder(rdr).Decode(&doc); err != nil {
+		return Report{}, fmt.Errorf("benchmark: %w", err)
+	}
+
+	report := Report{Title: doc.Title, Benchmarks: make([]Benchmark, 0, len(doc.Benchmarks))}
+
+	for _, b := range doc.Benchmarks {
+		if b.Statistics == nil {
+			continue
+		}
+
+		res := Benchmark{
+			Type:       b.Type,
+			Method:     b.Method,
+			Parameters: b.Parameters,
+			Mean:       b.Statistics.Mean,
+			StdDev:     b.Statistics.StandardDeviation,
+			Allocated:  -1,
+		}
+
+		if b.Memory != nil {
+			res.Allocated = b.Memory.BytesAllocatedPerOperation
+		}
+
+		report.Benchmarks = append(report.Benchmarks, res)
+	}
+
+	return report, nil
+}
+
+// Compare returns the benchmarks of report, each compared to the benchmark with the same name in baseline.
+func Compare(report, baseline Report) []Comparison {
+	byName := make(map[string]*Benchmark, len(baseline.Benchmarks))
+
+	for i := range baseline.Benchmarks {
+		byName[baseline.Benchmarks[i].Name()] = &baseline.Benchmarks[i]
+	}
+
+	resultSet := make([]Comparison, 0, len(report.Benchmarks))
+
+	for _, b := range report.Benchmarks {
+		resultSet = append(resultSet, Comparison{Benchmark: b, Baseline: byName[b.Name()]})
+	}
+
+	return resultSet
+}
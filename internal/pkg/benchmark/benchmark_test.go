@@ -0,0 +1,164 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "benchmark" package.
+package benchmark_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/benchmark"
+)
+
+// UT: Load a JSON export of BenchmarkDotNet.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	jsonData := `{
+  "Title": "App.Benchmarks.ParserBenchmarks-20230710-205319",
+  "HostEnvironmentInfo": { "BenchmarkDotNetVersion": "0.13.6" },
+  "Benchmarks": [
+    {
+      "DisplayInfo": "ParserBenchmarks.Parse: DefaultJob [N=10]",
+      "Type": "ParserBenchmarks",
+      "Method": "Parse",
+      "Parameters": "N=10",
+      "FullName": "App.Benchmarks.ParserBenchmarks.Parse(N: 10)",
+      "Statistics": { "Mean": 1234.5, "StandardDeviation": 12.5 },
+      "Memory": { "Gen0Collections": 1, "BytesAllocatedPerOperation": 256 }
+    },
+    {
+      "Type": "ParserBenchmarks",
+      "Method": "Tokenize",
+      "Parameters": "",
+      "Statistics": { "Mean": 0.5, "StandardDeviation": 0.01 }
+    },
+    { "Type": "ParserBenchmarks", "Method": "Broken", "Statistics": null }
+  ]
+}`
+
+	// ACT.
+	got, err := benchmark.Load(strings.NewReader(jsonData))
+
+	// ASSERT.
+	want := benchmark.Report{
+		Title: "App.Benchmarks.ParserBenchmarks-20230710-205319",
+		Benchmarks: []benchmark.Benchmark{
+			{Type: "ParserBenchmarks", Method: "Parse", Parameters: "N=10", Mean: 1234.5, StdDev: 12.5, Allocated: 256},
+			{Type: "ParserBenchmarks", Method: "Tokenize", Mean: 0.5, StdDev: 0.01, Allocated: -1},
+		},
+	}
+
+	assert.NoError(t, err, "Load()")
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Load a JSON export of BenchmarkDotNet.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Load a CSV export of BenchmarkDotNet.
+func TestLoadCSV(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		csvData string
+		want    benchmark.Report
+		wantErr bool
+	}{
+		{
+			name: "An export with parameters and allocations",
+			csvData: "Method,Job,AnalyzeLaunchVariance,Runtime,N,Mean,Error,StdDev,Gen0,Allocated\n" +
+				"Parse,DefaultJob,False,.NET 7.0,10,\"1,234.5 ns\",1.0 ns,12.5 ns,0.0010,256 B\n" +
+				"Parse,DefaultJob,False,.NET 7.0,100,1.5 μs,1.0 ns,0.02 μs,0.0100,1.5 KB\n" +
+				"Tokenize,DefaultJob,False,.NET 7.0,10,2 ms,1.0 ns,NA,-,-\n" +
+				"Broken,DefaultJob,False,.NET 7.0,10,NA,NA,NA,NA,NA\n",
+			want: benchmark.Report{Benchmarks: []benchmark.Benchmark{
+				{Method: "Parse", Parameters: "N=10", Mean: 1234.5, StdDev: 12.5, Allocated: 256},
+				{Method: "Parse", Parameters: "N=100", Mean: 1500, StdDev: 20, Allocated: 1536},
+				{Method: "Tokenize", Parameters: "N=10", Mean: 2e6, Allocated: 0},
+			}},
+		},
+		{
+			name:    "An export with an invalid duration",
+			csvData: "Method,Mean\nParse,1.5 weeks\n",
+			wantErr: true,
+		},
+		{
+			name:    "An export without a Mean column",
+			csvData: "Method,Median\nParse,1 ns\n",
+			wantErr: true,
+		},
+	} {
+		// ACT.
+		got, err := benchmark.LoadCSV(strings.NewReader(tc.csvData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load a CSV export of BenchmarkDotNet.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a CSV export of BenchmarkDotNet.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Compare the benchmarks of a run against a baseline run.
+func TestCompare(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	report := benchmark.Report{Benchmarks: []benchmark.Benchmark{
+		{Type: "B", Method: "Parse", Parameters: "N=10", Mean: 300},
+		{Type: "B", Method: "Parse", Parameters: "N=100", Mean: 3000},
+	}}
+	baseline := benchmark.Report{Benchmarks: []benchmark.Benchmark{
+		{Type: "B", Method: "Parse", Parameters: "N=10", Mean: 200},
+	}}
+
+	// ACT.
+	comparisons := benchmark.Compare(report, baseline)
+
+	// ASSERT.
+	got := []float64{comparisons[0].Ratio(), comparisons[1].Ratio()}
+	want := []float64{1.5, 0}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Compare the benchmarks of a run against a baseline run.\n"+
+		"\033[32mExpected:   Ratios %v\033[0m\n"+
+		"\033[31mActual:     Ratios %v\033[0m\n\n", want, got)
+}
@@ -0,0 +1,173 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package benchmark
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The columns of a CSV export which aren't parameters of the benchmarks (the statistics, and the characteristics of
+// the jobs running the benchmarks).
+var knownColumns = []string{
+	"Method", "Type", "Namespace", "Job", "Runtime", "Toolchain", "Platform", "Jit", "Server", "Concurrent", "Force",
+	"AnalyzeLaunchVariance", "EvaluateOverhead", "MaxAbsoluteError", "MaxRelativeError", "MinInvokeCount",
+	"MinIterationTime", "OutlierMode", "Affinity", "EnvironmentVariables", "PowerPlanMode", "Arguments",
+	"BuildConfiguration", "Clock", "EngineFactory", "NuGetReferences", "EnableDebugging", "IsMutator",
+	"InvocationCount", "IterationCount", "IterationTime", "LaunchCount", "MaxIterationCount", "MaxWarmupIterationCount",
+	"MemoryRandomization", "MinIterationCount", "MinWarmupIterationCount", "RunStrategy", "UnrollFactor",
+	"WarmupCount", "RetainVm", "AllowVeryLargeObjects", "CpuGroups", "HeapAffinitizeMask", "HeapCount", "NoAffinitize",
+	"Error", "StdDev", "StdErr", "Median", "Min", "Max", "Q1", "Q3", "Op/s", "Ratio", "RatioSD", "Rank", "Baseline",
+	"Gen0", "Gen1", "Gen2", "Allocated", "Alloc Ratio", "Code Size", "Completed Work Items", "Lock Contentions",
+}
+
+// The durations of the units of time of a CSV export, in nanoseconds.
+var timeUnits = map[string]float64{"ns": 1, "μs": 1e3, "us": 1e3, "ms": 1e6, "s": 1e9}
+
+// The sizes of the units of memory of a CSV export, in bytes.
+var sizeUnits = map[string]float64{"B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+
+// LoadCSV reads a CSV export of BenchmarkDotNet (e.g. "*-report.csv") from rdr, and returns the results it contains.
+// The columns which aren't statistics (or characteristics of the job) are the parameters of the benchmarks. The
+// benchmarks which didn't produce a result (e.g. because they failed) are omitted.
+func LoadCSV(rdr io.Reader) (Report, error) {
+	records, err := csv.NewReader(rdr).ReadAll()
+
+	if err != nil {
+		return Report{}, fmt.Errorf("benchmark: %w", err)
+	}
+
+	if len(records) == 0 {
+		return Report{}, errors.New("benchmark: the CSV export doesn't have a header")
+	}
+
+	header := records[0]
+	columns := make(map[string]int, len(header))
+
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	if _, ok := columns["Method"]; !ok {
+		return Report{}, errors.New("benchmark: the CSV export doesn't have a Method column")
+	}
+
+	if _, ok := columns["Mean"]; !ok {
+		return Report{}, errors.New("benchmark: the CSV export doesn't have a Mean column")
+	}
+
+	report := Report{Benchmarks: make([]Benchmark, 0, len(records)-1)}
+
+	for row, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return record[i]
+			}
+
+			return ""
+		}
+
+		mean, ok, err := parseDuration(field("Mean"))
+
+		if err != nil {
+			return Report{}, fmt.Errorf("benchmark: row %d: %w", row+1, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		stdDev, _, err := parseDuration(field("StdDev"))
+
+		if err != nil {
+			return Report{}, fmt.Errorf("benchmark: row %d: %w", row+1, err)
+		}
+
+		b := Benchmark{Type: field("Type"), Method: field("Method"), Mean: mean, StdDev: stdDev, Allocated: -1}
+
+		if _, ok := columns["Allocated"]; ok {
+			if b.Allocated, err = parseSize(field("Allocated")); err != nil {
+				return Report{}, fmt.Errorf("benchmark: row %d: %w", row+1, err)
+			}
+		}
+
+		params := make([]string, 0)
+
+		for i, name := range header[:columns["Mean"]] {
+			if !slices.Contains(knownColumns, name) {
+				params = append(params, name+"="+record[i])
+			}
+		}
+
+		b.Parameters = strings.Join(params, "&")
+		report.Benchmarks = append(report.Benchmarks, b)
+	}
+
+	return report, nil
+}
+
+// Returns the number of nanoseconds in s (e.g. "1,234.5 ns"), and false if s isn't a result (e.g. "NA").
+func parseDuration(s string) (float64, bool, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "" || s == "NA" || s == "?" {
+		return 0, false, nil
+	}
+
+	value, unit, _ := strings.Cut(s, " ")
+	scale, ok := timeUnits[unit]
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+
+	if !ok || err != nil {
+		return 0, false, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return number * scale, true, nil
+}
+
+// Returns the number of bytes in s (e.g. "1.2 KB"). A "-" means that nothing was allocated.
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "-" || s == "0" || s == "" {
+		return 0, nil
+	}
+
+	value, unit, _ := strings.Cut(s, " ")
+	scale, ok := sizeUnits[unit]
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+
+	if !ok || err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	return int64(number * scale), nil
+}
@@ -0,0 +1,302 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package client defines a client for the HTTP API of a DTVisual server (see the "api" package).
+//
+// The types of this package mirror the schemas of the OpenAPI document served on /api/openapi.json (which the tests
+// of this package verify, along with the operations the client uses). The "report" command uses it in its remote mode
+// (--server).
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Run is a test run, with its statistics.
+type Run struct {
+	ID        string `json:"id"`        // The identifier of the run.
+	Name      string `json:"name"`      // The name of the run (e.g. the name of the file it was loaded from).
+	Project   string `json:"project"`   // The project (e.g. repository) the run belongs to.
+	Commit    string `json:"commit"`    // The commit the run tested (if known).
+	Branch    string `json:"branch"`    // The branch the run tested (if known).
+	Computer  string `json:"computer"`  // The name of the computer which executed the tests.
+	User      string `json:"user"`      // The name of the user who executed the tests.
+	Timestamp string `json:"timestamp"` // The time the tests were executed (as stored in the result file).
+	Stats     Stats  `json:"stats"`     // The statistics of the run.
+}
+
+// Stats contains the statistics of a run.
+type Stats struct {
+	Assemblies int      `json:"assemblies"` // The number of assemblies.
+	Errors     int      `json:"errors"`     // The number of errors.
+	Passed     int      `json:"passed"`     // The number of passed tests.
+	Failed     int      `json:"failed"`     // The number of failed tests.
+	Skipped    int      `json:"skipped"`    // The number of skipped tests.
+	NotRun     int      `json:"notRun"`     // The number of tests which weren't run.
+	Total      int      `json:"total"`      // The total number of tests.
+	PassRate   float64  `json:"passRate"`   // The percentage of passed tests.
+	Duration   Duration `json:"duration"`   // The time it took to execute the tests.
+}
+
+// Assembly is an assembly of a run, with its statistics.
+type Assembly struct {
+	Name     string   `json:"name"`     // The name of the assembly.
+	Errors   int      `json:"errors"`   // The number of errors.
+	Passed   int      `json:"passed"`   // The number of passed tests.
+	Failed   int      `json:"failed"`   // The number of failed tests.
+	Skipped  int      `json:"skipped"`  // The number of skipped tests.
+	NotRun   int      `json:"notRun"`   // The number of tests which weren't run.
+	Total    int      `json:"total"`    // The total number of tests.
+	Duration Duration `json:"duration"` // The time it took to execute the tests.
+}
+
+// Test is a test of a run.
+type Test struct {
+	ID         string   `json:"id,omitempty"`         // The identifier of the test.
+	Assembly   string   `json:"assembly"`             // The name of the assembly the test belongs to.
+	Path       []string `json:"path"`                 // The names of the groups the test belongs to.
+	Name       string   `json:"name"`                 // The name of the test.
+	Result     string   `json:"result"`               // The result of the test (e.g. "Pass").
+	Duration   Duration `json:"duration"`             // The time it took to execute the test.
+	SourceFile string   `json:"sourceFile,omitempty"` // The file containing the test.
+	SourceLine int      `json:"sourceLine,omitempty"` // The line (in SourceFile) containing the test.
+	Reason     string   `json:"reason,omitempty"`     // The reason why the test was skipped.
+	Failure    *Failure `json:"failure,omitempty"`    // The failure of the test (nil if it didn't fail).
+}
+
+// Failure is the failure of a test.
+type Failure struct {
+	ExceptionType string `json:"exceptionType,omitempty"` // The type of the exception which failed the test.
+	Message       string `json:"message,omitempty"`       // The message of the exception.
+	StackTrace    string `json:"stackTrace,omitempty"`    // The stack trace of the exception.
+}
+
+// Upload describes a result file which is uploaded with Client.Ingest. Empty fields are omitted.
+type Upload struct {
+	Name    string // The name of the result file.
+	Project string // The project (e.g. repository) the run belongs to.
+	Commit  string // The commit the run tested.
+	Branch  string // The branch the run tested.
+}
+
+// TestFilter restricts the tests returned by Client.Tests. Empty fields don't restrict anything.
+type TestFilter struct {
+	Result   string // Only return the tests with this result (case-insensitive).
+	Assembly string // Only return the tests of the assembly with this name.
+}
+
+// Duration is a time.Duration, which is expressed in seconds in JSON.
+type Duration time.Duration
+
+// UnmarshalJSON parses a number of seconds into d.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+
+	*d = Duration(time.Duration(seconds * float64(time.Second)).Round(time.Microsecond))
+
+	return nil
+}
+
+// MarshalJSON returns d as a number of seconds.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// Error is returned when the server responds with an error.
+type Error struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The message of the error, as returned by the server.
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return fmt.Sprintf("client: %s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Client is a client for the API of a DTVisual server.
+type Client struct {
+	baseURL    string       // The URL of the server (without a trailing slash).
+	httpClient *http.Client // The client which sends the requests.
+	token      string       // The API token sent with each request (if not empty).
+}
+
+// New returns a client for the API of the server at baseURL (e.g. "http://localhost:8080"), which sends its requests
+// with httpClient (or http.DefaultClient if it's nil).
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// WithToken returns a copy of c, which sends token (an API token) with each request.
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.token = token
+
+	return &clone
+}
+
+// Runs returns the runs exposed by the server.
+func (c *Client) Runs(ctx context.Context) ([]Run, error) {
+	var runs []Run
+
+	if err := c.get(ctx, "/api/runs", &runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// Run returns the run with the given ID.
+func (c *Client) Run(ctx context.Context, id string) (Run, error) {
+	var run Run
+
+	if err := c.get(ctx, "/api/runs/"+url.PathEscape(id), &run); err != nil {
+		return Run{}, err
+	}
+
+	return run, nil
+}
+
+// Assemblies returns the assemblies of the run with the given ID.
+func (c *Client) Assemblies(ctx context.Context, id string) ([]Assembly, error) {
+	var assemblies []Assembly
+
+	if err := c.get(ctx, "/api/runs/"+url.PathEscape(id)+"/assemblies", &assemblies); err != nil {
+		return nil, err
+	}
+
+	return assemblies, nil
+}
+
+// Tests returns the tests of the run with the given ID, which match filter.
+func (c *Client) Tests(ctx context.Context, id string, filter TestFilter) ([]Test, error) {
+	query := url.Values{}
+
+	if filter.Result != "" {
+		query.Set("result", filter.Result)
+	}
+
+	if filter.Assembly != "" {
+		query.Set("assembly", filter.Assembly)
+	}
+
+	path := "/api/runs/" + url.PathEscape(id) + "/tests"
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var tests []Test
+
+	if err := c.get(ctx, path, &tests); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// Ingest uploads the result file read from r (in xUnit's v2+ XML format), described by upload, and returns the run
+// which has been created.
+func (c *Client) Ingest(ctx context.Context, upload Upload, r io.Reader) (Run, error) {
+	var run Run
+
+	query := url.Values{}
+
+	for key, value := range map[string]string{
+		"name": upload.Name, "project": upload.Project, "commit": upload.Commit, "branch": upload.Branch,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	path := "/api/runs?" + query.Encode()
+
+	if err := c.do(ctx, http.MethodPost, path, r, http.StatusCreated, &run); err != nil {
+		return Run{}, err
+	}
+
+	return run, nil
+}
+
+// Sends a GET request for path, and decodes the (JSON) response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	return c.do(ctx, http.MethodGet, path, nil, http.StatusOK, v)
+}
+
+// Sends a request for path, and decodes the (JSON) response into v if its status code is wantCode.
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, wantCode int, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantCode {
+		var body struct {
+			Error string `json:"error"`
+		}
+
+		if json.NewDecoder(resp.Body).Decode(&body) != nil || body.Error == "" {
+			body.Error = "unexpected response"
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,433 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "client" package.
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/auth"
+	"github.com/kdeconinck/dtvisual/internal/pkg/client"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Returns a client for a server which exposes a single run (with ID "nightly").
+func newClient(t *testing.T) *client.Client {
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies computer=\"WIN11\">\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Passes\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Fails\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"AssertException\">\n" +
+		"          <message>Expected: 1</message>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	srv := httptest.NewServer(api.Handler(func() ([]api.Run, error) {
+		return []api.Run{{ID: "nightly", Name: "nightly.xml", TestRun: testRun}}, nil
+	}, nil))
+	t.Cleanup(srv.Close)
+
+	return client.New(srv.URL+"/", srv.Client())
+}
+
+// UT: Get the runs exposed by a server.
+func TestClient_Runs(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	c := newClient(t)
+
+	// ACT.
+	got, err := c.Runs(context.Background())
+
+	// ASSERT.
+	want := []client.Run{{
+		ID:       "nightly",
+		Name:     "nightly.xml",
+		Computer: "WIN11",
+		Stats: client.Stats{
+			Assemblies: 1, Passed: 1, Failed: 1, Total: 2, PassRate: 50, Duration: client.Duration(1500 * time.Millisecond),
+		},
+	}}
+
+	assert.NoError(t, err, "Runs()")
+
+	assert.EqualFn(t, got, want, func(got, want []client.Run) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Get the runs exposed by a server.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Get the assemblies of a run exposed by a server.
+func TestClient_Assemblies(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	c := newClient(t)
+
+	// ACT.
+	got, err := c.Assemblies(context.Background(), "nightly")
+
+	// ASSERT.
+	want := []client.Assembly{
+		{Name: "App.dll", Passed: 1, Failed: 1, Total: 2, Duration: client.Duration(1500 * time.Millisecond)},
+	}
+
+	assert.NoError(t, err, "Assemblies()")
+
+	assert.EqualFn(t, got, want, func(got, want []client.Assembly) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Get the assemblies of a run exposed by a server.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Get the (filtered) tests of a run exposed by a server.
+func TestClient_Tests(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	c := newClient(t)
+
+	// ACT.
+	got, err := c.Tests(context.Background(), "nightly", client.TestFilter{Result: "fail", Assembly: "App.dll"})
+
+	// ASSERT.
+	want := []client.Test{{
+		Assembly: "App.dll",
+		Path:     []string{"TestClass", "Method"},
+		Name:     "NS.TestClass+Method.Fails",
+		Result:   "Fail",
+		Duration: client.Duration(time.Second),
+		Failure:  &client.Failure{ExceptionType: "AssertException", Message: "Expected: 1"},
+	}}
+
+	assert.NoError(t, err, "Tests()")
+
+	assert.EqualFn(t, got, want, func(got, want []client.Test) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Get the (filtered) tests of a run exposed by a server.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Get a run which isn't exposed by a server.
+func TestClient_Run_NotFound(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	c := newClient(t)
+
+	// ACT.
+	_, err := c.Run(context.Background(), "weekly")
+
+	// ASSERT.
+	var apiErr *client.Error
+	got := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Message == "unknown run weekly"
+
+	assert.Equal(t, got, true, "", "\n\n"+
+		"UT Name:    Get a run which isn't exposed by a server.\n"+
+		"\033[32mExpected:   client: unknown run weekly (404 Not Found)\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+}
+
+// UT: Upload a result file to a server which requires an API token.
+func TestClient_Ingest(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	var runs []api.Run
+
+	cfg := auth.Config{Tokens: []auth.Token{{Name: "CI", Token: "s3cr3t", Scopes: []auth.Scope{auth.ScopeIngest}}}}
+	source := func() ([]api.Run, error) { return runs, nil }
+	handler := api.Handler(source, func(upload api.Upload, r io.Reader) (api.Run, error) {
+		testRun, err := xunit.Load(r)
+
+		if err != nil {
+			return api.Run{}, err
+		}
+
+		run := api.Run{
+			ID:      strings.TrimSuffix(upload.Name, ".xml"),
+			Name:    upload.Name,
+			Project: upload.Project,
+			Branch:  upload.Branch,
+			TestRun: testRun,
+		}
+		runs = append(runs, run)
+
+		return run, nil
+	})
+	srv := httptest.NewServer(auth.Require(cfg, auth.MethodScope, handler))
+	defer srv.Close()
+
+	data := "<assemblies computer=\"WIN11\"><assembly name=\"~/App.dll\" total=\"1\" passed=\"1\" /></assemblies>"
+	c := client.New(srv.URL, srv.Client())
+
+	// ACT.
+	upload := client.Upload{Name: "nightly.xml", Project: "api", Branch: "main"}
+	_, errWithoutToken := c.Ingest(context.Background(), upload, strings.NewReader(data))
+	got, err := c.WithToken("s3cr3t").Ingest(context.Background(), upload, strings.NewReader(data))
+
+	// ASSERT.
+	var apiErr *client.Error
+	unauthorized := errors.As(errWithoutToken, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+
+	assert.Equal(t, unauthorized, true, "", "\n\n"+
+		"UT Name:    Upload a result file to a server which requires an API token.\n"+
+		"\033[32mExpected:   An error with status code 401 (without a token)\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", errWithoutToken)
+
+	assert.NoError(t, err, "Ingest()")
+
+	want := client.Run{
+		ID:       "nightly",
+		Name:     "nightly.xml",
+		Project:  "api",
+		Branch:   "main",
+		Computer: "WIN11",
+		Stats:    client.Stats{Assemblies: 1, Passed: 1, Total: 1, PassRate: 100},
+	}
+
+	assert.EqualFn(t, got, want, func(got, want client.Run) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Upload a result file to a server which requires an API token.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// The parts of the OpenAPI document of the API (see the "api" package) which the client depends on.
+type openAPI struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"` // The operations of each path, by method.
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// A schema of an OpenAPI document.
+type schema struct {
+	Type       string            `json:"type"`
+	Ref        string            `json:"$ref"`
+	Items      *schema           `json:"items"`
+	Properties map[string]schema `json:"properties"`
+	Required   []string          `json:"required"`
+}
+
+// Returns the OpenAPI document served by the API.
+func readOpenAPI(t *testing.T) openAPI {
+	data, err := os.ReadFile("../api/assets/openapi.json")
+
+	if err != nil {
+		t.Fatalf("ReadFile() = %v, want <nil>", err)
+	}
+
+	var spec openAPI
+
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Unmarshal() = %v, want <nil>", err)
+	}
+
+	return spec
+}
+
+// Returns the differences between the fields of typ (a struct) and the properties of the schema s.
+func schemaDiff(s schema, typ reflect.Type) []string {
+	var diffs []string
+
+	seen := make(map[string]bool)
+
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		prop, ok := s.Properties[name]
+		seen[name] = true
+
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: not in the schema", name))
+
+			continue
+		}
+
+		if required := slices.Contains(s.Required, name); required == (opts == "omitempty") {
+			diffs = append(diffs, fmt.Sprintf("%s: required is %t, but omitempty is %t", name, required, !required))
+		}
+
+		if want := schemaType(prop); want != typeName(field.Type) {
+			diffs = append(diffs, fmt.Sprintf("%s: type %s, want %s", name, typeName(field.Type), want))
+		}
+	}
+
+	for name := range s.Properties {
+		if !seen[name] {
+			diffs = append(diffs, fmt.Sprintf("%s: not in the client", name))
+		}
+	}
+
+	return diffs
+}
+
+// Returns the type of the schema s (e.g. "integer", "array of string" or the name of a referenced schema).
+func schemaType(s schema) string {
+	switch {
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	case s.Type == "array" && s.Items != nil:
+		return "array of " + schemaType(*s.Items)
+	}
+
+	return s.Type
+}
+
+// Returns the type of the schemas which match typ (see schemaType).
+func typeName(typ reflect.Type) string {
+	if typ == reflect.TypeOf(client.Duration(0)) {
+		return "number" // NOTE: A duration is encoded as a number of seconds.
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	case reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice:
+		return "array of " + typeName(typ.Elem())
+	case reflect.Pointer:
+		return typeName(typ.Elem())
+	}
+
+	return typ.Name()
+}
+
+// UT: Compare the types of the client with the schemas of the OpenAPI document.
+func TestClient_Schemas(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	spec := readOpenAPI(t)
+
+	for _, tc := range []any{client.Run{}, client.Stats{}, client.Assembly{}, client.Test{}, client.Failure{}} {
+		typ := reflect.TypeOf(tc)
+
+		// ACT.
+		s, ok := spec.Components.Schemas[typ.Name()]
+		got := schemaDiff(s, typ)
+
+		// ASSERT.
+		assert.Equal(t, ok, true, "", "\n\n"+
+			"UT Name:    Compare the types of the client with the schemas of the OpenAPI document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   A schema named %s\033[0m\n"+
+			"\033[31mActual:     No schema\033[0m\n\n", typ.Name(), typ.Name())
+
+		assert.Empty(t, got, "", "\n\n"+
+			"UT Name:    Compare the types of the client with the schemas of the OpenAPI document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   No differences\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", typ.Name(), got)
+	}
+}
+
+// UT: Compare the requests sent by the client with the operations of the OpenAPI document.
+func TestClient_Operations(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	spec := readOpenAPI(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: The ID of the run is the only parameter of the paths.
+		path := strings.Replace(r.URL.Path, "/nightly", "/{id}", 1)
+
+		mu.Lock()
+		requests = append(requests, strings.ToLower(r.Method)+" "+path)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	// ACT.
+	c.Runs(ctx)
+	c.Run(ctx, "nightly")
+	c.Assemblies(ctx, "nightly")
+	c.Tests(ctx, "nightly", client.TestFilter{Result: "Fail"})
+	c.Ingest(ctx, client.Upload{Name: "nightly.xml"}, strings.NewReader("<assemblies />"))
+
+	// ASSERT.
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Len(t, requests, 5, "", "\n\n"+
+		"UT Name:    Compare the requests sent by the client with the operations of the OpenAPI document.\n"+
+		"\033[32mExpected:   5 requests\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", requests)
+
+	for _, req := range requests {
+		method, path, _ := strings.Cut(req, " ")
+		_, got := spec.Paths[path][method]
+
+		assert.Equal(t, got, true, "", "\n\n"+
+			"UT Name:    Compare the requests sent by the client with the operations of the OpenAPI document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   An operation of the OpenAPI document\033[0m\n"+
+			"\033[31mActual:     No operation\033[0m\n\n", req)
+	}
+}
@@ -0,0 +1,126 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package compare defines functions for comparing .NET test result(s).
+package compare
+
+import (
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Change contains information about a single test which differs between two test runs.
+type Change struct {
+	Assembly string          // The name of the assembly the test belongs to.
+	Path     []string        // The names of the groups the test belongs to.
+	Before   *xunit.TestCase // The test in the first test run (or nil if it was added).
+	After    *xunit.TestCase // The test in the second test run (or nil if it was removed).
+}
+
+// Delta contains the differences between two test runs.
+// Tests are identified by the name of their assembly and their own name.
+type Delta struct {
+	Added           []Change // The tests which are only part of the second test run.
+	Removed         []Change // The tests which are only part of the first test run.
+	StatusChanged   []Change // The tests whose result differs between the test runs.
+	DurationChanged []Change // The tests whose duration differs between the test runs.
+}
+
+// The key identifying a test across test runs.
+type key struct {
+	assembly string
+	name     string
+}
+
+// A test, together with the names of the groups it belongs to.
+type indexedTest struct {
+	path []string
+	tc   *xunit.TestCase
+}
+
+// Runs returns the differences between the test runs a and b.
+// The changes are ordered as the tests appear in b (or in a, for the removed tests).
+func Runs(a, b xunit.TestRun) Delta {
+	aTests, aKeys := index(a)
+	bTests, bKeys := index(b)
+
+	delta := Delta{
+		Added:           make([]Change, 0),
+		Removed:         make([]Change, 0),
+		StatusChanged:   make([]Change, 0),
+		DurationChanged: make([]Change, 0),
+	}
+
+	for _, k := range bKeys {
+		change := Change{Assembly: k.assembly, Path: bTests[k].path, After: bTests[k].tc}
+		aTest, ok := aTests[k]
+
+		if !ok {
+			delta.Added = append(delta.Added, change)
+
+			continue
+		}
+
+		change.Before = aTest.tc
+
+		if change.Before.Result != change.After.Result {
+			delta.StatusChanged = append(delta.StatusChanged, change)
+		}
+
+		if change.Before.Duration != change.After.Duration {
+			delta.DurationChanged = append(delta.DurationChanged, change)
+		}
+	}
+
+	for _, k := range aKeys {
+		if _, ok := bTests[k]; !ok {
+			change := Change{Assembly: k.assembly, Path: aTests[k].path, Before: aTests[k].tc}
+			delta.Removed = append(delta.Removed, change)
+		}
+	}
+
+	return delta
+}
+
+// Returns the tests of testRun by their key, and the keys in the order in which the tests are found.
+// When a test is found more than once, only its first occurrence is kept.
+func index(testRun xunit.TestRun) (map[key]indexedTest, []key) {
+	tests := make(map[key]indexedTest)
+	keys := make([]key, 0)
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			k := key{assembly: assembly.Name, name: tc.RawName}
+
+			if _, ok := tests[k]; ok {
+				return
+			}
+
+			tests[k] = indexedTest{path: path, tc: &tc}
+			keys = append(keys, k)
+		})
+	}
+
+	return tests, keys
+}
@@ -0,0 +1,173 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "compare" package.
+package compare_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/compare"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Compare two test runs.
+func TestRuns(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// The summary of a change, used to compare the changes.
+	type summary struct {
+		Assembly string
+		Path     []string
+		Name     string
+		Before   string
+		After    string
+	}
+
+	// Returns the summaries of changes.
+	summarize := func(changes []compare.Change) []summary {
+		resultSet := make([]summary, 0, len(changes))
+
+		for _, change := range changes {
+			s := summary{Assembly: change.Assembly, Path: change.Path}
+
+			if change.Before != nil {
+				s.Name, s.Before = change.Before.Name, change.Before.Result+" "+change.Before.Duration.String()
+			}
+
+			if change.After != nil {
+				s.Name, s.After = change.After.Name, change.After.Result+" "+change.After.Duration.String()
+			}
+
+			resultSet = append(resultSet, s)
+		}
+
+		return resultSet
+	}
+
+	// ARRANGE.
+	a, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Unchanged\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Fixed\" result=\"Fail\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Slower\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Removed\" result=\"Pass\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	b, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Unchanged\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Fixed\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Slower\" result=\"Pass\" time=\"1\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Added\" result=\"Fail\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	// ACT.
+	got := compare.Runs(a, b)
+
+	// ASSERT.
+	path := []string{"TestClass", "Method"}
+
+	for _, tc := range []struct {
+		name string
+		got  []compare.Change
+		want []summary
+	}{
+		{
+			name: "Added",
+			got:  got.Added,
+			want: []summary{{Assembly: "App.dll", Path: path, Name: "NS.TestClass+Method.Added", After: "Fail 500ms"}},
+		},
+		{
+			name: "Removed",
+			got:  got.Removed,
+			want: []summary{{Assembly: "App.dll", Path: path, Name: "NS.TestClass+Method.Removed", Before: "Pass 500ms"}},
+		},
+		{
+			name: "StatusChanged",
+			got:  got.StatusChanged,
+			want: []summary{
+				{Assembly: "App.dll", Path: path, Name: "NS.TestClass+Method.Fixed", Before: "Fail 500ms", After: "Pass 500ms"},
+			},
+		},
+		{
+			name: "DurationChanged",
+			got:  got.DurationChanged,
+			want: []summary{
+				{Assembly: "App.dll", Path: path, Name: "NS.TestClass+Method.Slower", Before: "Pass 500ms", After: "Pass 1s"},
+			},
+		},
+	} {
+		gotSummary := summarize(tc.got)
+
+		assert.EqualFn(t, gotSummary, tc.want, func(got, want []summary) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Compare two test runs.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.want, gotSummary)
+	}
+}
+
+// UT: Compare a test run with itself.
+func TestRuns_Identical(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	a, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A test.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	// ACT.
+	got := compare.Runs(a, a)
+
+	// ASSERT.
+	want := compare.Delta{
+		Added:           make([]compare.Change, 0),
+		Removed:         make([]compare.Change, 0),
+		StatusChanged:   make([]compare.Change, 0),
+		DurationChanged: make([]compare.Change, 0),
+	}
+
+	assert.EqualFn(t, got, want, func(got, want compare.Delta) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Compare a test run with itself.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
@@ -0,0 +1,141 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package cobertura contains functions for loading code coverage in the Cobertura XML format (e.g. as written by
+// coverlet).
+// More information regarding this format can be found @ https://github.com/cobertura/cobertura.
+package cobertura
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+)
+
+// A document is the root element of the document.
+type document struct {
+	XMLName  xml.Name `xml:"coverage"`
+	Sources  []string `xml:"sources>source"`
+	Packages []pkg    `xml:"packages>package"`
+}
+
+// A pkg contains the classes of a single assembly.
+type pkg struct {
+	Name    string  `xml:"name,attr"`
+	Classes []class `xml:"classes>class"`
+}
+
+// A class contains the lines of a single class (which is part of a source file).
+type class struct {
+	Filename string `xml:"filename,attr"`
+	Lines    []line `xml:"lines>line"`
+}
+
+// A line contains the coverage of a single line of a source file.
+type line struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            string `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr"` // E.g. "50% (1/2)".
+}
+
+// Load reads a document in the Cobertura XML format from rdr, and returns the coverage it contains.
+// Each package is an assembly. When the document has a single source (directory), the relative paths of the source
+// files are resolved against it.
+func Load(rdr io.Reader) (coverage.Report, error) {
+	var doc document
+
+	if err := xml.NewDecoder(rdr).Decode(&doc); err != nil {
+		return coverage.Report{}, fmt.Errorf("cobertura: %w", err)
+	}
+
+	report := coverage.Report{Assemblies: make([]coverage.Assembly, 0, len(doc.Packages))}
+
+	for _, p := range doc.Packages {
+		assembly := coverage.Assembly{Name: p.Name}
+
+		for _, c := range p.Classes {
+			file := assembly.File(resolve(doc.Sources, c.Filename))
+
+			for _, l := range c.Lines {
+				cov := coverage.Line{Hits: l.Hits}
+
+				if strings.EqualFold(l.Branch, "true") {
+					var err error
+
+					if cov.CoveredBranches, cov.Branches, err = parseConditions(l.ConditionCoverage); err != nil {
+						return coverage.Report{}, fmt.Errorf("cobertura: line %d of %s: %w", l.Number, c.Filename, err)
+					}
+				}
+
+				file.AddLine(l.Number, cov)
+			}
+		}
+
+		report.Assemblies = append(report.Assemblies, assembly)
+	}
+
+	return report, nil
+}
+
+// Returns the number of covered conditions and the total number of conditions in s (e.g. "50% (1/2)").
+func parseConditions(s string) (int, int, error) {
+	var covered, total int
+
+	_, counts, ok := strings.Cut(s, "(")
+
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid condition coverage %q", s)
+	}
+
+	if _, err := fmt.Sscanf(counts, "%d/%d)", &covered, &total); err != nil {
+		return 0, 0, fmt.Errorf("invalid condition coverage %q", s)
+	}
+
+	return covered, total, nil
+}
+
+// Returns the path of the source file name, resolved against the source (directory) of sources if there's only one.
+func resolve(sources []string, name string) string {
+	if len(sources) != 1 || strings.TrimSpace(sources[0]) == "" || isAbs(name) {
+		return name
+	}
+
+	source, sep := strings.TrimSpace(sources[0]), "/"
+
+	if strings.Contains(source, `\`) && !strings.Contains(source, "/") {
+		sep = `\`
+	}
+
+	return strings.TrimRight(source, `/\`) + sep + name
+}
+
+// Returns true if path is an absolute path (on Unix or Windows).
+func isAbs(path string) bool {
+	return strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\`) || (len(path) > 1 && path[1] == ':')
+}
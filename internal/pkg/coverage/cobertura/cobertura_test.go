@@ -0,0 +1,142 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "cobertura" package.
+package cobertura_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/cobertura"
+)
+
+// UT: Load the coverage of a document in the Cobertura XML format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		xmlData string
+		want    coverage.Report
+		wantErr bool
+	}{
+		{
+			name: "A report written by coverlet",
+			xmlData: "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+				"<coverage line-rate=\"0.75\" branch-rate=\"0.5\" version=\"1.9\" timestamp=\"1688979199\">\n" +
+				"  <sources>\n" +
+				"    <source>/src/</source>\n" +
+				"  </sources>\n" +
+				"  <packages>\n" +
+				"    <package name=\"App\" line-rate=\"0.75\" branch-rate=\"0.5\" complexity=\"2\">\n" +
+				"      <classes>\n" +
+				"        <class name=\"App.Calculator\" filename=\"App/Calculator.cs\" line-rate=\"0.66\">\n" +
+				"          <methods />\n" +
+				"          <lines>\n" +
+				"            <line number=\"10\" hits=\"1\" branch=\"False\" />\n" +
+				"            <line number=\"11\" hits=\"3\" branch=\"True\" condition-coverage=\"50% (1/2)\">\n" +
+				"              <conditions><condition number=\"0\" type=\"jump\" coverage=\"50%\" /></conditions>\n" +
+				"            </line>\n" +
+				"            <line number=\"12\" hits=\"0\" branch=\"False\" />\n" +
+				"          </lines>\n" +
+				"        </class>\n" +
+				"        <class name=\"App.Calculator/&lt;Add&gt;d__1\" filename=\"App/Calculator.cs\">\n" +
+				"          <lines><line number=\"12\" hits=\"2\" branch=\"False\" /></lines>\n" +
+				"        </class>\n" +
+				"        <class name=\"App.Parser\" filename=\"/lib/Parser.cs\">\n" +
+				"          <lines><line number=\"1\" hits=\"0\" branch=\"False\" /></lines>\n" +
+				"        </class>\n" +
+				"      </classes>\n" +
+				"    </package>\n" +
+				"  </packages>\n" +
+				"</coverage>",
+			want: coverage.Report{Assemblies: []coverage.Assembly{
+				{
+					Name: "App",
+					Files: []coverage.File{
+						{
+							Path: "/src/App/Calculator.cs",
+							Lines: map[int]coverage.Line{
+								10: {Hits: 1},
+								11: {Hits: 3, Branches: 2, CoveredBranches: 1},
+								12: {Hits: 2},
+							},
+						},
+						{Path: "/lib/Parser.cs", Lines: map[int]coverage.Line{1: {}}},
+					},
+				},
+			}},
+		},
+		{
+			name: "A report with multiple sources",
+			xmlData: "<coverage>\n" +
+				"  <sources><source>C:\\src</source><source>D:\\lib</source></sources>\n" +
+				"  <packages>\n" +
+				"    <package name=\"App\">\n" +
+				"      <classes><class filename=\"App\\Calculator.cs\"><lines /></class></classes>\n" +
+				"    </package>\n" +
+				"  </packages>\n" +
+				"</coverage>",
+			want: coverage.Report{Assemblies: []coverage.Assembly{
+				{Name: "App", Files: []coverage.File{{Path: "App\\Calculator.cs", Lines: map[int]coverage.Line{}}}},
+			}},
+		},
+		{
+			name: "A report with an invalid condition coverage",
+			xmlData: "<coverage><packages><package name=\"App\"><classes><class filename=\"A.cs\"><lines>" +
+				"<line number=\"1\" hits=\"1\" branch=\"true\" condition-coverage=\"50%\" />" +
+				"</lines></class></classes></package></packages></coverage>",
+			wantErr: true,
+		},
+		{
+			name:    "A document in another format",
+			xmlData: "<assemblies />",
+			wantErr: true,
+		},
+	} {
+		// ACT.
+		got, err := cobertura.Load(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the Cobertura XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the Cobertura XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
@@ -0,0 +1,212 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package coverage contains the code coverage of a test run, independent of the format it's loaded from.
+package coverage
+
+// Report contains the code coverage of a test run.
+type Report struct {
+	Assemblies []Assembly // The assemblies, in the order of the report they're loaded from.
+}
+
+// Assembly contains the code coverage of a single assembly.
+type Assembly struct {
+	Name  string // The name of the assembly (or package).
+	Files []File // The source files of the assembly, in the order of the report they're loaded from.
+}
+
+// File contains the code coverage of a single source file.
+type File struct {
+	Path  string       // The path of the source file.
+	Lines map[int]Line // The lines which contain code, by number.
+}
+
+// Line contains the code coverage of a single line of a source file.
+type Line struct {
+	Hits            int // The number of times the line was executed.
+	Branches        int // The number of branches (or conditions) in the line.
+	CoveredBranches int // The number of branches which were taken.
+}
+
+// Counter contains the number of covered elements (lines or branches), and the total number of elements.
+type Counter struct {
+	Covered int // The number of elements which are covered.
+	Total   int // The total number of elements.
+}
+
+// Merge returns a single Report containing the coverage of all the reports (e.g. of sharded test runs).
+// The assemblies with the same name, and their source files with the same path, are merged: the hits of each line are
+// added, and the branches of the line with the most covered branches are kept.
+func Merge(reports ...Report) Report {
+	var res Report
+
+	for _, r := range reports {
+		for _, a := range r.Assemblies {
+			assembly := res.Assembly(a.Name)
+
+			for _, f := range a.Files {
+				file := assembly.File(f.Path)
+
+				for number, line := range f.Lines {
+					file.AddLine(number, line)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// Rate returns the percentage (0 - 100) of elements which are covered.
+// When there are no elements, the rate is 0.
+func (c Counter) Rate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+
+	return float64(c.Covered) / float64(c.Total) * 100
+}
+
+// Add returns the sum of c and other.
+func (c Counter) Add(other Counter) Counter {
+	return Counter{Covered: c.Covered + other.Covered, Total: c.Total + other.Total}
+}
+
+// Assembly returns the assembly of r with the given name, which is added to r if it doesn't exist yet.
+func (r *Report) Assembly(name string) *Assembly {
+	for i := range r.Assemblies {
+		if r.Assemblies[i].Name == name {
+			return &r.Assemblies[i]
+		}
+	}
+
+	r.Assemblies = append(r.Assemblies, Assembly{Name: name})
+
+	return &r.Assemblies[len(r.Assemblies)-1]
+}
+
+// LineCoverage returns the number of lines of all the assemblies which are covered.
+func (r Report) LineCoverage() Counter {
+	var c Counter
+
+	for _, a := range r.Assemblies {
+		c = c.Add(a.LineCoverage())
+	}
+
+	return c
+}
+
+// BranchCoverage returns the number of branches of all the assemblies which are covered.
+func (r Report) BranchCoverage() Counter {
+	var c Counter
+
+	for _, a := range r.Assemblies {
+		c = c.Add(a.BranchCoverage())
+	}
+
+	return c
+}
+
+// File returns the source file of a with the given path, which is added to a if it doesn't exist yet.
+func (a *Assembly) File(path string) *File {
+	for i := range a.Files {
+		if a.Files[i].Path == path {
+			return &a.Files[i]
+		}
+	}
+
+	a.Files = append(a.Files, File{Path: path, Lines: make(map[int]Line)})
+
+	return &a.Files[len(a.Files)-1]
+}
+
+// LineCoverage returns the number of lines of the source files of a which are covered.
+func (a Assembly) LineCoverage() Counter {
+	var c Counter
+
+	for _, f := range a.Files {
+		c = c.Add(f.LineCoverage())
+	}
+
+	return c
+}
+
+// BranchCoverage returns the number of branches of the source files of a which are covered.
+func (a Assembly) BranchCoverage() Counter {
+	var c Counter
+
+	for _, f := range a.Files {
+		c = c.Add(f.BranchCoverage())
+	}
+
+	return c
+}
+
+// AddLine adds the coverage of the line with the given number to f.
+// When f already contains the line (e.g. because it's part of multiple classes), the hits are added, and the
+// branches of the line with the most covered branches are kept.
+func (f *File) AddLine(number int, line Line) {
+	if f.Lines == nil {
+		f.Lines = make(map[int]Line)
+	}
+
+	existing, ok := f.Lines[number]
+
+	if ok {
+		line.Hits += existing.Hits
+
+		// NOTE: The branches of the line can't be matched, so the covered branches of both aren't combined.
+		if existing.CoveredBranches > line.CoveredBranches ||
+			(existing.CoveredBranches == line.CoveredBranches && existing.Branches > line.Branches) {
+			line.Branches, line.CoveredBranches = existing.Branches, existing.CoveredBranches
+		}
+	}
+
+	f.Lines[number] = line
+}
+
+// LineCoverage returns the number of lines of f which are covered (executed at least once).
+func (f File) LineCoverage() Counter {
+	c := Counter{Total: len(f.Lines)}
+
+	for _, line := range f.Lines {
+		if line.Hits > 0 {
+			c.Covered++
+		}
+	}
+
+	return c
+}
+
+// BranchCoverage returns the number of branches of f which are covered (taken at least once).
+func (f File) BranchCoverage() Counter {
+	var c Counter
+
+	for _, line := range f.Lines {
+		c = c.Add(Counter{Covered: line.CoveredBranches, Total: line.Branches})
+	}
+
+	return c
+}
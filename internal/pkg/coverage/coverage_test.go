@@ -0,0 +1,126 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "coverage" package.
+package coverage_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+)
+
+// UT: Count the covered lines and branches of a report.
+func TestReport_Coverage(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	app := coverage.Assembly{Name: "App"}
+	file := app.File("/src/App/Calculator.cs")
+
+	file.AddLine(10, coverage.Line{Hits: 1})
+	file.AddLine(11, coverage.Line{Hits: 0})
+	file.AddLine(12, coverage.Line{Hits: 2, Branches: 2, CoveredBranches: 1})
+	file.AddLine(12, coverage.Line{Hits: 1, Branches: 2, CoveredBranches: 2})
+	app.File("/src/App/Parser.cs").AddLine(5, coverage.Line{Hits: 3})
+
+	report := coverage.Report{Assemblies: []coverage.Assembly{app, {Name: "Empty"}}}
+
+	// ACT.
+	got := []coverage.Counter{report.LineCoverage(), report.BranchCoverage(), report.Assemblies[1].LineCoverage()}
+
+	// ASSERT.
+	want := []coverage.Counter{{Covered: 3, Total: 4}, {Covered: 2, Total: 2}, {}}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Count the covered lines and branches of a report.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+
+	assert.Equal(t, file.Lines[12].Hits, 3, "", "\n\n"+
+		"UT Name:    Count the covered lines and branches of a report.\n"+
+		"\033[32mExpected:   3 hits of line 12\033[0m\n"+
+		"\033[31mActual:     %d hits of line 12\033[0m\n\n", file.Lines[12].Hits)
+}
+
+// UT: Merge the coverage of multiple reports.
+func TestMerge(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	first := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{
+			{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {Branches: 2}}},
+		}},
+	}}
+	second := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "Lib", Files: []coverage.File{{Path: "L.cs", Lines: map[int]coverage.Line{1: {}}}}},
+		{Name: "App", Files: []coverage.File{
+			{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 2}, 2: {Hits: 1, Branches: 2, CoveredBranches: 1}}},
+			{Path: "B.cs", Lines: map[int]coverage.Line{1: {Hits: 1}}},
+		}},
+	}}
+
+	// ACT.
+	got := coverage.Merge(first, second)
+
+	// ASSERT.
+	want := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{
+			{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 3}, 2: {Hits: 1, Branches: 2, CoveredBranches: 1}}},
+			{Path: "B.cs", Lines: map[int]coverage.Line{1: {Hits: 1}}},
+		}},
+		{Name: "Lib", Files: []coverage.File{{Path: "L.cs", Lines: map[int]coverage.Line{1: {}}}}},
+	}}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Merge the coverage of multiple reports.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Calculate the percentage of covered elements.
+func TestCounter_Rate(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		counter coverage.Counter
+		want    float64
+	}{
+		{counter: coverage.Counter{Covered: 3, Total: 4}, want: 75},
+		{counter: coverage.Counter{}, want: 0},
+	} {
+		// ACT.
+		got := tc.counter.Rate()
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Calculate the percentage of covered elements.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.counter, tc.want, got)
+	}
+}
@@ -0,0 +1,119 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package coverlet contains functions for loading code coverage in the native JSON format of coverlet.
+// More information regarding coverlet can be found @ https://github.com/coverlet-coverage/coverlet.
+package coverlet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+)
+
+// A document contains the methods of each class of each source file of each module (by name).
+type document map[string]map[string]map[string]map[string]method
+
+// A method contains the coverage of a single method.
+type method struct {
+	Lines    map[string]int `json:"Lines"`    // The number of times each line was executed, by number.
+	Branches []branch       `json:"Branches"` // The branches of the method.
+}
+
+// A branch contains the coverage of a single branch of a method.
+type branch struct {
+	Line int `json:"Line"` // The number of the line containing the branch.
+	Hits int `json:"Hits"` // The number of times the branch was taken.
+}
+
+// Load reads a document in the JSON format of coverlet from rdr, and returns the coverage it contains.
+// Each module is an assembly (named without its extension, as in the Cobertura format). The assemblies and their
+// source files are sorted by name.
+func Load(rdr io.Reader) (coverage.Report, error) {
+	var doc document
+
+	if err := json.NewDecoder(rdr).Decode(&doc); err != nil {
+		return coverage.Report{}, fmt.Errorf("coverlet: %w", err)
+	}
+
+	report := coverage.Report{Assemblies: make([]coverage.Assembly, 0, len(doc))}
+
+	for _, module := range maps.SortedKeys(doc) {
+		assembly := coverage.Assembly{Name: strings.TrimSuffix(strings.TrimSuffix(module, ".dll"), ".exe")}
+
+		for _, path := range maps.SortedKeys(doc[module]) {
+			file := assembly.File(path)
+
+			for _, methods := range doc[module][path] {
+				for _, m := range methods {
+					if err := addMethod(file, m); err != nil {
+						return coverage.Report{}, fmt.Errorf("coverlet: %s: %w", path, err)
+					}
+				}
+			}
+		}
+
+		report.Assemblies = append(report.Assemblies, assembly)
+	}
+
+	return report, nil
+}
+
+// Adds the lines (and branches) of m to file.
+func addMethod(file *coverage.File, m method) error {
+	lines := make(map[int]coverage.Line, len(m.Lines))
+
+	for key, hits := range m.Lines {
+		number, err := strconv.Atoi(key)
+
+		if err != nil {
+			return fmt.Errorf("invalid line number %q", key)
+		}
+
+		lines[number] = coverage.Line{Hits: hits}
+	}
+
+	for _, b := range m.Branches {
+		line := lines[b.Line]
+		line.Branches++
+
+		if b.Hits > 0 {
+			line.CoveredBranches++
+		}
+
+		lines[b.Line] = line
+	}
+
+	for number, line := range lines {
+		file.AddLine(number, line)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "coverlet" package.
+package coverlet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/coverlet"
+)
+
+// UT: Load the coverage of a document in the JSON format of coverlet.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name     string
+		jsonData string
+		want     coverage.Report
+		wantErr  bool
+	}{
+		{
+			name: "A report written by coverlet",
+			jsonData: `{
+  "App.dll": {
+    "/src/App/Calculator.cs": {
+      "App.Calculator": {
+        "System.Int32 App.Calculator::Add(System.Int32,System.Int32)": {
+          "Lines": { "10": 1, "11": 3, "12": 0 },
+          "Branches": [
+            { "Line": 11, "Offset": 5, "EndOffset": 7, "Path": 0, "Ordinal": 0, "Hits": 3 },
+            { "Line": 11, "Offset": 5, "EndOffset": 9, "Path": 1, "Ordinal": 1, "Hits": 0 }
+          ]
+        },
+        "System.Void App.Calculator/<>c::<Add>b__0_0()": {
+          "Lines": { "12": 2 },
+          "Branches": []
+        }
+      }
+    }
+  },
+  "Lib.dll": {}
+}`,
+			want: coverage.Report{Assemblies: []coverage.Assembly{
+				{
+					Name: "App",
+					Files: []coverage.File{
+						{
+							Path: "/src/App/Calculator.cs",
+							Lines: map[int]coverage.Line{
+								10: {Hits: 1},
+								11: {Hits: 3, Branches: 2, CoveredBranches: 1},
+								12: {Hits: 2},
+							},
+						},
+					},
+				},
+				{Name: "Lib"},
+			}},
+		},
+		{
+			name:     "A report with an invalid line number",
+			jsonData: `{"App.dll": {"A.cs": {"A": {"M": {"Lines": {"x": 1}}}}}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "A document in another format",
+			jsonData: `<coverage />`,
+			wantErr:  true,
+		},
+	} {
+		// ACT.
+		got, err := coverlet.Load(strings.NewReader(tc.jsonData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the JSON format of coverlet.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the JSON format of coverlet.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
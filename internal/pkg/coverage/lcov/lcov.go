@@ -0,0 +1,130 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package lcov contains functions for loading code coverage in the LCOV tracefile format (e.g. as written by Istanbul
+// for JavaScript and TypeScript).
+// More information regarding this format can be found @ https://github.com/linux-test-project/lcov.
+package lcov
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+)
+
+// Load reads a tracefile in the LCOV format from rdr, and returns the coverage it contains.
+// Since the format doesn't have assemblies, the source files of each test (TN) are an assembly, and the source files
+// without a test name are part of the assembly with the given name.
+func Load(rdr io.Reader, name string) (coverage.Report, error) {
+	var report coverage.Report
+	var file *coverage.File
+
+	assembly := name
+	scanner := bufio.NewScanner(rdr)
+	scanner.Buffer(nil, 1024*1024)
+
+	for number := 1; scanner.Scan(); number++ {
+		kind, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		var err error
+
+		switch kind {
+		case "TN":
+			if assembly = value; assembly == "" {
+				assembly = name
+			}
+		case "SF":
+			file = report.Assembly(assembly).File(value)
+		case "DA":
+			err = addLine(file, value)
+		case "BRDA":
+			err = addBranch(file, value)
+		case "end_of_record":
+			file, assembly = nil, name
+		}
+
+		if err != nil {
+			return coverage.Report{}, fmt.Errorf("lcov: line %d: %w", number, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return coverage.Report{}, fmt.Errorf("lcov: %w", err)
+	}
+
+	return report, nil
+}
+
+// Adds the line described by value ("<line>,<hits>[,<checksum>]") to file.
+func addLine(file *coverage.File, value string) error {
+	fields := strings.Split(value, ",")
+
+	if file == nil || len(fields) < 2 {
+		return fmt.Errorf("invalid line record %q", value)
+	}
+
+	number, err := strconv.Atoi(fields[0])
+	hits, hitsErr := strconv.Atoi(fields[1])
+
+	if err != nil || hitsErr != nil {
+		return fmt.Errorf("invalid line record %q", value)
+	}
+
+	line := file.Lines[number]
+	line.Hits += hits
+	file.Lines[number] = line
+
+	return nil
+}
+
+// Adds the branch described by value ("<line>,<block>,<branch>,<taken>") to file, where taken is "-" if the branch
+// was never evaluated.
+func addBranch(file *coverage.File, value string) error {
+	fields := strings.Split(value, ",")
+
+	if file == nil || len(fields) != 4 {
+		return fmt.Errorf("invalid branch record %q", value)
+	}
+
+	number, err := strconv.Atoi(fields[0])
+
+	if err != nil {
+		return fmt.Errorf("invalid branch record %q", value)
+	}
+
+	line := file.Lines[number]
+	line.Branches++
+
+	if taken, err := strconv.Atoi(fields[3]); err == nil && taken > 0 {
+		line.CoveredBranches++
+	}
+
+	file.Lines[number] = line
+
+	return nil
+}
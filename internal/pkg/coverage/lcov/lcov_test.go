@@ -0,0 +1,115 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "lcov" package.
+package lcov_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/lcov"
+)
+
+// UT: Load the coverage of a tracefile in the LCOV format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name     string
+		lcovData string
+		want     coverage.Report
+		wantErr  bool
+	}{
+		{
+			name: "A tracefile written by Istanbul",
+			lcovData: "TN:\n" +
+				"SF:src/app/calculator.ts\n" +
+				"FN:1,add\n" +
+				"FNDA:3,add\n" +
+				"DA:1,3\n" +
+				"DA:2,3\n" +
+				"DA:3,0\n" +
+				"BRDA:2,0,0,3\n" +
+				"BRDA:2,0,1,0\n" +
+				"BRDA:3,1,0,-\n" +
+				"LF:3\n" +
+				"LH:2\n" +
+				"end_of_record\n" +
+				"TN:web\n" +
+				"SF:src/web/index.ts\n" +
+				"DA:1,1\n" +
+				"end_of_record\n",
+			want: coverage.Report{Assemblies: []coverage.Assembly{
+				{
+					Name: "frontend",
+					Files: []coverage.File{
+						{
+							Path: "src/app/calculator.ts",
+							Lines: map[int]coverage.Line{
+								1: {Hits: 3},
+								2: {Hits: 3, Branches: 2, CoveredBranches: 1},
+								3: {Branches: 1},
+							},
+						},
+					},
+				},
+				{Name: "web", Files: []coverage.File{{Path: "src/web/index.ts", Lines: map[int]coverage.Line{1: {Hits: 1}}}}},
+			}},
+		},
+		{
+			name:     "A tracefile with a line outside of a source file",
+			lcovData: "DA:1,1\n",
+			wantErr:  true,
+		},
+		{
+			name:     "A tracefile with an invalid line",
+			lcovData: "SF:a.ts\nDA:one,1\n",
+			wantErr:  true,
+		},
+	} {
+		// ACT.
+		got, err := lcov.Load(strings.NewReader(tc.lcovData), "frontend")
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the coverage of a tracefile in the LCOV format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load the coverage of a tracefile in the LCOV format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
@@ -0,0 +1,154 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package opencover contains functions for loading code coverage in the OpenCover XML format (e.g. as written by
+// OpenCover, or by coverlet with `--format opencover`).
+// More information regarding this format can be found @ https://github.com/OpenCover/opencover.
+package opencover
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+)
+
+// A session is the root element of the document.
+type session struct {
+	XMLName xml.Name `xml:"CoverageSession"`
+	Modules []module `xml:"Modules>Module"`
+}
+
+// A module contains the source files and the classes of a single assembly.
+type module struct {
+	SkippedDueTo string  `xml:"skippedDueTo,attr"` // If not empty, the reason why the module isn't covered.
+	Name         string  `xml:"ModuleName"`
+	Files        []file  `xml:"Files>File"`
+	Classes      []class `xml:"Classes>Class"`
+}
+
+// A file is a source file of a module.
+type file struct {
+	UID      string `xml:"uid,attr"`
+	FullPath string `xml:"fullPath,attr"`
+}
+
+// A class contains the methods of a single class.
+type class struct {
+	Methods []method `xml:"Methods>Method"`
+}
+
+// A method contains the sequence points (statements) and the branch points of a single method.
+type method struct {
+	SequencePoints []point `xml:"SequencePoints>SequencePoint"`
+	BranchPoints   []point `xml:"BranchPoints>BranchPoint"`
+}
+
+// A point is a sequence point or a branch point.
+type point struct {
+	Visits int    `xml:"vc,attr"`     // The number of times the point was visited.
+	Line   int    `xml:"sl,attr"`     // The number of the line where the point starts.
+	FileID string `xml:"fileid,attr"` // The uid of the file containing the point.
+}
+
+// A lineKey identifies a line of a source file (by the uid of the file).
+type lineKey struct {
+	fileID string
+	number int
+}
+
+// Load reads a document in the OpenCover XML format from rdr, and returns the coverage it contains.
+// Each module is an assembly, except the modules which were skipped (e.g. due to a filter). A line is covered when
+// one of the sequence points starting on it was visited.
+func Load(rdr io.Reader) (coverage.Report, error) {
+	var doc session
+
+	if err := xml.NewDecoder(rdr).Decode(&doc); err != nil {
+		return coverage.Report{}, fmt.Errorf("opencover: %w", err)
+	}
+
+	report := coverage.Report{Assemblies: make([]coverage.Assembly, 0, len(doc.Modules))}
+
+	for _, m := range doc.Modules {
+		if m.SkippedDueTo != "" {
+			continue
+		}
+
+		assembly := coverage.Assembly{Name: m.Name}
+		paths := make(map[string]string, len(m.Files))
+
+		for _, f := range m.Files {
+			paths[f.UID] = f.FullPath
+			assembly.File(f.FullPath)
+		}
+
+		for _, c := range m.Classes {
+			for _, meth := range c.Methods {
+				if err := addMethod(&assembly, paths, meth); err != nil {
+					return coverage.Report{}, fmt.Errorf("opencover: module %s: %w", m.Name, err)
+				}
+			}
+		}
+
+		report.Assemblies = append(report.Assemblies, assembly)
+	}
+
+	return report, nil
+}
+
+// Adds the lines (and branches) of m to the source files of assembly, which are identified by their uid in paths.
+func addMethod(assembly *coverage.Assembly, paths map[string]string, m method) error {
+	lines := make(map[lineKey]coverage.Line, len(m.SequencePoints))
+
+	// NOTE: The sequence points are visited first (i == 0), followed by the branch points.
+	for i, points := range [][]point{m.SequencePoints, m.BranchPoints} {
+		for _, p := range points {
+			if _, ok := paths[p.FileID]; !ok {
+				return fmt.Errorf("unknown file %q", p.FileID)
+			}
+
+			key := lineKey{fileID: p.FileID, number: p.Line}
+			line := lines[key]
+
+			switch {
+			case i == 0:
+				line.Hits = max(line.Hits, p.Visits)
+			case p.Visits > 0:
+				line.Branches, line.CoveredBranches = line.Branches+1, line.CoveredBranches+1
+			default:
+				line.Branches++
+			}
+
+			lines[key] = line
+		}
+	}
+
+	for key, line := range lines {
+		assembly.File(paths[key.fileID]).AddLine(key.number, line)
+	}
+
+	return nil
+}
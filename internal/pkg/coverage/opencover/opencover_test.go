@@ -0,0 +1,135 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "opencover" package.
+package opencover_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage/opencover"
+)
+
+// UT: Load the coverage of a document in the OpenCover XML format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		xmlData string
+		want    coverage.Report
+		wantErr bool
+	}{
+		{
+			name: "A report written by OpenCover",
+			xmlData: "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+				"<CoverageSession>\n" +
+				"  <Summary numSequencePoints=\"3\" visitedSequencePoints=\"2\" />\n" +
+				"  <Modules>\n" +
+				"    <Module hash=\"ABC\">\n" +
+				"      <ModulePath>C:\\src\\App\\bin\\App.dll</ModulePath>\n" +
+				"      <ModuleName>App</ModuleName>\n" +
+				"      <Files>\n" +
+				"        <File uid=\"1\" fullPath=\"C:\\src\\App\\Calculator.cs\" />\n" +
+				"      </Files>\n" +
+				"      <Classes>\n" +
+				"        <Class>\n" +
+				"          <FullName>App.Calculator</FullName>\n" +
+				"          <Methods>\n" +
+				"            <Method visited=\"true\">\n" +
+				"              <Name>System.Int32 App.Calculator::Add(System.Int32,System.Int32)</Name>\n" +
+				"              <FileRef uid=\"1\" />\n" +
+				"              <SequencePoints>\n" +
+				"                <SequencePoint vc=\"1\" sl=\"10\" el=\"10\" fileid=\"1\" />\n" +
+				"                <SequencePoint vc=\"3\" sl=\"11\" el=\"11\" bec=\"2\" bev=\"1\" fileid=\"1\" />\n" +
+				"                <SequencePoint vc=\"0\" sl=\"12\" el=\"12\" fileid=\"1\" />\n" +
+				"              </SequencePoints>\n" +
+				"              <BranchPoints>\n" +
+				"                <BranchPoint vc=\"3\" sl=\"11\" path=\"0\" fileid=\"1\" />\n" +
+				"                <BranchPoint vc=\"0\" sl=\"11\" path=\"1\" fileid=\"1\" />\n" +
+				"              </BranchPoints>\n" +
+				"            </Method>\n" +
+				"          </Methods>\n" +
+				"        </Class>\n" +
+				"      </Classes>\n" +
+				"    </Module>\n" +
+				"    <Module skippedDueTo=\"Filter\">\n" +
+				"      <ModuleName>App.Tests</ModuleName>\n" +
+				"    </Module>\n" +
+				"  </Modules>\n" +
+				"</CoverageSession>",
+			want: coverage.Report{Assemblies: []coverage.Assembly{
+				{
+					Name: "App",
+					Files: []coverage.File{
+						{
+							Path: "C:\\src\\App\\Calculator.cs",
+							Lines: map[int]coverage.Line{
+								10: {Hits: 1},
+								11: {Hits: 3, Branches: 2, CoveredBranches: 1},
+								12: {},
+							},
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "A report with a point in an unknown file",
+			xmlData: "<CoverageSession><Modules><Module><ModuleName>App</ModuleName><Classes><Class><Methods><Method>" +
+				"<SequencePoints><SequencePoint vc=\"1\" sl=\"1\" fileid=\"2\" /></SequencePoints>" +
+				"</Method></Methods></Class></Classes></Module></Modules></CoverageSession>",
+			wantErr: true,
+		},
+		{
+			name:    "A document in another format",
+			xmlData: "<coverage />",
+			wantErr: true,
+		},
+	} {
+		// ACT.
+		got, err := opencover.Load(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the OpenCover XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load the coverage of a document in the OpenCover XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
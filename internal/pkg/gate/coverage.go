@@ -0,0 +1,88 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package gate
+
+import (
+	"fmt"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+)
+
+// CoverageRule is a single check which the code coverage of a test run must satisfy.
+// It returns the descriptions of the violations if report doesn't satisfy the rule (e.g. one per assembly).
+type CoverageRule func(report coverage.Report) []string
+
+// MinLineCoverage returns a CoverageRule which is violated when the line coverage of the test run is below p (a
+// percentage). If perAssembly is true, the rule is violated by each assembly (with lines) whose line coverage is below
+// p instead.
+func MinLineCoverage(p float64, perAssembly bool) CoverageRule {
+	return minCoverage("line", p, perAssembly, coverage.Report.LineCoverage, coverage.Assembly.LineCoverage)
+}
+
+// MinBranchCoverage returns a CoverageRule which is violated when the branch coverage of the test run is below p (a
+// percentage). If perAssembly is true, the rule is violated by each assembly (with branches) whose branch coverage is
+// below p instead.
+func MinBranchCoverage(p float64, perAssembly bool) CoverageRule {
+	return minCoverage("branch", p, perAssembly, coverage.Report.BranchCoverage, coverage.Assembly.BranchCoverage)
+}
+
+// EvaluateCoverage returns the descriptions of the rules which are violated by report (in the order of rules).
+func EvaluateCoverage(report coverage.Report, rules ...CoverageRule) []string {
+	violations := make([]string, 0)
+
+	for _, rule := range rules {
+		violations = append(violations, rule(report)...)
+	}
+
+	return violations
+}
+
+// Returns a CoverageRule which is violated when the coverage of the given kind (returned by total, or by assembly for
+// each assembly if perAssembly is true) is below p.
+func minCoverage(kind string, p float64, perAssembly bool, total func(coverage.Report) coverage.Counter,
+	assembly func(coverage.Assembly) coverage.Counter,
+) CoverageRule {
+	return func(report coverage.Report) []string {
+		if !perAssembly {
+			if rate := total(report).Rate(); rate < p {
+				return []string{fmt.Sprintf("has a %s coverage of %.2f%%, which is below %.2f%%", kind, rate, p)}
+			}
+
+			return nil
+		}
+
+		violations := make([]string, 0)
+
+		for _, a := range report.Assemblies {
+			if c := assembly(a); c.Total > 0 && c.Rate() < p {
+				violations = append(violations, fmt.Sprintf("has a %s coverage of %.2f%% in %s, which is below %.2f%%",
+					kind, c.Rate(), a.Name, p))
+			}
+		}
+
+		return violations
+	}
+}
@@ -0,0 +1,118 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package gate defines rules which are evaluated against the aggregated statistics (or the code coverage) of a test
+// run, e.g. to decide whether a CI pipeline should fail.
+package gate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Rule is a single check which a test run must satisfy.
+// It returns a description of the violation if stats doesn't satisfy the rule, or an empty string otherwise.
+type Rule func(stats xunit.Stats) string
+
+// NoFailures returns a Rule which is violated when the test run contains failed tests.
+func NoFailures() Rule {
+	return func(stats xunit.Stats) string {
+		if stats.FailedCount > 0 {
+			return fmt.Sprintf("contains %d failed test(s)", stats.FailedCount)
+		}
+
+		return ""
+	}
+}
+
+// NoErrors returns a Rule which is violated when the test run contains environmental errors.
+func NoErrors() Rule {
+	return func(stats xunit.Stats) string {
+		if stats.ErrorCount > 0 {
+			return fmt.Sprintf("contains %d environmental error(s)", stats.ErrorCount)
+		}
+
+		return ""
+	}
+}
+
+// NoSkipped returns a Rule which is violated when the test run contains skipped tests.
+func NoSkipped() Rule {
+	return func(stats xunit.Stats) string {
+		if stats.SkippedCount > 0 {
+			return fmt.Sprintf("contains %d skipped test(s)", stats.SkippedCount)
+		}
+
+		return ""
+	}
+}
+
+// NoNotRun returns a Rule which is violated when the test run contains tests that weren't run.
+func NoNotRun() Rule {
+	return func(stats xunit.Stats) string {
+		if stats.NotRunCount > 0 {
+			return fmt.Sprintf("contains %d test(s) that weren't run", stats.NotRunCount)
+		}
+
+		return ""
+	}
+}
+
+// MinPassRate returns a Rule which is violated when the pass rate of the test run is below p (a percentage).
+func MinPassRate(p float64) Rule {
+	return func(stats xunit.Stats) string {
+		if stats.PassRate < p {
+			return fmt.Sprintf("has a pass rate of %.2f%%, which is below %.2f%%", stats.PassRate, p)
+		}
+
+		return ""
+	}
+}
+
+// MaxDuration returns a Rule which is violated when running the tests took longer than d.
+func MaxDuration(d time.Duration) Rule {
+	return func(stats xunit.Stats) string {
+		if stats.TotalDuration > d {
+			return fmt.Sprintf("took %s, which is longer than %s", stats.TotalDuration.Round(time.Millisecond), d)
+		}
+
+		return ""
+	}
+}
+
+// Evaluate returns the descriptions of the rules which are violated by stats (in the order of rules).
+func Evaluate(stats xunit.Stats, rules ...Rule) []string {
+	violations := make([]string, 0)
+
+	for _, rule := range rules {
+		if v := rule(stats); v != "" {
+			violations = append(violations, v)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,138 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "gate" package.
+package gate_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/gate"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Evaluate rules against the statistics of a test run.
+func TestEvaluate(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	stats := xunit.Stats{
+		PassedCount: 95, FailedCount: 5, ErrorCount: 2, SkippedCount: 3, NotRunCount: 4,
+		PassRate: 95, TotalDuration: 90 * time.Second,
+	}
+
+	for _, tc := range []struct {
+		stats xunit.Stats
+		rules []gate.Rule
+		want  []string
+	}{
+		{
+			stats: stats,
+			rules: []gate.Rule{},
+			want:  []string{},
+		},
+		{
+			stats: xunit.Stats{PassedCount: 10, PassRate: 100, TotalDuration: time.Second},
+			rules: []gate.Rule{
+				gate.NoFailures(), gate.NoErrors(), gate.NoSkipped(), gate.NoNotRun(), gate.MinPassRate(99.5),
+				gate.MaxDuration(time.Minute),
+			},
+			want: []string{},
+		},
+		{
+			stats: stats,
+			rules: []gate.Rule{
+				gate.NoFailures(), gate.NoErrors(), gate.NoSkipped(), gate.NoNotRun(), gate.MinPassRate(99.5),
+				gate.MaxDuration(time.Minute),
+			},
+			want: []string{
+				"contains 5 failed test(s)",
+				"contains 2 environmental error(s)",
+				"contains 3 skipped test(s)",
+				"contains 4 test(s) that weren't run",
+				"has a pass rate of 95.00%, which is below 99.50%",
+				"took 1m30s, which is longer than 1m0s",
+			},
+		},
+	} {
+		// ACT.
+		got := gate.Evaluate(tc.stats, tc.rules...)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []string) bool { return reflect.DeepEqual(got, want) }, "", "\n\n"+
+			"UT Name:    Evaluate rules against the statistics of a test run.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.stats, tc.want, got)
+	}
+}
+
+// UT: Evaluate rules against the code coverage of a test run.
+func TestEvaluateCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	report := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{{Path: "A.cs", Lines: map[int]coverage.Line{
+			1: {Hits: 1}, 2: {Hits: 1, Branches: 2, CoveredBranches: 1}, 3: {}, 4: {Hits: 2},
+		}}}},
+		{Name: "Lib", Files: []coverage.File{{Path: "L.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {}}}}},
+	}}
+
+	for _, tc := range []struct {
+		rules []gate.CoverageRule
+		want  []string
+	}{
+		{
+			rules: []gate.CoverageRule{gate.MinLineCoverage(60, false), gate.MinBranchCoverage(50, false)},
+			want:  []string{},
+		},
+		{
+			rules: []gate.CoverageRule{gate.MinLineCoverage(70, false), gate.MinBranchCoverage(75, false)},
+			want: []string{
+				"has a line coverage of 66.67%, which is below 70.00%",
+				"has a branch coverage of 50.00%, which is below 75.00%",
+			},
+		},
+		{
+			rules: []gate.CoverageRule{gate.MinLineCoverage(70, true), gate.MinBranchCoverage(75, true)},
+			want: []string{
+				"has a line coverage of 50.00% in Lib, which is below 70.00%",
+				"has a branch coverage of 50.00% in App, which is below 75.00%",
+			},
+		},
+	} {
+		// ACT.
+		got := gate.EvaluateCoverage(report, tc.rules...)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []string) bool { return reflect.DeepEqual(got, want) }, "", "\n\n"+
+			"UT Name:    Evaluate rules against the code coverage of a test run.\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.want, got)
+	}
+}
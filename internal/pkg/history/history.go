@@ -0,0 +1,324 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package history defines a store for the results of past test runs, which is used to analyze trends across runs.
+package history
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The extension of the files in which the runs are stored.
+const ext = ".json"
+
+// The name of the file containing the ID of the baseline run.
+const baselineFile = "BASELINE"
+
+// ErrInvalidID is returned when the ID of a run isn't a valid (non-empty) file name.
+var ErrInvalidID = errors.New("history: the run has no valid ID")
+
+// ErrNotFound is returned when there's no run with the requested ID.
+var ErrNotFound = errors.New("history: the run doesn't exist")
+
+// ErrNoBaseline is returned when no run has been marked as the baseline.
+var ErrNoBaseline = errors.New("history: there's no baseline run")
+
+// Run contains the results of a single test run, and the revision of the code it tested.
+type Run struct {
+	ID        string    `json:"id"`               // The identifier of the run (unique in a store).
+	Commit    string    `json:"commit,omitempty"` // The commit which was tested.
+	Branch    string    `json:"branch,omitempty"` // The branch which was tested.
+	Timestamp time.Time `json:"timestamp"`        // The time the run took place.
+	Tests     []Test    `json:"tests"`            // The results of the tests of the run.
+
+	// The code coverage of the source files, if it was recorded with the run (see NewCoverage).
+	Coverage []FileCoverage `json:"coverage,omitempty"`
+}
+
+// Test contains the result of a single test in a run.
+type Test struct {
+	Assembly string        `json:"assembly"`         // The name of the assembly containing the test.
+	Name     string        `json:"name"`             // The name of the test, as written in its document.
+	Result   string        `json:"result"`           // The result of the test.
+	Duration time.Duration `json:"duration"`         // The time it took to run the test.
+	Traits   []string      `json:"traits,omitempty"` // The traits of the test (e.g. "Category - Unit").
+}
+
+// FileCoverage contains the code coverage of a single source file in a run.
+type FileCoverage struct {
+	Assembly        string `json:"assembly"`        // The name of the assembly containing the source file.
+	Path            string `json:"path"`            // The path of the source file.
+	Lines           int    `json:"lines"`           // The number of lines which contain code.
+	CoveredLines    int    `json:"coveredLines"`    // The number of lines which were executed.
+	Branches        int    `json:"branches"`        // The number of branches.
+	CoveredBranches int    `json:"coveredBranches"` // The number of branches which were taken.
+}
+
+// NewCoverage returns the code coverage in report, per source file, as it's stored with a run.
+func NewCoverage(report coverage.Report) []FileCoverage {
+	files := make([]FileCoverage, 0)
+
+	for _, a := range report.Assemblies {
+		for _, f := range a.Files {
+			lines, branches := f.LineCoverage(), f.BranchCoverage()
+
+			files = append(files, FileCoverage{
+				Assembly:        a.Name,
+				Path:            f.Path,
+				Lines:           lines.Total,
+				CoveredLines:    lines.Covered,
+				Branches:        branches.Total,
+				CoveredBranches: branches.Covered,
+			})
+		}
+	}
+
+	return files
+}
+
+// NewRun returns a Run containing the results of testRun, identified by the commit and the time it took place.
+func NewRun(testRun xunit.TestRun, commit, branch string, timestamp time.Time) Run {
+	run := Run{
+		ID:        timestamp.UTC().Format("20060102T150405.000000000Z"),
+		Commit:    commit,
+		Branch:    branch,
+		Timestamp: timestamp,
+		Tests:     make([]Test, 0),
+	}
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			run.Tests = append(run.Tests, Test{
+				Assembly: assembly.Name,
+				Name:     tc.RawName,
+				Result:   tc.Result,
+				Duration: tc.Duration,
+				Traits:   testTraits(tc),
+			})
+		})
+	}
+
+	return run
+}
+
+// Returns the friendly names of the traits of tc (nil if it doesn't have any).
+func testTraits(tc xunit.TestCase) []string {
+	var traits []string
+
+	for _, trait := range tc.Traits {
+		traits = append(traits, trait.String())
+	}
+
+	return traits
+}
+
+// Stats returns the aggregated statistics of the tests of run.
+// Since the run doesn't record the environmental errors and the counts of its assemblies, they're derived from its
+// tests.
+func (run Run) Stats() xunit.Stats {
+	var stats xunit.Stats
+
+	assemblies := make(map[string]bool)
+
+	for _, test := range run.Tests {
+		assemblies[test.Assembly] = true
+
+		switch test.Result {
+		case "Pass":
+			stats.PassedCount++
+		case "Fail":
+			stats.FailedCount++
+		case "Skip":
+			stats.SkippedCount++
+		case "NotRun":
+			stats.NotRunCount++
+		}
+
+		stats.TotalCount++
+		stats.TotalDuration += test.Duration
+	}
+
+	stats.AssemblyCount = len(assemblies)
+
+	if executed := stats.PassedCount + stats.FailedCount; executed > 0 {
+		stats.PassRate = float64(stats.PassedCount) / float64(executed) * 100
+	}
+
+	return stats
+}
+
+// LineCoverage returns the line coverage of all the source files of run (which is empty if the run doesn't contain
+// coverage).
+func (run Run) LineCoverage() coverage.Counter {
+	var c coverage.Counter
+
+	for _, f := range run.Coverage {
+		c = c.Add(coverage.Counter{Covered: f.CoveredLines, Total: f.Lines})
+	}
+
+	return c
+}
+
+// Store is a directory containing runs, stored as one JSON document per run.
+type Store struct {
+	dir string
+}
+
+// Open returns the Store in dir, creating the directory if it doesn't exist.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Add stores run, replacing the run with the same ID (if any).
+func (s *Store) Add(run Run) error {
+	if !validID(run.ID) {
+		return ErrInvalidID
+	}
+
+	data, err := json.Marshal(run)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(run.ID), data, 0o644)
+}
+
+// Runs returns all the stored runs, ordered from the oldest to the newest.
+func (s *Store) Runs() ([]Run, error) {
+	entries, err := os.ReadDir(s.dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ext) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		var run Run
+
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		runs = append(runs, run)
+	}
+
+	slices.SortStableFunc(runs, func(a, b Run) int {
+		if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return runs, nil
+}
+
+// Run returns the run with the given ID.
+func (s *Store) Run(id string) (Run, error) {
+	if !validID(id) {
+		return Run{}, ErrInvalidID
+	}
+
+	data, err := os.ReadFile(s.path(id))
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Run{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+
+		return Run{}, err
+	}
+
+	var run Run
+
+	if err := json.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("%s: %w", id+ext, err)
+	}
+
+	return run, nil
+}
+
+// SetBaseline marks the run with the given ID as the baseline, which is the reference the other runs are compared
+// with.
+func (s *Store) SetBaseline(id string) error {
+	if _, err := s.Run(id); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.dir, baselineFile), []byte(id), 0o644)
+}
+
+// Baseline returns the run which is marked as the baseline.
+func (s *Store) Baseline() (Run, error) {
+	id, err := os.ReadFile(filepath.Join(s.dir, baselineFile))
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Run{}, ErrNoBaseline
+		}
+
+		return Run{}, err
+	}
+
+	return s.Run(strings.TrimSpace(string(id)))
+}
+
+// Returns true if id can be used as the ID of a run, false otherwise.
+func validID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+// Returns the path of the file in which the run with the given ID is stored.
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+ext)
+}
@@ -0,0 +1,295 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "history" package.
+package history_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Create a run from a test run.
+func TestNewRun(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\" />\n" +
+		"      <test name=\"A slow test.\" result=\"Pass\" time=\"2\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /><trait name=\"Timing\" value=\"Slow\" /></traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	timestamp := time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC)
+
+	// ACT.
+	got := history.NewRun(testRun, "abc123", "main", timestamp)
+
+	// ASSERT.
+	want := history.Run{
+		ID:        "20230710T205319.000000000Z",
+		Commit:    "abc123",
+		Branch:    "main",
+		Timestamp: timestamp,
+		Tests: []history.Test{
+			{Assembly: "App.dll", Name: "A passing test.", Result: "Pass", Duration: 500 * time.Millisecond},
+			{Assembly: "App.dll", Name: "A failing test.", Result: "Fail", Duration: time.Second},
+			{
+				Assembly: "App.dll",
+				Name:     "A slow test.",
+				Result:   "Pass",
+				Duration: 2 * time.Second,
+				Traits:   []string{"Category - Unit", "Timing - Slow"},
+			},
+		},
+	}
+
+	assert.EqualFn(t, got, want, func(got, want history.Run) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Create a run from a test run.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
+
+// UT: Create a run from a test run whose tests aren't grouped by trait.
+func TestNewRun_GroupBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.LoadWithOptions(strings.NewReader("<assemblies>\n"+
+		"  <assembly name=\"~/App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"A slow test.\" result=\"Pass\" time=\"2\">\n"+
+		"        <traits><trait name=\"Timing\" value=\"Slow\" /></traits>\n"+
+		"      </test>\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>"), xunit.Options{GroupBy: xunit.GroupByResult})
+
+	// ACT.
+	got := history.NewRun(testRun, "abc123", "main", time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC)).Tests[0].Traits
+
+	// ASSERT.
+	want := []string{"Timing - Slow"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Create a run from a test run whose tests aren't grouped by trait.\n"+
+		"\033[32mExpected:   The traits %q\033[0m\n"+
+		"\033[31mActual:     The traits %q\033[0m\n\n", want, got)
+}
+
+// UT: Create the coverage of a run from a coverage report.
+func TestNewCoverage(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	report := coverage.Report{Assemblies: []coverage.Assembly{
+		{Name: "App", Files: []coverage.File{
+			{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1, Branches: 2, CoveredBranches: 1}, 2: {}}},
+			{Path: "B.cs", Lines: map[int]coverage.Line{1: {Hits: 3}}},
+		}},
+	}}
+
+	// ACT.
+	got := history.NewCoverage(report)
+
+	// ASSERT.
+	want := []history.FileCoverage{
+		{Assembly: "App", Path: "A.cs", Lines: 2, CoveredLines: 1, Branches: 2, CoveredBranches: 1},
+		{Assembly: "App", Path: "B.cs", Lines: 1, CoveredLines: 1},
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Create the coverage of a run from a coverage report.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Aggregate the statistics (and the line coverage) of a run.
+func TestRunStats(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	run := history.Run{
+		Tests: []history.Test{
+			{Assembly: "App.dll", Name: "A", Result: "Pass", Duration: time.Second},
+			{Assembly: "App.dll", Name: "B", Result: "Fail", Duration: 2 * time.Second},
+			{Assembly: "Lib.dll", Name: "C", Result: "Pass", Duration: time.Second},
+			{Assembly: "Lib.dll", Name: "D", Result: "Skip"},
+		},
+		Coverage: []history.FileCoverage{
+			{Assembly: "App", Path: "A.cs", Lines: 3, CoveredLines: 2},
+			{Assembly: "App", Path: "B.cs", Lines: 1, CoveredLines: 1},
+		},
+	}
+
+	// ACT.
+	gotStats, gotCoverage := run.Stats(), run.LineCoverage()
+
+	// ASSERT.
+	wantStats := xunit.Stats{
+		AssemblyCount: 2,
+		PassedCount:   2,
+		FailedCount:   1,
+		SkippedCount:  1,
+		TotalCount:    4,
+		PassRate:      float64(2) / 3 * 100,
+		TotalDuration: 4 * time.Second,
+	}
+	wantCoverage := coverage.Counter{Covered: 3, Total: 4}
+
+	assert.Equal(t, gotStats, wantStats, "", "\n\n"+
+		"UT Name:    Aggregate the statistics (and the line coverage) of a run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", wantStats, gotStats)
+
+	assert.Equal(t, gotCoverage, wantCoverage, "", "\n\n"+
+		"UT Name:    Aggregate the statistics (and the line coverage) of a run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", wantCoverage, gotCoverage)
+}
+
+// UT: Add runs to a store, and read them back.
+func TestStore(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	store, err := history.Open(t.TempDir() + "/history")
+
+	if err != nil {
+		t.Fatalf("Open() = %v, want <nil>", err)
+	}
+
+	older := history.Run{ID: "b", Branch: "main", Timestamp: time.Date(2023, 7, 9, 0, 0, 0, 0, time.UTC)}
+	newer := history.Run{ID: "a", Branch: "main", Timestamp: time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)}
+
+	for _, run := range []history.Run{newer, older, newer} {
+		if err := store.Add(run); err != nil {
+			t.Fatalf("Add() = %v, want <nil>", err)
+		}
+	}
+
+	// ACT.
+	got, err := store.Runs()
+
+	// ASSERT.
+	assert.NoError(t, err, "", "\n\n"+
+		"UT Name:    Add runs to a store, and read them back.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", nil, err)
+
+	want := []history.Run{older, newer}
+
+	assert.EqualFn(t, got, want, func(got, want []history.Run) bool {
+		if len(got) != len(want) {
+			return false
+		}
+
+		for idx := range got {
+			if got[idx].ID != want[idx].ID || !got[idx].Timestamp.Equal(want[idx].Timestamp) {
+				return false
+			}
+		}
+
+		return true
+	}, "", "\n\n"+
+		"UT Name:    Add runs to a store, and read them back.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
+
+// UT: Add a run without a valid ID to a store.
+func TestStoreAdd_InvalidID(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	store, _ := history.Open(t.TempDir())
+
+	for _, tc := range []string{"", ".", "..", "../run", "dir/run"} {
+		// ACT.
+		err := store.Add(history.Run{ID: tc})
+
+		// ASSERT.
+		assert.Equal(t, errors.Is(err, history.ErrInvalidID), true, "", "\n\n"+
+			"UT Name:    Add a run without a valid ID to a store.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc, history.ErrInvalidID, err)
+	}
+}
+
+// UT: Mark a run as the baseline of a store.
+func TestStoreBaseline(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	store, _ := history.Open(t.TempDir())
+	_, errBefore := store.Baseline()
+
+	if err := store.Add(history.Run{ID: "run-1", Commit: "abc123"}); err != nil {
+		t.Fatalf("Add() = %v, want <nil>", err)
+	}
+
+	// ACT.
+	errMissing := store.SetBaseline("run-2")
+	errSet := store.SetBaseline("run-1")
+	got, errAfter := store.Baseline()
+
+	// ASSERT.
+	for _, tc := range []struct {
+		name string
+		got  error
+		want error
+	}{
+		{name: "Baseline() without a baseline", got: errBefore, want: history.ErrNoBaseline},
+		{name: "SetBaseline() of a missing run", got: errMissing, want: history.ErrNotFound},
+		{name: "SetBaseline() of an existing run", got: errSet, want: nil},
+		{name: "Baseline() with a baseline", got: errAfter, want: nil},
+	} {
+		assert.Equal(t, errors.Is(tc.got, tc.want), true, "", "\n\n"+
+			"UT Name:    Mark a run as the baseline of a store.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.want, tc.got)
+	}
+
+	assert.Equal(t, got.Commit, "abc123", "", "\n\n"+
+		"UT Name:    Mark a run as the baseline of a store.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", "abc123", got.Commit)
+}
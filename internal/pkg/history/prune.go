@@ -0,0 +1,119 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package history
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Retention is the policy which decides the runs to keep in a Store.
+// Each limit is disabled when it's zero. The baseline run is always kept.
+type Retention struct {
+	KeepPerBranch int           // The number of most recent runs to keep per branch.
+	MaxAge        time.Duration // The maximum age of the runs to keep.
+	MaxSize       int64         // The maximum total size (in bytes) of the stored runs (the oldest runs go first).
+}
+
+// Prune removes the stored runs which aren't kept by policy (ages are relative to now), and returns the IDs of the
+// removed runs, ordered from the oldest to the newest.
+func (s *Store) Prune(policy Retention, now time.Time) ([]string, error) {
+	runs, err := s.Runs()
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := s.Baseline()
+
+	if err != nil && !errors.Is(err, ErrNoBaseline) {
+		return nil, err
+	}
+
+	remove := make(map[string]bool)
+	perBranch := make(map[string]int)
+
+	// NOTE: The runs are ordered from the oldest to the newest, so the newest runs of a branch are counted first.
+	for idx := len(runs) - 1; idx >= 0; idx-- {
+		run := runs[idx]
+		perBranch[run.Branch]++
+
+		if policy.KeepPerBranch > 0 && perBranch[run.Branch] > policy.KeepPerBranch {
+			remove[run.ID] = true
+		}
+
+		if policy.MaxAge > 0 && now.Sub(run.Timestamp) > policy.MaxAge {
+			remove[run.ID] = true
+		}
+	}
+
+	if policy.MaxSize > 0 {
+		sizes := make(map[string]int64, len(runs))
+		var total int64
+
+		for _, run := range runs {
+			info, err := os.Stat(s.path(run.ID))
+
+			if err != nil {
+				return nil, err
+			}
+
+			sizes[run.ID] = info.Size()
+
+			if !remove[run.ID] || run.ID == baseline.ID {
+				total += info.Size()
+			}
+		}
+
+		for _, run := range runs {
+			if total <= policy.MaxSize {
+				break
+			}
+
+			if !remove[run.ID] && run.ID != baseline.ID {
+				remove[run.ID] = true
+				total -= sizes[run.ID]
+			}
+		}
+	}
+
+	removed := make([]string, 0, len(remove))
+
+	for _, run := range runs {
+		if !remove[run.ID] || run.ID == baseline.ID {
+			continue
+		}
+
+		if err := os.Remove(s.path(run.ID)); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, run.ID)
+	}
+
+	return removed, nil
+}
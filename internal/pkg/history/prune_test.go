@@ -0,0 +1,127 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "history" package.
+package history_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+)
+
+// UT: Remove the runs from a store which aren't retained.
+func TestStorePrune(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	now := time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		policy   history.Retention
+		baseline string
+		want     []string
+	}{
+		{
+			name:   "No limits",
+			policy: history.Retention{},
+			want:   []string{},
+		},
+		{
+			name:   "Keep the last run per branch",
+			policy: history.Retention{KeepPerBranch: 1},
+			want:   []string{"main-1", "main-2", "feature-1"},
+		},
+		{
+			name:     "Keep the last run per branch, and the baseline",
+			policy:   history.Retention{KeepPerBranch: 1},
+			baseline: "main-1",
+			want:     []string{"main-2", "feature-1"},
+		},
+		{
+			name:   "Keep the runs of the last 30 hours",
+			policy: history.Retention{MaxAge: 30 * time.Hour},
+			want:   []string{"main-1", "main-2"},
+		},
+		{
+			name:   "Keep at most 2 runs worth of data",
+			policy: history.Retention{MaxSize: 2 * 100},
+			want:   []string{"main-1", "main-2", "feature-1"},
+		},
+	} {
+		// ARRANGE.
+		store, _ := history.Open(t.TempDir())
+
+		for idx, run := range []history.Run{
+			{ID: "main-1", Branch: "main"},
+			{ID: "main-2", Branch: "main"},
+			{ID: "feature-1", Branch: "feature"},
+			{ID: "feature-2", Branch: "feature"},
+			{ID: "main-3", Branch: "main"},
+		} {
+			// NOTE: The runs are 12 hours apart, and have (about) the same size.
+			run.Timestamp = now.Add(time.Duration(idx-4) * 12 * time.Hour)
+
+			if err := store.Add(run); err != nil {
+				t.Fatalf("Add() = %v, want <nil>", err)
+			}
+		}
+
+		if tc.baseline != "" {
+			if err := store.SetBaseline(tc.baseline); err != nil {
+				t.Fatalf("SetBaseline() = %v, want <nil>", err)
+			}
+		}
+
+		// ACT.
+		got, err := store.Prune(tc.policy, now)
+
+		// ASSERT.
+		assert.NoError(t, err, "", "\n\n"+
+			"UT Name:    Remove the runs from a store which aren't retained.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, nil, err)
+
+		assert.EqualFn(t, got, tc.want, func(got, want []string) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Remove the runs from a store which aren't retained.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.want, got)
+
+		runs, _ := store.Runs()
+
+		assert.Equal(t, len(runs), 5-len(tc.want), "", "\n\n"+
+			"UT Name:    Remove the runs from a store which aren't retained.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %d remaining run(s)\033[0m\n"+
+			"\033[31mActual:     %d remaining run(s)\033[0m\n\n", tc.name, 5-len(tc.want), len(runs))
+	}
+}
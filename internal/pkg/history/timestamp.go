@@ -0,0 +1,88 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package history
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The layouts of the timestamps in xUnit's v2+ XML format, which depend on the culture of the machine running the
+// tests.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"01/02/2006 15:04:05",
+	"1/2/2006 15:04:05",
+	"1/2/2006 3:04:05 PM",
+}
+
+// The pattern of a timestamp in the name of a file (e.g. "results-20230710-205319.xml" or "2023-07-10.xml").
+var fileNameTimestamp = regexp.MustCompile(`(\d{4})-?(\d{2})-?(\d{2})(?:[T_ -]?(\d{2})[-:]?(\d{2})[-:]?(\d{2}))?`)
+
+// The sources of the time a test run took place, as returned by Timestamp.
+const (
+	SourceDocument = "document"          // The time is stored in the test run itself.
+	SourceFileName = "file name"         // The time is part of the name of the file containing the test run.
+	SourceModTime  = "modification time" // The time is the modification time of the file containing the test run.
+)
+
+// Timestamp returns the time testRun took place, and its source. The time is taken from testRun itself if possible,
+// otherwise from name (the name of the file containing testRun), and as a last resort it's modTime (the modification
+// time of that file).
+// Times which don't specify a time zone are interpreted as UTC.
+func Timestamp(testRun xunit.TestRun, name string, modTime time.Time) (time.Time, string) {
+	candidates := []string{testRun.StartTimeRTF, testRun.Timestamp}
+
+	for _, assembly := range testRun.Assemblies {
+		candidates = append(candidates, assembly.Time, assembly.RunDate+" "+assembly.RunTime)
+	}
+
+	for _, candidate := range candidates {
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, candidate); err == nil {
+				return t, SourceDocument
+			}
+		}
+	}
+
+	if m := fileNameTimestamp.FindStringSubmatch(name); m != nil {
+		layout, value := "20060102", m[1]+m[2]+m[3]
+
+		if m[4] != "" {
+			layout, value = layout+"150405", value+m[4]+m[5]+m[6]
+		}
+
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, SourceFileName
+		}
+	}
+
+	return modTime, SourceModTime
+}
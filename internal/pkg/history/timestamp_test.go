@@ -0,0 +1,99 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "history" package.
+package history_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Infer the time a test run took place.
+func TestTimestamp(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		xmlData    string
+		name       string
+		want       time.Time
+		wantSource string
+	}{
+		{
+			xmlData:    "<assemblies start-rtf=\"2023-07-10T20:53:19.1234567+02:00\" />",
+			name:       "results-20220101.xml",
+			want:       time.Date(2023, 7, 10, 20, 53, 19, 123456700, time.FixedZone("", 2*60*60)),
+			wantSource: history.SourceDocument,
+		},
+		{
+			xmlData:    "<assemblies timestamp=\"07/10/2023 20:53:19\" />",
+			want:       time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
+			wantSource: history.SourceDocument,
+		},
+		{
+			xmlData:    "<assemblies><assembly run-date=\"2023-07-10\" run-time=\"20:53:19\" /></assemblies>",
+			want:       time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
+			wantSource: history.SourceDocument,
+		},
+		{
+			xmlData:    "<assemblies />",
+			name:       "results-20230710-205319.xml",
+			want:       time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
+			wantSource: history.SourceFileName,
+		},
+		{
+			xmlData:    "<assemblies />",
+			name:       "nightly_2023-07-10.xml",
+			want:       time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC),
+			wantSource: history.SourceFileName,
+		},
+		{
+			xmlData:    "<assemblies timestamp=\"yesterday\" />",
+			name:       "results.xml",
+			want:       modTime,
+			wantSource: history.SourceModTime,
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(tc.xmlData))
+
+		// ACT.
+		got, gotSource := history.Timestamp(testRun, tc.name, modTime)
+
+		// ASSERT.
+		assert.Equal(t, got.Equal(tc.want) && gotSource == tc.wantSource, true, "", "\n\n"+
+			"UT Name:    Infer the time a test run took place.\n"+
+			"Input:      %v (file name: %q)\n"+
+			"\033[32mExpected:   %v (%s)\033[0m\n"+
+			"\033[31mActual:     %v (%s)\033[0m\n\n", tc.xmlData, tc.name, tc.want, tc.wantSource, got, gotSource)
+	}
+}
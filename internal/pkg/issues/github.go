@@ -0,0 +1,157 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The number of issues requested per page from GitHub.
+const githubPageSize = 100
+
+// The marker which records the stable ID of the test in the body of an issue on GitHub.
+var githubMarker = regexp.MustCompile(`<!-- dtvisual-test-id: ([0-9a-f]+) -->`)
+
+// GitHub is the issue tracker of a repository on GitHub.
+type GitHub struct {
+	baseURL    string       // The URL of the API of the repository (without a trailing slash).
+	token      string       // The token sent with each request.
+	httpClient *http.Client // The client which sends the requests.
+}
+
+// The representation of an issue on GitHub.
+type githubIssueJSON struct {
+	Number      int    `json:"number"`
+	Body        string `json:"body"`
+	PullRequest any    `json:"pull_request"` // Not nil if the issue is a pull request.
+}
+
+// NewGitHub returns the issue tracker of the repository (e.g. "owner/name") of the GitHub API at apiURL (e.g.
+// "https://api.github.com"), which authenticates with token and sends its requests with httpClient (or
+// http.DefaultClient if it's nil).
+func NewGitHub(apiURL, repo, token string, httpClient *http.Client) *GitHub {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &GitHub{baseURL: strings.TrimSuffix(apiURL, "/") + "/repos/" + repo, token: token, httpClient: httpClient}
+}
+
+// OpenIssues returns the open issues (labeled "dtvisual") of the repository.
+func (g *GitHub) OpenIssues(ctx context.Context) ([]Issue, error) {
+	resultSet := make([]Issue, 0)
+
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state":    {"open"},
+			"labels":   {label},
+			"per_page": {strconv.Itoa(githubPageSize)},
+			"page":     {strconv.Itoa(page)},
+		}
+
+		var issues []githubIssueJSON
+
+		if err := g.do(ctx, http.MethodGet, "/issues?"+query.Encode(), nil, &issues); err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if m := githubMarker.FindStringSubmatch(issue.Body); m != nil && issue.PullRequest == nil {
+				resultSet = append(resultSet, Issue{ID: strconv.Itoa(issue.Number), TestID: m[1]})
+			}
+		}
+
+		if len(issues) < githubPageSize {
+			return resultSet, nil
+		}
+	}
+}
+
+// Create opens a new issue (labeled "dtvisual") in the repository, and returns its number.
+func (g *GitHub) Create(ctx context.Context, issue Issue) (string, error) {
+	body := map[string]any{"title": title(issue), "body": githubBody(issue), "labels": []string{label}}
+
+	var created githubIssueJSON
+
+	if err := g.do(ctx, http.MethodPost, "/issues", body, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(created.Number), nil
+}
+
+// Close closes issue, with the given comment.
+func (g *GitHub) Close(ctx context.Context, issue Issue, comment string) error {
+	path := "/issues/" + url.PathEscape(issue.ID)
+
+	if err := g.do(ctx, http.MethodPost, path+"/comments", map[string]string{"body": comment}, nil); err != nil {
+		return err
+	}
+
+	return g.do(ctx, http.MethodPatch, path, map[string]string{"state": "closed", "state_reason": "completed"}, nil)
+}
+
+// Sends a request for path (relative to the repository) to GitHub.
+func (g *GitHub) do(ctx context.Context, method, path string, body, v any) error {
+	return doJSON(ctx, g.httpClient, func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}, method, g.baseURL+path, body, v)
+}
+
+// Returns the body (in Markdown) of issue on GitHub.
+func githubBody(issue Issue) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "The test `%s` of `%s` fails.\n\n", issue.Test.Name, issue.Assembly)
+
+	if issue.Trait != "" {
+		fmt.Fprintf(&sb, "**Trait:** %s\n\n", issue.Trait)
+	}
+
+	if issue.Test.Failure.ExceptionType != "" {
+		fmt.Fprintf(&sb, "**Exception:** `%s`\n\n", issue.Test.Failure.ExceptionType)
+	}
+
+	if issue.Test.Failure.Message != "" {
+		fmt.Fprintf(&sb, "```\n%s\n```\n\n", strings.TrimSpace(issue.Test.Failure.Message))
+	}
+
+	if issue.Test.Failure.StackTrace != "" {
+		fmt.Fprintf(&sb, "<details><summary>Stack trace</summary>\n\n```\n%s\n```\n\n</details>\n\n",
+			strings.TrimSpace(issue.Test.Failure.StackTrace))
+	}
+
+	fmt.Fprintf(&sb, "<!-- dtvisual-test-id: %s -->\n", issue.TestID)
+
+	return sb.String()
+}
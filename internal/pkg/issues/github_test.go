@@ -0,0 +1,193 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "issues" package.
+package issues_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/issues"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Returns a fake server which responds to each request (identified by its method and path) with the given response,
+// and records the requests it receives (with their JSON body).
+func newServer(t *testing.T, responses map[string]string) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := r.Method + " " + r.URL.Path
+
+		if len(body) > 0 {
+			var v any
+
+			json.Unmarshal(body, &v)
+			compact, _ := json.Marshal(v)
+			request += " " + string(compact)
+		}
+
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+
+		response, ok := responses[r.Method+" "+r.URL.Path]
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message": "Not Found", "errorMessages": ["Not Found"]}`)
+
+			return
+		}
+
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+// UT: Open, list and close issues on GitHub.
+func TestGitHub(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv, requests := newServer(t, map[string]string{
+		"GET /repos/owner/app/issues": `[
+			{"number": 1, "body": "Some text.\n<!-- dtvisual-test-id: 0123456789ab -->\n"},
+			{"number": 2, "body": "<!-- dtvisual-test-id: ba9876543210 -->", "pull_request": {}},
+			{"number": 3, "body": "An issue which wasn't opened by dtvisual."}
+		]`,
+		"POST /repos/owner/app/issues":            `{"number": 4}`,
+		"POST /repos/owner/app/issues/1/comments": `{}`,
+		"PATCH /repos/owner/app/issues/1":         `{}`,
+	})
+	tracker := issues.NewGitHub(srv.URL+"/", "owner/app", "s3cr3t", srv.Client())
+	issue := newTestRunIssue()
+
+	// ACT.
+	open, errOpen := tracker.OpenIssues(context.Background())
+	id, errCreate := tracker.Create(context.Background(), issue)
+	errClose := tracker.Close(context.Background(), issues.Issue{ID: "1"}, "Fixed.")
+
+	// ASSERT.
+	assert.NoError(t, errOpen, "OpenIssues()")
+	assert.NoError(t, errCreate, "Create()")
+	assert.NoError(t, errClose, "Close()")
+
+	wantOpen := []issues.Issue{{ID: "1", TestID: "0123456789ab"}}
+
+	assert.EqualFn(t, open, wantOpen, func(got, want []issues.Issue) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open, list and close issues on GitHub.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", wantOpen, open)
+
+	assert.Equal(t, id, "4", "", "\n\n"+
+		"UT Name:    Open, list and close issues on GitHub.\n"+
+		"\033[32mExpected:   Issue 4\033[0m\n"+
+		"\033[31mActual:     Issue %s\033[0m\n\n", id)
+
+	body, _ := json.Marshal(map[string]any{
+		"title": "Failing test: NS.Tests.Fails",
+		"body": "The test `NS.Tests.Fails` of `App.dll` fails.\n\n" +
+			"**Trait:** Owner - Team A\n\n" +
+			"**Exception:** `AssertException`\n\n" +
+			"```\nExpected: 1\n```\n\n" +
+			"<details><summary>Stack trace</summary>\n\n```\nat NS.Tests.Fails()\n```\n\n</details>\n\n" +
+			"<!-- dtvisual-test-id: 0123456789ab -->\n",
+		"labels": []string{"dtvisual"},
+	})
+	want := []string{
+		"GET /repos/owner/app/issues",
+		"POST /repos/owner/app/issues " + string(body),
+		"POST /repos/owner/app/issues/1/comments {\"body\":\"Fixed.\"}",
+		"PATCH /repos/owner/app/issues/1 {\"state\":\"closed\",\"state_reason\":\"completed\"}",
+	}
+
+	assert.EqualFn(t, requests(), want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open, list and close issues on GitHub.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, requests())
+}
+
+// Returns the (new) issue of a failing test.
+func newTestRunIssue() issues.Issue {
+	testRun := newTestRun()
+	issue := issues.Issue{TestID: "0123456789ab", Assembly: "App.dll", Trait: "Owner - Team A"}
+
+	testRun.Assemblies[0].Walk(func(_ []string, tc xunit.TestCase) {
+		if tc.Name == "Fails" {
+			issue.Test = tc
+		}
+	})
+
+	issue.Test.Name = "NS.Tests.Fails"
+	issue.Test.Failure.StackTrace = "at NS.Tests.Fails()"
+
+	return issue
+}
+
+// UT: Report the errors of GitHub.
+func TestGitHub_Error(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv, _ := newServer(t, nil)
+	tracker := issues.NewGitHub(srv.URL, "owner/app", "s3cr3t", srv.Client())
+
+	// ACT.
+	_, err := tracker.OpenIssues(context.Background())
+
+	// ASSERT.
+	got := ""
+
+	if err != nil {
+		got = err.Error()
+	}
+
+	assert.Contains(t, got, "issues: Not Found (404 Not Found)", "", "\n\n"+
+		"UT Name:    Report the errors of GitHub.\n"+
+		"\033[32mExpected:   issues: Not Found (404 Not Found)\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", got)
+}
@@ -0,0 +1,237 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package issues opens an issue (on GitHub, or in Jira) for each test which fails, and closes it again when the test
+// passes.
+//
+// Each issue is tied to a test by the stable ID of the test (see TestID), which is recorded in the issue itself, so
+// there's never more than one open issue for the same test.
+package issues
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/set"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The label of the issues opened by this package.
+const label = "dtvisual"
+
+// The comment added to an issue when it's closed.
+const closeComment = "The test passes again, so this issue has been closed by dtvisual."
+
+// Issue is an issue about a failing test.
+type Issue struct {
+	ID       string         // The identifier of the issue in the tracker (e.g. its number on GitHub).
+	TestID   string         // The stable ID of the test (see TestID).
+	Assembly string         // The name of the assembly the test belongs to (only for new issues).
+	Trait    string         // The first trait of the test (e.g. "Owner - Team A"), if any (only for new issues).
+	Test     xunit.TestCase // The failing test itself (only for new issues).
+}
+
+// Tracker is an issue tracker.
+type Tracker interface {
+	// OpenIssues returns the open issues which have been opened by this package.
+	OpenIssues(ctx context.Context) ([]Issue, error)
+
+	// Create opens a new issue, and returns its ID.
+	Create(ctx context.Context, issue Issue) (string, error)
+
+	// Close closes issue, with the given comment.
+	Close(ctx context.Context, issue Issue, comment string) error
+}
+
+// Result contains the issues which have been opened and closed by Sync.
+type Result struct {
+	Opened []Issue // The issues of the tests which started failing.
+	Closed []Issue // The issues of the tests which pass again.
+}
+
+// Error is returned when an issue tracker responds with an error.
+type Error struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The message of the error, as returned by the issue tracker.
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return fmt.Sprintf("issues: %s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// TestID returns the stable ID of the test with the given name in assembly, which doesn't change between runs.
+func TestID(assembly, name string) string {
+	sum := sha256.Sum256([]byte(assembly + "\x00" + name))
+
+	return hex.EncodeToString(sum[:6])
+}
+
+// Sync opens an issue in tracker for each failed test of testRun which doesn't have an open issue yet, and closes the
+// open issues of the tests which passed.
+// The issues of tests which were skipped, or which aren't part of testRun, are left open.
+func Sync(ctx context.Context, tracker Tracker, testRun xunit.TestRun) (Result, error) {
+	open, err := tracker.OpenIssues(ctx)
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	openByTest := make(map[string]Issue, len(open))
+
+	for _, issue := range open {
+		openByTest[issue.TestID] = issue
+	}
+
+	var failed []Issue
+
+	passed := set.New[string]()
+
+	for _, a := range testRun.Assemblies {
+		a.Walk(func(_ []string, tc xunit.TestCase) {
+			id := TestID(a.Name, tc.RawName)
+
+			switch tc.Result {
+			case "Pass":
+				passed.Add(id)
+			case "Fail":
+				issue := Issue{TestID: id, Assembly: a.Name, Test: tc}
+
+				if len(tc.Traits) > 0 {
+					issue.Trait = tc.Traits[0].String()
+				}
+
+				failed = append(failed, issue)
+			}
+		})
+	}
+
+	var result Result
+
+	for _, issue := range failed {
+		if _, ok := openByTest[issue.TestID]; ok {
+			continue
+		}
+
+		if issue.ID, err = tracker.Create(ctx, issue); err != nil {
+			return result, err
+		}
+
+		openByTest[issue.TestID] = issue
+		result.Opened = append(result.Opened, issue)
+	}
+
+	for _, issue := range open {
+		if !passed.Has(issue.TestID) {
+			continue
+		}
+
+		if err := tracker.Close(ctx, issue, closeComment); err != nil {
+			return result, err
+		}
+
+		result.Closed = append(result.Closed, issue)
+	}
+
+	return result, nil
+}
+
+// Returns the title of issue.
+func title(issue Issue) string {
+	return "Failing test: " + issue.Test.Name
+}
+
+// Sends a request (with authorize) to url with body as JSON (unless it's nil), and decodes the (JSON) response into v
+// (unless it's nil).
+func doJSON(ctx context.Context, httpClient *http.Client, authorize func(*http.Request), method, url string, body,
+	v any,
+) error {
+	var rdr io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+
+		if err != nil {
+			return err
+		}
+
+		rdr = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, rdr)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	authorize(req)
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body struct {
+			Message       string   `json:"message"`       // The message of an error on GitHub.
+			ErrorMessages []string `json:"errorMessages"` // The messages of an error in Jira.
+		}
+
+		json.NewDecoder(resp.Body).Decode(&body)
+
+		if body.Message == "" && len(body.ErrorMessages) > 0 {
+			body.Message = body.ErrorMessages[0]
+		}
+
+		if body.Message == "" {
+			body.Message = "unexpected response"
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Message: body.Message}
+	}
+
+	if v == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,178 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "issues" package.
+package issues_test
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/issues"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A fake issue tracker, which keeps its issues in memory.
+type fakeTracker struct {
+	open   []issues.Issue // The open issues.
+	calls  []string       // The calls to Create and Close.
+	nextID int            // The ID of the previously created issue.
+}
+
+// OpenIssues returns the open issues of t.
+func (t *fakeTracker) OpenIssues(context.Context) ([]issues.Issue, error) {
+	return t.open, nil
+}
+
+// Create records the creation of issue.
+func (t *fakeTracker) Create(_ context.Context, issue issues.Issue) (string, error) {
+	t.calls = append(t.calls, "create "+issue.Test.Name+" ("+issue.Trait+")")
+	t.nextID++
+
+	return "#" + strconv.Itoa(t.nextID), nil
+}
+
+// Close records the closing of issue.
+func (t *fakeTracker) Close(_ context.Context, issue issues.Issue, _ string) error {
+	t.calls = append(t.calls, "close "+issue.ID)
+
+	return nil
+}
+
+// Returns a test run with a test which passes, a test which fails (twice, with different traits) and a test which is
+// skipped.
+func newTestRun() xunit.TestRun {
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" total=\"3\" passed=\"1\" failed=\"1\" skipped=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Passes\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"Fails\" result=\"Fail\" time=\"1\">\n" +
+		"        <traits><trait name=\"Owner\" value=\"Team A\" /><trait name=\"Owner\" value=\"Team B\" /></traits>\n" +
+		"        <failure exception-type=\"AssertException\"><message>Expected: 1</message></failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"FailsToo\" result=\"Fail\" time=\"1\" />\n" +
+		"      <test name=\"Skips\" result=\"Skip\" time=\"0\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	return testRun
+}
+
+// UT: Open (and close) the issues of the failing tests of a test run.
+func TestSync(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	tracker := &fakeTracker{open: []issues.Issue{
+		{ID: "#7", TestID: issues.TestID("App.dll", "Passes")},
+		{ID: "#8", TestID: issues.TestID("App.dll", "FailsToo")},
+		{ID: "#9", TestID: issues.TestID("App.dll", "Skips")},
+		{ID: "#10", TestID: issues.TestID("App.dll", "Removed")},
+	}}
+
+	// ACT.
+	result, err := issues.Sync(context.Background(), tracker, newTestRun())
+
+	// ASSERT.
+	assert.NoError(t, err, "Sync()")
+
+	want := []string{"create Fails (Owner - Team A)", "close #7"}
+
+	assert.EqualFn(t, tracker.calls, want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open (and close) the issues of the failing tests of a test run.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, tracker.calls)
+
+	got := len(result.Opened) == 1 && result.Opened[0].ID == "#1" &&
+		len(result.Closed) == 1 && result.Closed[0].ID == "#7"
+
+	assert.Equal(t, got, true, "", "\n\n"+
+		"UT Name:    Open (and close) the issues of the failing tests of a test run.\n"+
+		"\033[32mExpected:   Opened #1, closed #7\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", result)
+}
+
+// UT: Open the issues of the failing tests of a test run whose tests aren't grouped by trait.
+func TestSync_GroupBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	tracker := &fakeTracker{}
+	testRun, _ := xunit.LoadWithOptions(strings.NewReader("<assemblies>\n"+
+		"  <assembly name=\"~/App.dll\" total=\"2\" failed=\"2\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"Fails\" result=\"Fail\" time=\"1\">\n"+
+		"        <traits><trait name=\"Owner\" value=\"Team A\" /></traits>\n"+
+		"      </test>\n"+
+		"      <test name=\"FailsToo\" result=\"Fail\" time=\"1\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>"), xunit.Options{GroupBy: xunit.GroupByResult})
+
+	// ACT.
+	_, err := issues.Sync(context.Background(), tracker, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Sync()")
+
+	want := []string{"create Fails (Owner - Team A)", "create FailsToo ()"}
+
+	assert.EqualFn(t, tracker.calls, want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open the issues of the failing tests of a test run whose tests aren't grouped by trait.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, tracker.calls)
+}
+
+// UT: Compute the stable ID of a test.
+func TestTestID(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		assembly, name, other string
+	}{
+		{assembly: "App.dll", name: "NS.Tests.Fails", other: "NS.Tests.Passes"},
+		{assembly: "App.dll", name: "A", other: "App.dllA"},
+	} {
+		// ACT.
+		id, again := issues.TestID(tc.assembly, tc.name), issues.TestID(tc.assembly, tc.name)
+		other := issues.TestID("", tc.other)
+
+		// ASSERT.
+		assert.Equal(t, id == again && id != other && len(id) == 12, true, "", "\n\n"+
+			"UT Name:    Compute the stable ID of a test.\n"+
+			"Input:      %s, %s\n"+
+			"\033[32mExpected:   A stable ID of 12 characters, which differs from the ID of %s\033[0m\n"+
+			"\033[31mActual:     %s, %s (other: %s)\033[0m\n\n", tc.assembly, tc.name, tc.other, id, again, other)
+	}
+}
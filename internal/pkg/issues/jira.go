@@ -0,0 +1,199 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The number of issues requested per page from Jira.
+const jiraPageSize = 100
+
+// The prefix of the label which records the stable ID of the test of an issue in Jira.
+const jiraTestLabel = label + "-"
+
+// Jira is the issue tracker of a project in Jira.
+type Jira struct {
+	baseURL    string       // The URL of the Jira site (without a trailing slash).
+	project    string       // The key of the project (e.g. "APP").
+	issueType  string       // The type of the issues which are created (e.g. "Bug").
+	user       string       // The user (e.g. the email address) which authenticates.
+	token      string       // The API token of the user.
+	httpClient *http.Client // The client which sends the requests.
+}
+
+// The representation of an issue in Jira.
+type jiraIssueJSON struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Labels []string `json:"labels"`
+	} `json:"fields"`
+}
+
+// NewJira returns the issue tracker of the project (e.g. "APP") of the Jira site at siteURL (e.g.
+// "https://contoso.atlassian.net"), which creates issues of issueType (e.g. "Bug"), authenticates with the API token
+// of user and sends its requests with httpClient (or http.DefaultClient if it's nil).
+func NewJira(siteURL, project, issueType, user, token string, httpClient *http.Client) *Jira {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Jira{
+		baseURL:    strings.TrimSuffix(siteURL, "/"),
+		project:    project,
+		issueType:  issueType,
+		user:       user,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// OpenIssues returns the unresolved issues (labeled "dtvisual") of the project.
+func (j *Jira) OpenIssues(ctx context.Context) ([]Issue, error) {
+	resultSet := make([]Issue, 0)
+	jql := fmt.Sprintf("project = %q AND labels = %s AND statusCategory != Done", j.project, label)
+
+	for startAt := 0; ; startAt += jiraPageSize {
+		query := url.Values{
+			"jql":        {jql},
+			"fields":     {"labels"},
+			"startAt":    {strconv.Itoa(startAt)},
+			"maxResults": {strconv.Itoa(jiraPageSize)},
+		}
+
+		var page struct {
+			Issues []jiraIssueJSON `json:"issues"`
+			Total  int             `json:"total"`
+		}
+
+		if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+
+		for _, issue := range page.Issues {
+			for _, l := range issue.Fields.Labels {
+				if testID, ok := strings.CutPrefix(l, jiraTestLabel); ok {
+					resultSet = append(resultSet, Issue{ID: issue.Key, TestID: testID})
+				}
+			}
+		}
+
+		if len(page.Issues) < jiraPageSize || startAt+len(page.Issues) >= page.Total {
+			return resultSet, nil
+		}
+	}
+}
+
+// Create opens a new issue (labeled "dtvisual") in the project, and returns its key.
+func (j *Jira) Create(ctx context.Context, issue Issue) (string, error) {
+	body := map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": j.issueType},
+		"summary":     title(issue),
+		"description": jiraDescription(issue),
+		"labels":      []string{label, jiraTestLabel + issue.TestID},
+	}}
+
+	var created jiraIssueJSON
+
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &created); err != nil {
+		return "", err
+	}
+
+	return created.Key, nil
+}
+
+// Close resolves issue (with the first transition to a status in the "Done" category), with the given comment.
+func (j *Jira) Close(ctx context.Context, issue Issue, comment string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(issue.ID)
+
+	if err := j.do(ctx, http.MethodPost, path+"/comment", map[string]string{"body": comment}, nil); err != nil {
+		return err
+	}
+
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+
+	if err := j.do(ctx, http.MethodGet, path+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+
+	for _, t := range transitions.Transitions {
+		if t.To.StatusCategory.Key == "done" {
+			body := map[string]any{"transition": map[string]string{"id": t.ID}}
+
+			return j.do(ctx, http.MethodPost, path+"/transitions", body, nil)
+		}
+	}
+
+	return fmt.Errorf("issues: %s can't be resolved (no transition to a status in the \"Done\" category)", issue.ID)
+}
+
+// Sends a request for path to Jira.
+func (j *Jira) do(ctx context.Context, method, path string, body, v any) error {
+	return doJSON(ctx, j.httpClient, func(req *http.Request) {
+		req.SetBasicAuth(j.user, j.token)
+	}, method, j.baseURL+path, body, v)
+}
+
+// Returns the description (in Jira's wiki markup) of issue.
+func jiraDescription(issue Issue) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "The test {{%s}} of {{%s}} fails.\n\n", issue.Test.Name, issue.Assembly)
+
+	if issue.Trait != "" {
+		fmt.Fprintf(&sb, "*Trait:* %s\n\n", issue.Trait)
+	}
+
+	if issue.Test.Failure.ExceptionType != "" {
+		fmt.Fprintf(&sb, "*Exception:* {{%s}}\n\n", issue.Test.Failure.ExceptionType)
+	}
+
+	if issue.Test.Failure.Message != "" {
+		fmt.Fprintf(&sb, "{noformat}\n%s\n{noformat}\n\n", strings.TrimSpace(issue.Test.Failure.Message))
+	}
+
+	if issue.Test.Failure.StackTrace != "" {
+		fmt.Fprintf(&sb, "*Stack trace:*\n{noformat}\n%s\n{noformat}\n",
+			strings.TrimSpace(issue.Test.Failure.StackTrace))
+	}
+
+	return sb.String()
+}
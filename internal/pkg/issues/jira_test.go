@@ -0,0 +1,108 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "issues" package.
+package issues_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/issues"
+)
+
+// UT: Open, list and close issues in Jira.
+func TestJira(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv, requests := newServer(t, map[string]string{
+		"GET /rest/api/2/search": `{"total": 2, "issues": [
+			{"key": "APP-1", "fields": {"labels": ["dtvisual", "dtvisual-0123456789ab"]}},
+			{"key": "APP-2", "fields": {"labels": ["dtvisual"]}}
+		]}`,
+		"POST /rest/api/2/issue":               `{"key": "APP-3"}`,
+		"POST /rest/api/2/issue/APP-1/comment": `{}`,
+		"GET /rest/api/2/issue/APP-1/transitions": `{"transitions": [
+			{"id": "11", "to": {"statusCategory": {"key": "indeterminate"}}},
+			{"id": "31", "to": {"statusCategory": {"key": "done"}}}
+		]}`,
+		"POST /rest/api/2/issue/APP-1/transitions": ``,
+	})
+	tracker := issues.NewJira(srv.URL, "APP", "Bug", "ci@contoso.com", "s3cr3t", srv.Client())
+
+	// ACT.
+	open, errOpen := tracker.OpenIssues(context.Background())
+	id, errCreate := tracker.Create(context.Background(), newTestRunIssue())
+	errClose := tracker.Close(context.Background(), issues.Issue{ID: "APP-1"}, "Fixed.")
+
+	// ASSERT.
+	assert.NoError(t, errOpen, "OpenIssues()")
+	assert.NoError(t, errCreate, "Create()")
+	assert.NoError(t, errClose, "Close()")
+
+	wantOpen := []issues.Issue{{ID: "APP-1", TestID: "0123456789ab"}}
+
+	assert.EqualFn(t, open, wantOpen, func(got, want []issues.Issue) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open, list and close issues in Jira.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", wantOpen, open)
+
+	assert.Equal(t, id, "APP-3", "", "\n\n"+
+		"UT Name:    Open, list and close issues in Jira.\n"+
+		"\033[32mExpected:   Issue APP-3\033[0m\n"+
+		"\033[31mActual:     Issue %s\033[0m\n\n", id)
+
+	body, _ := json.Marshal(map[string]any{"fields": map[string]any{
+		"project":   map[string]string{"key": "APP"},
+		"issuetype": map[string]string{"name": "Bug"},
+		"summary":   "Failing test: NS.Tests.Fails",
+		"description": "The test {{NS.Tests.Fails}} of {{App.dll}} fails.\n\n" +
+			"*Trait:* Owner - Team A\n\n" +
+			"*Exception:* {{AssertException}}\n\n" +
+			"{noformat}\nExpected: 1\n{noformat}\n\n" +
+			"*Stack trace:*\n{noformat}\nat NS.Tests.Fails()\n{noformat}\n",
+		"labels": []string{"dtvisual", "dtvisual-0123456789ab"},
+	}})
+	want := []string{
+		"GET /rest/api/2/search",
+		"POST /rest/api/2/issue " + string(body),
+		"POST /rest/api/2/issue/APP-1/comment {\"body\":\"Fixed.\"}",
+		"GET /rest/api/2/issue/APP-1/transitions",
+		"POST /rest/api/2/issue/APP-1/transitions {\"transition\":{\"id\":\"31\"}}",
+	}
+
+	assert.EqualFn(t, requests(), want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Open, list and close issues in Jira.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, requests())
+}
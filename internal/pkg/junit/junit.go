@@ -0,0 +1,167 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package junit contains functions for writing .NET test result(s) in the JUnit XML format, which is understood by
+// most CI systems.
+// More information regarding this format can be found @ https://github.com/testmoapp/junitxml.
+package junit
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A testSuites is the top-level element of the document.
+type testSuites struct {
+	XMLName    xml.Name    `xml:"testsuites"`
+	Tests      int         `xml:"tests,attr"`
+	Failures   int         `xml:"failures,attr"`
+	Errors     int         `xml:"errors,attr"`
+	Skipped    int         `xml:"skipped,attr"`
+	Time       string      `xml:"time,attr"`
+	Timestamp  string      `xml:"timestamp,attr,omitempty"`
+	TestSuites []testSuite `xml:"testsuite"`
+}
+
+// A testSuite contains the tests of a single assembly.
+type testSuite struct {
+	Name      string     `xml:"name,attr"`
+	Tests     int        `xml:"tests,attr"`
+	Failures  int        `xml:"failures,attr"`
+	Errors    int        `xml:"errors,attr"`
+	Skipped   int        `xml:"skipped,attr"`
+	Time      string     `xml:"time,attr"`
+	Hostname  string     `xml:"hostname,attr,omitempty"`
+	TestCases []testCase `xml:"testcase"`
+}
+
+// A testCase contains the result of a single test.
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr"`
+	File      string   `xml:"file,attr,omitempty"`
+	Line      int      `xml:"line,attr,omitempty"`
+	Failure   *failure `xml:"failure"`
+	Skipped   *skipped `xml:"skipped"`
+}
+
+// A failure contains the details of a failed test.
+type failure struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// A skipped marks a test as skipped.
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Write writes testRun to w in the JUnit XML format.
+// Each assembly is written as a test suite. The class name of a test is the path of the groups it belongs to (joined
+// by dots), or the name of the assembly if the test doesn't belong to a named group.
+func Write(w io.Writer, testRun xunit.TestRun) error {
+	stats := testRun.Stats()
+	doc := testSuites{
+		Tests:      stats.TotalCount,
+		Failures:   stats.FailedCount,
+		Errors:     stats.ErrorCount,
+		Skipped:    stats.SkippedCount,
+		Time:       fmtSeconds(stats.TotalDuration),
+		Timestamp:  testRun.Timestamp,
+		TestSuites: make([]testSuite, 0, len(testRun.Assemblies)),
+	}
+
+	for _, assembly := range testRun.Assemblies {
+		suite := testSuite{
+			Name:      assembly.Name,
+			Tests:     assembly.TotalCount,
+			Failures:  assembly.FailedCount,
+			Errors:    assembly.ErrorCount,
+			Skipped:   assembly.SkippedCount,
+			Time:      fmtSeconds(assembly.Duration),
+			Hostname:  testRun.Computer,
+			TestCases: make([]testCase, 0, assembly.TotalCount),
+		}
+
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			suite.TestCases = append(suite.TestCases, newTestCase(assembly.Name, path, tc))
+		})
+
+		doc.TestSuites = append(doc.TestSuites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Returns the testCase representation of tc.
+func newTestCase(assemblyName string, path []string, tc xunit.TestCase) testCase {
+	className := assemblyName
+
+	if len(path) > 0 {
+		className = strings.Join(path, ".")
+	}
+
+	res := testCase{
+		Name:      tc.Name,
+		ClassName: className,
+		Time:      fmtSeconds(tc.Duration),
+		File:      tc.SourceFile,
+		Line:      tc.SourceLine,
+	}
+
+	switch tc.Result {
+	case "Fail":
+		res.Failure = &failure{Message: tc.Failure.Message, Type: tc.Failure.ExceptionType, Text: tc.Failure.StackTrace}
+	case "Skip", "NotRun":
+		res.Skipped = &skipped{Message: tc.Reason}
+	}
+
+	return res
+}
+
+// Returns d as a number of seconds, with millisecond precision.
+func fmtSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,93 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "junit" package.
+package junit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/junit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Write a test run in the JUnit XML format.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    string
+	}{
+		{
+			xmlData: "<assemblies computer=\"WIN11\" timestamp=\"07/10/2023 20:53:19\">\n" +
+				"  <assembly name=\"~/App.dll\" total=\"3\" passed=\"1\" failed=\"1\" skipped=\"1\" time=\"1.5\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"A test with a display name.\" result=\"Pass\" time=\"0.5\" />\n" +
+				"      <test name=\"NS.TestClass+Method.Result\" result=\"Fail\" time=\"1\" source-file=\"Tests.cs\" source-line=\"7\">\n" +
+				"        <failure exception-type=\"Xunit.Sdk.TrueException\">\n" +
+				"          <message>Expected: True &amp; Actual: False</message>\n" +
+				"          <stack-trace>at Result()</stack-trace>\n" +
+				"        </failure>\n" +
+				"      </test>\n" +
+				"      <test name=\"A skipped test.\" result=\"Skip\">\n" +
+				"        <reason>Not implemented.</reason>\n" +
+				"      </test>\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<testsuites tests=\"3\" failures=\"1\" errors=\"0\" skipped=\"1\" time=\"1.500\" timestamp=\"07/10/2023 20:53:19\">\n" +
+				"  <testsuite name=\"App.dll\" tests=\"3\" failures=\"1\" errors=\"0\" skipped=\"1\" time=\"1.500\" hostname=\"WIN11\">\n" +
+				"    <testcase name=\"A test with a display name.\" classname=\"App.dll\" time=\"0.500\"></testcase>\n" +
+				"    <testcase name=\"A skipped test.\" classname=\"App.dll\" time=\"0.000\">\n" +
+				"      <skipped message=\"Not implemented.\"></skipped>\n" +
+				"    </testcase>\n" +
+				"    <testcase name=\"NS.TestClass+Method.Result\" classname=\"TestClass.Method\" time=\"1.000\" file=\"Tests.cs\" line=\"7\">\n" +
+				"      <failure message=\"Expected: True &amp; Actual: False\" type=\"Xunit.Sdk.TrueException\">at Result()</failure>\n" +
+				"    </testcase>\n" +
+				"  </testsuite>\n" +
+				"</testsuites>\n",
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(tc.xmlData))
+
+		var sb strings.Builder
+
+		// ACT.
+		err := junit.Write(&sb, testRun)
+
+		// ASSERT.
+		assert.NoError(t, err, "Write()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Write a test run in the JUnit XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.xmlData, tc.want, sb.String())
+	}
+}
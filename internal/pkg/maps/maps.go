@@ -43,3 +43,51 @@ func SortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
 
 	return r
 }
+
+// SortedKeysFunc returns the keys of the map m, sorted with the comparison function cmp (which returns a negative
+// number if a < b, a positive number if a > b, and 0 if they're equal).
+func SortedKeysFunc[M ~map[K]V, K comparable, V any](m M, cmp func(a, b K) int) []K {
+	r := make([]K, 0, len(m))
+
+	for k := range m {
+		r = append(r, k)
+	}
+
+	slices.SortFunc(r, cmp)
+
+	return r
+}
+
+// Values returns the values of the map m (in an unspecified order).
+func Values[M ~map[K]V, K comparable, V any](m M) []V {
+	r := make([]V, 0, len(m))
+
+	for _, v := range m {
+		r = append(r, v)
+	}
+
+	return r
+}
+
+// SortedValues returns the values of the map m (sorted by their key).
+func SortedValues[M ~map[K]V, K cmp.Ordered, V any](m M) []V {
+	r := make([]V, 0, len(m))
+
+	for _, k := range SortedKeys(m) {
+		r = append(r, m[k])
+	}
+
+	return r
+}
+
+// Invert returns a map from each value of the map m to its key.
+// If multiple keys have the same value, it's unspecified which one of them is kept.
+func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
+	r := make(map[V]K, len(m))
+
+	for k, v := range m {
+		r[v] = k
+	}
+
+	return r
+}
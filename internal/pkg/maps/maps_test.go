@@ -28,6 +28,8 @@ package maps_test
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
@@ -56,3 +58,110 @@ func TestSortedKeys(t *testing.T) {
 			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
 	}
 }
+
+// UT: Get the keys of a map (sorted with a custom comparison function).
+func TestSortedKeysFunc(t *testing.T) {
+	for _, tc := range []struct {
+		input map[string]bool
+		want  []string
+	}{
+		{
+			input: map[string]bool{"Scenario 10": true, "Scenario 2": true, "Scenario 1": false},
+			want:  []string{"Scenario 1", "Scenario 2", "Scenario 10"},
+		},
+	} {
+		// ACT.
+		got := maps.SortedKeysFunc(tc.input, func(a, b string) int {
+			if len(a) != len(b) {
+				return len(a) - len(b)
+			}
+
+			return strings.Compare(a, b)
+		})
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the keys of a map (sorted with a custom comparison function).\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Get the values of a map.
+func TestValues(t *testing.T) {
+	for _, tc := range []struct {
+		input map[string]int
+		want  []int
+	}{
+		{
+			input: map[string]int{"Pass": 3, "Fail": 1, "Skip": 2},
+			want:  []int{1, 2, 3},
+		},
+		{
+			input: nil,
+			want:  []int{},
+		},
+	} {
+		// ACT.
+		got := maps.Values(tc.input)
+		slices.Sort(got)
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the values of a map.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Get the values of a map (sorted by their key).
+func TestSortedValues(t *testing.T) {
+	for _, tc := range []struct {
+		input map[string]int
+		want  []int
+	}{
+		{
+			input: map[string]int{"Pass": 1, "Fail": 2, "Skip": 3},
+			want:  []int{2, 1, 3},
+		},
+	} {
+		// ACT.
+		got := maps.SortedValues(tc.input)
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the values of a map (sorted by their key).\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Invert a map.
+func TestInvert(t *testing.T) {
+	for _, tc := range []struct {
+		input map[string]int
+		want  map[int]string
+	}{
+		{
+			input: map[string]int{"Pass": 1, "Fail": 2},
+			want:  map[int]string{1: "Pass", 2: "Fail"},
+		},
+		{
+			input: map[string]int{},
+			want:  map[int]string{},
+		},
+	} {
+		// ACT.
+		got := maps.Invert(tc.input)
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Invert a map.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
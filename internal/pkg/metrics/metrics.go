@@ -0,0 +1,167 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package metrics defines an HTTP handler, which exposes the health of the tests of each project as Prometheus metrics
+// (in the text exposition format), so they can be watched by existing monitoring and alerting stacks.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/history"
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The content type of the text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// A metric is a gauge with a value per project.
+type metric struct {
+	name  string                  // The name of the metric.
+	help  string                  // The description of the metric.
+	value func(p project) float64 // Returns the value of the metric for a project.
+}
+
+// The runs of a project, from the oldest to the newest.
+type project struct {
+	runs []api.Run
+}
+
+// The exposed metrics.
+var metrics = []metric{
+	{
+		name:  "dtvisual_runs",
+		help:  "The number of runs of the project.",
+		value: func(p project) float64 { return float64(len(p.runs)) },
+	},
+	{
+		name:  "dtvisual_latest_tests",
+		help:  "The number of tests in the latest run of the project.",
+		value: func(p project) float64 { return float64(p.latest().TotalCount) },
+	},
+	{
+		name:  "dtvisual_latest_failed_tests",
+		help:  "The number of failed tests in the latest run of the project.",
+		value: func(p project) float64 { return float64(p.latest().FailedCount) },
+	},
+	{
+		name:  "dtvisual_latest_pass_ratio",
+		help:  "The fraction (0-1) of the tests in the latest run of the project which passed.",
+		value: func(p project) float64 { return p.latest().PassRate / 100 },
+	},
+	{
+		name:  "dtvisual_latest_duration_seconds",
+		help:  "The time it took to execute the tests in the latest run of the project.",
+		value: func(p project) float64 { return p.latest().TotalDuration.Seconds() },
+	},
+	{
+		name:  "dtvisual_flaky_tests",
+		help:  "The number of tests of the project whose result flips between runs of the same commit (or branch).",
+		value: func(p project) float64 { return float64(len(analysis.Flaky(p.history(), 0))) },
+	},
+}
+
+// Handler returns the handler which exposes the metrics of the runs returned by source, grouped by project.
+func Handler(source api.Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runs, err := source()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+
+		Write(w, runs)
+	})
+}
+
+// Write writes the metrics of runs (grouped by project) to w, in the text exposition format.
+// The runs must be ordered from the oldest to the newest.
+func Write(w io.Writer, runs []api.Run) error {
+	projects := make(map[string]*project)
+
+	for _, run := range runs {
+		name := run.Project
+
+		if name == "" {
+			name = api.DefaultProject
+		}
+
+		if projects[name] == nil {
+			projects[name] = &project{}
+		}
+
+		projects[name].runs = append(projects[name].runs, run)
+	}
+
+	names := maps.SortedKeys(projects)
+
+	var sb strings.Builder
+
+	for _, m := range metrics {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+
+		for _, name := range names {
+			fmt.Fprintf(&sb, "%s{project=\"%s\"} %s\n", m.name, escape(name),
+				strconv.FormatFloat(m.value(*projects[name]), 'g', -1, 64))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// Returns the statistics of the latest run of p.
+func (p project) latest() xunit.Stats {
+	return p.runs[len(p.runs)-1].TestRun.Stats()
+}
+
+// Returns the runs of p, as stored in the history (which is used to detect flaky tests).
+func (p project) history() []history.Run {
+	resultSet := make([]history.Run, 0, len(p.runs))
+
+	for _, run := range p.runs {
+		resultSet = append(resultSet, history.NewRun(run.TestRun, run.Commit, run.Branch, time.Time{}))
+	}
+
+	return resultSet
+}
+
+// Returns value, escaped as the value of a label.
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,106 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "metrics" package.
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/metrics"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Returns a test run in which "Test 1" has the given result ("Pass" or "Fail"), and "Test 2" passed.
+func newTestRun(result string) xunit.TestRun {
+	counts := "passed=\"2\" failed=\"0\""
+
+	if result == "Fail" {
+		counts = "passed=\"1\" failed=\"1\""
+	}
+
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" " + counts + " time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Test 1\" result=\"" + result + "\" time=\"1\" />\n" +
+		"      <test name=\"Test 2\" result=\"Pass\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	return testRun
+}
+
+// UT: Expose the metrics of the runs of each project.
+func TestHandler(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	rec := httptest.NewRecorder()
+	handler := metrics.Handler(func() ([]api.Run, error) {
+		return []api.Run{
+			{ID: "api-1", Project: "api", Branch: "main", TestRun: newTestRun("Pass")},
+			{ID: "nightly", TestRun: newTestRun("Fail")},
+			{ID: "api-2", Project: "api", Branch: "main", TestRun: newTestRun("Fail")},
+			{ID: "web-1", Project: "web \"app\"", Branch: "main", TestRun: newTestRun("Pass")},
+		}, nil
+	})
+
+	// ACT.
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// ASSERT.
+	gotType, wantType := rec.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8"
+
+	assert.Equal(t, gotType, wantType, "", "\n\n"+
+		"UT Name:    Expose the metrics of the runs of each project.\n"+
+		"\033[32mExpected:   Content-Type: %s\033[0m\n"+
+		"\033[31mActual:     Content-Type: %s\033[0m\n\n", wantType, gotType)
+
+	for _, want := range []string{
+		"# HELP dtvisual_runs The number of runs of the project.\n" +
+			"# TYPE dtvisual_runs gauge\n" +
+			"dtvisual_runs{project=\"api\"} 2\n" +
+			"dtvisual_runs{project=\"default\"} 1\n" +
+			"dtvisual_runs{project=\"web \\\"app\\\"\"} 1\n",
+		"dtvisual_latest_tests{project=\"api\"} 2\n",
+		"dtvisual_latest_failed_tests{project=\"api\"} 1\n",
+		"dtvisual_latest_failed_tests{project=\"web \\\"app\\\"\"} 0\n",
+		"dtvisual_latest_pass_ratio{project=\"api\"} 0.5\n",
+		"dtvisual_latest_duration_seconds{project=\"api\"} 1.5\n",
+		"dtvisual_flaky_tests{project=\"api\"} 1\n",
+		"dtvisual_flaky_tests{project=\"default\"} 0\n",
+	} {
+		assert.Contains(t, rec.Body.String(), want, "", "\n\n"+
+			"UT Name:    Expose the metrics of the runs of each project.\n"+
+			"\033[32mExpected:   Output containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", want, rec.Body.String())
+	}
+}
@@ -0,0 +1,220 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package mutation contains functions for loading the results of mutation testing, as reported by Stryker.NET (in
+// the JSON format of mutation-testing-elements), and summarizing them per namespace.
+// More information regarding this format can be found @ https://github.com/stryker-mutator/mutation-testing-elements.
+package mutation
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Matches the (first) namespace declaration of a C# source file.
+var namespaceDecl = regexp.MustCompile(`(?m)^\s*namespace\s+([\w.]+)`)
+
+// Report contains the results of mutation testing, per namespace.
+type Report struct {
+	Namespaces []Namespace // The namespaces, ordered by name.
+	Thresholds Thresholds  // The thresholds of the mutation score.
+}
+
+// Namespace contains the results of mutation testing of the source files in a single namespace.
+type Namespace struct {
+	Name   string // The name of the namespace (or the directory of the source files if it isn't known).
+	Counts        // The number of mutants of each status.
+}
+
+// Thresholds contains the mutation scores above which the results are considered to be good or acceptable.
+type Thresholds struct {
+	High float64 // The score (0 - 100) at or above which the results are good.
+	Low  float64 // The score (0 - 100) below which the results are bad.
+}
+
+// Counts contains the number of mutants of each status.
+type Counts struct {
+	Killed     int // The number of mutants which made a test fail.
+	Timeout    int // The number of mutants which made the tests time out.
+	Survived   int // The number of mutants which didn't make any test fail.
+	NoCoverage int // The number of mutants which aren't exercised by any test.
+	Ignored    int // The number of mutants which weren't tested, or which failed to compile or run.
+}
+
+// The document of the JSON report.
+type document struct {
+	Thresholds *Thresholds `json:"thresholds"`
+	Files      map[string]struct {
+		Source  string `json:"source"`
+		Mutants []struct {
+			Status string `json:"status"`
+		} `json:"mutants"`
+	} `json:"files"`
+}
+
+// Load reads a JSON report of Stryker.NET (e.g. "mutation-report.json") from rdr, and returns the results it contains.
+// The namespace of each source file is its first namespace declaration, or the directory of the file if the report
+// doesn't contain its source.
+func Load(rdr io.Reader) (Report, error) {
+	var doc document
+
+	if err := json.NewDecoder(rdr).Decode(&doc); err != nil {
+		return Report{}, fmt.Errorf("mutation: %w", err)
+	}
+
+	if doc.Files == nil {
+		return Report{}, errors.New("mutation: the report doesn't have any files")
+	}
+
+	var report Report
+
+	if doc.Thresholds != nil {
+		report.Thresholds = *doc.Thresholds
+	}
+
+	for file, f := range doc.Files {
+		ns := report.namespace(namespaceOf(file, f.Source))
+
+		for _, m := range f.Mutants {
+			if err := ns.add(m.Status); err != nil {
+				return Report{}, fmt.Errorf("mutation: %s: %w", file, err)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Merge returns a single Report containing the results of all the reports (e.g. of the projects of a solution).
+// The counts of the namespaces with the same name are added, and the thresholds of the first report which has them are
+// kept.
+func Merge(reports ...Report) Report {
+	var res Report
+
+	for _, r := range reports {
+		if res.Thresholds == (Thresholds{}) {
+			res.Thresholds = r.Thresholds
+		}
+
+		for _, n := range r.Namespaces {
+			ns := res.namespace(n.Name)
+			ns.Counts = ns.Counts.sum(n.Counts)
+		}
+	}
+
+	return res
+}
+
+// Counts returns the number of mutants of each status, in all the namespaces of r.
+func (r Report) Counts() Counts {
+	var res Counts
+
+	for _, n := range r.Namespaces {
+		res = res.sum(n.Counts)
+	}
+
+	return res
+}
+
+// Detected returns the number of mutants which were detected by the tests (killed, or timed out).
+func (c Counts) Detected() int {
+	return c.Killed + c.Timeout
+}
+
+// Undetected returns the number of mutants which weren't detected by the tests (survived, or not covered).
+func (c Counts) Undetected() int {
+	return c.Survived + c.NoCoverage
+}
+
+// Score returns the percentage (0 - 100) of the (valid) mutants which were detected by the tests.
+// When there are no valid mutants, the score is 0.
+func (c Counts) Score() float64 {
+	if c.Detected()+c.Undetected() == 0 {
+		return 0
+	}
+
+	return float64(c.Detected()) / float64(c.Detected()+c.Undetected()) * 100
+}
+
+// Returns the sum of c and other.
+func (c Counts) sum(other Counts) Counts {
+	return Counts{
+		Killed:     c.Killed + other.Killed,
+		Timeout:    c.Timeout + other.Timeout,
+		Survived:   c.Survived + other.Survived,
+		NoCoverage: c.NoCoverage + other.NoCoverage,
+		Ignored:    c.Ignored + other.Ignored,
+	}
+}
+
+// Adds a mutant with the given status to c.
+func (c *Counts) add(status string) error {
+	switch status {
+	case "Killed":
+		c.Killed++
+	case "Timeout":
+		c.Timeout++
+	case "Survived":
+		c.Survived++
+	case "NoCoverage":
+		c.NoCoverage++
+	case "Ignored", "Pending", "CompileError", "RuntimeError":
+		c.Ignored++
+	default:
+		return fmt.Errorf("unknown mutant status %q", status)
+	}
+
+	return nil
+}
+
+// Returns the namespace of r with the given name, which is added (keeping the namespaces ordered) if r doesn't
+// contain it yet.
+func (r *Report) namespace(name string) *Namespace {
+	idx, found := slices.BinarySearchFunc(r.Namespaces, name, func(n Namespace, name string) int {
+		return cmp.Compare(n.Name, name)
+	})
+
+	if !found {
+		r.Namespaces = slices.Insert(r.Namespaces, idx, Namespace{Name: name})
+	}
+
+	return &r.Namespaces[idx]
+}
+
+// Returns the namespace of the source file at file, with the given source.
+func namespaceOf(file, source string) string {
+	if m := namespaceDecl.FindStringSubmatch(source); m != nil {
+		return m[1]
+	}
+
+	return path.Dir(strings.ReplaceAll(file, `\`, "/"))
+}
@@ -0,0 +1,163 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "mutation" package.
+package mutation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+)
+
+// UT: Load a JSON report of Stryker.NET.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name     string
+		jsonData string
+		want     mutation.Report
+		wantErr  bool
+	}{
+		{
+			name: "A report with the source of its files",
+			jsonData: `{
+  "schemaVersion": "1",
+  "thresholds": { "high": 80, "low": 60 },
+  "files": {
+    "src/App/Calculator.cs": {
+      "language": "cs",
+      "source": "using System;\n\nnamespace App.Math\n{\n}\n",
+      "mutants": [
+        { "id": "1", "mutatorName": "Arithmetic", "status": "Killed" },
+        { "id": "2", "mutatorName": "Arithmetic", "status": "Survived" },
+        { "id": "3", "mutatorName": "Equality", "status": "Timeout" }
+      ]
+    },
+    "src/App/Parser.cs": {
+      "language": "cs",
+      "source": "namespace App.Text;\n",
+      "mutants": [
+        { "id": "4", "status": "NoCoverage" },
+        { "id": "5", "status": "CompileError" }
+      ]
+    },
+    "src/App/Scanner.cs": {
+      "language": "cs",
+      "source": "namespace App.Math { }",
+      "mutants": [{ "id": "6", "status": "Killed" }]
+    }
+  }
+}`,
+			want: mutation.Report{
+				Namespaces: []mutation.Namespace{
+					{Name: "App.Math", Counts: mutation.Counts{Killed: 2, Timeout: 1, Survived: 1}},
+					{Name: "App.Text", Counts: mutation.Counts{NoCoverage: 1, Ignored: 1}},
+				},
+				Thresholds: mutation.Thresholds{High: 80, Low: 60},
+			},
+		},
+		{
+			name:     "A report without the source of its files",
+			jsonData: `{"files": {"src\\Util\\Guard.cs": {"mutants": [{"status": "Killed"}]}}}`,
+			want:     mutation.Report{Namespaces: []mutation.Namespace{{Name: "src/Util", Counts: mutation.Counts{Killed: 1}}}},
+		},
+		{
+			name:     "A report with an unknown status",
+			jsonData: `{"files": {"A.cs": {"mutants": [{"status": "Maybe"}]}}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "A document which isn't a report",
+			jsonData: `{"Benchmarks": []}`,
+			wantErr:  true,
+		},
+	} {
+		// ACT.
+		got, err := mutation.Load(strings.NewReader(tc.jsonData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load a JSON report of Stryker.NET.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a JSON report of Stryker.NET.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Merge the reports of multiple projects, and calculate their mutation score.
+func TestMerge(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	first := mutation.Report{Namespaces: []mutation.Namespace{
+		{Name: "App.Math", Counts: mutation.Counts{Killed: 2, Survived: 1}},
+	}}
+	second := mutation.Report{
+		Namespaces: []mutation.Namespace{
+			{Name: "App.Core", Counts: mutation.Counts{NoCoverage: 1, Ignored: 3}},
+			{Name: "App.Math", Counts: mutation.Counts{Timeout: 1}},
+		},
+		Thresholds: mutation.Thresholds{High: 80, Low: 60},
+	}
+
+	// ACT.
+	got := mutation.Merge(first, second)
+
+	// ASSERT.
+	want := mutation.Report{
+		Namespaces: []mutation.Namespace{
+			{Name: "App.Core", Counts: mutation.Counts{NoCoverage: 1, Ignored: 3}},
+			{Name: "App.Math", Counts: mutation.Counts{Killed: 2, Timeout: 1, Survived: 1}},
+		},
+		Thresholds: mutation.Thresholds{High: 80, Low: 60},
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Merge the reports of multiple projects.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+
+	scores := []float64{got.Namespaces[0].Score(), got.Namespaces[1].Score(), got.Counts().Score()}
+	wantScores := []float64{0, 75, 60}
+
+	assert.DeepEqual(t, scores, wantScores, "", "\n\n"+
+		"UT Name:    Calculate the mutation score of the merged reports.\n"+
+		"\033[32mExpected:   Scores %v\033[0m\n"+
+		"\033[31mActual:     Scores %v\033[0m\n\n", wantScores, scores)
+}
@@ -0,0 +1,460 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package otlp exports .NET test result(s) as OpenTelemetry traces, with the OTLP/HTTP protocol (in its JSON
+// encoding), so test executions show up in tracing backends (e.g. Jaeger or Tempo).
+//
+// Each test run is a trace, which consists of a span for the run, a span for each of its assemblies, a span for each
+// group (e.g. trait or class) of tests and a span for each test. The result files don't record when each test
+// started, so the spans of the tests of an assembly are laid out one after the other, starting when the assembly
+// started.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The kind of the spans (SPAN_KIND_INTERNAL).
+const spanKindInternal = 1
+
+// The codes of the status of a span.
+const (
+	statusUnset = 0 // STATUS_CODE_UNSET: the test was skipped (or not run).
+	statusOK    = 1 // STATUS_CODE_OK: the test passed.
+	statusError = 2 // STATUS_CODE_ERROR: the test failed.
+)
+
+// ErrInvalidParent is returned when the parent of a trace isn't a valid W3C traceparent header.
+var ErrInvalidParent = errors.New("otlp: invalid traceparent")
+
+// Options control the traces of test runs.
+type Options struct {
+	ServiceName string    // The name of the service the spans belong to ("dtvisual" if empty).
+	Start       time.Time // The time the test run started.
+	Parent      string    // The W3C traceparent of the span the run belongs to (e.g. a CI job), empty if none.
+	IDs         io.Reader // The source of the (random) trace and span IDs (crypto/rand.Reader if nil).
+}
+
+// Error is returned when the collector responds with an error.
+type Error struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The body of the response.
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return fmt.Sprintf("otlp: %s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// The representation of an ExportTraceServiceRequest.
+type tracesJSON struct {
+	ResourceSpans []resourceSpansJSON `json:"resourceSpans"`
+}
+
+// The representation of the spans of a resource.
+type resourceSpansJSON struct {
+	Resource   resourceJSON     `json:"resource"`
+	ScopeSpans []scopeSpansJSON `json:"scopeSpans"`
+}
+
+// The representation of a resource.
+type resourceJSON struct {
+	Attributes []attributeJSON `json:"attributes"`
+}
+
+// The representation of the spans of an instrumentation scope.
+type scopeSpansJSON struct {
+	Scope scopeJSON  `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+// The representation of an instrumentation scope.
+type scopeJSON struct {
+	Name string `json:"name"`
+}
+
+// The representation of a span.
+type spanJSON struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []attributeJSON `json:"attributes,omitempty"`
+	Status            statusJSON      `json:"status"`
+
+	start, end time.Time // The (typed) start and end of the span.
+}
+
+// The representation of an attribute.
+type attributeJSON struct {
+	Key   string    `json:"key"`
+	Value valueJSON `json:"value"`
+}
+
+// The representation of the value of an attribute.
+type valueJSON struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"` // 64-bit integers are encoded as strings.
+}
+
+// The representation of the status of a span.
+type statusJSON struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Encode writes the trace of testRun to w, as an OTLP/JSON ExportTraceServiceRequest.
+func Encode(w io.Writer, testRun xunit.TestRun, opts Options) error {
+	traces, err := newTracesJSON(testRun, opts)
+
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(traces)
+}
+
+// Exporter exports test runs to an OpenTelemetry collector.
+type Exporter struct {
+	url        string            // The URL the traces are sent to.
+	headers    map[string]string // The headers sent with each request (e.g. an API key).
+	httpClient *http.Client      // The client which sends the requests.
+}
+
+// NewExporter returns an exporter which sends the traces to the collector at endpoint (e.g.
+// "http://localhost:4318"), with the given headers, using httpClient (or http.DefaultClient if it's nil).
+func NewExporter(endpoint string, headers map[string]string, httpClient *http.Client) *Exporter {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Exporter{url: strings.TrimSuffix(endpoint, "/") + "/v1/traces", headers: headers, httpClient: httpClient}
+}
+
+// Export sends the trace of testRun to the collector.
+func (e *Exporter) Export(ctx context.Context, testRun xunit.TestRun, opts Options) error {
+	var body bytes.Buffer
+
+	if err := Encode(&body, testRun, opts); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, &body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+
+	return nil
+}
+
+// The builder of the spans of a trace.
+type traceBuilder struct {
+	ids     io.Reader   // The source of the span IDs.
+	traceID string      // The ID of the trace.
+	spans   []*spanJSON // The spans of the trace.
+}
+
+// Returns the representation of the trace of testRun.
+func newTracesJSON(testRun xunit.TestRun, opts Options) (tracesJSON, error) {
+	b := &traceBuilder{ids: opts.IDs}
+
+	if b.ids == nil {
+		b.ids = rand.Reader
+	}
+
+	var parentID string
+	var err error
+
+	if opts.Parent != "" {
+		if b.traceID, parentID, err = parseParent(opts.Parent); err != nil {
+			return tracesJSON{}, err
+		}
+	} else if b.traceID, err = b.newID(16); err != nil {
+		return tracesJSON{}, err
+	}
+
+	stats := testRun.Stats()
+	root, err := b.span(parentID, "test run", opts.Start, 0, stringAttr("host.name", testRun.Computer),
+		stringAttr("enduser.id", testRun.User), intAttr("test.count", stats.TotalCount),
+		intAttr("test.failed", stats.FailedCount))
+
+	if err != nil {
+		return tracesJSON{}, err
+	}
+
+	root.Status.Code = statusOK
+
+	if stats.FailedCount > 0 || stats.ErrorCount > 0 {
+		root.Status = statusJSON{Code: statusError, Message: fmt.Sprintf("%d test(s) failed", stats.FailedCount)}
+	}
+
+	cursor := opts.Start
+
+	for _, a := range testRun.Assemblies {
+		if cursor, err = b.assembly(root, a, cursor); err != nil {
+			return tracesJSON{}, err
+		}
+
+		root.end = cursor
+	}
+
+	serviceName := opts.ServiceName
+
+	if serviceName == "" {
+		serviceName = "dtvisual"
+	}
+
+	spans := make([]spanJSON, 0, len(b.spans))
+
+	for _, span := range b.spans {
+		span.StartTimeUnixNano = strconv.FormatInt(span.start.UnixNano(), 10)
+		span.EndTimeUnixNano = strconv.FormatInt(span.end.UnixNano(), 10)
+		spans = append(spans, *span)
+	}
+
+	return tracesJSON{ResourceSpans: []resourceSpansJSON{{
+		Resource:   resourceJSON{Attributes: []attributeJSON{stringAttr("service.name", serviceName)}},
+		ScopeSpans: []scopeSpansJSON{{Scope: scopeJSON{Name: "dtvisual"}, Spans: spans}},
+	}}}, nil
+}
+
+// Adds the spans of assembly a (which starts at start) to b, as children of parent, and returns the time a ended.
+func (b *traceBuilder) assembly(parent *spanJSON, a xunit.Assembly, start time.Time) (time.Time, error) {
+	span, err := b.span(parent.SpanID, a.Name, start, a.Duration, stringAttr("test.assembly", a.Name),
+		intAttr("test.count", a.TotalCount), intAttr("test.failed", a.FailedCount))
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	span.Status.Code = statusOK
+
+	if a.FailedCount > 0 || a.ErrorCount > 0 {
+		span.Status = statusJSON{Code: statusError, Message: fmt.Sprintf("%d test(s) failed", a.FailedCount)}
+	}
+
+	groups := make(map[string]*spanJSON)
+	cursor := start
+
+	a.Walk(func(path []string, tc xunit.TestCase) {
+		if err != nil {
+			return
+		}
+
+		testParent := span
+
+		for idx := range path {
+			key := strings.Join(path[:idx+1], "\x00")
+			group, ok := groups[key]
+
+			if !ok {
+				if group, err = b.span(testParent.SpanID, path[idx], cursor, 0); err != nil {
+					return
+				}
+
+				groups[key] = group
+			}
+
+			group.end = cursor.Add(tc.Duration)
+			testParent = group
+		}
+
+		var test *spanJSON
+
+		if test, err = b.span(testParent.SpanID, tc.Name, cursor, tc.Duration, testAttrs(a, path, tc)...); err != nil {
+			return
+		}
+
+		switch {
+		case tc.Result == "Pass":
+			test.Status.Code = statusOK
+		case tc.Result == "Fail":
+			test.Status = statusJSON{Code: statusError, Message: tc.Failure.Message}
+		}
+
+		cursor = test.end
+	})
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	span.end = maxTime(span.end, cursor)
+
+	return span.end, nil
+}
+
+// Returns the attributes of the span of test tc, in the given path of assembly a. The traits of the test are joined
+// with a comma (since the path only contains one of them, and only if the tests are grouped by trait).
+func testAttrs(a xunit.Assembly, path []string, tc xunit.TestCase) []attributeJSON {
+	attrs := []attributeJSON{
+		stringAttr("test.assembly", a.Name),
+		stringAttr("test.name", tc.Name),
+		stringAttr("test.result", tc.Result),
+		stringAttr("test.id", tc.ID),
+		stringAttr("code.filepath", tc.SourceFile),
+		stringAttr("test.skip_reason", tc.Reason),
+		stringAttr("exception.type", tc.Failure.ExceptionType),
+		stringAttr("exception.message", tc.Failure.Message),
+		stringAttr("exception.stacktrace", tc.Failure.StackTrace),
+	}
+
+	traits := make([]string, 0, len(tc.Traits))
+
+	for _, trait := range tc.Traits {
+		traits = append(traits, trait.String())
+	}
+
+	attrs = append(attrs, stringAttr("test.trait", strings.Join(traits, ", ")))
+
+	if len(path) > 0 {
+		attrs = append(attrs, stringAttr("test.path", strings.Join(path, " › ")))
+	}
+
+	if tc.SourceLine != 0 {
+		attrs = append(attrs, intAttr("code.lineno", tc.SourceLine))
+	}
+
+	return attrs
+}
+
+// Adds a new span to b, with the given parent (empty for the root span), name, start and duration, and returns it.
+// Attributes with an empty value are left out.
+func (b *traceBuilder) span(parentID, name string, start time.Time, d time.Duration, attrs ...attributeJSON) (
+	*spanJSON, error,
+) {
+	id, err := b.newID(8)
+
+	if err != nil {
+		return nil, err
+	}
+
+	span := &spanJSON{
+		TraceID:      b.traceID,
+		SpanID:       id,
+		ParentSpanID: parentID,
+		Name:         name,
+		Kind:         spanKindInternal,
+		start:        start,
+		end:          start.Add(d),
+	}
+
+	for _, attr := range attrs {
+		if attr.Value.StringValue == nil || *attr.Value.StringValue != "" {
+			span.Attributes = append(span.Attributes, attr)
+		}
+	}
+
+	b.spans = append(b.spans, span)
+
+	return span, nil
+}
+
+// Returns a new (random) ID of n bytes, hex-encoded.
+func (b *traceBuilder) newID(n int) (string, error) {
+	id := make([]byte, n)
+
+	if _, err := io.ReadFull(b.ids, id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// Returns the trace ID and the span ID of the W3C traceparent header value (e.g.
+// "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01").
+func parseParent(value string) (string, string, error) {
+	parts := strings.Split(value, "-")
+
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", ErrInvalidParent
+	}
+
+	for _, part := range parts[1:3] {
+		if _, err := hex.DecodeString(part); err != nil || strings.Trim(part, "0") == "" {
+			return "", "", ErrInvalidParent
+		}
+	}
+
+	return strings.ToLower(parts[1]), strings.ToLower(parts[2]), nil
+}
+
+// Returns a string attribute.
+func stringAttr(key, value string) attributeJSON {
+	return attributeJSON{Key: key, Value: valueJSON{StringValue: &value}}
+}
+
+// Returns an integer attribute.
+func intAttr(key string, value int) attributeJSON {
+	s := strconv.Itoa(value)
+
+	return attributeJSON{Key: key, Value: valueJSON{IntValue: &s}}
+}
+
+// Returns the latest of a and b.
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
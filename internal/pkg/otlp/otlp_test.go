@@ -0,0 +1,335 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "otlp" package.
+package otlp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/otlp"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The trace of a test run, as decoded from OTLP/JSON.
+type traces struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []span `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+// A span, as decoded from OTLP/JSON.
+type span struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Start        string `json:"startTimeUnixNano"`
+	End          string `json:"endTimeUnixNano"`
+	Attributes   []struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	} `json:"attributes"`
+	Status struct {
+		Code int `json:"code"`
+	} `json:"status"`
+}
+
+// A reader which returns the bytes 1, 2, 3, ... (so the IDs are predictable).
+type counter struct {
+	n byte // The last byte which has been returned.
+}
+
+// Read fills p with the next bytes.
+func (c *counter) Read(p []byte) (int, error) {
+	for idx := range p {
+		c.n++
+		p[idx] = c.n
+	}
+
+	return len(p), nil
+}
+
+// Returns a test run with a test (with a trait) which passes, and a test which fails.
+func newTestRun() xunit.TestRun {
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies computer=\"WIN11\">\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Passes\" result=\"Pass\" time=\"0.5\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"Fails\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"AssertException\"><message>Expected: 1</message></failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+
+	return testRun
+}
+
+// Returns the spans of the trace of testRun, encoded with opts.
+func encode(t *testing.T, testRun xunit.TestRun, opts otlp.Options) []span {
+	var buf bytes.Buffer
+
+	if err := otlp.Encode(&buf, testRun, opts); err != nil {
+		t.Fatalf("Encode() = %v, want <nil>", err)
+	}
+
+	var got traces
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal() = %v, want <nil>", err)
+	}
+
+	return got.ResourceSpans[0].ScopeSpans[0].Spans
+}
+
+// Returns a description of spans: the name, the parent, the status, the time range (relative to start) and the
+// attributes of each span.
+func describe(spans []span, start time.Time) []string {
+	names := map[string]string{"": "-"}
+
+	for _, s := range spans {
+		names[s.SpanID] = s.Name
+	}
+
+	resultSet := make([]string, 0, len(spans))
+
+	for _, s := range spans {
+		var startNs, endNs int64
+
+		fmt.Sscan(s.Start, &startNs)
+		fmt.Sscan(s.End, &endNs)
+
+		desc := fmt.Sprintf("%s < %s (%d) %v-%v", s.Name, names[s.ParentSpanID], s.Status.Code,
+			time.Unix(0, startNs).Sub(start), time.Unix(0, endNs).Sub(start))
+
+		for _, attr := range s.Attributes {
+			if attr.Value.StringValue != "" {
+				desc += " " + attr.Key + "=" + attr.Value.StringValue
+			}
+		}
+
+		resultSet = append(resultSet, desc)
+	}
+
+	return resultSet
+}
+
+// UT: Encode a test run as an OTLP trace.
+func TestEncode(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	start := time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC)
+
+	// ACT.
+	spans := encode(t, newTestRun(), otlp.Options{Start: start, IDs: &counter{}})
+
+	// ASSERT.
+	got := describe(spans, start)
+	want := []string{
+		"test run < - (2) 0s-1.5s host.name=WIN11",
+		"App.dll < test run (2) 0s-1.5s test.assembly=App.dll",
+		"Fails < App.dll (2) 0s-1s test.assembly=App.dll test.name=Fails test.result=Fail " +
+			"exception.type=AssertException exception.message=Expected: 1",
+		"Category - Unit < App.dll (0) 1s-1.5s",
+		"Passes < Category - Unit (1) 1s-1.5s test.assembly=App.dll test.name=Passes test.result=Pass " +
+			"test.trait=Category - Unit test.path=Category - Unit",
+	}
+
+	assert.EqualFn(t, got, want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Encode a test run as an OTLP trace.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+
+	assert.Equal(t, spans[0].TraceID, "0102030405060708090a0b0c0d0e0f10", "", "\n\n"+
+		"UT Name:    Encode a test run as an OTLP trace.\n"+
+		"\033[32mExpected:   Trace ID 0102030405060708090a0b0c0d0e0f10\033[0m\n"+
+		"\033[31mActual:     Trace ID %s\033[0m\n\n", spans[0].TraceID)
+}
+
+// UT: Encode a test run whose tests aren't grouped by trait as an OTLP trace.
+func TestEncode_GroupBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	start := time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC)
+	testRun, _ := xunit.LoadWithOptions(strings.NewReader("<assemblies computer=\"WIN11\">\n"+
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"2\" time=\"1\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"Passes\" result=\"Pass\" time=\"0.5\">\n"+
+		"        <traits><trait name=\"Category\" value=\"Unit\" /><trait name=\"Timing\" value=\"Slow\" /></traits>\n"+
+		"      </test>\n"+
+		"      <test name=\"Also passes\" result=\"Pass\" time=\"0.5\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>"), xunit.Options{GroupBy: xunit.GroupByResult})
+
+	// ACT.
+	spans := encode(t, testRun, otlp.Options{Start: start, IDs: &counter{}})
+
+	// ASSERT.
+	got := describe(spans, start)
+	want := []string{
+		"test run < - (1) 0s-1s host.name=WIN11",
+		"App.dll < test run (1) 0s-1s test.assembly=App.dll",
+		"Pass < App.dll (0) 0s-1s",
+		"Passes < Pass (1) 0s-500ms test.assembly=App.dll test.name=Passes test.result=Pass " +
+			"test.trait=Category - Unit, Timing - Slow test.path=Pass",
+		"Also passes < Pass (1) 500ms-1s test.assembly=App.dll test.name=Also passes test.result=Pass test.path=Pass",
+	}
+
+	assert.EqualFn(t, got, want, func(got, want []string) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Encode a test run whose tests aren't grouped by trait as an OTLP trace.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Encode a test run as part of an existing trace (e.g. of a CI job).
+func TestEncode_Parent(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		parent      string
+		wantTraceID string
+		wantParent  string
+		wantErr     error
+	}{
+		{
+			parent:      "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			wantTraceID: "0af7651916cd43dd8448eb211c80319c",
+			wantParent:  "b7ad6b7169203331",
+		},
+		{
+			parent:  "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+			wantErr: otlp.ErrInvalidParent,
+		},
+		{
+			parent:  "invalid",
+			wantErr: otlp.ErrInvalidParent,
+		},
+	} {
+		// ACT.
+		var buf bytes.Buffer
+		err := otlp.Encode(&buf, newTestRun(), otlp.Options{Parent: tc.parent})
+
+		// ASSERT.
+		assert.Equal(t, errors.Is(err, tc.wantErr), true, "", "\n\n"+
+			"UT Name:    Encode a test run as part of an existing trace (e.g. of a CI job).\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error %v\033[0m\n"+
+			"\033[31mActual:     Error %v\033[0m\n\n", tc.parent, tc.wantErr, err)
+
+		if tc.wantErr != nil {
+			continue
+		}
+
+		var got traces
+
+		json.Unmarshal(buf.Bytes(), &got)
+		root := got.ResourceSpans[0].ScopeSpans[0].Spans[0]
+
+		assert.Equal(t, root.TraceID+" "+root.ParentSpanID, tc.wantTraceID+" "+tc.wantParent, "", "\n\n"+
+			"UT Name:    Encode a test run as part of an existing trace (e.g. of a CI job).\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %s %s\033[0m\n"+
+			"\033[31mActual:     %s %s\033[0m\n\n", tc.parent, tc.wantTraceID, tc.wantParent, root.TraceID,
+			root.ParentSpanID)
+	}
+}
+
+// UT: Export a test run to an OpenTelemetry collector.
+func TestExporter_Export(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		code    int
+		wantErr string
+	}{
+		{code: http.StatusOK},
+		{code: http.StatusUnauthorized, wantErr: "otlp: invalid API key (401 Unauthorized)"},
+	} {
+		// ARRANGE.
+		var target, apiKey string
+		var body traces
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target, apiKey = r.URL.Path, r.Header.Get("X-Api-Key")
+			json.NewDecoder(r.Body).Decode(&body)
+
+			w.WriteHeader(tc.code)
+			io.WriteString(w, "invalid API key\n")
+		}))
+
+		exporter := otlp.NewExporter(srv.URL+"/", map[string]string{"X-Api-Key": "s3cr3t"}, srv.Client())
+
+		// ACT.
+		err := exporter.Export(context.Background(), newTestRun(), otlp.Options{})
+		srv.Close()
+
+		// ASSERT.
+		gotErr := ""
+
+		if err != nil {
+			gotErr = err.Error()
+		}
+
+		assert.Equal(t, gotErr, tc.wantErr, "", "\n\n"+
+			"UT Name:    Export a test run to an OpenTelemetry collector.\n"+
+			"Input:      Status code %d\n"+
+			"\033[32mExpected:   Error %q\033[0m\n"+
+			"\033[31mActual:     Error %q\033[0m\n\n", tc.code, tc.wantErr, gotErr)
+
+		got := target == "/v1/traces" && apiKey == "s3cr3t" && len(body.ResourceSpans[0].ScopeSpans[0].Spans) == 5
+
+		assert.Equal(t, got, true, "", "\n\n"+
+			"UT Name:    Export a test run to an OpenTelemetry collector.\n"+
+			"Input:      Status code %d\n"+
+			"\033[32mExpected:   The trace (5 spans), sent to /v1/traces with the API key\033[0m\n"+
+			"\033[31mActual:     %+v, sent to %s with the API key %q\033[0m\n\n", tc.code, body, target, apiKey)
+	}
+}
@@ -0,0 +1,188 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package custom contains functions for rendering .NET test result(s) with a user-supplied Go template, so that
+// bespoke reports can be produced without changing the renderers.
+// Templates for HTML (with a name ending with ".html", ".htm" or ".gohtml", optionally followed by another extension
+// such as ".tmpl") are parsed as an html/template, which escapes the data. Other templates are parsed as a
+// text/template.
+// More information regarding the syntax of the templates can be found @ https://pkg.go.dev/text/template.
+package custom
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/benchmark"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The extensions of the names of the templates which are parsed as an html/template.
+var htmlExts = []string{".html", ".htm", ".gohtml"}
+
+// The functions which are available in the templates.
+var funcs = map[string]any{
+	"tests":    tests,
+	"failed":   failed,
+	"duration": fmtDuration,
+	"percent":  fmtPercent,
+	"join":     strings.Join,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"json":     toJSON,
+}
+
+// Data is the data model which is passed to a template.
+type Data struct {
+	Title      string                 // The title of the report (empty if it isn't set).
+	Run        xunit.TestRun          // The test run.
+	Stats      xunit.Stats            // The statistics of the test run.
+	Coverage   coverage.Report        // The code coverage of the test run (without assemblies if it isn't known).
+	Benchmarks []benchmark.Comparison // The benchmarks, compared to the baseline run (if known).
+	Mutations  mutation.Report        // The mutation score of each namespace (without namespaces if it isn't known).
+}
+
+// Template is a parsed user-supplied template.
+type Template struct {
+	execute func(w io.Writer, data any) error // Executes the parsed template.
+}
+
+// ParseFile reads the template at path, and parses it (see Parse).
+func ParseFile(path string) (*Template, error) {
+	text, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(filepath.Base(path), string(text))
+}
+
+// Parse parses text as the template with the given name, as an html/template or a text/template depending on the
+// extensions of name.
+// Next to the functions of the template packages, the templates can use the following functions:
+//
+//   - tests: Returns all the tests of a test run (as analysis.LocatedTest), in the order in which they appear.
+//   - failed: Returns the failed tests of a test run (as analysis.LocatedTest), in the order in which they appear.
+//   - duration: Returns a duration in a human-readable format, rounded to milliseconds.
+//   - percent: Returns a percentage (0 - 100) with 2 decimals (e.g. "75.00%").
+//   - join, lower and upper: The functions of the same name of the strings package.
+//   - json: Returns a value in the JSON format.
+func Parse(name, text string) (*Template, error) {
+	if isHTML(name) {
+		t, err := htmltemplate.New(name).Funcs(funcs).Parse(text)
+
+		if err != nil {
+			return nil, fmt.Errorf("custom: %w", err)
+		}
+
+		return &Template{execute: t.Execute}, nil
+	}
+
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+
+	if err != nil {
+		return nil, fmt.Errorf("custom: %w", err)
+	}
+
+	return &Template{execute: t.Execute}, nil
+}
+
+// Execute writes the result of applying t to data to w.
+func (t *Template) Execute(w io.Writer, data Data) error {
+	if err := t.execute(w, data); err != nil {
+		return fmt.Errorf("custom: %w", err)
+	}
+
+	return nil
+}
+
+// Returns true if the template with the given name is an HTML template.
+func isHTML(name string) bool {
+	for ext := filepath.Ext(name); ext != ""; ext = filepath.Ext(name) {
+		for _, htmlExt := range htmlExts {
+			if strings.EqualFold(ext, htmlExt) {
+				return true
+			}
+		}
+
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	return false
+}
+
+// Returns all the tests of testRun, in the order in which they appear.
+func tests(testRun xunit.TestRun) []analysis.LocatedTest {
+	resultSet := make([]analysis.LocatedTest, 0)
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			resultSet = append(resultSet, analysis.LocatedTest{Assembly: assembly.Name, Path: path, Test: tc})
+		})
+	}
+
+	return resultSet
+}
+
+// Returns the failed tests of testRun, in the order in which they appear.
+func failed(testRun xunit.TestRun) []analysis.LocatedTest {
+	resultSet := make([]analysis.LocatedTest, 0)
+
+	for _, t := range tests(testRun) {
+		if t.Test.Result == "Fail" {
+			resultSet = append(resultSet, t)
+		}
+	}
+
+	return resultSet
+}
+
+// Returns d in a human-readable format, rounded to milliseconds.
+func fmtDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// Returns p (a percentage from 0 to 100) with 2 decimals.
+func fmtPercent(p float64) string {
+	return fmt.Sprintf("%.2f%%", p)
+}
+
+// Returns v in the JSON format.
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+
+	return string(data), err
+}
@@ -0,0 +1,121 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "custom" package.
+package custom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/custom"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The XML data used by the tests in this file.
+const xmlData = "<assemblies>\n" +
+	"  <assembly name=\"~/App.dll\" passed=\"1\" failed=\"1\" total=\"2\" time=\"1.5\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"NS.Calc+Math.Adds\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"A &lt;failing&gt; test.\" result=\"Fail\" time=\"1\">\n" +
+	"        <failure>\n" +
+	"          <message>Expected: 1</message>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// UT: Render a test run with a user-supplied template.
+func TestTemplate_Execute(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name, text string
+		want       string
+	}{
+		{
+			name: "summary.txt",
+			text: "{{.Title}}: {{.Stats.PassedCount}}/{{.Stats.TotalCount}} ({{percent .Stats.PassRate}}) in " +
+				"{{duration .Stats.TotalDuration}}",
+			want: "Nightly: 1/2 (50.00%) in 1.5s",
+		},
+		{
+			name: "tests.md",
+			text: "{{range tests .Run}}- {{upper .Assembly}} › {{join .Path \" › \"}}{{if .Path}} › {{end}}" +
+				"{{.Test.Name}}: {{.Test.Result}}\n{{end}}",
+			want: "- APP.DLL › A <failing> test.: Fail\n- APP.DLL › Calc › Math › NS.Calc+Math.Adds: Pass\n",
+		},
+		{
+			name: "failures.html.tmpl",
+			text: "<ul>{{range failed .Run}}<li title=\"{{.Test.Failure.Message}}\">{{.Test.Name}}</li>{{end}}</ul>",
+			want: "<ul><li title=\"Expected: 1\">A &lt;failing&gt; test.</li></ul>",
+		},
+		{
+			name: "failures.txt",
+			text: "{{range failed .Run}}{{.Test.Name}}{{end}}",
+			want: "A <failing> test.",
+		},
+		{
+			name: "stats.json",
+			text: "{{json .Stats.FailedCount}}",
+			want: "1",
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+		tmpl, err := custom.Parse(tc.name, tc.text)
+
+		assert.NoError(t, err, "Parse()")
+
+		var sb strings.Builder
+
+		// ACT.
+		err = tmpl.Execute(&sb, custom.Data{Title: "Nightly", Run: testRun, Stats: testRun.Stats()})
+
+		// ASSERT.
+		assert.NoError(t, err, "Execute()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Render a test run with a user-supplied template.\n"+
+			"Input:      %s: %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.text, tc.want, sb.String())
+	}
+}
+
+// UT: Parse an invalid user-supplied template.
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ACT.
+	_, err := custom.Parse("report.tmpl", "{{range .Run.Assemblies}}")
+
+	// ASSERT.
+	assert.Equal(t, err != nil && strings.HasPrefix(err.Error(), "custom: "), true, "", "\n\n"+
+		"UT Name:    Parse an invalid user-supplied template.\n"+
+		"\033[32mExpected:   An error\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+}
@@ -0,0 +1,244 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package github contains functions for rendering .NET test result(s) for GitHub Actions.
+// The job summary is written in GitHub flavored Markdown, and failures are reported as workflow commands, which
+// GitHub turns into annotations.
+// More information regarding workflow commands can be found @
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+package github
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The name of the environment variable which holds the path of the job summary file.
+const stepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// ErrNoStepSummary is returned when the job summary file isn't known (e.g. when running outside of GitHub Actions).
+var ErrNoStepSummary = errors.New("github: " + stepSummaryEnv + " is not set")
+
+// WriteStepSummary appends the job summary of testRun to the file referred to by the `GITHUB_STEP_SUMMARY` environment
+// variable.
+func WriteStepSummary(testRun xunit.TestRun) error {
+	path := os.Getenv(stepSummaryEnv)
+
+	if path == "" {
+		return ErrNoStepSummary
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	if err := Summary(f, testRun); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// Summary writes the job summary of testRun to w.
+// The summary contains a table with the totals of the test run and a collapsible section per assembly. The section of
+// an assembly with failures is expanded by default.
+func Summary(w io.Writer, testRun xunit.TestRun) error {
+	var b strings.Builder
+
+	stats := testRun.Stats()
+
+	b.WriteString("## Test results\n\n")
+	b.WriteString("| Assemblies | Total | Passed | Failed | Skipped | Not run | Errors | Pass rate | Duration |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %d | %d | %d | %.2f%% | %s |\n\n",
+		stats.AssemblyCount, stats.TotalCount, stats.PassedCount, stats.FailedCount, stats.SkippedCount,
+		stats.NotRunCount, stats.ErrorCount, stats.PassRate, fmtDuration(stats.TotalDuration))
+
+	for _, assembly := range testRun.Assemblies {
+		writeAssembly(&b, assembly)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// Annotations writes a workflow command to w for each failed test of testRun, so that GitHub shows the failure as an
+// error annotation (at the location of the test, if known).
+func Annotations(w io.Writer, testRun xunit.TestRun) error {
+	var b strings.Builder
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			if tc.Result != "Fail" {
+				return
+			}
+
+			b.WriteString("::error ")
+
+			if tc.SourceFile != "" {
+				fmt.Fprintf(&b, "file=%s,", escapeProperty(tc.SourceFile))
+
+				if tc.SourceLine > 0 {
+					fmt.Fprintf(&b, "line=%d,", tc.SourceLine)
+				}
+			}
+
+			fmt.Fprintf(&b, "title=%s::%s\n", escapeProperty(assembly.Name+": "+tc.Name), escapeData(failureText(tc)))
+		})
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// Writes the collapsible section of assembly to b.
+func writeAssembly(b *strings.Builder, assembly xunit.Assembly) {
+	if assembly.FailedCount > 0 || assembly.ErrorCount > 0 {
+		b.WriteString("<details open>\n")
+	} else {
+		b.WriteString("<details>\n")
+	}
+
+	fmt.Fprintf(b, "<summary>%s %s: %d passed, %d failed, %d skipped (%s)</summary>\n\n",
+		assemblyIcon(assembly), html.EscapeString(assembly.Name), assembly.PassedCount, assembly.FailedCount,
+		assembly.SkippedCount, fmtDuration(assembly.Duration))
+
+	failures := make([]string, 0)
+
+	b.WriteString("| Result | Test | Duration |\n")
+	b.WriteString("| :---: | :--- | ---: |\n")
+
+	assembly.Walk(func(path []string, tc xunit.TestCase) {
+		name := escape(strings.Join(append(path[:len(path):len(path)], tc.Name), " › "))
+
+		fmt.Fprintf(b, "| %s | %s | %s |\n", resultIcon(tc.Result), name, fmtDuration(tc.Duration))
+
+		if tc.Result == "Fail" {
+			text := failureText(tc)
+			fence := codeFence(text)
+
+			failures = append(failures, fmt.Sprintf("**%s**\n\n%stext\n%s\n%s\n", name, fence, text, fence))
+		}
+	})
+
+	b.WriteString("\n")
+
+	for _, failure := range failures {
+		b.WriteString(failure)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("</details>\n\n")
+}
+
+// Returns the icon representing the status of assembly.
+func assemblyIcon(assembly xunit.Assembly) string {
+	if assembly.FailedCount > 0 || assembly.ErrorCount > 0 {
+		return resultIcon("Fail")
+	}
+
+	return resultIcon("Pass")
+}
+
+// Returns the icon representing result.
+func resultIcon(result string) string {
+	switch result {
+	case "Pass":
+		return "✅"
+	case "Fail":
+		return "❌"
+	case "Skip":
+		return "⏭️"
+	default:
+		return "❔"
+	}
+}
+
+// Returns the fence of a code block containing text, which is longer than the longest run of backticks in text, so
+// the text can't end the code block (e.g. "````" for a text containing "```").
+func codeFence(text string) string {
+	longest, run := 0, 0
+
+	for _, r := range text {
+		if r != '`' {
+			run = 0
+
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// Returns the text describing the failure of tc.
+func failureText(tc xunit.TestCase) string {
+	text := strings.TrimSpace(tc.Failure.Message)
+
+	if text == "" {
+		text = "Test failed."
+	}
+
+	if st := strings.TrimSpace(tc.Failure.StackTrace); st != "" {
+		text += "\n" + st
+	}
+
+	return text
+}
+
+// Returns d in a human-readable format, rounded to milliseconds.
+func fmtDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// Returns s, escaped for use inside a Markdown table cell.
+func escape(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "|", "\\|")
+}
+
+// Returns s, escaped for use as the data of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// Returns s, escaped for use as a property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,182 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "github" package.
+package github_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/github"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The XML data used by the tests in this file.
+const xmlData = "<assemblies>\n" +
+	"  <assembly name=\"~/App.dll\" passed=\"1\" failed=\"1\" total=\"2\" time=\"1.5\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A | B\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"NS.TestClass+Method.Result\" result=\"Fail\" time=\"1\" source-file=\"Tests.cs\" source-line=\"7\">\n" +
+	"        <failure>\n" +
+	"          <message>Expected: 1, Actual: 2</message>\n" +
+	"          <stack-trace>at Result()</stack-trace>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"  <assembly name=\"~/Other.dll\" passed=\"0\" total=\"0\" />\n" +
+	"</assemblies>"
+
+// The job summary of xmlData.
+const wantSummary = "## Test results\n\n" +
+	"| Assemblies | Total | Passed | Failed | Skipped | Not run | Errors | Pass rate | Duration |\n" +
+	"| ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+	"| 2 | 2 | 1 | 1 | 0 | 0 | 0 | 50.00% | 1.5s |\n\n" +
+	"<details open>\n" +
+	"<summary>❌ App.dll: 1 passed, 1 failed, 0 skipped (1.5s)</summary>\n\n" +
+	"| Result | Test | Duration |\n" +
+	"| :---: | :--- | ---: |\n" +
+	"| ✅ | A \\| B | 500ms |\n" +
+	"| ❌ | TestClass › Method › NS.TestClass+Method.Result | 1s |\n\n" +
+	"**TestClass › Method › NS.TestClass+Method.Result**\n\n" +
+	"```text\nExpected: 1, Actual: 2\nat Result()\n```\n\n" +
+	"</details>\n\n" +
+	"<details>\n" +
+	"<summary>✅ Other.dll: 0 passed, 0 failed, 0 skipped (0s)</summary>\n\n" +
+	"| Result | Test | Duration |\n" +
+	"| :---: | :--- | ---: |\n\n" +
+	"</details>\n\n"
+
+// UT: Write the job summary of a test run.
+func TestSummary(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader(xmlData))
+
+	var sb strings.Builder
+
+	// ACT.
+	err := github.Summary(&sb, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Summary()")
+	assert.Equal(t, sb.String(), wantSummary, "", "\n\n"+
+		"UT Name:    Write the job summary of a test run.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", wantSummary, sb.String())
+}
+
+// UT: Write the job summary of a test run, with a failure containing a code block.
+func TestSummary_Fence(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" failed=\"1\" total=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A failing test.\" result=\"Fail\">\n" +
+		"        <failure><message>Expected:\n```\n1\n```</message></failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	want := "````text\nExpected:\n```\n1\n```\n````\n"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := github.Summary(&sb, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Summary()")
+	assert.Contains(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Write the job summary of a test run, with a failure containing a code block.\n"+
+		"\033[32mExpected:   A summary containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, sb.String())
+}
+
+// UT: Write the annotations of a test run.
+func TestAnnotations(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader(xmlData))
+	want := "::error file=Tests.cs,line=7,title=App.dll%3A NS.TestClass+Method.Result::Expected: 1, Actual: 2%0Aat Result()\n"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := github.Annotations(&sb, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Annotations()")
+	assert.Equal(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Write the annotations of a test run.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, sb.String())
+}
+
+// UT: Append the job summary of a test run to the file referred to by `GITHUB_STEP_SUMMARY`.
+func TestWriteStepSummary(t *testing.T) {
+	for _, tc := range []struct {
+		path    string
+		want    string
+		wantErr error
+	}{
+		{
+			path:    "",
+			wantErr: github.ErrNoStepSummary,
+		},
+		{
+			path: filepath.Join(t.TempDir(), "summary.md"),
+			want: wantSummary,
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+
+		t.Setenv("GITHUB_STEP_SUMMARY", tc.path)
+
+		// ACT.
+		err := github.WriteStepSummary(testRun)
+
+		// ASSERT.
+		assert.Equal(t, err, tc.wantErr, "WriteStepSummary()")
+
+		if tc.path != "" {
+			got, _ := os.ReadFile(tc.path)
+
+			assert.Equal(t, string(got), tc.want, "", "\n\n"+
+				"UT Name:    Append the job summary of a test run to the file referred to by `GITHUB_STEP_SUMMARY`.\n"+
+				"\033[32mExpected:   %s\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.want, string(got))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Filters the tests of the report by name (the search box) and by result (the checkboxes), and reloads the report when
+// the server pushes an update (if the page is live).
+// The tests of large groups are rendered inside a template, which is only added to the page when its group is opened.
+(function () {
+  "use strict";
+
+  var search = document.getElementById("search");
+  var filters = document.querySelectorAll(".toolbar input[type=checkbox]");
+  var stateKey = "dtvisual:filters";
+
+  // Adds the tests of the lazy templates matching selector (inside root) to the page.
+  function expand(root, selector) {
+    root.querySelectorAll(selector).forEach(function (lazy) {
+      lazy.replaceWith(lazy.content);
+    });
+  }
+
+  function apply() {
+    expand(document, "template.lazy");
+
+    var query = search.value.trim().toLowerCase();
+    var shown = {};
+
+    filters.forEach(function (filter) {
+      shown[filter.value] = filter.checked;
+    });
+
+    document.querySelectorAll("li.test").forEach(function (test) {
+      var name = test.querySelector(".name").textContent.toLowerCase();
+
+      test.hidden = shown[test.dataset.result] === false || name.indexOf(query) === -1;
+    });
+
+    document.querySelectorAll("details.assembly, li.group").forEach(function (group) {
+      var visible = group.querySelector("li.test:not([hidden])") !== null;
+      var details = group.tagName === "DETAILS" ? group : group.querySelector("details");
+
+      group.hidden = !visible;
+
+      if (visible && query !== "") {
+        details.open = true;
+      }
+    });
+  }
+
+  // Reloads the page, keeping the search and the filters.
+  function reload() {
+    if (search) {
+      var state = { query: search.value, hidden: [] };
+
+      filters.forEach(function (filter) {
+        if (!filter.checked) {
+          state.hidden.push(filter.value);
+        }
+      });
+
+      sessionStorage.setItem(stateKey, JSON.stringify(state));
+    }
+
+    location.reload();
+  }
+
+  // Connects to the WebSocket of the server, reconnecting when the connection is lost.
+  function connect(url) {
+    var socket = new WebSocket(url);
+
+    socket.addEventListener("message", reload);
+    socket.addEventListener("close", function () {
+      setTimeout(function () {
+        connect(url);
+      }, 1000);
+    });
+  }
+
+  document.querySelectorAll("li.group > details").forEach(function (details) {
+    details.addEventListener("toggle", function () {
+      if (details.open) {
+        expand(details, ":scope > ul > template.lazy");
+      }
+    });
+  });
+
+  if (search) {
+    var state = JSON.parse(sessionStorage.getItem(stateKey) || "null");
+
+    sessionStorage.removeItem(stateKey);
+
+    if (state) {
+      search.value = state.query;
+
+      filters.forEach(function (filter) {
+        filter.checked = state.hidden.indexOf(filter.value) === -1;
+      });
+
+      apply();
+    }
+
+    search.addEventListener("input", apply);
+
+    filters.forEach(function (filter) {
+      filter.addEventListener("change", apply);
+    });
+  }
+
+  if (document.body.dataset.live) {
+    var url = new URL(document.body.dataset.live, location.href);
+
+    url.protocol = url.protocol === "https:" ? "wss:" : "ws:";
+    connect(url.href);
+  }
+})();
@@ -0,0 +1,705 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package html contains functions for rendering .NET test result(s) as standalone HTML pages.
+// The pages don't depend on any external resource, because the stylesheet, the script and the icon are embedded in
+// each page, unless they're rendered with an AssetsURL (see Assets). Such a self-contained page also forbids loading
+// anything else (through its Content Security Policy), so it can be opened without network access (e.g. from a CI
+// artifact).
+package html
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/benchmark"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The stylesheet of the report.
+//
+//go:embed assets/report.css
+var reportCSS string
+
+// The static assets of the pages.
+//
+//go:embed assets/report.css assets/report.js
+var assets embed.FS
+
+// The script of the interactive report.
+//
+//go:embed assets/report.js
+var reportJS string
+
+// The template of the report.
+//
+//go:embed assets/report.gohtml
+var reportTmpl string
+
+// The template of the overview of multiple test runs.
+//
+//go:embed assets/index.gohtml
+var indexTmpl string
+
+// The template of the dashboard of multiple projects.
+//
+//go:embed assets/dashboard.gohtml
+var dashboardTmpl string
+
+// The number of (most recent) test runs of a project which are shown in its trend.
+const trendLength = 10
+
+// The number of tests above which the tests of a (closed) group of an interactive page are only added to the page
+// when the group is opened.
+const lazyTests = 200
+
+// The relative change of the mean duration of a benchmark, compared to its baseline, which is considered to be noise.
+const benchmarkTolerance = 0.05
+
+// The Content Security Policy of the self-contained pages, which only allows the embedded stylesheet, script and
+// images.
+const selfContainedCSP = "default-src 'none'; style-src 'unsafe-inline'; script-src 'unsafe-inline'; img-src data:"
+
+// The icons of the pages: of the test runs without and with failed tests, and of the other pages.
+var (
+	passIcon    = icon("#2da44e")
+	failIcon    = icon("#cf222e")
+	neutralIcon = icon("#6e7781")
+)
+
+// Themes contains the names of the color themes of the pages. The "auto" theme is light or dark, following the
+// preference of the browser (`prefers-color-scheme`).
+var Themes = []string{"auto", "light", "dark"}
+
+// The characters of a sparkline, from the lowest to the highest value.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// The functions which are available in the templates.
+var funcs = template.FuncMap{
+	"bytes":     fmtBytes,
+	"duration":  fmtDuration,
+	"nanos":     fmtNanos,
+	"localTime": fmtLocalTime,
+	"lower":     strings.ToLower,
+}
+
+// The parsed templates.
+var (
+	tmpl      = template.Must(template.New("report").Funcs(funcs).Parse(reportTmpl))
+	indexPage = template.Must(template.New("index").Funcs(funcs).Parse(indexTmpl))
+	dashboard = template.Must(template.New("dashboard").Funcs(funcs).Parse(dashboardTmpl))
+)
+
+// Options controls how a test run is rendered.
+type Options struct {
+	Title    string // The title of the page (defaults to "Test results").
+	IndexURL string // If not empty, the page contains a link to this URL (e.g. an overview of all the test runs).
+
+	// If true, the page contains a search box and filters, which hide the tests that don't match (using JavaScript).
+	Interactive bool
+
+	// If not empty, the page connects to the WebSocket at this URL, and reloads itself whenever it receives a message
+	// (keeping the search and the filters of an interactive page).
+	LiveURL string
+
+	// If not empty, the page links to the stylesheet and the script at this URL (without a trailing slash), instead of
+	// embedding them. The files at this URL are expected to be served from Assets.
+	AssetsURL string
+
+	// If not empty, the page contains a table with the mean duration, and the allocations, of each benchmark (compared
+	// to the baseline run).
+	Benchmarks []benchmark.Comparison
+
+	// If not nil, returns the URL of the trace file (in a format speedscope understands) of the test with the given
+	// (raw) name, or an empty string if the test doesn't have one. The tests with a trace file link to it: through
+	// speedscope if the URL is absolute (since speedscope fetches the trace file), or directly otherwise. Only http(s)
+	// and relative URLs are linked (e.g. not "javascript:" URLs).
+	TraceURL func(name string) string
+
+	// The URL of speedscope, which opens the trace files (defaults to "https://www.speedscope.app/").
+	SpeedscopeURL string
+
+	// If it contains assemblies, the page contains a table with the line and branch coverage of each assembly, and a
+	// table with the source files which are exercised by failed tests (see analysis.CorrelateCoverage).
+	Coverage coverage.Report
+
+	// If not nil (and the page contains the coverage), the page contains a table with the change of the coverage of
+	// all the source files, and of each source file of which the coverage changed, compared to a baseline run.
+	CoverageDiff *analysis.CoverageDiff
+
+	// If it contains namespaces, the page contains a table with the mutation score of each namespace.
+	Mutations mutation.Report
+
+	// The color theme of the page (one of Themes, defaults to "auto").
+	Theme string
+
+	// If not empty, a stylesheet which is added after the stylesheet of the page, e.g. to override the CSS variables of
+	// the themes (such as --pass, --fail, --text, --background or --link) with the colors of a corporate branding.
+	ThemeCSS string
+}
+
+// IndexEntry is a single test run, as shown in the overview of multiple test runs.
+type IndexEntry struct {
+	Name string        // The name of the test run.
+	URL  string        // The URL of the report of the test run.
+	Run  xunit.TestRun // The test run itself.
+}
+
+// Project is a group of test runs (e.g. of the same repository), as shown on a dashboard.
+type Project struct {
+	Name string          // The name of the project.
+	URL  string          // The URL of the report of the latest test run of the project.
+	Runs []xunit.TestRun // The test runs of the project, ordered from the oldest to the newest (must not be empty).
+}
+
+// The data which is passed to the template of the dashboard.
+type dashboardPage struct {
+	Title     string
+	AssetsURL string
+	CSP       string // The Content Security Policy of the page, if it's self-contained.
+	Theme     string // The color theme of the page (empty to follow the preference of the browser).
+	Icon      template.URL
+	CSS       template.CSS
+	ThemeCSS  template.CSS
+	Projects  []dashboardProject
+}
+
+// A project on the dashboard, including the statistics of its latest test run and its trend.
+type dashboardProject struct {
+	Project
+	Latest    xunit.TestRun
+	Stats     xunit.Stats
+	Change    string    // The change of the pass rate, compared to the previous test run.
+	Trend     []float64 // The pass rates of the most recent test runs, from the oldest to the newest.
+	Sparkline string    // The trend, as a sparkline.
+}
+
+// The data which is passed to the template of the overview.
+type index struct {
+	Title     string
+	AssetsURL string
+	CSP       string // The Content Security Policy of the page, if it's self-contained.
+	Theme     string // The color theme of the page (empty to follow the preference of the browser).
+	Icon      template.URL
+	CSS       template.CSS
+	ThemeCSS  template.CSS
+	Entries   []indexEntry
+}
+
+// An entry in the overview, including its statistics.
+type indexEntry struct {
+	IndexEntry
+	Stats xunit.Stats
+}
+
+// The data which is passed to the template of the report.
+type page struct {
+	Title       string
+	IndexURL    string
+	Interactive bool
+	LiveURL     string
+	AssetsURL   string
+	CSP         string // The Content Security Policy of the page, if it's self-contained.
+	Theme       string // The color theme of the page (empty to follow the preference of the browser).
+	Icon        template.URL
+	CSS         template.CSS
+	ThemeCSS    template.CSS
+	Script      template.JS
+	Run         xunit.TestRun
+	Stats       xunit.Stats
+	Coverage    *coverage.Report
+	Failing     []analysis.CoveredFile // The source files which are exercised by failed tests.
+	Diff        *analysis.CoverageDiff // The change of the coverage, compared to a baseline run.
+	Benchmarks  []benchmarkRow
+	Mutations   []mutationRow
+	MutantTotal mutationRow // The mutation score of all the namespaces.
+}
+
+// A benchmark, as shown in the report.
+type benchmarkRow struct {
+	benchmark.Comparison
+	Change string // "slower" or "faster" if the benchmark changed beyond benchmarkTolerance, compared to its baseline.
+}
+
+// A namespace (or the total) of a mutation report, as shown in the report.
+type mutationRow struct {
+	Name string
+	mutation.Counts
+	Level string // "good" or "bad" if the mutation score is at or above the high, or below the low, threshold.
+}
+
+// An assembly or a group, as shown in the report.
+type group struct {
+	Name     string
+	Duration time.Duration
+	Counts   counts
+}
+
+// The counts of an assembly or a group, per result.
+type counts struct {
+	Passed, Failed, Skipped int
+}
+
+// A reportWriter writes the parts of a report as the tests are traversed, so the report is never held in memory.
+// The first error is kept, and the remaining parts aren't written.
+type reportWriter struct {
+	w          *bufio.Writer
+	err        error
+	traceURL   func(name string) string    // Returns the URL of the trace file of a test (see Options.TraceURL).
+	speedscope string                      // The URL of speedscope.
+	lazy       bool                        // True to render the tests of large (closed) groups inside a lazy template.
+	counts     map[*xunit.TestGroup]counts // The counts of the groups which are already computed.
+}
+
+// Render writes testRun to w as a standalone HTML page.
+// The tests of each assembly are shown as a tree of collapsible groups. Groups containing failed tests are expanded.
+// The page is written while the groups are traversed. On a page with a script, the tests of the large groups without
+// failed tests are only added to the page when their group is expanded.
+func Render(w io.Writer, testRun xunit.TestRun, opts Options) error {
+	p := page{
+		Title:       opts.Title,
+		IndexURL:    opts.IndexURL,
+		Interactive: opts.Interactive,
+		LiveURL:     opts.LiveURL,
+		AssetsURL:   opts.AssetsURL,
+		Theme:       theme(opts),
+		Icon:        passIcon,
+		CSS:         template.CSS(reportCSS),
+		ThemeCSS:    template.CSS(opts.ThemeCSS),
+		Run:         testRun,
+		Stats:       testRun.Stats(),
+	}
+
+	if p.Stats.FailedCount > 0 {
+		p.Icon = failIcon
+	}
+
+	if opts.AssetsURL == "" && opts.LiveURL == "" {
+		p.CSP = selfContainedCSP
+	}
+
+	if p.Title == "" {
+		p.Title = "Test results"
+	}
+
+	if opts.Interactive || opts.LiveURL != "" {
+		p.Script = template.JS(reportJS)
+	}
+
+	if len(opts.Coverage.Assemblies) > 0 {
+		p.Coverage, p.Diff = &opts.Coverage, opts.CoverageDiff
+
+		for _, file := range analysis.CorrelateCoverage(testRun, opts.Coverage) {
+			if file.Failed > 0 {
+				p.Failing = append(p.Failing, file)
+			}
+		}
+	}
+
+	for _, c := range opts.Benchmarks {
+		row := benchmarkRow{Comparison: c}
+
+		switch ratio := c.Ratio(); {
+		case ratio > 1+benchmarkTolerance:
+			row.Change = "slower"
+		case ratio > 0 && ratio < 1-benchmarkTolerance:
+			row.Change = "faster"
+		}
+
+		p.Benchmarks = append(p.Benchmarks, row)
+	}
+
+	for _, ns := range opts.Mutations.Namespaces {
+		p.Mutations = append(p.Mutations, newMutationRow(ns.Name, ns.Counts, opts.Mutations.Thresholds))
+	}
+
+	p.MutantTotal = newMutationRow("Total", opts.Mutations.Counts(), opts.Mutations.Thresholds)
+
+	rw := reportWriter{
+		w:          bufio.NewWriter(w),
+		traceURL:   opts.TraceURL,
+		speedscope: opts.SpeedscopeURL,
+		lazy:       p.Script != "",
+		counts:     make(map[*xunit.TestGroup]counts),
+	}
+
+	if rw.speedscope == "" {
+		rw.speedscope = "https://www.speedscope.app/"
+	}
+
+	rw.execute("header", p)
+
+	for _, a := range testRun.Assemblies {
+		rw.execute("assembly-start", group{Name: a.Name, Duration: a.Duration, Counts: assemblyCounts(a)})
+
+		for _, g := range a.Tests {
+			if g.Name == "" {
+				rw.groupContent(g, false)
+			} else {
+				rw.group(g)
+			}
+		}
+
+		rw.execute("assembly-end", nil)
+	}
+
+	rw.execute("footer", p)
+
+	if rw.err != nil {
+		return rw.err
+	}
+
+	return rw.w.Flush()
+}
+
+// Returns the row of the table of the mutation scores with the given name and counts.
+func newMutationRow(name string, counts mutation.Counts, thresholds mutation.Thresholds) mutationRow {
+	row := mutationRow{Name: name, Counts: counts}
+
+	switch score := counts.Score(); {
+	case counts.Detected()+counts.Undetected() == 0:
+	case thresholds.High > 0 && score >= thresholds.High:
+		row.Level = "good"
+	case score < thresholds.Low:
+		row.Level = "bad"
+	}
+
+	return row
+}
+
+// Returns the value of the data-theme attribute of a page rendered with opts (empty for the "auto" theme).
+func theme(opts Options) string {
+	if opts.Theme == "auto" {
+		return ""
+	}
+
+	return opts.Theme
+}
+
+// Returns the icon of a page (a circle of the given color), as a data URL.
+func icon(color string) template.URL {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16"><circle cx="8" cy="8" r="7" fill="` + color +
+		`"/></svg>`
+
+	return template.URL("data:image/svg+xml," + url.PathEscape(svg))
+}
+
+// Assets returns the static assets of the pages (the files "report.css" and "report.js"), which are linked by the pages
+// rendered with an AssetsURL.
+func Assets() fs.FS {
+	sub, _ := fs.Sub(assets, "assets")
+
+	return sub
+}
+
+// RenderIndex writes an overview of entries to w as a standalone HTML page, in the given order.
+// The overview contains a table with the statistics of each test run, and a link to its report.
+// The IndexURL of opts is ignored.
+func RenderIndex(w io.Writer, entries []IndexEntry, opts Options) error {
+	p := index{Title: opts.Title, AssetsURL: opts.AssetsURL, Theme: theme(opts), Icon: neutralIcon}
+	p.CSS, p.ThemeCSS = template.CSS(reportCSS), template.CSS(opts.ThemeCSS)
+	p.Entries = make([]indexEntry, 0, len(entries))
+
+	if opts.AssetsURL == "" {
+		p.CSP = selfContainedCSP
+	}
+
+	if p.Title == "" {
+		p.Title = "Test runs"
+	}
+
+	for _, entry := range entries {
+		p.Entries = append(p.Entries, indexEntry{IndexEntry: entry, Stats: entry.Run.Stats()})
+	}
+
+	return indexPage.Execute(w, p)
+}
+
+// RenderDashboard writes a dashboard of projects to w as a standalone HTML page, in the given order.
+// The dashboard contains a table with the status and the pass rate of the latest test run of each project, the change
+// of its pass rate compared to the previous test run, and the trend of its pass rate over the most recent test runs.
+// The IndexURL of opts is ignored.
+func RenderDashboard(w io.Writer, projects []Project, opts Options) error {
+	p := dashboardPage{Title: opts.Title, AssetsURL: opts.AssetsURL, Theme: theme(opts), Icon: neutralIcon}
+	p.CSS, p.ThemeCSS = template.CSS(reportCSS), template.CSS(opts.ThemeCSS)
+	p.Projects = make([]dashboardProject, 0, len(projects))
+
+	if opts.AssetsURL == "" {
+		p.CSP = selfContainedCSP
+	}
+
+	if p.Title == "" {
+		p.Title = "Projects"
+	}
+
+	for _, project := range projects {
+		runs := project.Runs[max(0, len(project.Runs)-trendLength):]
+		trend := make([]float64, 0, len(runs))
+
+		for _, run := range runs {
+			trend = append(trend, run.Stats().PassRate)
+		}
+
+		latest := project.Runs[len(project.Runs)-1]
+		entry := dashboardProject{
+			Project:   project,
+			Latest:    latest,
+			Stats:     latest.Stats(),
+			Trend:     trend,
+			Sparkline: sparkline(trend),
+		}
+
+		if len(trend) > 1 {
+			entry.Change = fmt.Sprintf("%+.2f%%", trend[len(trend)-1]-trend[len(trend)-2])
+		}
+
+		p.Projects = append(p.Projects, entry)
+	}
+
+	return dashboard.Execute(w, p)
+}
+
+// Returns values as a sparkline, scaled between the lowest and the highest value.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := slices.Min(values), slices.Max(values)
+	line := make([]rune, 0, len(values))
+
+	for _, v := range values {
+		idx := len(sparks) - 1
+
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+
+		line = append(line, sparks[idx])
+	}
+
+	return string(line)
+}
+
+// Writes the template name, applied to data.
+func (rw *reportWriter) execute(name string, data any) {
+	if rw.err == nil {
+		rw.err = tmpl.ExecuteTemplate(rw.w, name, data)
+	}
+}
+
+// Writes g as a collapsible group.
+func (rw *reportWriter) group(g *xunit.TestGroup) {
+	c := rw.groupCounts(g)
+
+	rw.execute("group-start", group{Name: g.Name, Counts: c})
+	rw.groupContent(g, rw.lazy && c.Failed == 0 && len(g.Tests) > lazyTests)
+	rw.execute("group-end", nil)
+}
+
+// Writes the tests of g (inside a lazy template if lazy is true), followed by its subgroups.
+func (rw *reportWriter) groupContent(g *xunit.TestGroup, lazy bool) {
+	if lazy {
+		rw.execute("lazy-start", nil)
+	}
+
+	for _, tc := range g.Tests {
+		rw.test(tc)
+	}
+
+	if lazy {
+		rw.execute("lazy-end", nil)
+	}
+
+	for _, sGroup := range g.Groups {
+		rw.group(sGroup)
+	}
+}
+
+// Writes tc as an item of the list of tests of its group.
+// NOTE: The tests aren't written by a template, since executing a template per test takes most of the time of
+// rendering a report with many tests.
+func (rw *reportWriter) test(tc xunit.TestCase) {
+	if rw.err != nil {
+		return
+	}
+
+	result := template.HTMLEscapeString(strings.ToLower(tc.Result))
+
+	rw.w.WriteString("\n      <li class=\"test " + result + "\" data-result=\"" + result + "\">")
+	rw.w.WriteString("\n        <span class=\"name\">" + template.HTMLEscapeString(tc.Name))
+	rw.w.WriteString("</span> <span class=\"duration\">" + fmtDuration(tc.Duration) + "</span>")
+
+	if rw.traceURL != nil {
+		if traceURL := rw.traceURL(tc.RawName); traceURL != "" && safeURL(traceURL) {
+			if strings.Contains(traceURL, "://") {
+				traceURL = rw.speedscope + "#profileURL=" + url.QueryEscape(traceURL)
+			}
+
+			rw.w.WriteString(" <a class=\"trace\" href=\"" + template.HTMLEscapeString(traceURL) + "\">profile</a>")
+		}
+	}
+
+	if failure := failureText(tc); failure != "" {
+		rw.w.WriteString("\n        <pre class=\"failure\">" + template.HTMLEscapeString(failure) + "</pre>")
+	}
+
+	rw.w.WriteString("\n      </li>")
+}
+
+// Returns the counts of all the tests in g, including the tests of its subgroups.
+func (rw *reportWriter) groupCounts(g *xunit.TestGroup) counts {
+	if c, ok := rw.counts[g]; ok {
+		return c
+	}
+
+	var c counts
+
+	for _, tc := range g.Tests {
+		c.count(tc.Result)
+	}
+
+	for _, sGroup := range g.Groups {
+		c = c.add(rw.groupCounts(sGroup))
+	}
+
+	rw.counts[g] = c
+
+	return c
+}
+
+// Returns the counts of the tests of a. Unlike the counts of its groups, a test which belongs to multiple groups (e.g.
+// because it has multiple traits) is only counted once.
+func assemblyCounts(a xunit.Assembly) counts {
+	var c counts
+
+	a.Walk(func(_ []string, tc xunit.TestCase) {
+		c.count(tc.Result)
+	})
+
+	return c
+}
+
+// Adds a test with the given result to c.
+func (c *counts) count(result string) {
+	switch result {
+	case "Pass":
+		c.Passed++
+	case "Fail":
+		c.Failed++
+	case "Skip":
+		c.Skipped++
+	}
+}
+
+// Returns the sum of c and other.
+func (c counts) add(other counts) counts {
+	return counts{Passed: c.Passed + other.Passed, Failed: c.Failed + other.Failed, Skipped: c.Skipped + other.Skipped}
+}
+
+// Returns true if href is an http(s) URL or a relative URL (without a host), which are safe to link to, and false
+// otherwise (e.g. for a "javascript:" URL).
+func safeURL(href string) bool {
+	u, err := url.Parse(href)
+
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return true
+	case "":
+		return u.Host == ""
+	default:
+		return false
+	}
+}
+
+// Returns the text describing the failure of tc (or an empty string if tc didn't fail).
+func failureText(tc xunit.TestCase) string {
+	if tc.Result != "Fail" {
+		return ""
+	}
+
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{tc.Failure.ExceptionType, tc.Failure.Message, tc.Failure.StackTrace} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// Returns d in a human-readable format, rounded to milliseconds.
+func fmtDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// Returns ns (a number of nanoseconds) in a human-readable format, with 2 decimals.
+func fmtNanos(ns float64) string {
+	switch {
+	case ns < 1e3:
+		return fmt.Sprintf("%.2f ns", ns)
+	case ns < 1e6:
+		return fmt.Sprintf("%.2f μs", ns/1e3)
+	case ns < 1e9:
+		return fmt.Sprintf("%.2f ms", ns/1e6)
+	default:
+		return fmt.Sprintf("%.2f s", ns/1e9)
+	}
+}
+
+// Returns n (a number of bytes) in a human-readable format, or "-" if n is negative (unknown).
+func fmtBytes(n int64) string {
+	switch {
+	case n < 0:
+		return "-"
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.2f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+	}
+}
+
+// Returns t in the local time zone, in a human-readable format.
+func fmtLocalTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05 MST")
+}
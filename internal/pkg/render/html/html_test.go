@@ -0,0 +1,595 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "html" package.
+package html_test
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/analysis"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/benchmark"
+	"github.com/kdeconinck/dtvisual/internal/pkg/coverage"
+	"github.com/kdeconinck/dtvisual/internal/pkg/mutation"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/html"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit/xunittest"
+)
+
+// UT: Render a test run as an HTML page.
+func TestRender(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	report := "<assemblies computer=\"WIN11\" timestamp=\"07/10/2023 20:53:19\">\n" +
+		"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A &lt;b&gt; test.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Result\" result=\"Fail\">\n" +
+		"        <failure>\n" +
+		"          <message>Expected: 1</message>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		xmlData string
+		opts    html.Options
+		want    []string
+		notWant []string
+	}{
+		{
+			opts: html.Options{},
+			want: []string{
+				"<title>Test results</title>",
+				"<p class=\"meta\">WIN11 07/10/2023 20:53:19</p>",
+				"<div class=\"stat\"><span class=\"value\">50.00%</span> pass rate</div>",
+				"<summary>App.dll <span class=\"counts\"><span class=\"pass\">1</span> / <span class=\"fail\">1</span> / " +
+					"<span class=\"skip\">0</span></span> <span class=\"duration\">1s</span></summary>",
+				"<span class=\"name\">A &lt;b&gt; test.</span> <span class=\"duration\">500ms</span>",
+				"<details open>\n          <summary>TestClass",
+				"<pre class=\"failure\">Expected: 1</pre>",
+				"<meta http-equiv=\"Content-Security-Policy\" content=\"default-src &#39;none&#39;;",
+				"<link rel=\"icon\" href=\"data:image/svg&#43;xml,",
+				"fill=%22%23cf222e%22",
+			},
+			notWant: []string{"class=\"back\"", "<script>", "id=\"search\"", "class=\"coverage\"", "class=\"benchmarks\"",
+				"class=\"mutations\""},
+		},
+		{
+			opts: html.Options{Interactive: true},
+			want: []string{
+				"<input id=\"search\" type=\"search\"",
+				"<label><input type=\"checkbox\" value=\"fail\" checked> Failed</label>",
+				"<li class=\"test fail\" data-result=\"fail\">",
+				"<script>// Filters the tests of the report",
+			},
+			notWant: []string{"data-live"},
+		},
+		{
+			opts: html.Options{Interactive: true, AssetsURL: "../assets"},
+			want: []string{
+				"<link rel=\"stylesheet\" href=\"../assets/report.css\">",
+				"<script src=\"../assets/report.js\"></script>",
+			},
+			notWant: []string{"<style>", "<script>", "Content-Security-Policy"},
+		},
+		{
+			opts: html.Options{LiveURL: "/api/live"},
+			want: []string{
+				"<body data-live=\"/api/live\">",
+				"<script>// Filters the tests of the report",
+			},
+			notWant: []string{"id=\"search\"", "Content-Security-Policy"},
+		},
+		{
+			xmlData: "<assemblies computer=\"WIN11\" start-rtf=\"2023-07-10T20:53:19Z\" />",
+			opts:    html.Options{},
+			want: []string{
+				"<p class=\"meta\">WIN11 " + time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC).Local().
+					Format("2006-01-02 15:04:05 MST") + "</p>",
+			},
+		},
+		{
+			opts: html.Options{Coverage: coverage.Report{Assemblies: []coverage.Assembly{
+				{Name: "App", Files: []coverage.File{{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {}}}}},
+			}}},
+			want: []string{
+				"<td>App</td>\n          <td class=\"num\">50.00% <span class=\"counts\">(1 / 2)</span></td>\n" +
+					"          <td class=\"num\">-</td>",
+				"<th>Total</th>",
+			},
+		},
+		{
+			opts: html.Options{
+				Coverage: coverage.Report{Assemblies: []coverage.Assembly{
+					{Name: "App", Files: []coverage.File{{Path: "A.cs", Lines: map[int]coverage.Line{1: {Hits: 1}, 2: {}}}}},
+				}},
+				CoverageDiff: &analysis.CoverageDiff{
+					Line: analysis.CoverageChange{
+						Before: coverage.Counter{Covered: 3, Total: 4}, After: coverage.Counter{Covered: 1, Total: 2},
+					},
+					Files: []analysis.FileCoverageDiff{{
+						Assembly: "App", Path: "A.cs",
+						Line: analysis.CoverageChange{
+							Before: coverage.Counter{Covered: 2, Total: 2}, After: coverage.Counter{Covered: 1, Total: 2},
+						},
+					}},
+				},
+			},
+			want: []string{
+				"<tr class=\"dropped\">\n          <td>A.cs <span class=\"counts\">(App)</span></td>\n" +
+					"          <td class=\"num\">50.00% <span class=\"counts\">(-50.00)</span></td>\n" +
+					"          <td class=\"num\">-</td>",
+				"<th>Compared to the baseline</th>\n" +
+					"          <th class=\"num\">50.00% <span class=\"counts\">(-25.00)</span></th>",
+			},
+		},
+		{
+			xmlData: "<assemblies><assembly name=\"App.Tests.dll\"><collection>" +
+				"<test name=\"Adds\" result=\"Fail\" source-file=\"/src/CalculatorTests.cs\" />" +
+				"</collection></assembly></assemblies>",
+			opts: html.Options{Coverage: coverage.Report{Assemblies: []coverage.Assembly{
+				{Name: "App", Files: []coverage.File{{Path: "/src/Calculator.cs", Lines: map[int]coverage.Line{1: {}}}}},
+			}}},
+			want: []string{
+				"<td>/src/Calculator.cs <span class=\"counts\">(App)</span></td>",
+				"<td><span class=\"fail\">Adds</span> </td>",
+			},
+		},
+		{
+			opts: html.Options{Benchmarks: []benchmark.Comparison{
+				{
+					Benchmark: benchmark.Benchmark{Type: "B", Method: "Parse", Parameters: "N=10", Mean: 1500, Allocated: 256},
+					Baseline:  &benchmark.Benchmark{Mean: 1000},
+				},
+				{Benchmark: benchmark.Benchmark{Type: "B", Method: "Tokenize", Mean: 2e6, Allocated: -1}},
+			}},
+			want: []string{
+				"<tr class=\"slower\">\n          <td>B.Parse(N=10)</td>\n          <td class=\"num\">1.50 μs</td>",
+				"<td class=\"num\">256 B</td>\n          <td class=\"num\">1.00 μs</td>\n" +
+					"          <td class=\"num\">1.50x</td>",
+				"<td>B.Tokenize</td>\n          <td class=\"num\">2.00 ms</td>",
+			},
+		},
+		{
+			opts: html.Options{Mutations: mutation.Report{
+				Namespaces: []mutation.Namespace{
+					{Name: "App.Math", Counts: mutation.Counts{Killed: 8, Timeout: 1, Survived: 1}},
+					{Name: "App.Text", Counts: mutation.Counts{Killed: 1, NoCoverage: 3}},
+					{Name: "App.Util", Counts: mutation.Counts{Ignored: 2}},
+				},
+				Thresholds: mutation.Thresholds{High: 80, Low: 60},
+			}},
+			want: []string{
+				"<tr class=\"good\">\n          <td>App.Math</td>\n          <td class=\"num\">9</td>",
+				"<tr class=\"bad\">\n          <td>App.Text</td>",
+				"<td>App.Util</td>\n          <td class=\"num\">0</td>\n          <td class=\"num\">0</td>\n" +
+					"          <td class=\"num\">0</td>\n          <td class=\"num\">2</td>\n          <td class=\"num\">-</td>",
+				"<tfoot>\n        <tr>\n          <td>Total</td>",
+				"<td class=\"num\">71.43%</td>\n        </tr>\n      </tfoot>",
+			},
+		},
+		{
+			opts: html.Options{TraceURL: func(name string) string {
+				if name == "A <b> test." {
+					return "https://ci.example.com/traces/a.speedscope.json"
+				}
+
+				return "traces/" + url.PathEscape(name) + ".speedscope.json"
+			}},
+			want: []string{
+				"<span class=\"duration\">500ms</span> <a class=\"trace\" href=\"https://www.speedscope.app/" +
+					"#profileURL=https%3A%2F%2Fci.example.com%2Ftraces%2Fa.speedscope.json\">profile</a>",
+				"<a class=\"trace\" href=\"traces/NS.TestClass+Method.Result.speedscope.json\">profile</a>",
+			},
+		},
+		{
+			opts: html.Options{TraceURL: func(name string) string {
+				if name == "A <b> test." {
+					return "javascript:alert(1)"
+				}
+
+				return "//evil.example.com/a.speedscope.json"
+			}},
+			notWant: []string{"javascript:", "evil.example.com", "class=\"trace\""},
+		},
+		{
+			opts: html.Options{Title: "Nightly", IndexURL: "../index.html"},
+			want: []string{
+				"<title>Nightly</title>",
+				"<a class=\"back\" href=\"../index.html\">&larr; All runs</a>",
+			},
+		},
+	} {
+		// ARRANGE.
+		if tc.xmlData == "" {
+			tc.xmlData = report
+		}
+
+		testRun, _ := xunit.Load(strings.NewReader(tc.xmlData))
+
+		var sb strings.Builder
+
+		// ACT.
+		err := html.Render(&sb, testRun, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+
+		for _, want := range tc.want {
+			assert.Contains(t, sb.String(), want, "", "\n\n"+
+				"UT Name:    Render a test run as an HTML page.\n"+
+				"Input:      %+v\n"+
+				"\033[32mExpected:   Output containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.opts, want, sb.String())
+		}
+
+		for _, notWant := range tc.notWant {
+			assert.Equal(t, strings.Contains(sb.String(), notWant), false, "", "\n\n"+
+				"UT Name:    Render a test run as an HTML page.\n"+
+				"Input:      %+v\n"+
+				"\033[32mExpected:   Output NOT containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.opts, notWant, sb.String())
+		}
+	}
+}
+
+// UT: Render the tests of the large groups of an interactive HTML page inside a lazy template.
+func TestRender_LazyGroups(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	var sb strings.Builder
+
+	sb.WriteString("<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n")
+
+	for i := 0; i < 300; i++ {
+		sb.WriteString("      <test name=\"NS.Passing+Test.Result" + strconv.Itoa(i) + "\" result=\"Pass\" />\n")
+		sb.WriteString("      <test name=\"NS.Failing+Test.Result" + strconv.Itoa(i) + "\" result=\"Fail\" />\n")
+	}
+
+	sb.WriteString("    </collection>\n  </assembly>\n</assemblies>")
+
+	testRun, _ := xunit.Load(strings.NewReader(sb.String()))
+
+	for _, tc := range []struct {
+		opts     html.Options
+		wantLazy int
+	}{
+		{opts: html.Options{}, wantLazy: 0},
+		{opts: html.Options{Interactive: true}, wantLazy: 1},
+		{opts: html.Options{LiveURL: "/api/live"}, wantLazy: 1},
+	} {
+		var out strings.Builder
+
+		// ACT.
+		err := html.Render(&out, testRun, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+
+		got := strings.Count(out.String(), "<template class=\"lazy\">")
+
+		assert.Equal(t, got, tc.wantLazy, "", "\n\n"+
+			"UT Name:    Render the tests of the large groups of an interactive HTML page inside a lazy template.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %d lazy template(s)\033[0m\n"+
+			"\033[31mActual:     %d lazy template(s)\033[0m\n\n", tc.opts, tc.wantLazy, got)
+
+		assert.Equal(t, strings.Count(out.String(), "<li class=\"test "), 600, "", "\n\n"+
+			"UT Name:    Render the tests of the large groups of an interactive HTML page inside a lazy template.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   600 tests\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.opts, out.String())
+	}
+}
+
+// UT: Render a test run as an HTML page, with a test which has multiple traits.
+func TestRender_MultipleTraits(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" time=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"Another passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	want := "<summary>App.dll <span class=\"counts\"><span class=\"pass\">2</span> / <span class=\"fail\">0</span> / " +
+		"<span class=\"skip\">0</span></span>"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := html.Render(&sb, testRun, html.Options{})
+
+	// ASSERT.
+	assert.NoError(t, err, "Render()")
+	assert.Contains(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Render a test run as an HTML page, with a test which has multiple traits.\n"+
+		"\033[32mExpected:   A page containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, sb.String())
+}
+
+// UT: Render a test run as a self-contained HTML page.
+func TestRender_SelfContained(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	var sb strings.Builder
+
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies><assembly name=\"App.dll\"><collection>" +
+		"<test name=\"Adds\" result=\"Pass\" time=\"1\" />" +
+		"</collection></assembly></assemblies>"))
+	resources := regexp.MustCompile(`<(?:link|script|img)[^>]*\s(?:src|href)="([^"]*)"`)
+
+	// ACT.
+	err := html.Render(&sb, testRun, html.Options{Interactive: true})
+
+	// ASSERT.
+	assert.NoError(t, err, "Render()")
+
+	for _, m := range resources.FindAllStringSubmatch(sb.String(), -1) {
+		assert.Equal(t, strings.HasPrefix(m[1], "data:"), true, "", "\n\n"+
+			"UT Name:    Render a test run as a self-contained HTML page.\n"+
+			"\033[32mExpected:   Only embedded resources\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", m[0])
+	}
+
+	assert.Contains(t, sb.String(), "fill=%22%232da44e%22", "", "\n\n"+
+		"UT Name:    Render a test run as a self-contained HTML page.\n"+
+		"\033[32mExpected:   The icon of a test run without failed tests\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", sb.String())
+}
+
+// UT: Render a test run as an HTML page with a color theme.
+func TestRender_Theme(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		opts     html.Options
+		want     string
+		wantNone string
+	}{
+		{
+			opts:     html.Options{},
+			want:     "<html lang=\"en\">",
+			wantNone: "data-theme=\"",
+		},
+		{
+			opts:     html.Options{Theme: "auto"},
+			want:     "<html lang=\"en\">",
+			wantNone: "data-theme=\"",
+		},
+		{
+			opts: html.Options{Theme: "dark"},
+			want: "<html lang=\"en\" data-theme=\"dark\">",
+		},
+		{
+			opts: html.Options{Theme: "light", ThemeCSS: ":root { --link: #e20074; }"},
+			want: "<style>:root { --link: #e20074; }</style>\n</head>",
+		},
+	} {
+		// ARRANGE.
+		var sb strings.Builder
+
+		testRun, _ := xunit.Load(strings.NewReader("<assemblies><assembly name=\"App.dll\" /></assemblies>"))
+
+		// ACT.
+		err := html.Render(&sb, testRun, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+		assert.Contains(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Render a test run as an HTML page with a color theme.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   A page containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.opts, tc.want, sb.String())
+
+		if tc.wantNone != "" {
+			assert.Equal(t, strings.Contains(sb.String(), tc.wantNone), false, "", "\n\n"+
+				"UT Name:    Render a test run as an HTML page with a color theme.\n"+
+				"Input:      %+v\n"+
+				"\033[32mExpected:   A page without %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.opts, tc.wantNone, sb.String())
+		}
+	}
+}
+
+// UT: Render an overview of multiple test runs as an HTML page.
+func TestRenderIndex(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		entries []html.IndexEntry
+		opts    html.Options
+		want    []string
+	}{
+		{
+			entries: []html.IndexEntry{},
+			opts:    html.Options{},
+			want: []string{
+				"<title>Test runs</title>", "<p class=\"meta\">0 runs</p>", "Content-Security-Policy",
+				"<link rel=\"icon\" href=\"data:image/svg&#43;xml,",
+			},
+		},
+		{
+			entries: []html.IndexEntry{
+				{
+					Name: "nightly",
+					URL:  "runs/nightly.html",
+					Run: xunit.TestRun{
+						Computer:   "WIN11",
+						Assemblies: []xunit.Assembly{{TotalCount: 4, PassedCount: 3, FailedCount: 1}},
+					},
+				},
+			},
+			opts: html.Options{Title: "History"},
+			want: []string{
+				"<title>History</title>",
+				"<tr class=\"fail\">\n        <td><a href=\"runs/nightly.html\">nightly</a></td>",
+				"<td>WIN11</td>",
+				"<td class=\"num\">75.00%</td>",
+			},
+		},
+	} {
+		// ARRANGE.
+		var sb strings.Builder
+
+		// ACT.
+		err := html.RenderIndex(&sb, tc.entries, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "RenderIndex()")
+
+		for _, want := range tc.want {
+			assert.Contains(t, sb.String(), want, "", "\n\n"+
+				"UT Name:    Render an overview of multiple test runs as an HTML page.\n"+
+				"Input:      %+v\n"+
+				"\033[32mExpected:   Output containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.entries, want, sb.String())
+		}
+	}
+}
+
+// UT: Render a dashboard of multiple projects as an HTML page.
+func TestRenderDashboard(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Returns a test run in which the given number of tests (out of 4) passed.
+	newRun := func(passed int) xunit.TestRun {
+		return xunit.TestRun{
+			Timestamp:  "2023-07-10T20:53:19",
+			Assemblies: []xunit.Assembly{{TotalCount: 4, PassedCount: passed, FailedCount: 4 - passed}},
+		}
+	}
+
+	for _, tc := range []struct {
+		projects []html.Project
+		opts     html.Options
+		want     []string
+	}{
+		{
+			projects: []html.Project{},
+			opts:     html.Options{},
+			want:     []string{"<title>Projects</title>", "<p class=\"meta\">0 projects</p>"},
+		},
+		{
+			projects: []html.Project{
+				{Name: "api", URL: "/runs/api-3", Runs: []xunit.TestRun{newRun(2), newRun(4), newRun(3)}},
+				{Name: "web", URL: "/runs/web", Runs: []xunit.TestRun{newRun(4)}},
+			},
+			opts: html.Options{Title: "Team"},
+			want: []string{
+				"<title>Team</title>",
+				"<tr class=\"fail\">\n        <td><a href=\"/runs/api-3\">api</a></td>\n        <td>Failing</td>\n" +
+					"        <td>2023-07-10T20:53:19</td>",
+				"<td class=\"num\">75.00%</td>\n        <td class=\"num\">-25.00%</td>\n" +
+					"        <td class=\"trend\" title=\"Pass rate of the last 3 runs\">▁█▄</td>",
+				"<tr class=\"pass\">\n        <td><a href=\"/runs/web\">web</a></td>\n        <td>Passing</td>",
+				"<td class=\"num\"></td>\n        <td class=\"trend\" title=\"Pass rate of the last 1 runs\">█</td>",
+			},
+		},
+	} {
+		// ARRANGE.
+		var sb strings.Builder
+
+		// ACT.
+		err := html.RenderDashboard(&sb, tc.projects, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "RenderDashboard()")
+
+		for _, want := range tc.want {
+			assert.Contains(t, sb.String(), want, "", "\n\n"+
+				"UT Name:    Render a dashboard of multiple projects as an HTML page.\n"+
+				"Input:      %+v\n"+
+				"\033[32mExpected:   Output containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.projects, want, sb.String())
+		}
+	}
+}
+
+// UT: Get the static assets of the pages.
+func TestAssets(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{name: "report.css", want: ":root {"},
+		{name: "report.js", want: "// Filters the tests of the report"},
+	} {
+		// ACT.
+		data, err := fs.ReadFile(html.Assets(), tc.name)
+
+		// ASSERT.
+		assert.NoError(t, err, "ReadFile()")
+
+		assert.Equal(t, strings.HasPrefix(string(data), tc.want), true, "", "\n\n"+
+			"UT Name:    Get the static assets of the pages.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Content starting with %q\033[0m\n"+
+			"\033[31mActual:     %.40q\033[0m\n\n", tc.name, tc.want, data)
+	}
+}
+
+// Benchmark: Render a test run as an HTML page.
+func BenchmarkRender(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		b.Run(strconv.Itoa(n)+" tests", func(b *testing.B) {
+			testRun, err := xunit.Load(bytes.NewReader(xunittest.Generate(n)))
+
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = html.Render(io.Discard, testRun, html.Options{})
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package pipelines contains functions for rendering .NET test result(s) for Azure Pipelines.
+// Failures are reported as logging commands, which Azure Pipelines turns into errors (on the summary of the run), and
+// the HTML report is published as an attachment of the run.
+// More information regarding logging commands can be found @
+// https://learn.microsoft.com/en-us/azure/devops/pipelines/scripts/logging-commands.
+package pipelines
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The type of the attachment holding the HTML report.
+const reportAttachmentType = "dtvisual.report"
+
+// LogIssues writes a logging command to w for each failed test of testRun, so that Azure Pipelines reports the failure
+// as an error (at the location of the test, if known).
+func LogIssues(w io.Writer, testRun xunit.TestRun) error {
+	var b strings.Builder
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			if tc.Result != "Fail" {
+				return
+			}
+
+			b.WriteString("##vso[task.logissue type=error;")
+
+			if tc.SourceFile != "" {
+				fmt.Fprintf(&b, "sourcepath=%s;", escapeProperty(tc.SourceFile))
+
+				if tc.SourceLine > 0 {
+					fmt.Fprintf(&b, "linenumber=%d;", tc.SourceLine)
+				}
+			}
+
+			fmt.Fprintf(&b, "]%s\n", escapeData(assembly.Name+": "+tc.Name+": "+failureText(tc)))
+		})
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// AddReportAttachment writes a logging command to w, which uploads the HTML report at path (preferably an absolute
+// path) as an attachment of the run with the given name.
+func AddReportAttachment(w io.Writer, name, path string) error {
+	_, err := fmt.Fprintf(w, "##vso[task.addattachment type=%s;name=%s;]%s\n", reportAttachmentType,
+		escapeProperty(name), escapeData(path))
+
+	return err
+}
+
+// Returns the text describing the failure of tc.
+func failureText(tc xunit.TestCase) string {
+	text := strings.TrimSpace(tc.Failure.Message)
+
+	if text == "" {
+		text = "Test failed."
+	}
+
+	if st := strings.TrimSpace(tc.Failure.StackTrace); st != "" {
+		text += "\n" + st
+	}
+
+	return text
+}
+
+// Returns s, escaped for use as the data of a logging command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// Returns s, escaped for use as a property value of a logging command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D").Replace(s)
+}
@@ -0,0 +1,112 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "pipelines" package.
+package pipelines_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/pipelines"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The XML data used by the tests in this file.
+const xmlData = "<assemblies>\n" +
+	"  <assembly name=\"~/App.dll\" passed=\"1\" failed=\"2\" total=\"3\" time=\"2.5\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"NS.TestClass.Result\" result=\"Fail\" time=\"1\" source-file=\"Tests.cs\" source-line=\"7\">\n" +
+	"        <failure>\n" +
+	"          <message>Expected: 100%, Actual: 50%</message>\n" +
+	"          <stack-trace>at Result()</stack-trace>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\">\n" +
+	"        <failure />\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// UT: Write the issues of a test run.
+func TestLogIssues(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader(xmlData))
+	want := "##vso[task.logissue type=error;sourcepath=Tests.cs;linenumber=7;]App.dll: NS.TestClass.Result: " +
+		"Expected: 100%AZP25, Actual: 50%AZP25%0Aat Result()\n" +
+		"##vso[task.logissue type=error;]App.dll: A failing test.: Test failed.\n"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := pipelines.LogIssues(&sb, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "LogIssues()")
+	assert.Equal(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Write the issues of a test run.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, sb.String())
+}
+
+// UT: Write the command publishing the HTML report as an attachment.
+func TestAddReportAttachment(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name, path string
+		want       string
+	}{
+		{
+			name: "Test report",
+			path: "/agent/_work/1/s/report.html",
+			want: "##vso[task.addattachment type=dtvisual.report;name=Test report;]/agent/_work/1/s/report.html\n",
+		},
+		{
+			name: "Tests; [nightly]",
+			path: "/tmp/100%.html",
+			want: "##vso[task.addattachment type=dtvisual.report;name=Tests%3B [nightly%5D;]/tmp/100%AZP25.html\n",
+		},
+	} {
+		// ARRANGE.
+		var sb strings.Builder
+
+		// ACT.
+		err := pipelines.AddReportAttachment(&sb, tc.name, tc.path)
+
+		// ASSERT.
+		assert.NoError(t, err, "AddReportAttachment()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Write the command publishing the HTML report as an attachment.\n"+
+			"Input:      %s, %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.path, tc.want, sb.String())
+	}
+}
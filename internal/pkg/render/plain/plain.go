@@ -0,0 +1,374 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package plain contains functions for rendering .NET test result(s) as plain text, without any ANSI escape sequence
+// or other formatting, so the report can be read in log files, emails and the consoles of CI systems.
+// Each line of the report fits in the width it's rendered with (when the line can be split, or truncated).
+package plain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// DefaultWidth is the width a report is rendered with when no width is given.
+const DefaultWidth = 80
+
+// The minimum width of a report.
+const minWidth = 40
+
+// The indentation of each level of the tree of tests (and of the details of a failure).
+const indent = "  "
+
+// Options controls how a test run is rendered.
+type Options struct {
+	Title string // The title of the report (defaults to "Test results").
+	Width int    // The maximum number of characters of a line (defaults to DefaultWidth, and is at least 40).
+}
+
+// A node is either a group or a test in the rendered tree.
+type node struct {
+	group *xunit.TestGroup
+	test  xunit.TestCase
+}
+
+// The counts of a group, per result.
+type counts struct {
+	passed, failed, skipped, other int
+}
+
+// A failed test, as listed after the tree of tests.
+type failure struct {
+	name string         // The full name of the test (including its assembly and groups).
+	test xunit.TestCase // The test itself.
+}
+
+// A renderer writes a test run to b.
+type renderer struct {
+	b        strings.Builder
+	width    int
+	failures []failure
+}
+
+// Render writes testRun to w as plain text.
+// The report starts with the statistics of the test run, followed by the tests of each assembly as a tree (where each
+// assembly and group is followed by the number of tests it contains, per result) and the details of each failed test.
+// The names which don't fit in a line are truncated, and the details of the failures are wrapped.
+func Render(w io.Writer, testRun xunit.TestRun, opts Options) error {
+	r := renderer{width: max(opts.Width, minWidth)}
+
+	if opts.Width == 0 {
+		r.width = DefaultWidth
+	}
+
+	title := opts.Title
+
+	if title == "" {
+		title = "Test results"
+	}
+
+	stats := testRun.Stats()
+
+	r.writeHeading(title, "=")
+	r.writeWrapped(fmt.Sprintf("%d tests in %d assemblies: %d passed, %d failed, %d skipped, %d not run (%.2f%% pass "+
+		"rate) in %s.", stats.TotalCount, stats.AssemblyCount, stats.PassedCount, stats.FailedCount, stats.SkippedCount,
+		stats.NotRunCount, stats.PassRate, fmtDuration(stats.TotalDuration)), "", "")
+
+	for _, assembly := range testRun.Assemblies {
+		nodes := assemblyNodes(assembly)
+
+		r.b.WriteString("\n")
+		r.writeLine("", assembly.Name+" "+fmtCounts(countAssembly(assembly)), fmtDuration(assembly.Duration))
+		r.writeNodes(nodes, indent, []string{assembly.Name})
+	}
+
+	if len(r.failures) > 0 {
+		r.b.WriteString("\n")
+		r.writeHeading(fmt.Sprintf("Failures (%d)", len(r.failures)), "-")
+	}
+
+	for idx, f := range r.failures {
+		if idx > 0 {
+			r.b.WriteString("\n")
+		}
+
+		r.writeWrapped(fmt.Sprintf("%d) %s", idx+1, f.name), "", indent)
+
+		for _, line := range strings.Split(failureText(f.test), "\n") {
+			r.writeWrapped(line, indent, indent)
+		}
+	}
+
+	_, err := io.WriteString(w, r.b.String())
+
+	return err
+}
+
+// Writes nodes to the renderer, indenting each line with prefix, where path contains the names of the assembly and the
+// groups the nodes belong to.
+func (r *renderer) writeNodes(nodes []node, prefix string, path []string) {
+	for _, n := range nodes {
+		if n.group != nil {
+			children := groupNodes(n.group)
+
+			r.writeLine(prefix, n.group.Name+" "+fmtCounts(countNodes(children)), "")
+			r.writeNodes(children, prefix+indent, append(path[:len(path):len(path)], n.group.Name))
+
+			continue
+		}
+
+		r.writeLine(prefix, resultLabel(n.test.Result)+"  "+n.test.Name, fmtDuration(n.test.Duration))
+
+		if n.test.Result == "Fail" {
+			name := strings.Join(append(path[:len(path):len(path)], n.test.Name), " > ")
+			r.failures = append(r.failures, failure{name: name, test: n.test})
+		}
+	}
+}
+
+// Writes a line with prefix and left (truncated if it doesn't fit) at the start, and right aligned to the end.
+func (r *renderer) writeLine(prefix, left, right string) {
+	left = oneLine(left)
+	avail := r.width - runeCount(prefix)
+
+	if right != "" {
+		avail -= runeCount(right) + 1
+	}
+
+	avail = max(avail, 4)
+
+	if runeCount(left) > avail {
+		left = string([]rune(left)[:avail-3]) + "..."
+	}
+
+	r.b.WriteString(prefix)
+	r.b.WriteString(left)
+
+	if right != "" {
+		r.b.WriteString(strings.Repeat(" ", max(1, r.width-runeCount(prefix)-runeCount(left)-runeCount(right))))
+		r.b.WriteString(right)
+	}
+
+	r.b.WriteString("\n")
+}
+
+// Writes s, wrapped at the width of the renderer, indenting the first line with first and the other lines with rest.
+func (r *renderer) writeWrapped(s, first, rest string) {
+	prefix := first
+	width := max(r.width-max(runeCount(first), runeCount(rest)), 1)
+
+	for _, line := range wrap(strings.TrimSpace(s), width) {
+		r.b.WriteString(strings.TrimRight(prefix+line, " "))
+		r.b.WriteString("\n")
+
+		prefix = rest
+	}
+}
+
+// Writes a heading with the given text, underlined with the character c.
+func (r *renderer) writeHeading(text, c string) {
+	text = oneLine(text)
+
+	r.b.WriteString(text)
+	r.b.WriteString("\n")
+	r.b.WriteString(strings.Repeat(c, min(runeCount(text), r.width)))
+	r.b.WriteString("\n\n")
+}
+
+// Returns the lines of s, split at the spaces so that each line contains at most width characters. Words which are
+// longer than width are split.
+func wrap(s string, width int) []string {
+	lines := make([]string, 0, 1)
+	line := make([]rune, 0, width)
+
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+
+		if len(line) > 0 && len(line)+1+len(w) > width {
+			lines, line = append(lines, string(line)), line[:0]
+		}
+
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+
+		for len(line)+len(w) > width {
+			n := width - len(line)
+			lines, line, w = append(lines, string(append(line, w[:n]...))), line[:0], w[n:]
+		}
+
+		line = append(line, w...)
+	}
+
+	return append(lines, string(line))
+}
+
+// Returns the top-level nodes of assembly.
+// The tests (and groups) of the unnamed group are shown directly below the assembly.
+func assemblyNodes(assembly xunit.Assembly) []node {
+	nodes := make([]node, 0, len(assembly.Tests))
+
+	for _, group := range assembly.Tests {
+		if group.Name == "" {
+			nodes = append(nodes, groupNodes(group)...)
+		} else {
+			nodes = append(nodes, node{group: group})
+		}
+	}
+
+	return nodes
+}
+
+// Returns the child nodes of group (the tests first, followed by the subgroups).
+func groupNodes(group *xunit.TestGroup) []node {
+	nodes := make([]node, 0, len(group.Tests)+len(group.Groups))
+
+	for _, tc := range group.Tests {
+		nodes = append(nodes, node{test: tc})
+	}
+
+	for _, sGroup := range group.Groups {
+		nodes = append(nodes, node{group: sGroup})
+	}
+
+	return nodes
+}
+
+// Returns the counts of all the tests in nodes, including the tests of the subgroups.
+func countNodes(nodes []node) counts {
+	var c counts
+
+	for _, n := range nodes {
+		if n.group != nil {
+			sc := countNodes(groupNodes(n.group))
+
+			c.passed += sc.passed
+			c.failed += sc.failed
+			c.skipped += sc.skipped
+			c.other += sc.other
+
+			continue
+		}
+
+		c.add(n.test.Result)
+	}
+
+	return c
+}
+
+// Returns the counts of the tests of assembly. Unlike the counts of its groups, a test which belongs to multiple
+// groups (e.g. because it has multiple traits) is only counted once.
+func countAssembly(assembly xunit.Assembly) counts {
+	var c counts
+
+	assembly.Walk(func(_ []string, tc xunit.TestCase) {
+		c.add(tc.Result)
+	})
+
+	return c
+}
+
+// Adds a test with the given result to c.
+func (c *counts) add(result string) {
+	switch result {
+	case "Pass":
+		c.passed++
+	case "Fail":
+		c.failed++
+	case "Skip":
+		c.skipped++
+	default:
+		c.other++
+	}
+}
+
+// Returns c in a human-readable format.
+func fmtCounts(c counts) string {
+	parts := make([]string, 0, 4)
+
+	for _, part := range []struct {
+		n    int
+		name string
+	}{{c.passed, "passed"}, {c.failed, "failed"}, {c.skipped, "skipped"}, {c.other, "other"}} {
+		if part.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", part.n, part.name))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "(no tests)"
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// Returns the label representing result, which is 4 characters wide.
+func resultLabel(result string) string {
+	switch result {
+	case "Pass":
+		return "PASS"
+	case "Fail":
+		return "FAIL"
+	case "Skip":
+		return "SKIP"
+	default:
+		return "----"
+	}
+}
+
+// Returns the text describing the failure of tc.
+func failureText(tc xunit.TestCase) string {
+	text := strings.TrimSpace(tc.Failure.Message)
+
+	if text == "" {
+		text = "Test failed."
+	}
+
+	if st := strings.TrimSpace(tc.Failure.StackTrace); st != "" {
+		text += "\n" + st
+	}
+
+	return text
+}
+
+// Returns s on a single line, with its line breaks (and tabs) replaced by spaces.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(s)
+}
+
+// Returns the number of characters in s.
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Returns d in a human-readable format, rounded to milliseconds.
+func fmtDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
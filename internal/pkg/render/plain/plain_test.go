@@ -0,0 +1,172 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "plain" package.
+package plain_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/plain"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// If true, the golden files are updated with the actual output (go test -update).
+var update = flag.Bool("update", false, "Update the golden files in testdata.")
+
+// The XML data used by the tests in this file.
+const xmlData = "<assemblies>\n" +
+	"  <assembly name=\"~/App.Tests.dll\" passed=\"2\" failed=\"2\" skipped=\"1\" total=\"5\" time=\"3.25\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"NS.Calculator+Add.Returns_the_sum_of_two_numbers_even_when_both_of_them_are_negative\" " +
+	"result=\"Pass\" time=\"0.25\" />\n" +
+	"      <test name=\"NS.Calculator+Divide.Fails\" result=\"Fail\" time=\"1.5\">\n" +
+	"        <failure exception-type=\"System.DivideByZeroException\">\n" +
+	"          <message>Attempted to divide by zero, which isn't supported by the calculator (it should return " +
+	"an error instead).</message>\n" +
+	"          <stack-trace>   at NS.Calculator.Divide(Int32 a, Int32 b) in /src/Calculator.cs:line 42\n" +
+	"   at NS.Calculator+Divide.Fails() in /src/CalculatorTests.cs:line 7</stack-trace>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\">\n" +
+	"        <failure />\n" +
+	"      </test>\n" +
+	"      <test name=\"A skipped test.\" result=\"Skip\">\n" +
+	"        <reason>Not implemented.</reason>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"  <assembly name=\"~/Empty.Tests.dll\" total=\"0\" />\n" +
+	"</assemblies>"
+
+// UT: Render a test run as plain text.
+func TestRender(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		golden string
+		opts   plain.Options
+	}{
+		{golden: "default.golden", opts: plain.Options{}},
+		{golden: "narrow.golden", opts: plain.Options{Title: "Nightly build", Width: 50}},
+		{golden: "minimum.golden", opts: plain.Options{Width: 1}},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+		path := filepath.Join("testdata", tc.golden)
+
+		var sb strings.Builder
+
+		// ACT.
+		err := plain.Render(&sb, testRun, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+
+		if *update {
+			assert.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o644), "WriteFile()")
+		}
+
+		want, err := os.ReadFile(path)
+
+		assert.NoError(t, err, "ReadFile()")
+		assert.Equal(t, sb.String(), string(want), "", "\n\n"+
+			"UT Name:    Render a test run as plain text.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.opts, want, sb.String())
+	}
+}
+
+// UT: Render a test run as plain text, where no line is wider than the width of the report.
+func TestRender_Width(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, width := range []int{40, 60, 80, 120} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+
+		var sb strings.Builder
+
+		// ACT.
+		err := plain.Render(&sb, testRun, plain.Options{Width: width})
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+
+		for _, line := range strings.Split(sb.String(), "\n") {
+			assert.Equal(t, len([]rune(line)) <= width, true, "", "\n\n"+
+				"UT Name:    Render a test run as plain text, where no line is wider than the width of the report.\n"+
+				"Input:      %d\n"+
+				"\033[32mExpected:   Lines of at most %d characters\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", width, width, line)
+
+			assert.Equal(t, strings.Contains(line, "\033"), false, "", "\n\n"+
+				"UT Name:    Render a test run as plain text, where no line is wider than the width of the report.\n"+
+				"Input:      %d\n"+
+				"\033[32mExpected:   Lines without ANSI escape sequences\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", width, line)
+		}
+	}
+}
+
+// UT: Render a test run as plain text, with a test which has multiple traits.
+func TestRender_MultipleTraits(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" time=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"Another passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	want := "App.dll (2 passed)"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := plain.Render(&sb, testRun, plain.Options{})
+
+	// ASSERT.
+	assert.NoError(t, err, "Render()")
+	assert.Contains(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Render a test run as plain text, with a test which has multiple traits.\n"+
+		"\033[32mExpected:   A report containing %q\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, sb.String())
+}
@@ -0,0 +1,264 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package term contains functions for rendering .NET test result(s) as a tree in a terminal.
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// ANSI escape sequences.
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	faint  = "\033[2m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+)
+
+// Options controls how a test run is rendered.
+type Options struct {
+	Color bool // If true, the output is colored using ANSI escape sequences.
+}
+
+// ColorEnabled returns true if the output written to f should be colored, false otherwise.
+// Colors are enabled when f is a terminal, unless the `NO_COLOR` environment variable is set (see https://no-color.org).
+func ColorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Render writes the tests of each assembly in testRun to w as a tree.
+// Each assembly and group is followed by the number of (passed, failed and skipped) tests it contains.
+func Render(w io.Writer, testRun xunit.TestRun, opts Options) error {
+	r := renderer{opts: opts}
+
+	for idx, assembly := range testRun.Assemblies {
+		if idx > 0 {
+			r.b.WriteString("\n")
+		}
+
+		nodes := assemblyNodes(assembly)
+
+		r.b.WriteString(r.style(bold, assembly.Name))
+		fmt.Fprintf(&r.b, " %s %s\n", r.counts(countAssembly(assembly)), r.style(faint, fmtDuration(assembly.Duration)))
+		r.writeNodes(nodes, "")
+	}
+
+	_, err := io.WriteString(w, r.b.String())
+
+	return err
+}
+
+// A node is either a group or a test in the rendered tree.
+type node struct {
+	group *xunit.TestGroup
+	test  xunit.TestCase
+}
+
+// The counts of a group, per result.
+type counts struct {
+	passed, failed, skipped, other int
+}
+
+// A renderer writes a test run to b.
+type renderer struct {
+	b    strings.Builder
+	opts Options
+}
+
+// Writes nodes to the renderer, indenting each line with prefix.
+func (r *renderer) writeNodes(nodes []node, prefix string) {
+	for idx, n := range nodes {
+		branch, indent := "├── ", "│   "
+
+		if idx == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		r.b.WriteString(prefix)
+		r.b.WriteString(r.style(faint, branch))
+
+		if n.group != nil {
+			children := groupNodes(n.group)
+
+			fmt.Fprintf(&r.b, "%s %s\n", r.style(bold, n.group.Name), r.counts(countNodes(children)))
+			r.writeNodes(children, prefix+r.style(faint, indent))
+
+			continue
+		}
+
+		color, icon := resultStyle(n.test.Result)
+
+		fmt.Fprintf(&r.b, "%s %s %s\n", r.style(color, icon), n.test.Name, r.style(faint, fmtDuration(n.test.Duration)))
+	}
+}
+
+// Returns c in a human-readable format.
+func (r *renderer) counts(c counts) string {
+	parts := make([]string, 0, 4)
+
+	if c.passed > 0 {
+		parts = append(parts, r.style(green, fmt.Sprintf("%d passed", c.passed)))
+	}
+
+	if c.failed > 0 {
+		parts = append(parts, r.style(red, fmt.Sprintf("%d failed", c.failed)))
+	}
+
+	if c.skipped > 0 {
+		parts = append(parts, r.style(yellow, fmt.Sprintf("%d skipped", c.skipped)))
+	}
+
+	if c.other > 0 {
+		parts = append(parts, fmt.Sprintf("%d other", c.other))
+	}
+
+	if len(parts) == 0 {
+		return "(no tests)"
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// Returns s wrapped in the ANSI escape sequence seq, if colors are enabled.
+func (r *renderer) style(seq, s string) string {
+	if !r.opts.Color {
+		return s
+	}
+
+	return seq + s + reset
+}
+
+// Returns the top-level nodes of assembly.
+// The tests (and groups) of the unnamed group are shown directly below the assembly.
+func assemblyNodes(assembly xunit.Assembly) []node {
+	nodes := make([]node, 0, len(assembly.Tests))
+
+	for _, group := range assembly.Tests {
+		if group.Name == "" {
+			nodes = append(nodes, groupNodes(group)...)
+		} else {
+			nodes = append(nodes, node{group: group})
+		}
+	}
+
+	return nodes
+}
+
+// Returns the child nodes of group (the tests first, followed by the subgroups).
+func groupNodes(group *xunit.TestGroup) []node {
+	nodes := make([]node, 0, len(group.Tests)+len(group.Groups))
+
+	for _, tc := range group.Tests {
+		nodes = append(nodes, node{test: tc})
+	}
+
+	for _, sGroup := range group.Groups {
+		nodes = append(nodes, node{group: sGroup})
+	}
+
+	return nodes
+}
+
+// Returns the counts of all the tests in nodes, including the tests of the subgroups.
+func countNodes(nodes []node) counts {
+	var c counts
+
+	for _, n := range nodes {
+		if n.group != nil {
+			sc := countNodes(groupNodes(n.group))
+
+			c.passed += sc.passed
+			c.failed += sc.failed
+			c.skipped += sc.skipped
+			c.other += sc.other
+
+			continue
+		}
+
+		c.add(n.test.Result)
+	}
+
+	return c
+}
+
+// Returns the counts of the tests of assembly. Unlike the counts of its groups, a test which belongs to multiple
+// groups (e.g. because it has multiple traits) is only counted once.
+func countAssembly(assembly xunit.Assembly) counts {
+	var c counts
+
+	assembly.Walk(func(_ []string, tc xunit.TestCase) {
+		c.add(tc.Result)
+	})
+
+	return c
+}
+
+// Adds a test with the given result to c.
+func (c *counts) add(result string) {
+	switch result {
+	case "Pass":
+		c.passed++
+	case "Fail":
+		c.failed++
+	case "Skip":
+		c.skipped++
+	default:
+		c.other++
+	}
+}
+
+// Returns the color and the icon representing result.
+func resultStyle(result string) (string, string) {
+	switch result {
+	case "Pass":
+		return green, "✔"
+	case "Fail":
+		return red, "✘"
+	case "Skip":
+		return yellow, "○"
+	default:
+		return faint, "?"
+	}
+}
+
+// Returns d in a human-readable format, rounded to milliseconds.
+func fmtDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
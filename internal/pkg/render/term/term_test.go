@@ -0,0 +1,178 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "term" package.
+package term_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/term"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit/xunittest"
+)
+
+// UT: Render a test run as a tree.
+func TestRender(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" time=\"1.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A test with a display name.\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.TestClass+Method.Result\" result=\"Fail\" time=\"1\" />\n" +
+		"      <test name=\"A skipped test.\" result=\"Skip\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"  <assembly name=\"~/Empty.dll\" />\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		opts term.Options
+		want string
+	}{
+		{
+			opts: term.Options{Color: false},
+			want: "App.dll (1 passed, 1 failed, 1 skipped) 1.5s\n" +
+				"├── ✔ A test with a display name. 500ms\n" +
+				"├── TestClass (1 failed)\n" +
+				"│   └── Method (1 failed)\n" +
+				"│       └── ✘ NS.TestClass+Method.Result 1s\n" +
+				"└── Category - Unit (1 skipped)\n" +
+				"    └── ○ A skipped test. 0s\n" +
+				"\n" +
+				"Empty.dll (no tests) 0s\n",
+		},
+		{
+			opts: term.Options{Color: true},
+			want: "\033[1mApp.dll\033[0m (\033[32m1 passed\033[0m, \033[31m1 failed\033[0m, \033[33m1 skipped\033[0m) " +
+				"\033[2m1.5s\033[0m\n" +
+				"\033[2m├── \033[0m\033[32m✔\033[0m A test with a display name. \033[2m500ms\033[0m\n" +
+				"\033[2m├── \033[0m\033[1mTestClass\033[0m (\033[31m1 failed\033[0m)\n" +
+				"\033[2m│   \033[0m\033[2m└── \033[0m\033[1mMethod\033[0m (\033[31m1 failed\033[0m)\n" +
+				"\033[2m│   \033[0m\033[2m    \033[0m\033[2m└── \033[0m\033[31m✘\033[0m NS.TestClass+Method.Result " +
+				"\033[2m1s\033[0m\n" +
+				"\033[2m└── \033[0m\033[1mCategory - Unit\033[0m (\033[33m1 skipped\033[0m)\n" +
+				"\033[2m    \033[0m\033[2m└── \033[0m\033[33m○\033[0m A skipped test. \033[2m0s\033[0m\n" +
+				"\n" +
+				"\033[1mEmpty.dll\033[0m (no tests) \033[2m0s\033[0m\n",
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+
+		var sb strings.Builder
+
+		// ACT.
+		err := term.Render(&sb, testRun, tc.opts)
+
+		// ASSERT.
+		assert.NoError(t, err, "Render()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Render a test run as a tree.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:\033[0m\n%s\n"+
+			"\033[31mActual:\033[0m\n%s\n\n", tc.opts, tc.want, sb.String())
+	}
+}
+
+// UT: Render a test run as a tree, with a test which has multiple traits.
+func TestRender_MultipleTraits(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" time=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	want := "App.dll (1 failed) 1s\n" +
+		"├── Category - Unit (1 failed)\n" +
+		"│   └── ✘ A failing test. 1s\n" +
+		"└── Timing - Slow (1 failed)\n" +
+		"    └── ✘ A failing test. 1s\n"
+
+	var sb strings.Builder
+
+	// ACT.
+	err := term.Render(&sb, testRun, term.Options{})
+
+	// ASSERT.
+	assert.NoError(t, err, "Render()")
+	assert.Equal(t, sb.String(), want, "", "\n\n"+
+		"UT Name:    Render a test run as a tree, with a test which has multiple traits.\n"+
+		"\033[32mExpected:\033[0m\n%s\n"+
+		"\033[31mActual:\033[0m\n%s\n\n", want, sb.String())
+}
+
+// UT: Check if the output written to a file should be colored.
+func TestColorEnabled(t *testing.T) {
+	// ARRANGE.
+	f, _ := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	defer f.Close()
+
+	// ACT.
+	got := term.ColorEnabled(f)
+
+	// ASSERT.
+	assert.Equal(t, got, false, "ColorEnabled(<regular file>)")
+}
+
+// Benchmark: Render a test run as a tree.
+func BenchmarkRender(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		b.Run(strconv.Itoa(n)+" tests", func(b *testing.B) {
+			testRun, err := xunit.Load(bytes.NewReader(xunittest.Generate(n)))
+
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = term.Render(io.Discard, testRun, term.Options{})
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package set defines a generic set of comparable values.
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Set is a set of comparable values.
+// The zero value is an empty set which can't be modified (use New instead).
+type Set[T comparable] map[T]struct{}
+
+// New returns a set containing elems.
+func New[T comparable](elems ...T) Set[T] {
+	s := make(Set[T], len(elems))
+	s.Add(elems...)
+
+	return s
+}
+
+// Add adds elems to s.
+func (s Set[T]) Add(elems ...T) {
+	for _, e := range elems {
+		s[e] = struct{}{}
+	}
+}
+
+// Has returns true if s contains e.
+func (s Set[T]) Has(e T) bool {
+	_, ok := s[e]
+
+	return ok
+}
+
+// Len returns the number of elements in s.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Union returns a new set containing the elements of s and the elements of other.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	r := make(Set[T], max(len(s), len(other)))
+
+	for e := range s {
+		r[e] = struct{}{}
+	}
+
+	for e := range other {
+		r[e] = struct{}{}
+	}
+
+	return r
+}
+
+// Diff returns a new set containing the elements of s which aren't part of other.
+func (s Set[T]) Diff(other Set[T]) Set[T] {
+	r := make(Set[T])
+
+	for e := range s {
+		if !other.Has(e) {
+			r[e] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+// Sorted returns the elements of s (sorted).
+func Sorted[T cmp.Ordered](s Set[T]) []T {
+	r := make([]T, 0, len(s))
+
+	for e := range s {
+		r = append(r, e)
+	}
+
+	slices.Sort(r)
+
+	return r
+}
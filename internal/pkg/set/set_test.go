@@ -0,0 +1,97 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "set" package.
+package set_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/set"
+)
+
+// UT: Add elements to a set.
+func TestSet_Add(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	s := set.New("Pass")
+
+	// ACT.
+	s.Add("Fail", "Pass", "Skip")
+
+	// ASSERT.
+	got := set.Sorted(s)
+	want := []string{"Fail", "Pass", "Skip"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Add elements to a set.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+
+	assert.Equal(t, s.Has("Fail") && !s.Has("NotRun") && s.Len() == 3, true, "", "\n\n"+
+		"UT Name:    Add elements to a set.\n"+
+		"\033[32mExpected:   A set containing \"Fail\" (but not \"NotRun\"), with 3 elements\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", s)
+}
+
+// UT: Combine 2 sets.
+func TestSet_UnionDiff(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		a, b      set.Set[int]
+		wantUnion []int
+		wantDiff  []int
+	}{
+		{
+			a: set.New(1, 2, 3), b: set.New(3, 4),
+			wantUnion: []int{1, 2, 3, 4},
+			wantDiff:  []int{1, 2},
+		},
+		{
+			a: set.New[int](), b: nil,
+			wantUnion: []int{},
+			wantDiff:  []int{},
+		},
+	} {
+		// ACT.
+		gotUnion, gotDiff := set.Sorted(tc.a.Union(tc.b)), set.Sorted(tc.a.Diff(tc.b))
+
+		// ASSERT.
+		assert.DeepEqual(t, gotUnion, tc.wantUnion, "", "\n\n"+
+			"UT Name:    Combine 2 sets.\n"+
+			"Input:      %v ∪ %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.a, tc.b, tc.wantUnion, gotUnion)
+
+		assert.DeepEqual(t, gotDiff, tc.wantDiff, "", "\n\n"+
+			"UT Name:    Combine 2 sets.\n"+
+			"Input:      %v \\ %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.a, tc.b, tc.wantDiff, gotDiff)
+	}
+}
@@ -0,0 +1,176 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package site contains functions for generating a static website from a directory of .NET test result(s), e.g. for
+// publishing the history of a test suite on GitHub Pages.
+package site
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/html"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Matches the characters which aren't allowed in the file name of a page.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Options controls how a site is generated.
+type Options struct {
+	Title string // The title of the index page (defaults to "Test runs").
+}
+
+// A run is a single result file, loaded from the input directory.
+type run struct {
+	name    string
+	modTime time.Time
+	testRun xunit.TestRun
+}
+
+// Generate loads each XML file (in xUnit's v2+ XML format) in inDir, and writes a static site to outDir.
+// The site consists of an index page (`index.html`) listing the runs (from the most recently modified file to the
+// least recently modified file), and a page per run (in the `runs` directory).
+func Generate(inDir, outDir string, opts Options) error {
+	runs, err := loadRuns(inDir)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(outDir, "runs"), 0o755); err != nil {
+		return err
+	}
+
+	entries := make([]html.IndexEntry, 0, len(runs))
+
+	for _, r := range runs {
+		page := "runs/" + unsafeChars.ReplaceAllString(r.name, "-") + ".html"
+
+		if err := writePage(filepath.Join(outDir, page), func(f *os.File) error {
+			return html.Render(f, r.testRun, html.Options{Title: r.name, IndexURL: "../index.html"})
+		}); err != nil {
+			return err
+		}
+
+		entries = append(entries, html.IndexEntry{Name: r.name, URL: page, Run: r.testRun})
+	}
+
+	if err := writePage(filepath.Join(outDir, "index.html"), func(f *os.File) error {
+		return html.RenderIndex(f, entries, html.Options{Title: opts.Title})
+	}); err != nil {
+		return err
+	}
+
+	// NOTE: Prevent GitHub Pages from processing the site with Jekyll.
+	return os.WriteFile(filepath.Join(outDir, ".nojekyll"), nil, 0o644)
+}
+
+// Returns the runs stored in dir, from the most recently modified file to the least recently modified file.
+func loadRuns(dir string) ([]run, error) {
+	dirEntries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]run, 0, len(dirEntries))
+
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".xml") {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return nil, err
+		}
+
+		testRun, err := loadFile(filepath.Join(dir, entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run{
+			name:    strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			modTime: info.ModTime(),
+			testRun: testRun,
+		})
+	}
+
+	slices.SortStableFunc(runs, func(a, b run) int {
+		if c := b.modTime.Compare(a.modTime); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b.name, a.name)
+	})
+
+	return runs, nil
+}
+
+// Returns the TestRun stored in the file at path.
+func loadFile(path string) (xunit.TestRun, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	defer f.Close()
+
+	testRun, err := xunit.Load(f)
+
+	if err != nil {
+		return xunit.TestRun{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return testRun, nil
+}
+
+// Creates the file at path, and calls render to write its content.
+func writePage(path string, render func(f *os.File) error) error {
+	f, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := render(f); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
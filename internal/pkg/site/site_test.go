@@ -0,0 +1,95 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "site" package.
+package site_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/site"
+)
+
+// UT: Generate a static site from a directory of result files.
+func TestGenerate(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	inDir, outDir := t.TempDir(), t.TempDir()
+	now := time.Now()
+
+	for idx, name := range []string{"run 1.xml", "run-2.XML"} {
+		path := filepath.Join(inDir, name)
+
+		os.WriteFile(path, []byte("<assemblies><assembly name=\"App.dll\" total=\"1\" passed=\"1\" /></assemblies>"), 0o644)
+		os.Chtimes(path, now, now.Add(time.Duration(idx)*time.Hour))
+	}
+
+	os.WriteFile(filepath.Join(inDir, "notes.txt"), []byte("Not a result file."), 0o644)
+
+	// ACT.
+	err := site.Generate(inDir, outDir, site.Options{Title: "History"})
+
+	// ASSERT.
+	assert.NoError(t, err, "Generate()")
+
+	for _, name := range []string{".nojekyll", "runs/run-1.html", "runs/run-2.html"} {
+		_, err := os.Stat(filepath.Join(outDir, name))
+
+		assert.NoError(t, err, "", "\n\n"+
+			"UT Name:    Generate a static site from a directory of result files.\n"+
+			"\033[32mExpected:   File %q exists\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", name, err)
+	}
+
+	index, _ := os.ReadFile(filepath.Join(outDir, "index.html"))
+	run2, run1 := strings.Index(string(index), "runs/run-2.html"), strings.Index(string(index), "runs/run-1.html")
+
+	assert.Equal(t, run2 >= 0 && run1 > run2, true, "", "\n\n"+
+		"UT Name:    Generate a static site from a directory of result files.\n"+
+		"\033[32mExpected:   The index lists `run-2` before `run 1`\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", index)
+}
+
+// UT: Generate a static site from a directory containing an invalid result file.
+func TestGenerateInvalidFile(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	inDir := t.TempDir()
+
+	os.WriteFile(filepath.Join(inDir, "broken.xml"), []byte("{}"), 0o644)
+
+	// ACT.
+	err := site.Generate(inDir, t.TempDir(), site.Options{})
+
+	// ASSERT.
+	assert.NotNil(t, err, "Generate()")
+}
@@ -0,0 +1,106 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package slices defines functions useful with slices of any type.
+package slices
+
+// Map returns a slice containing the result of fn for each element of s (in the same order).
+func Map[S ~[]E, E, R any](s S, fn func(E) R) []R {
+	r := make([]R, 0, len(s))
+
+	for _, e := range s {
+		r = append(r, fn(e))
+	}
+
+	return r
+}
+
+// Filter returns a slice containing the elements of s for which keep returns true (in the same order).
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	var r S
+
+	for _, e := range s {
+		if keep(e) {
+			r = append(r, e)
+		}
+	}
+
+	return r
+}
+
+// Reduce returns the result of calling fn for each element of s (in order), with the result of the previous call (or
+// initial, for the first element).
+func Reduce[S ~[]E, E, R any](s S, initial R, fn func(acc R, e E) R) R {
+	acc := initial
+
+	for _, e := range s {
+		acc = fn(acc, e)
+	}
+
+	return acc
+}
+
+// GroupBy returns the elements of s, grouped by the key returned by key (each group is in the same order as s).
+func GroupBy[S ~[]E, E any, K comparable](s S, key func(E) K) map[K]S {
+	r := make(map[K]S)
+
+	for _, e := range s {
+		k := key(e)
+		r[k] = append(r[k], e)
+	}
+
+	return r
+}
+
+// Unique returns the elements of s without duplicates, in the order in which they're first found.
+func Unique[S ~[]E, E comparable](s S) S {
+	seen := make(map[E]bool, len(s))
+	r := make(S, 0, len(s))
+
+	for _, e := range s {
+		if !seen[e] {
+			seen[e] = true
+			r = append(r, e)
+		}
+	}
+
+	return r
+}
+
+// IndexFunc returns the index of the first element of s for which match returns true, or -1 if there's no such element.
+func IndexFunc[S ~[]E, E any](s S, match func(E) bool) int {
+	for idx, e := range s {
+		if match(e) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// ContainsFunc returns true if match returns true for at least one element of s.
+func ContainsFunc[S ~[]E, E any](s S, match func(E) bool) bool {
+	return IndexFunc(s, match) >= 0
+}
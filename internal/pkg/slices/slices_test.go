@@ -0,0 +1,231 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "slices" package.
+package slices_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/slices"
+)
+
+// UT: Transform each element of a slice.
+func TestMap(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input []int
+		want  []string
+	}{
+		{
+			input: []int{1, 2, 3},
+			want:  []string{"1", "2", "3"},
+		},
+		{
+			input: nil,
+			want:  []string{},
+		},
+	} {
+		// ACT.
+		got := slices.Map(tc.input, strconv.Itoa)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []string) bool { return reflect.DeepEqual(got, want) }, "",
+			"\n\n"+
+				"UT Name:    Transform each element of a slice.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   %q\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Keep the elements of a slice which match a predicate.
+func TestFilter(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input []string
+		want  []string
+	}{
+		{
+			input: []string{"Pass", "Fail", "Skip", "Fail"},
+			want:  []string{"Fail", "Fail"},
+		},
+		{
+			input: []string{"Pass"},
+			want:  nil,
+		},
+	} {
+		// ACT.
+		got := slices.Filter(tc.input, func(result string) bool { return result == "Fail" })
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []string) bool { return reflect.DeepEqual(got, want) }, "",
+			"\n\n"+
+				"UT Name:    Keep the elements of a slice which match a predicate.\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   %q\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Combine the elements of a slice into a single value.
+func TestReduce(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input []int
+		want  int
+	}{
+		{input: []int{1, 2, 3}, want: 106},
+		{input: nil, want: 100},
+	} {
+		// ACT.
+		got := slices.Reduce(tc.input, 100, func(acc, e int) int { return acc + e })
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Combine the elements of a slice into a single value.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %d\033[0m\n"+
+			"\033[31mActual:     %d\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Group the elements of a slice by a key.
+func TestGroupBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input []string
+		want  map[int][]string
+	}{
+		{
+			input: []string{"Pass", "Fail", "Skip", "NotRun", "Pass"},
+			want:  map[int][]string{4: {"Pass", "Fail", "Skip", "Pass"}, 6: {"NotRun"}},
+		},
+		{
+			input: nil,
+			want:  map[int][]string{},
+		},
+	} {
+		// ACT.
+		got := slices.GroupBy(tc.input, func(result string) int { return len(result) })
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Group the elements of a slice by a key.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Remove the duplicates from a slice.
+func TestUnique(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input []string
+		want  []string
+	}{
+		{
+			input: []string{"Owner - Team B", "Category - Unit", "Owner - Team B", "Owner - Team A"},
+			want:  []string{"Owner - Team B", "Category - Unit", "Owner - Team A"},
+		},
+		{
+			input: nil,
+			want:  []string{},
+		},
+	} {
+		// ACT.
+		got := slices.Unique(tc.input)
+
+		// ASSERT.
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Remove the duplicates from a slice.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// UT: Find the first element of a slice which matches a predicate.
+func TestIndexFunc(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input        []string
+		want         int
+		wantContains bool
+	}{
+		{input: []string{"Pass", "Fail", "Fail"}, want: 1, wantContains: true},
+		{input: []string{"Pass", "Skip"}, want: -1},
+		{input: nil, want: -1},
+	} {
+		// ACT.
+		isFailure := func(result string) bool { return result == "Fail" }
+		got, gotContains := slices.IndexFunc(tc.input, isFailure), slices.ContainsFunc(tc.input, isFailure)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Find the first element of a slice which matches a predicate.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Index %d\033[0m\n"+
+			"\033[31mActual:     Index %d\033[0m\n\n", tc.input, tc.want, got)
+
+		assert.Equal(t, gotContains, tc.wantContains, "", "\n\n"+
+			"UT Name:    Find the first element of a slice which matches a predicate.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Contains %t\033[0m\n"+
+			"\033[31mActual:     Contains %t\033[0m\n\n", tc.input, tc.wantContains, gotContains)
+	}
+}
+
+// Benchmark: Remove the duplicates from a slice.
+func BenchmarkUnique(b *testing.B) {
+	input := make([]int, 10000)
+
+	for idx := range input {
+		input[idx] = idx % 100
+	}
+
+	for i := 0; i < b.N; i++ {
+		_ = slices.Unique(input)
+	}
+}
+
+// Benchmark: Transform each element of a slice.
+func BenchmarkMap(b *testing.B) {
+	input := make([]int, 1000)
+
+	for i := 0; i < b.N; i++ {
+		_ = slices.Map(input, func(e int) int { return e * 2 })
+	}
+}
@@ -0,0 +1,127 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package sonar contains functions for writing .NET test result(s) in the generic test execution format of SonarQube,
+// which can be imported with the `sonar.testExecutionReportPaths` analysis parameter.
+// More information regarding this format can be found @
+// https://docs.sonarsource.com/sonarqube/latest/analyzing-source-code/test-coverage/generic-test-data/.
+package sonar
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// A testExecutions is the top-level element of the document.
+type testExecutions struct {
+	XMLName xml.Name `xml:"testExecutions"`
+	Version int      `xml:"version,attr"`
+	Files   []file   `xml:"file"`
+}
+
+// A file contains the tests defined in a single source file.
+type file struct {
+	Path      string     `xml:"path,attr"`
+	TestCases []testCase `xml:"testCase"`
+}
+
+// A testCase contains the result of a single test.
+type testCase struct {
+	Name     string   `xml:"name,attr"`
+	Duration int64    `xml:"duration,attr"`
+	Failure  *problem `xml:"failure"`
+	Error    *problem `xml:"error"`
+	Skipped  *problem `xml:"skipped"`
+}
+
+// A problem contains the details of a failed (or skipped) test.
+type problem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Write writes testRun to w in the generic test execution format of SonarQube.
+// The tests are grouped by source file, in the order in which the files first appear in testRun. Since SonarQube
+// attaches each test to its source file, the tests without a source file are omitted. Failures caused by a failed
+// assertion are reported as failures, and the other failures as errors.
+func Write(w io.Writer, testRun xunit.TestRun) error {
+	doc := testExecutions{Version: 1, Files: make([]file, 0)}
+	byPath := make(map[string]int)
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			if tc.SourceFile == "" {
+				return
+			}
+
+			idx, ok := byPath[tc.SourceFile]
+
+			if !ok {
+				idx = len(doc.Files)
+				byPath[tc.SourceFile] = idx
+				doc.Files = append(doc.Files, file{Path: tc.SourceFile})
+			}
+
+			doc.Files[idx].TestCases = append(doc.Files[idx].TestCases, newTestCase(tc))
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Returns the testCase representation of tc.
+func newTestCase(tc xunit.TestCase) testCase {
+	res := testCase{Name: tc.RawName, Duration: tc.Duration.Milliseconds()}
+
+	switch tc.Result {
+	case "Fail":
+		p := &problem{Message: tc.Failure.Message, Text: tc.Failure.StackTrace}
+
+		if tc.Failure.IsAssertion() {
+			res.Failure = p
+		} else {
+			res.Error = p
+		}
+	case "Skip", "NotRun":
+		res.Skipped = &problem{Message: tc.Reason}
+	}
+
+	return res
+}
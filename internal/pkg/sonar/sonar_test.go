@@ -0,0 +1,115 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "sonar" package.
+package sonar_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/sonar"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Write a test run in the generic test execution format of SonarQube.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    string
+	}{
+		{
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"~/App.dll\" total=\"0\" passed=\"0\" failed=\"0\" skipped=\"0\" time=\"0\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"A test without a source file.\" result=\"Pass\" time=\"0.5\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<testExecutions version=\"1\"></testExecutions>\n",
+		},
+		{
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"~/App.dll\" total=\"4\" passed=\"1\" failed=\"2\" skipped=\"1\" time=\"2.25\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"NS.CalcTests.Adds\" result=\"Pass\" time=\"0.25\" source-file=\"CalcTests.cs\" />\n" +
+				"      <test name=\"NS.MathTests.Divides\" result=\"Fail\" time=\"1\" source-file=\"MathTests.cs\">\n" +
+				"        <failure exception-type=\"System.DivideByZeroException\">\n" +
+				"          <message>Attempted to divide by zero.</message>\n" +
+				"          <stack-trace>at Divides()</stack-trace>\n" +
+				"        </failure>\n" +
+				"      </test>\n" +
+				"      <test name=\"NS.CalcTests.Subtracts\" result=\"Fail\" time=\"1\" source-file=\"CalcTests.cs\">\n" +
+				"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n" +
+				"          <message>Expected: 1 &amp; Actual: 2</message>\n" +
+				"          <stack-trace>at Subtracts()</stack-trace>\n" +
+				"        </failure>\n" +
+				"      </test>\n" +
+				"      <test name=\"NS.CalcTests.Multiplies\" result=\"Skip\" source-file=\"CalcTests.cs\">\n" +
+				"        <reason>Not implemented.</reason>\n" +
+				"      </test>\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<testExecutions version=\"1\">\n" +
+				"  <file path=\"CalcTests.cs\">\n" +
+				"    <testCase name=\"NS.CalcTests.Adds\" duration=\"250\"></testCase>\n" +
+				"    <testCase name=\"NS.CalcTests.Subtracts\" duration=\"1000\">\n" +
+				"      <failure message=\"Expected: 1 &amp; Actual: 2\">at Subtracts()</failure>\n" +
+				"    </testCase>\n" +
+				"    <testCase name=\"NS.CalcTests.Multiplies\" duration=\"0\">\n" +
+				"      <skipped message=\"Not implemented.\"></skipped>\n" +
+				"    </testCase>\n" +
+				"  </file>\n" +
+				"  <file path=\"MathTests.cs\">\n" +
+				"    <testCase name=\"NS.MathTests.Divides\" duration=\"1000\">\n" +
+				"      <error message=\"Attempted to divide by zero.\">at Divides()</error>\n" +
+				"    </testCase>\n" +
+				"  </file>\n" +
+				"</testExecutions>\n",
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(tc.xmlData))
+
+		var sb strings.Builder
+
+		// ACT.
+		err := sonar.Write(&sb, testRun)
+
+		// ASSERT.
+		assert.NoError(t, err, "Write()")
+		assert.Equal(t, sb.String(), tc.want, "", "\n\n"+
+			"UT Name:    Write a test run in the generic test execution format of SonarQube.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.xmlData, tc.want, sb.String())
+	}
+}
@@ -0,0 +1,203 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The version of the REST API of Azure Blob Storage.
+const azureVersion = "2021-08-06"
+
+// AzureConfig configures the container an Azure uploader uploads to.
+type AzureConfig struct {
+	Endpoint  string // The URL of the service ("https://<account>.blob.core.windows.net" if empty).
+	Account   string // The name of the storage account.
+	Key       string // The (base64-encoded) access key of the storage account.
+	Container string // The name of the container.
+
+	Now func() time.Time // Returns the time the requests are signed at (time.Now if nil).
+}
+
+// Azure uploads blobs to a container of Azure Blob Storage, with requests signed with the access key of the storage
+// account (Shared Key authorization).
+type Azure struct {
+	cfg        AzureConfig  // The configuration of the container.
+	httpClient *http.Client // The client which sends the requests.
+}
+
+// NewAzure returns an uploader for the container configured by cfg, which sends its requests with httpClient (or
+// http.DefaultClient if it's nil).
+func NewAzure(cfg AzureConfig, httpClient *http.Client) *Azure {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.Account + ".blob.core.windows.net"
+	}
+
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Azure{cfg: cfg, httpClient: httpClient}
+}
+
+// Upload stores data as the (block) blob with the given key, and returns its URL (with a shared access signature
+// which grants read access to it, if expiry isn't 0).
+func (a *Azure) Upload(ctx context.Context, key, contentType string, data []byte, expiry time.Duration) (string,
+	error,
+) {
+	blobURL := a.blobURL(key)
+	secret, err := base64.StdEncoding.DecodeString(a.cfg.Key)
+
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, blobURL, bytes.NewReader(data))
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Date", a.cfg.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("X-Ms-Version", azureVersion)
+
+	contentLength := ""
+
+	if len(data) > 0 {
+		contentLength = strconv.Itoa(len(data))
+	}
+
+	stringToSign := strings.Join([]string{
+		http.MethodPut,
+		"",            // Content-Encoding.
+		"",            // Content-Language.
+		contentLength, // Content-Length.
+		"",            // Content-MD5.
+		contentType,   // Content-Type.
+		"",            // Date (x-ms-date is used instead).
+		"",            // If-Modified-Since.
+		"",            // If-Match.
+		"",            // If-None-Match.
+		"",            // If-Unmodified-Since.
+		"",            // Range.
+		canonicalizedHeaders(req.Header) + "/" + a.cfg.Account + req.URL.EscapedPath(),
+	}, "\n")
+
+	req.Header.Set("Authorization", "SharedKey "+a.cfg.Account+":"+base64.StdEncoding.EncodeToString(
+		hmacSHA256(secret, stringToSign)))
+
+	if err := send(a.httpClient, req); err != nil {
+		return "", err
+	}
+
+	if expiry == 0 {
+		return blobURL, nil
+	}
+
+	return a.Presign(key, expiry)
+}
+
+// Presign returns the URL of the blob with the given key, with a shared access signature (SAS) which grants read
+// access to it for the given time.
+func (a *Azure) Presign(key string, expiry time.Duration) (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(a.cfg.Key)
+
+	if err != nil {
+		return "", err
+	}
+
+	permissions, resource := "r", "b"
+	expiresAt := a.cfg.Now().UTC().Add(expiry).Format("2006-01-02T15:04:05Z")
+	stringToSign := strings.Join([]string{
+		permissions,
+		"", // The start of the validity.
+		expiresAt,
+		"/blob/" + a.cfg.Account + "/" + a.cfg.Container + "/" + strings.TrimPrefix(key, "/"),
+		"", // The identifier of the stored access policy.
+		"", // The allowed IP addresses.
+		"", // The allowed protocols.
+		azureVersion,
+		resource,
+		"", // The time of the snapshot.
+		"", // The encryption scope.
+		"", // The Cache-Control header of the response.
+		"", // The Content-Disposition header of the response.
+		"", // The Content-Encoding header of the response.
+		"", // The Content-Language header of the response.
+		"", // The Content-Type header of the response.
+	}, "\n")
+
+	query := url.Values{
+		"sv":  {azureVersion},
+		"sr":  {resource},
+		"sp":  {permissions},
+		"se":  {expiresAt},
+		"sig": {base64.StdEncoding.EncodeToString(hmacSHA256(secret, stringToSign))},
+	}
+
+	return a.blobURL(key) + "?" + query.Encode(), nil
+}
+
+// Returns the URL of the blob with the given key.
+func (a *Azure) blobURL(key string) string {
+	return strings.TrimSuffix(a.cfg.Endpoint, "/") + "/" + escape(a.cfg.Container) + "/" +
+		escapePath(strings.TrimPrefix(key, "/"))
+}
+
+// Returns the canonicalized "x-ms-" headers of header (sorted by name, one per line).
+func canonicalizedHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+
+	for name := range header {
+		if strings.HasPrefix(strings.ToLower(name), "x-ms-") {
+			names = append(names, name)
+		}
+	}
+
+	slices.SortFunc(names, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		sb.WriteString(strings.ToLower(name) + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+
+	return sb.String()
+}
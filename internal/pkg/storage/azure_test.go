@@ -0,0 +1,128 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "storage" package.
+package storage_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/storage"
+)
+
+// The access key of the storage account in the tests.
+var azureKey = base64.StdEncoding.EncodeToString([]byte("s3cr3t"))
+
+// Returns the (base64-encoded) HMAC-SHA256 of stringToSign, with the access key of the storage account.
+func azureSignature(stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write([]byte(stringToSign))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// UT: Upload a blob to Azure Blob Storage.
+func TestAzure_Upload(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	var request, authorization, blobType string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, authorization = r.Method+" "+r.URL.EscapedPath(), r.Header.Get("Authorization")
+		blobType = r.Header.Get("X-Ms-Blob-Type")
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	azure := storage.NewAzure(storage.AzureConfig{
+		Endpoint:  srv.URL + "/contoso",
+		Account:   "contoso",
+		Key:       azureKey,
+		Container: "reports",
+		Now:       func() time.Time { return exampleTime },
+	}, srv.Client())
+
+	// ACT.
+	got, err := azure.Upload(context.Background(), "123/report.html", "text/html", []byte("<html>"), 0)
+
+	// ASSERT.
+	assert.NoError(t, err, "Upload()")
+
+	wantAuth := "SharedKey contoso:" + azureSignature("PUT\n\n\n6\n\ntext/html\n\n\n\n\n\n\n"+
+		"x-ms-blob-type:BlockBlob\nx-ms-date:Fri, 24 May 2013 00:00:00 GMT\nx-ms-version:2021-08-06\n"+
+		"/contoso/contoso/reports/123/report.html")
+	ok := request == "PUT /contoso/reports/123/report.html" && blobType == "BlockBlob" && authorization == wantAuth &&
+		got == srv.URL+"/contoso/reports/123/report.html"
+
+	assert.Equal(t, ok, true, "", "\n\n"+
+		"UT Name:    Upload a blob to Azure Blob Storage.\n"+
+		"\033[32mExpected:   A PUT request, authorized with %s\033[0m\n"+
+		"\033[31mActual:     %s %s (%s), and the URL %s\033[0m\n\n", wantAuth, request, blobType, authorization, got)
+}
+
+// UT: Presign the URL of a blob in Azure Blob Storage.
+func TestAzure_Presign(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	azure := storage.NewAzure(storage.AzureConfig{
+		Account:   "contoso",
+		Key:       azureKey,
+		Container: "reports",
+		Now:       func() time.Time { return exampleTime },
+	}, nil)
+
+	// ACT.
+	got, err := azure.Presign("123/report.html", time.Hour)
+
+	// ASSERT.
+	query := url.Values{
+		"sv": {"2021-08-06"},
+		"sr": {"b"},
+		"sp": {"r"},
+		"se": {"2013-05-24T01:00:00Z"},
+		"sig": {azureSignature("r\n\n2013-05-24T01:00:00Z\n/blob/contoso/reports/123/report.html\n\n\n\n2021-08-06\nb" +
+			"\n\n\n\n\n\n\n")},
+	}
+	want := "https://contoso.blob.core.windows.net/reports/123/report.html?" + query.Encode()
+
+	assert.NoError(t, err, "Presign()")
+
+	assert.Equal(t, got, want, "", "\n\n"+
+		"UT Name:    Presign the URL of a blob in Azure Blob Storage.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, got)
+}
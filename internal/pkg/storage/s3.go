@@ -0,0 +1,250 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The layout of the timestamps of AWS Signature Version 4.
+const amzDateLayout = "20060102T150405Z"
+
+// S3Config configures the bucket an S3 uploader uploads to.
+type S3Config struct {
+	Endpoint        string // The URL of the service ("https://s3.<region>.amazonaws.com" if empty).
+	Region          string // The region of the bucket (e.g. "eu-west-1", or "auto" for Google Cloud Storage).
+	Bucket          string // The name of the bucket.
+	PathStyle       bool   // True to address the bucket in the path of the URL, instead of in its host name.
+	AccessKeyID     string // The ID of the access key (or of the HMAC key, for Google Cloud Storage).
+	SecretAccessKey string // The secret of the access key.
+	SessionToken    string // The session token of temporary credentials (if any).
+
+	Now func() time.Time // Returns the time the requests are signed at (time.Now if nil).
+}
+
+// S3 uploads objects to a bucket of Amazon S3 (or of an S3-compatible service), with requests signed with AWS
+// Signature Version 4.
+type S3 struct {
+	cfg        S3Config     // The configuration of the bucket.
+	httpClient *http.Client // The client which sends the requests.
+}
+
+// NewS3 returns an uploader for the bucket configured by cfg, which sends its requests with httpClient (or
+// http.DefaultClient if it's nil).
+func NewS3(cfg S3Config, httpClient *http.Client) *S3 {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &S3{cfg: cfg, httpClient: httpClient}
+}
+
+// Upload stores data as the object with the given key, and returns its URL (presigned, if expiry isn't 0).
+func (s *S3) Upload(ctx context.Context, key, contentType string, data []byte, expiry time.Duration) (string, error) {
+	objectURL, err := s.objectURL(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(data))
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	now := s.cfg.Now().UTC()
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	req.Header.Set("X-Amz-Date", now.Format(amzDateLayout))
+
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	signedHeaders, signature := s.sign(http.MethodPut, objectURL, req.Header, hex.EncodeToString(sum[:]), now)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKeyID+"/"+s.scope(now)+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+
+	if err := send(s.httpClient, req); err != nil {
+		return "", err
+	}
+
+	if expiry == 0 {
+		return objectURL.String(), nil
+	}
+
+	return s.Presign(key, expiry)
+}
+
+// Presign returns a URL which grants access to (GET) the object with the given key, for the given time (at most
+// MaxExpiry).
+func (s *S3) Presign(key string, expiry time.Duration) (string, error) {
+	objectURL, err := s.objectURL(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := s.cfg.Now().UTC()
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.cfg.AccessKeyID + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(amzDateLayout)},
+		"X-Amz-Expires":       {strconv.Itoa(int(min(expiry, MaxExpiry).Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+
+	if s.cfg.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	objectURL.RawQuery = canonicalQuery(query)
+	_, signature := s.sign(http.MethodGet, objectURL, http.Header{}, "UNSIGNED-PAYLOAD", now)
+	objectURL.RawQuery += "&X-Amz-Signature=" + signature
+
+	return objectURL.String(), nil
+}
+
+// Returns the URL of the object with the given key.
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.cfg.Endpoint, "/"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/" + escapePath(strings.TrimPrefix(key, "/"))
+
+	if s.cfg.PathStyle {
+		path = "/" + escape(s.cfg.Bucket) + path
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+
+	u.RawPath = u.EscapedPath() + path
+	u.Path, _ = url.PathUnescape(u.RawPath)
+
+	return u, nil
+}
+
+// Returns the scope of the credentials used at the given time.
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// Returns the signed headers (the host, and the headers starting with "X-Amz-" or "Content-"), and the signature of
+// a request for u with the given method, headers and payload hash, which is sent at the given time.
+func (s *S3) sign(method string, u *url.URL, header http.Header, payloadHash string, now time.Time) (string, string) {
+	headers := map[string]string{"host": u.Host}
+
+	for name, values := range header {
+		name = strings.ToLower(name)
+
+		if strings.HasPrefix(name, "x-amz-") || strings.HasPrefix(name, "content-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		method, u.EscapedPath(), u.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format(amzDateLayout) + "\n" + s.scope(now) + "\n" +
+		hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + s.cfg.SecretAccessKey)
+
+	for _, part := range []string{now.Format("20060102"), s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	return signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// Returns the query string of query, in its canonical form (sorted by key, and URI-encoded).
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	pairs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// Returns the HMAC-SHA256 of data, with the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,110 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package storage uploads (rendered) reports to object storage: Amazon S3 (or any S3-compatible service, such as
+// Google Cloud Storage or MinIO) and Azure Blob Storage.
+//
+// The requests are signed with the credentials of the storage account, and the uploaded objects can be shared with
+// a presigned URL, so CI jobs can link to reports without an artifact server.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The maximum time a presigned URL is valid (which is the maximum supported by S3).
+const MaxExpiry = 7 * 24 * time.Hour
+
+// Uploader uploads objects to a bucket (or container).
+type Uploader interface {
+	// Upload stores data as the object with the given key (e.g. "reports/123/report.html"), and returns the URL of
+	// the object. The URL is presigned (valid for expiry) unless expiry is 0, in which case it's the public URL of
+	// the object.
+	Upload(ctx context.Context, key, contentType string, data []byte, expiry time.Duration) (string, error)
+}
+
+// Error is returned when the storage service responds with an error.
+type Error struct {
+	StatusCode int    // The HTTP status code of the response.
+	Message    string // The body of the response (e.g. an XML document describing the error).
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return fmt.Sprintf("storage: %s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Sends req with httpClient, and returns an error if the response isn't successful.
+func send(httpClient *http.Client, req *http.Request) error {
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+
+	_, err = io.Copy(io.Discard, resp.Body)
+
+	return err
+}
+
+// Returns path (e.g. the key of an object) with each of its segments URI-encoded: every byte except the unreserved
+// characters (A-Z, a-z, 0-9, '-', '.', '_' and '~') is encoded as %XY.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+
+	for idx, segment := range segments {
+		segments[idx] = escape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Returns s, with every byte except the unreserved characters (A-Z, a-z, 0-9, '-', '.', '_' and '~') encoded as %XY.
+func escape(s string) string {
+	var sb strings.Builder
+
+	for _, b := range []byte(s) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || strings.IndexByte("-._~", b) >= 0 {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
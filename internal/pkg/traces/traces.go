@@ -0,0 +1,105 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package traces contains functions for finding the trace files (profiles) of tests, e.g. as written by `dotnet-trace
+// convert --format Speedscope`, so they can be linked from the reports.
+package traces
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The name of the manifest, which lists the trace file of each test.
+const manifestName = "traces.json"
+
+// The extension of the trace files which are found by naming convention.
+const traceExt = ".speedscope.json"
+
+// Matches the characters which aren't allowed in the name of a trace file.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Index contains the paths of the trace files of the tests (relative to the directory they're found in).
+type Index struct {
+	byName map[string]string // The paths of the trace files, by the (raw) name of their test.
+	byFile map[string]string // The paths of the trace files, by their name without extension.
+}
+
+// Find returns the trace files in dir.
+// If dir contains a manifest ("traces.json"), which maps the (raw) names of the tests to the paths of their trace file,
+// only the trace files listed in the manifest are used. Otherwise, each "*.speedscope.json" file in dir (or one of its
+// subdirectories) is the trace file of the test with the same name, where the characters which aren't allowed in a file
+// name are replaced by underscores (e.g. "NS.Class.Test_x_1_" for "NS.Class.Test(x: 1)").
+func Find(dir string) (Index, error) {
+	idx := Index{byName: make(map[string]string), byFile: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+
+	if err == nil {
+		if err := json.Unmarshal(data, &idx.byName); err != nil {
+			return Index{}, fmt.Errorf("traces: %s: %w", manifestName, err)
+		}
+
+		return idx, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return Index{}, err
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), traceExt) {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		idx.byFile[strings.TrimSuffix(d.Name(), traceExt)] = filepath.ToSlash(rel)
+
+		return err
+	})
+
+	return idx, err
+}
+
+// Lookup returns the path of the trace file of the test with the given (raw) name, and false if it doesn't have one.
+func (idx Index) Lookup(name string) (string, bool) {
+	if path, ok := idx.byName[name]; ok {
+		return path, true
+	}
+
+	path, ok := idx.byFile[unsafeChars.ReplaceAllString(name, "_")]
+
+	return path, ok
+}
+
+// Len returns the number of trace files in idx.
+func (idx Index) Len() int {
+	return len(idx.byName) + len(idx.byFile)
+}
@@ -0,0 +1,100 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "traces" package.
+package traces_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/traces"
+)
+
+// UT: Find the trace files of the tests in a directory.
+func TestFind(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		want  map[string]string
+	}{
+		{
+			name: "A directory with trace files named after their test",
+			files: map[string]string{
+				"NS.Class.Test_x_1_.speedscope.json":    "{}",
+				"nested/NS.Class.Other.speedscope.json": "{}",
+				"NS.Class.Ignored.nettrace":             "",
+			},
+			want: map[string]string{
+				"NS.Class.Test(x: 1)": "NS.Class.Test_x_1_.speedscope.json",
+				"NS.Class.Other":      "nested/NS.Class.Other.speedscope.json",
+				"NS.Class.Ignored":    "",
+			},
+		},
+		{
+			name: "A directory with a manifest",
+			files: map[string]string{
+				"traces.json":                    `{"NS.Class.Test(x: 1)": "runs/1.speedscope.json"}`,
+				"NS.Class.Other.speedscope.json": "{}",
+			},
+			want: map[string]string{
+				"NS.Class.Test(x: 1)": "runs/1.speedscope.json",
+				"NS.Class.Other":      "",
+			},
+		},
+	} {
+		// ARRANGE.
+		dir := t.TempDir()
+
+		for name, data := range tc.files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+
+			assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "MkdirAll()")
+			assert.NoError(t, os.WriteFile(path, []byte(data), 0o644), "WriteFile()")
+		}
+
+		// ACT.
+		idx, err := traces.Find(dir)
+
+		// ASSERT.
+		assert.NoError(t, err, "Find()")
+
+		got := make(map[string]string, len(tc.want))
+
+		for name := range tc.want {
+			got[name], _ = idx.Lookup(name)
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Find the trace files of the tests in a directory.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
@@ -0,0 +1,111 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package tui
+
+import "unicode/utf8"
+
+// KeyCode identifies a key pressed by the user.
+type KeyCode int
+
+// The keys which are recognized by the browser.
+const (
+	KeyRune KeyCode = iota // A printable character.
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDown
+	KeyEnter
+	KeyBackspace
+	KeyEsc
+	KeyCtrlC
+)
+
+// Key is a single key press.
+type Key struct {
+	Code KeyCode // The key which was pressed.
+	Rune rune    // The character which was typed (only if Code is KeyRune).
+}
+
+// The keys which are sent as a CSI sequence (`ESC [ <params> <final>`).
+var csiKeys = map[string]KeyCode{
+	"A": KeyUp, "B": KeyDown, "C": KeyRight, "D": KeyLeft, "H": KeyHome, "F": KeyEnd,
+	"1~": KeyHome, "4~": KeyEnd, "5~": KeyPgUp, "6~": KeyPgDown,
+}
+
+// ParseKeys returns the keys encoded in b, which is the raw input read from a terminal.
+// Unknown escape sequences and control characters are ignored.
+func ParseKeys(b []byte) []Key {
+	keys := make([]Key, 0, len(b))
+
+	for len(b) > 0 {
+		switch {
+		case b[0] == 0x1b && len(b) > 2 && (b[1] == '[' || b[1] == 'O'):
+			end := 2
+
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+
+			if end == len(b) {
+				return keys
+			}
+
+			if code, ok := csiKeys[string(b[2:end+1])]; ok {
+				keys = append(keys, Key{Code: code})
+			}
+
+			b = b[end+1:]
+		case b[0] == 0x1b:
+			keys = append(keys, Key{Code: KeyEsc})
+			b = b[1:]
+		case b[0] == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+			b = b[1:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+			b = b[1:]
+		case b[0] == 0x7f || b[0] == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+			b = b[1:]
+		case b[0] < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+
+			if r != utf8.RuneError {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+
+			b = b[size:]
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,78 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "tui" package.
+package tui_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/tui"
+)
+
+// UT: Parse the raw input read from a terminal.
+func TestParseKeys(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input string
+		want  []tui.Key
+	}{
+		{
+			input: "",
+			want:  []tui.Key{},
+		},
+		{
+			input: "aé/",
+			want:  []tui.Key{{Code: tui.KeyRune, Rune: 'a'}, {Code: tui.KeyRune, Rune: 'é'}, {Code: tui.KeyRune, Rune: '/'}},
+		},
+		{
+			input: "\x1b[A\x1b[B\x1b[C\x1b[D\x1bOA\x1b[5~\x1b[6~\x1b[H\x1b[4~",
+			want: []tui.Key{
+				{Code: tui.KeyUp}, {Code: tui.KeyDown}, {Code: tui.KeyRight}, {Code: tui.KeyLeft}, {Code: tui.KeyUp},
+				{Code: tui.KeyPgUp}, {Code: tui.KeyPgDown}, {Code: tui.KeyHome}, {Code: tui.KeyEnd},
+			},
+		},
+		{
+			input: "\x1b\r\x7f\x03\x01\x1b[1;5Ax",
+			want: []tui.Key{
+				{Code: tui.KeyEsc}, {Code: tui.KeyEnter}, {Code: tui.KeyBackspace}, {Code: tui.KeyCtrlC},
+				{Code: tui.KeyRune, Rune: 'x'},
+			},
+		},
+	} {
+		// ACT.
+		got := tui.ParseKeys([]byte(tc.input))
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []tui.Key) bool { return reflect.DeepEqual(got, want) }, "", "\n\n"+
+			"UT Name:    Parse the raw input read from a terminal.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
@@ -0,0 +1,581 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// ANSI escape sequences.
+const (
+	reset   = "\033[0m"
+	bold    = "\033[1m"
+	faint   = "\033[2m"
+	reverse = "\033[7m"
+	red     = "\033[31m"
+	green   = "\033[32m"
+	yellow  = "\033[33m"
+)
+
+// Model is the state of the interactive browser.
+// It's independent of the terminal: keys are fed to Update, and View returns the content of the screen.
+type Model struct {
+	items     []*item // The top-level items (the assemblies).
+	rows      []*item // The items which are currently shown.
+	cursor    int     // The index of the selected row.
+	offset    int     // The index of the first row shown on the screen.
+	width     int     // The width of the screen.
+	height    int     // The height of the screen.
+	filter    string  // Only the items whose label contains this text (case-insensitive) are shown.
+	filtering bool    // True while the filter is being typed, false otherwise.
+	details   bool    // True while the details of the selected test are shown, false otherwise.
+
+	index *xunit.Index // The index the details of the tests are loaded from (nil if the tests contain their details).
+}
+
+// An item is a single row in the tree (an assembly, a group or a test).
+type item struct {
+	label    string
+	depth    int
+	parent   *item
+	children []*item
+	expanded bool
+	test     *xunit.TestCase
+	passed   int
+	failed   int
+	skipped  int
+}
+
+// NewModel returns a Model for browsing testRun on a screen of the given size.
+// Initially, the assemblies and the groups containing failed tests are expanded.
+func NewModel(testRun xunit.TestRun, width, height int) *Model {
+	m := &Model{width: width, height: height}
+
+	for _, assembly := range testRun.Assemblies {
+		a := &item{label: assembly.Name}
+
+		for _, group := range assembly.Tests {
+			if group.Name == "" {
+				addChildren(a, group)
+			} else {
+				addGroup(a, group)
+			}
+		}
+
+		m.items = append(m.items, a)
+	}
+
+	for idx, a := range m.items {
+		count(a)
+		countAssembly(a, testRun.Assemblies[idx])
+
+		a.expanded = true
+	}
+
+	m.refresh()
+
+	return m
+}
+
+// NewIndexModel returns a Model for browsing the test run of index on a screen of the given size.
+// The details of a test are loaded from index when they're shown.
+func NewIndexModel(index *xunit.Index, width, height int) *Model {
+	m := NewModel(index.TestRun, width, height)
+	m.index = index
+
+	return m
+}
+
+// Resize changes the size of the screen.
+func (m *Model) Resize(width, height int) {
+	m.width, m.height = width, height
+
+	m.scroll()
+}
+
+// Update applies the key k to the model.
+// It returns true if the user requested to quit the browser, false otherwise.
+func (m *Model) Update(k Key) bool {
+	if k.Code == KeyCtrlC {
+		return true
+	}
+
+	switch {
+	case m.details:
+		m.updateDetails(k)
+
+		return false
+	case m.filtering:
+		m.updateFilter(k)
+
+		return false
+	}
+
+	switch {
+	case k.Code == KeyRune && k.Rune == 'q':
+		return true
+	case k.Code == KeyUp || k.Code == KeyRune && k.Rune == 'k':
+		m.move(-1)
+	case k.Code == KeyDown || k.Code == KeyRune && k.Rune == 'j':
+		m.move(1)
+	case k.Code == KeyPgUp:
+		m.move(-m.listHeight())
+	case k.Code == KeyPgDown:
+		m.move(m.listHeight())
+	case k.Code == KeyHome:
+		m.move(-len(m.rows))
+	case k.Code == KeyEnd:
+		m.move(len(m.rows))
+	case k.Code == KeyRight || k.Code == KeyRune && k.Rune == 'l':
+		m.expand()
+	case k.Code == KeyLeft || k.Code == KeyRune && k.Rune == 'h':
+		m.collapse()
+	case k.Code == KeyEnter || k.Code == KeyRune && k.Rune == ' ':
+		m.toggle()
+	case k.Code == KeyRune && k.Rune == '/':
+		m.filtering = true
+	case k.Code == KeyEsc:
+		m.setFilter("")
+	}
+
+	return false
+}
+
+// View returns the content of the screen.
+func (m *Model) View() string {
+	lines := make([]string, 0, m.height)
+
+	if m.details {
+		lines = append(lines, m.detailLines()...)
+	} else {
+		lines = append(lines, reverse+pad(m.header(), m.width)+reset)
+
+		for idx := m.offset; idx < len(m.rows) && idx < m.offset+m.listHeight(); idx++ {
+			lines = append(lines, m.rowLine(m.rows[idx], idx == m.cursor))
+		}
+	}
+
+	if len(lines) > m.height-1 {
+		lines = lines[:max(m.height-1, 0)]
+	}
+
+	for len(lines) < m.height-1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, faint+truncate(m.statusLine(), m.width)+reset)
+
+	return strings.Join(lines, "\r\n")
+}
+
+// Applies the key k while the details of a test are shown.
+func (m *Model) updateDetails(k Key) {
+	if k.Code == KeyEsc || k.Code == KeyEnter || k.Code == KeyLeft || k.Code == KeyRune && k.Rune == 'q' {
+		m.details = false
+	}
+}
+
+// Applies the key k while the filter is being typed.
+func (m *Model) updateFilter(k Key) {
+	switch k.Code {
+	case KeyRune:
+		m.setFilter(m.filter + string(k.Rune))
+	case KeyBackspace:
+		if m.filter != "" {
+			_, size := utf8.DecodeLastRuneInString(m.filter)
+
+			m.setFilter(m.filter[:len(m.filter)-size])
+		}
+	case KeyEnter:
+		m.filtering = false
+	case KeyEsc:
+		m.filtering = false
+
+		m.setFilter("")
+	case KeyUp:
+		m.move(-1)
+	case KeyDown:
+		m.move(1)
+	}
+}
+
+// Changes the filter and recomputes the rows which are shown.
+func (m *Model) setFilter(filter string) {
+	m.filter = filter
+
+	m.refresh()
+}
+
+// Moves the cursor delta rows down (or up, if delta is negative).
+func (m *Model) move(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.rows)-1, 0))
+
+	m.scroll()
+}
+
+// Expands the selected group, or moves to its first child if it's already expanded.
+func (m *Model) expand() {
+	sel := m.selected()
+
+	switch {
+	case sel == nil || len(sel.children) == 0:
+		return
+	case !sel.expanded:
+		sel.expanded = true
+
+		m.refresh()
+	default:
+		m.move(1)
+	}
+}
+
+// Collapses the selected group, or moves to its parent if it's already collapsed (or a test).
+func (m *Model) collapse() {
+	sel := m.selected()
+
+	switch {
+	case sel == nil:
+		return
+	case sel.expanded && len(sel.children) > 0 && m.filter == "":
+		sel.expanded = false
+
+		m.refresh()
+	case sel.parent != nil:
+		for idx, row := range m.rows {
+			if row == sel.parent {
+				m.move(idx - m.cursor)
+			}
+		}
+	}
+}
+
+// Toggles the selected group, or shows the details of the selected test.
+func (m *Model) toggle() {
+	sel := m.selected()
+
+	switch {
+	case sel == nil:
+		return
+	case sel.test != nil:
+		m.details = true
+
+		if m.index != nil && sel.test.Handle != 0 {
+			// NOTE: The details are only loaded once, since the handle isn't kept.
+			if tc, err := m.index.Details(*sel.test); err == nil {
+				*sel.test = tc
+				sel.test.Handle = 0
+			}
+		}
+	case len(sel.children) > 0:
+		sel.expanded = !sel.expanded
+
+		m.refresh()
+	}
+}
+
+// Returns the selected item (or nil if no item is shown).
+func (m *Model) selected() *item {
+	if m.cursor >= len(m.rows) {
+		return nil
+	}
+
+	return m.rows[m.cursor]
+}
+
+// Recomputes the rows which are shown, keeping the selected item selected (if it's still shown).
+func (m *Model) refresh() {
+	sel := m.selected()
+
+	m.rows = m.rows[:0]
+
+	for _, a := range m.items {
+		m.addRows(a, false)
+	}
+
+	m.cursor = 0
+
+	for idx, row := range m.rows {
+		if row == sel {
+			m.cursor = idx
+		}
+	}
+
+	m.scroll()
+}
+
+// Adds it (and its children) to the rows which are shown.
+// When filtering, an item is shown if it (or any of its ancestors or descendants) matches the filter; groups are
+// expanded automatically.
+func (m *Model) addRows(it *item, ancestorMatches bool) {
+	if m.filter == "" {
+		m.rows = append(m.rows, it)
+
+		if it.expanded {
+			for _, child := range it.children {
+				m.addRows(child, false)
+			}
+		}
+
+		return
+	}
+
+	matches := ancestorMatches || strings.Contains(strings.ToLower(it.label), strings.ToLower(m.filter))
+
+	if !matches && !m.descendantMatches(it) {
+		return
+	}
+
+	m.rows = append(m.rows, it)
+
+	for _, child := range it.children {
+		m.addRows(child, matches)
+	}
+}
+
+// Returns true if any descendant of it matches the filter, false otherwise.
+func (m *Model) descendantMatches(it *item) bool {
+	for _, child := range it.children {
+		if strings.Contains(strings.ToLower(child.label), strings.ToLower(m.filter)) || m.descendantMatches(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Changes the offset so that the cursor is shown on the screen.
+func (m *Model) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+
+	if h := m.listHeight(); m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+}
+
+// Returns the number of rows which fit on the screen (a line is reserved for the header and the status line).
+func (m *Model) listHeight() int {
+	return max(m.height-2, 1)
+}
+
+// Returns the header of the screen.
+func (m *Model) header() string {
+	var passed, failed, skipped int
+
+	for _, a := range m.items {
+		passed, failed, skipped = passed+a.passed, failed+a.failed, skipped+a.skipped
+	}
+
+	return fmt.Sprintf(" %d assemblies: %d passed, %d failed, %d skipped", len(m.items), passed, failed, skipped)
+}
+
+// Returns the status line of the screen.
+func (m *Model) statusLine() string {
+	switch {
+	case m.details:
+		return "esc back  ctrl+c quit"
+	case m.filtering:
+		return "/" + m.filter + "█"
+	case m.filter != "":
+		return "filter: " + m.filter + "  (esc to clear)  ↑/↓ move  ←/→ collapse/expand  enter details  q quit"
+	default:
+		return "↑/↓ move  ←/→ collapse/expand  enter details  / filter  q quit"
+	}
+}
+
+// Returns the line showing it on the screen.
+func (m *Model) rowLine(it *item, selected bool) string {
+	var b strings.Builder
+
+	b.WriteString(strings.Repeat("  ", it.depth))
+
+	if it.test != nil {
+		color, icon := resultStyle(it.test.Result)
+
+		line := truncate(b.String()+icon+" "+it.label, m.width)
+
+		if selected {
+			return reverse + pad(line, m.width) + reset
+		}
+
+		return strings.Replace(line, icon, color+icon+reset, 1)
+	}
+
+	if it.expanded || m.filter != "" {
+		b.WriteString("▾ ")
+	} else {
+		b.WriteString("▸ ")
+	}
+
+	fmt.Fprintf(&b, "%s (%d passed, %d failed, %d skipped)", it.label, it.passed, it.failed, it.skipped)
+
+	if selected {
+		return reverse + pad(truncate(b.String(), m.width), m.width) + reset
+	}
+
+	return bold + truncate(b.String(), m.width) + reset
+}
+
+// Returns the lines showing the details of the selected test.
+func (m *Model) detailLines() []string {
+	tc := m.selected().test
+	color, icon := resultStyle(tc.Result)
+	lines := []string{
+		reverse + pad(" "+tc.Name, m.width) + reset,
+		"",
+		"Result:    " + color + icon + " " + tc.Result + reset,
+		"Duration:  " + tc.Duration.Round(time.Millisecond).String(),
+	}
+
+	if tc.SourceFile != "" {
+		lines = append(lines, fmt.Sprintf("Source:    %s:%d", tc.SourceFile, tc.SourceLine))
+	}
+
+	if tc.Failure.ExceptionType != "" {
+		lines = append(lines, "Exception: "+tc.Failure.ExceptionType)
+	}
+
+	if tc.Failure.Message != "" {
+		lines = append(lines, "", bold+"Message:"+reset)
+		lines = append(lines, splitLines(tc.Failure.Message, m.width)...)
+	}
+
+	if tc.Failure.StackTrace != "" {
+		lines = append(lines, "", bold+"Stack trace:"+reset)
+		lines = append(lines, splitLines(tc.Failure.StackTrace, m.width)...)
+	}
+
+	return lines
+}
+
+// Adds group as a child of parent.
+func addGroup(parent *item, group *xunit.TestGroup) {
+	child := &item{label: group.Name, depth: parent.depth + 1, parent: parent}
+	parent.children = append(parent.children, child)
+
+	addChildren(child, group)
+}
+
+// Adds the tests and the subgroups of group as children of parent.
+func addChildren(parent *item, group *xunit.TestGroup) {
+	for idx := range group.Tests {
+		tc := group.Tests[idx]
+
+		parent.children = append(parent.children, &item{label: tc.Name, depth: parent.depth + 1, parent: parent, test: &tc})
+	}
+
+	for _, sGroup := range group.Groups {
+		addGroup(parent, sGroup)
+	}
+}
+
+// Computes the counts of it (and its descendants), and expands the groups containing failed tests.
+func count(it *item) {
+	if it.test != nil {
+		switch it.test.Result {
+		case "Pass":
+			it.passed = 1
+		case "Fail":
+			it.failed = 1
+		case "Skip":
+			it.skipped = 1
+		}
+
+		return
+	}
+
+	for _, child := range it.children {
+		count(child)
+
+		it.passed, it.failed, it.skipped = it.passed+child.passed, it.failed+child.failed, it.skipped+child.skipped
+	}
+
+	it.expanded = it.failed > 0
+}
+
+// Computes the counts of it, which represents assembly.
+// Unlike count, it counts the tests which belong to multiple groups (e.g. because they have multiple traits) once.
+func countAssembly(it *item, assembly xunit.Assembly) {
+	it.passed, it.failed, it.skipped = 0, 0, 0
+
+	assembly.Walk(func(_ []string, tc xunit.TestCase) {
+		switch tc.Result {
+		case "Pass":
+			it.passed++
+		case "Fail":
+			it.failed++
+		case "Skip":
+			it.skipped++
+		}
+	})
+}
+
+// Returns the color and the icon representing result.
+func resultStyle(result string) (string, string) {
+	switch result {
+	case "Pass":
+		return green, "✔"
+	case "Fail":
+		return red, "✘"
+	case "Skip":
+		return yellow, "○"
+	default:
+		return faint, "?"
+	}
+}
+
+// Returns the lines of s, each one truncated to width.
+func splitLines(s string, width int) []string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n"), "\n")
+
+	for idx, line := range lines {
+		lines[idx] = truncate(line, width)
+	}
+
+	return lines
+}
+
+// Returns s, truncated to width characters.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	r := []rune(s)
+
+	return string(r[:max(width-1, 0)]) + "…"
+}
+
+// Returns s, padded with spaces to width characters.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
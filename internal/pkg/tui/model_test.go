@@ -0,0 +1,270 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "tui" package.
+package tui_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/tui"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The XML data used by the tests in this file.
+const xmlData = "<assemblies>\n" +
+	"  <assembly name=\"~/App.dll\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A passing test.\" result=\"Pass\" />\n" +
+	"      <test name=\"NS.TestClass+Method.Result\" result=\"Fail\" time=\"1\">\n" +
+	"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n" +
+	"          <message>Expected: 1</message>\n" +
+	"          <stack-trace>at Result()</stack-trace>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"      <test name=\"A slow test.\" result=\"Pass\">\n" +
+	"        <traits>\n" +
+	"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+	"        </traits>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// UT: Browse a test run using the keyboard.
+func TestModel(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Initial state",
+			input: "",
+			want: " 1 assemblies: 2 passed, 1 failed, 0 skipped\n" +
+				"▾ App.dll (2 passed, 1 failed, 0 skipped)\n" +
+				"  ✔ A passing test.\n" +
+				"  ▾ TestClass (0 passed, 1 failed, 0 skipped)\n" +
+				"    ▾ Method (0 passed, 1 failed, 0 skipped)\n" +
+				"      ✘ NS.TestClass+Method.Result\n" +
+				"  ▸ Timing - Slow (1 passed, 0 failed, 0 skipped)\n" +
+				"\n" +
+				"↑/↓ move  ←/→ collapse/expand  enter details  / filter  q quit",
+		},
+		{
+			name:  "Expand a group",
+			input: "\x1b[F\x1b[C",
+			want: " 1 assemblies: 2 passed, 1 failed, 0 skipped\n" +
+				"▾ App.dll (2 passed, 1 failed, 0 skipped)\n" +
+				"  ✔ A passing test.\n" +
+				"  ▾ TestClass (0 passed, 1 failed, 0 skipped)\n" +
+				"    ▾ Method (0 passed, 1 failed, 0 skipped)\n" +
+				"      ✘ NS.TestClass+Method.Result\n" +
+				"  ▾ Timing - Slow (1 passed, 0 failed, 0 skipped)\n" +
+				"    ✔ A slow test.\n" +
+				"↑/↓ move  ←/→ collapse/expand  enter details  / filter  q quit",
+		},
+		{
+			name:  "Collapse a group",
+			input: "jj\x1b[D",
+			want: " 1 assemblies: 2 passed, 1 failed, 0 skipped\n" +
+				"▾ App.dll (2 passed, 1 failed, 0 skipped)\n" +
+				"  ✔ A passing test.\n" +
+				"  ▸ TestClass (0 passed, 1 failed, 0 skipped)\n" +
+				"  ▸ Timing - Slow (1 passed, 0 failed, 0 skipped)\n" +
+				"\n" +
+				"\n" +
+				"\n" +
+				"↑/↓ move  ←/→ collapse/expand  enter details  / filter  q quit",
+		},
+		{
+			name:  "Filter while typing",
+			input: "/SLOW",
+			want: " 1 assemblies: 2 passed, 1 failed, 0 skipped\n" +
+				"▾ App.dll (2 passed, 1 failed, 0 skipped)\n" +
+				"  ▾ Timing - Slow (1 passed, 0 failed, 0 skipped)\n" +
+				"    ✔ A slow test.\n" +
+				"\n" +
+				"\n" +
+				"\n" +
+				"\n" +
+				"/SLOW█",
+		},
+		{
+			name:  "Clear the filter",
+			input: "/SLOW\x1b",
+			want: " 1 assemblies: 2 passed, 1 failed, 0 skipped\n" +
+				"▾ App.dll (2 passed, 1 failed, 0 skipped)\n" +
+				"  ✔ A passing test.\n" +
+				"  ▾ TestClass (0 passed, 1 failed, 0 skipped)\n" +
+				"    ▾ Method (0 passed, 1 failed, 0 skipped)\n" +
+				"      ✘ NS.TestClass+Method.Result\n" +
+				"  ▸ Timing - Slow (1 passed, 0 failed, 0 skipped)\n" +
+				"\n" +
+				"↑/↓ move  ←/→ collapse/expand  enter details  / filter  q quit",
+		},
+		{
+			name:  "Show the details of a test",
+			input: "jjjj\r",
+			want: " NS.TestClass+Method.Result\n" +
+				"\n" +
+				"Result:    ✘ Fail\n" +
+				"Duration:  1s\n" +
+				"Exception: Xunit.Sdk.EqualException\n" +
+				"\n" +
+				"Message:\n" +
+				"Expected: 1\n" +
+				"esc back  ctrl+c quit",
+		},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+		m := tui.NewModel(testRun, 80, 9)
+
+		// ACT.
+		for _, k := range tui.ParseKeys([]byte(tc.input)) {
+			m.Update(k)
+		}
+
+		got := plain(m.View())
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Browse a test run using the keyboard (%s).\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:\033[0m\n%s\n"+
+			"\033[31mActual:\033[0m\n%s\n\n", tc.name, tc.input, tc.want, got)
+	}
+}
+
+// UT: Show the details of a test of an index.
+func TestIndexModel(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	index, _ := xunit.LoadIndex(strings.NewReader(xmlData), xunit.Options{})
+	m := tui.NewIndexModel(index, 80, 9)
+
+	// ACT.
+	for _, k := range tui.ParseKeys([]byte("jjjj\r")) {
+		m.Update(k)
+	}
+
+	got := plain(m.View())
+
+	// ASSERT.
+	want := " NS.TestClass+Method.Result\n" +
+		"\n" +
+		"Result:    ✘ Fail\n" +
+		"Duration:  1s\n" +
+		"Exception: Xunit.Sdk.EqualException\n" +
+		"\n" +
+		"Message:\n" +
+		"Expected: 1\n" +
+		"esc back  ctrl+c quit"
+
+	assert.Equal(t, got, want, "", "\n\n"+
+		"UT Name:    Show the details of a test of an index.\n"+
+		"\033[32mExpected:\033[0m\n%s\n"+
+		"\033[31mActual:\033[0m\n%s\n\n", want, got)
+}
+
+// UT: Browse a test run, with a test which has multiple traits.
+func TestModel_MultipleTraits(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A passing test.\" result=\"Pass\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"Another passing test.\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	m := tui.NewModel(testRun, 80, 9)
+
+	// ACT.
+	got := plain(m.View())
+
+	// ASSERT.
+	want := " 1 assemblies: 2 passed, 0 failed, 0 skipped\n" +
+		"▾ App.dll (2 passed, 0 failed, 0 skipped)\n"
+
+	assert.Contains(t, got, want, "", "\n\n"+
+		"UT Name:    Browse a test run, with a test which has multiple traits.\n"+
+		"\033[32mExpected:   A screen starting with %q\033[0m\n"+
+		"\033[31mActual:\033[0m\n%s\n\n", want, got)
+}
+
+// UT: Quit the browser.
+func TestModelQuit(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		input string
+		want  bool
+	}{
+		{input: "j", want: false},
+		{input: "q", want: true},
+		{input: "\x03", want: true},
+		{input: "/q", want: false},
+	} {
+		// ARRANGE.
+		testRun, _ := xunit.Load(strings.NewReader(xmlData))
+		m := tui.NewModel(testRun, 80, 9)
+		got := false
+
+		// ACT.
+		for _, k := range tui.ParseKeys([]byte(tc.input)) {
+			got = m.Update(k)
+		}
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Quit the browser.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %t\033[0m\n"+
+			"\033[31mActual:     %t\033[0m\n\n", tc.input, tc.want, got)
+	}
+}
+
+// Returns s without ANSI escape sequences, trailing spaces and carriage returns.
+func plain(s string) string {
+	s = regexp.MustCompile("\033\\[[0-9;]*m").ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	return regexp.MustCompile(" +\n").ReplaceAllString(s, "\n")
+}
@@ -0,0 +1,104 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package tui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// The console modes which are required by the browser.
+const (
+	enableProcessedInput            = 0x0001
+	enableLineInput                 = 0x0002
+	enableEchoInput                 = 0x0004
+	enableVirtualTerminalInput      = 0x0200
+	enableVirtualTerminalProcessing = 0x0004
+)
+
+// The functions of the Windows console API which aren't part of the syscall package.
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// Puts the console connected to in and out into raw (virtual terminal) mode.
+// It returns a function which restores the previous mode of the console.
+func makeRaw(in, out *os.File) (func(), error) {
+	var inMode, outMode uint32
+
+	if err := syscall.GetConsoleMode(syscall.Handle(in.Fd()), &inMode); err != nil {
+		return nil, err
+	}
+
+	if err := syscall.GetConsoleMode(syscall.Handle(out.Fd()), &outMode); err != nil {
+		return nil, err
+	}
+
+	rawIn := inMode&^(enableEchoInput|enableLineInput|enableProcessedInput) | enableVirtualTerminalInput
+
+	if err := setConsoleMode(in, rawIn); err != nil {
+		return nil, err
+	}
+
+	if err := setConsoleMode(out, outMode|enableVirtualTerminalProcessing); err != nil {
+		setConsoleMode(in, inMode)
+
+		return nil, err
+	}
+
+	return func() {
+		setConsoleMode(in, inMode)
+		setConsoleMode(out, outMode)
+	}, nil
+}
+
+// Returns the size (number of columns and rows) of the console connected to out.
+func size(out *os.File) (int, int, error) {
+	var info struct {
+		size, cursorPosition     struct{ x, y int16 }
+		attributes               uint16
+		left, top, right, bottom int16
+		maximumWindowSize        struct{ x, y int16 }
+	}
+
+	if r, _, err := procGetConsoleScreenBufferInfo.Call(out.Fd(), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, 0, err
+	}
+
+	return int(info.right-info.left) + 1, int(info.bottom-info.top) + 1, nil
+}
+
+// Sets the mode of the console connected to f.
+func setConsoleMode(f *os.File, mode uint32) error {
+	if r, _, err := procSetConsoleMode.Call(f.Fd(), uintptr(mode)); r == 0 {
+		return err
+	}
+
+	return nil
+}
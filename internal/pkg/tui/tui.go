@@ -0,0 +1,95 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package tui contains an interactive terminal browser for .NET test result(s).
+// The browser supports keyboard navigation, expanding and collapsing groups, showing the failure details of a test and
+// filtering the tests while typing.
+package tui
+
+import (
+	"io"
+	"os"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The size of the screen when it can't be determined.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Run shows the interactive browser for testRun on the terminal connected to in and out, until the user quits.
+func Run(in, out *os.File, testRun xunit.TestRun) error {
+	return run(in, out, NewModel(testRun, defaultWidth, defaultHeight))
+}
+
+// RunIndex is like Run, but shows the test run of index, whose details are loaded when they're shown.
+func RunIndex(in, out *os.File, index *xunit.Index) error {
+	return run(in, out, NewIndexModel(index, defaultWidth, defaultHeight))
+}
+
+// Shows the interactive browser m on the terminal connected to in and out, until the user quits.
+func run(in, out *os.File, m *Model) error {
+	restore, err := makeRaw(in, out)
+
+	if err != nil {
+		return err
+	}
+
+	defer restore()
+
+	if width, height, err := size(out); err == nil {
+		m.Resize(width, height)
+	}
+
+	// Switch to the alternate screen, and hide the cursor.
+	io.WriteString(out, "\033[?1049h\033[?25l")
+	defer io.WriteString(out, "\033[?25h\033[?1049l")
+
+	buf := make([]byte, 256)
+
+	for {
+		if _, err := io.WriteString(out, "\033[H\033[2J"+m.View()); err != nil {
+			return err
+		}
+
+		n, err := in.Read(buf)
+
+		if err != nil {
+			return err
+		}
+
+		if width, height, err := size(out); err == nil {
+			m.Resize(width, height)
+		}
+
+		for _, k := range ParseKeys(buf[:n]) {
+			if m.Update(k) {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package viewer defines the HTTP handler of the DTVisual viewer, which serves interactive HTML reports of .NET test
+// result(s) (and the API exposing them), so the viewer can be mounted inside other applications.
+//
+// The viewer consists of the following pages:
+//
+//	GET /                 The report of all the runs (merged), or a dashboard of the projects if uploads are accepted.
+//	GET /runs/{id}        The report of a single run.
+//	GET /assets/...       The stylesheet and the script of the pages.
+//	GET /api/...          The API (see the "api" package).
+//	GET /api/live         The WebSocket on which the runs are pushed when they change (if changes are subscribed to).
+//
+// All the links between the pages are relative, so the viewer can be mounted under a prefix with http.StripPrefix.
+package viewer
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/render/html"
+	"github.com/kdeconinck/dtvisual/internal/pkg/websocket"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// Options controls the behavior of the viewer.
+type Options struct {
+	// If not nil, result files can be uploaded with the API, and the landing page is a dashboard of the projects
+	// instead of the report of all the runs.
+	Ingest api.Ingest
+
+	// If not nil, the open reports reload whenever a value is received on a channel returned by Subscribe, and the
+	// runs are pushed on /api/live. The returned function cancels the subscription.
+	Subscribe func() (<-chan struct{}, func())
+
+	// The origins (e.g. https://example.com) from which the WebSocket on /api/live can be opened, besides the host of
+	// the viewer itself.
+	AllowedOrigins []string
+
+	// The logger of the problems which can't be reported in a response (nothing is logged if it's nil).
+	Logger *slog.Logger
+}
+
+// Handler returns the handler which serves the viewer of the runs returned by source.
+func Handler(source api.Source) http.Handler {
+	return NewHandler(source, Options{})
+}
+
+// NewHandler returns the handler which serves the viewer of the runs returned by source, as controlled by opts.
+func NewHandler(source api.Source, opts Options) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	mux := http.NewServeMux()
+	pageOpts := html.Options{Interactive: true}
+
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(html.Assets()))))
+	mux.Handle("/api/", api.Handler(source, opts.Ingest))
+
+	if opts.Subscribe != nil {
+		pageOpts.LiveURL = "api/live"
+		mux.Handle("/api/live", newLiveHandler(source, opts))
+	}
+
+	mux.HandleFunc("/runs/", func(w http.ResponseWriter, r *http.Request) {
+		runs, err := source()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/runs/")
+		idx := slices.IndexFunc(runs, func(run api.Run) bool { return run.ID == id })
+
+		if idx == -1 {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		runOpts := pageOpts
+		runOpts.Title, runOpts.IndexURL, runOpts.AssetsURL = runs[idx].Name, "../", "../assets"
+
+		if runOpts.LiveURL != "" {
+			runOpts.LiveURL = "../" + runOpts.LiveURL
+		}
+
+		testRun, err := runs[idx].WithDetails()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		html.Render(w, testRun, runOpts)
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		runs, err := source()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		if opts.Ingest != nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			html.RenderDashboard(w, projects(runs), html.Options{AssetsURL: "assets"})
+
+			return
+		}
+
+		testRuns := make([]xunit.TestRun, 0, len(runs))
+
+		for _, run := range runs {
+			testRun, err := run.WithDetails()
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			testRuns = append(testRuns, testRun)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		rootOpts := pageOpts
+		rootOpts.AssetsURL = "assets"
+
+		html.Render(w, xunit.Merge(testRuns...), rootOpts)
+	})
+
+	return mux
+}
+
+// Returns the runs grouped by project (ordered by name), as shown on the dashboard.
+// The runs which don't belong to a project are grouped in api.DefaultProject.
+func projects(runs []api.Run) []html.Project {
+	byName := make(map[string]*html.Project)
+
+	for _, run := range runs {
+		name := run.Project
+
+		if name == "" {
+			name = api.DefaultProject
+		}
+
+		project, ok := byName[name]
+
+		if !ok {
+			project = &html.Project{Name: name}
+			byName[name] = project
+		}
+
+		project.URL = "runs/" + url.PathEscape(run.ID)
+		project.Runs = append(project.Runs, run.TestRun)
+	}
+
+	resultSet := make([]html.Project, 0, len(byName))
+
+	for _, name := range maps.SortedKeys(byName) {
+		resultSet = append(resultSet, *byName[name])
+	}
+
+	return resultSet
+}
+
+// Returns the handler which upgrades requests to WebSocket connections, on which the runs returned by source are
+// pushed whenever a change is received from the subscription of opts.
+func newLiveHandler(source api.Source, opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: Subscribe before the handshake, so no change is missed once the client is connected.
+		changes, unsubscribe := opts.Subscribe()
+		defer unsubscribe()
+
+		conn, err := websocket.UpgradeWithOptions(w, r, websocket.Options{AllowedOrigins: opts.AllowedOrigins})
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-conn.Done():
+				return
+			case <-changes:
+				runs, err := source()
+
+				// NOTE: A result file might be read while it's being written, so the next change is awaited.
+				if err != nil {
+					opts.Logger.Warn("Failed to reload the test results", "error", err)
+
+					continue
+				}
+
+				var buf bytes.Buffer
+				api.Encode(&buf, runs)
+
+				if err := conn.WriteText(buf.Bytes()); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "viewer" package.
+package viewer_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/api"
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/viewer"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The result file which is exposed by the viewer.
+const xmlData = "<assemblies computer=\"WIN11\">\n" +
+	"  <assembly name=\"~/App.dll\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.5\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"A passing test.\" result=\"Pass\" time=\"0.5\" />\n" +
+	"      <test name=\"A failing test.\" result=\"Fail\" time=\"1\" />\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// Returns a source which exposes a single run (with ID "nightly"), which belongs to the given project.
+func newSource(project string) api.Source {
+	testRun, _ := xunit.Load(strings.NewReader(xmlData))
+
+	return func() ([]api.Run, error) {
+		return []api.Run{{ID: "nightly", Name: "nightly.xml", Project: project, TestRun: testRun}}, nil
+	}
+}
+
+// UT: Serve the viewer, mounted under a prefix.
+func TestHandler(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	mux := http.NewServeMux()
+	mux.Handle("/dtvisual/", http.StripPrefix("/dtvisual", viewer.Handler(newSource(""))))
+
+	for _, tc := range []struct {
+		target   string
+		wantCode int
+		want     string
+	}{
+		{
+			target:   "/dtvisual/",
+			wantCode: http.StatusOK,
+			want:     "<link rel=\"stylesheet\" href=\"assets/report.css\">",
+		},
+		{
+			target:   "/dtvisual/",
+			wantCode: http.StatusOK,
+			want:     "<span class=\"name\">A failing test.</span>",
+		},
+		{
+			target:   "/dtvisual/runs/nightly",
+			wantCode: http.StatusOK,
+			want:     "<script src=\"../assets/report.js\"></script>",
+		},
+		{
+			target:   "/dtvisual/runs/weekly",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			target:   "/dtvisual/assets/report.css",
+			wantCode: http.StatusOK,
+			want:     ":root {",
+		},
+		{
+			target:   "/dtvisual/assets/report.gohtml",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			target:   "/dtvisual/api/runs/nightly",
+			wantCode: http.StatusOK,
+			want:     "\"name\": \"nightly.xml\"",
+		},
+		{
+			target:   "/dtvisual/api/live",
+			wantCode: http.StatusNotFound,
+		},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+
+		// ACT.
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		// ASSERT.
+		assert.Equal(t, rec.Code, tc.wantCode, "", "\n\n"+
+			"UT Name:    Serve the viewer, mounted under a prefix.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d\033[0m\n\n", tc.target, tc.wantCode, rec.Code)
+
+		assert.Contains(t, rec.Body.String(), tc.want, "", "\n\n"+
+			"UT Name:    Serve the viewer, mounted under a prefix.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Body containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.target, tc.want, rec.Body.String())
+	}
+}
+
+// UT: Serve the viewer, with a dashboard of the projects and live updates.
+func TestNewHandler(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	handler := viewer.NewHandler(newSource("api"), viewer.Options{
+		Ingest: func(upload api.Upload, r io.Reader) (api.Run, error) { return api.Run{}, nil },
+		Subscribe: func() (<-chan struct{}, func()) {
+			return make(chan struct{}), func() {}
+		},
+	})
+
+	for _, tc := range []struct {
+		target string
+		want   string
+	}{
+		{target: "/", want: "<td><a href=\"runs/nightly\">api</a></td>"},
+		{target: "/runs/nightly", want: "<body data-live=\"../api/live\">"},
+	} {
+		// ARRANGE.
+		rec := httptest.NewRecorder()
+
+		// ACT.
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		// ASSERT.
+		assert.Contains(t, rec.Body.String(), tc.want, "", "\n\n"+
+			"UT Name:    Serve the viewer, with a dashboard of the projects and live updates.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Body containing %q\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.target, tc.want, rec.Body.String())
+	}
+}
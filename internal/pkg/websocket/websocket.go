@@ -0,0 +1,355 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package websocket implements the server side of the WebSocket protocol (RFC 6455), as far as needed to push messages
+// to browsers.
+//
+// Messages sent by the client are read (so control frames are handled), but their content is discarded.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The GUID which is appended to the key of the client to compute the accept key of the handshake.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The opcodes of the frames.
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// The status code of the close frame sent to a client which violates the protocol.
+const closeProtocolError = 1002
+
+// Returned when a frame sent by the client violates the protocol (e.g. because it isn't masked).
+var errProtocol = errors.New("websocket: protocol error")
+
+// ErrHandshake is returned when a request isn't a valid WebSocket handshake.
+var ErrHandshake = errors.New("websocket: not a valid handshake")
+
+// ErrOrigin is returned when the origin of a WebSocket handshake isn't allowed.
+var ErrOrigin = errors.New("websocket: the origin isn't allowed")
+
+// ErrClosed is returned when writing to a closed connection.
+var ErrClosed = errors.New("websocket: the connection is closed")
+
+// DefaultWriteTimeout is the time in which a message must be written to the client, unless configured otherwise.
+const DefaultWriteTimeout = 10 * time.Second
+
+// Options controls the behavior of a WebSocket connection.
+type Options struct {
+	// The origins (e.g. https://example.com) from which a handshake is accepted, besides the host of the request.
+	AllowedOrigins []string
+
+	// The time in which a message must be written to the client, after which the connection is closed (so a stalled
+	// client doesn't block the sender). If it's 0, DefaultWriteTimeout is used.
+	WriteTimeout time.Duration
+}
+
+// Conn is a WebSocket connection, on which messages can be sent to the client.
+// It's safe to send messages from multiple goroutines.
+type Conn struct {
+	conn    net.Conn      // The underlying connection.
+	timeout time.Duration // The time in which each frame must be written.
+	mu      sync.Mutex    // Serializes the writes.
+	closed  bool          // True if the connection has been closed.
+	done    chan struct{} // Closed when the connection is closed.
+}
+
+// Upgrade upgrades the HTTP connection of r to a WebSocket connection, with the default options.
+// If r isn't a valid WebSocket handshake, a "400 Bad Request" response is written to w and ErrHandshake is returned.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	return UpgradeWithOptions(w, r, Options{})
+}
+
+// UpgradeWithOptions upgrades the HTTP connection of r to a WebSocket connection, as controlled by opts.
+// If r isn't a valid WebSocket handshake, a "400 Bad Request" response is written to w and ErrHandshake is returned.
+// If the origin of r is neither the host of r nor one of the allowed origins, a "403 Forbidden" response is written to
+// w and ErrOrigin is returned.
+func UpgradeWithOptions(w http.ResponseWriter, r *http.Request, opts Options) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+
+		return nil, ErrHandshake
+	}
+
+	// NOTE: Browsers send the origin of the page, so another site can't use the session (or the credentials) of the
+	// user to connect. Clients which aren't browsers don't send it.
+	if origin := r.Header.Get("Origin"); origin != "" && !allowedOrigin(origin, r.Host, opts.AllowedOrigins) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+
+		return nil, ErrOrigin
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		return nil, errors.New("websocket: the connection can't be hijacked")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = DefaultWriteTimeout
+	}
+
+	c := &Conn{conn: conn, timeout: opts.WriteTimeout, done: make(chan struct{})}
+
+	go c.read(rw.Reader)
+
+	return c, nil
+}
+
+// WriteText sends msg to the client as a text message.
+func (c *Conn) WriteText(msg []byte) error {
+	return c.write(opText, msg)
+}
+
+// Close closes the connection (without waiting for the client to acknowledge it).
+func (c *Conn) Close() error {
+	c.write(opClose, nil)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.close()
+}
+
+// Done returns a channel which is closed when the connection is closed (by either side).
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Sends a frame with the given opcode and payload to the client.
+func (c *Conn) write(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	// NOTE: Frames sent by a server are never masked.
+	header := []byte{0x80 | opcode, 0}
+
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	// NOTE: A client which doesn't read its messages fails the write once the deadline passes, and is dropped.
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		c.close()
+
+		return err
+	}
+
+	return nil
+}
+
+// Reads the frames sent by the client until the connection is closed, replying to pings and closes.
+func (c *Conn) read(r *bufio.Reader) {
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.close()
+	}()
+
+	for {
+		opcode, payload, err := readFrame(r)
+
+		if errors.Is(err, errProtocol) {
+			c.write(opClose, binary.BigEndian.AppendUint16(nil, closeProtocolError))
+
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opPing:
+			c.write(opPong, payload)
+		case opClose:
+			c.write(opClose, nil)
+
+			return
+		}
+	}
+}
+
+// Closes the underlying connection. The caller must hold the lock.
+func (c *Conn) close() error {
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	close(c.done)
+
+	return c.conn.Close()
+}
+
+// Returns the opcode and the (unmasked) payload of the next frame read from r.
+// It returns errProtocol if the frame isn't masked, or if its length doesn't fit in 63 bits.
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	// NOTE: RFC 6455 requires the most significant bit of a 64-bit length to be 0, and the frames of a client to be
+	// masked.
+	if length > math.MaxInt64 || header[1]&0x80 == 0 {
+		return 0, nil, errProtocol
+	}
+
+	var mask [4]byte
+
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	// NOTE: The messages of the client are discarded, so only the payload of control frames (at most 125 bytes) is kept.
+	if length > 125 {
+		_, err := io.CopyN(io.Discard, r, int64(length))
+
+		return header[0] & 0x0F, nil, err
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	for idx := range payload {
+		payload[idx] ^= mask[idx%4]
+	}
+
+	return header[0] & 0x0F, payload, nil
+}
+
+// Returns the accept key of the handshake, for the given key of the client.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Returns true if the host of origin is host, or if origin is one of allowed (case-insensitive).
+func allowedOrigin(origin, host string, allowed []string) bool {
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, host) {
+		return true
+	}
+
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if the comma-separated values of the header with the given name contain value (case-insensitive).
+func headerContains(header http.Header, name, value string) bool {
+	for _, field := range header.Values(name) {
+		for _, v := range strings.Split(field, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
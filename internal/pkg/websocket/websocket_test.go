@@ -0,0 +1,340 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "websocket" package.
+package websocket_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/websocket"
+)
+
+// UT: Push messages to a WebSocket client.
+func TestUpgrade(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+
+		if err != nil {
+			return
+		}
+
+		conn.WriteText([]byte("Hello, World!"))
+		<-conn.Done()
+		close(closed)
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+
+	if err != nil {
+		t.Fatalf("net.Dial() = %v, want <nil>", err)
+	}
+
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// ACT.
+	io.WriteString(conn, "GET / HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+
+	if err != nil {
+		t.Fatalf("http.ReadResponse() = %v, want <nil>", err)
+	}
+
+	frame := make([]byte, 15)
+	io.ReadFull(r, frame)
+
+	// NOTE: Send a (masked) close frame, with an all-zero mask.
+	conn.Write([]byte{0x88, 0x80, 0, 0, 0, 0})
+
+	// ASSERT.
+	assert.Equal(t, resp.StatusCode, http.StatusSwitchingProtocols, "", "\n\n"+
+		"UT Name:    Push messages to a WebSocket client.\n"+
+		"\033[32mExpected:   Status code %d\033[0m\n"+
+		"\033[31mActual:     Status code %d\033[0m\n\n", http.StatusSwitchingProtocols, resp.StatusCode)
+
+	// NOTE: The accept key of the example handshake in RFC 6455.
+	gotAccept, wantAccept := resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+
+	assert.Equal(t, gotAccept, wantAccept, "", "\n\n"+
+		"UT Name:    Push messages to a WebSocket client.\n"+
+		"\033[32mExpected:   Sec-WebSocket-Accept: %s\033[0m\n"+
+		"\033[31mActual:     Sec-WebSocket-Accept: %s\033[0m\n\n", wantAccept, gotAccept)
+
+	gotFrame, wantFrame := string(frame), "\x81\x0dHello, World!"
+
+	assert.Equal(t, gotFrame, wantFrame, "", "\n\n"+
+		"UT Name:    Push messages to a WebSocket client.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", wantFrame, gotFrame)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\n\n" +
+			"UT Name:    Push messages to a WebSocket client.\n" +
+			"\033[32mExpected:   The connection is closed when the client closes it.\033[0m\n" +
+			"\033[31mActual:     The connection is still open.\033[0m\n\n")
+	}
+}
+
+// UT: Upgrade a request which isn't a WebSocket handshake.
+func TestUpgrade_InvalidHandshake(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(""))
+
+	// ACT.
+	_, err := websocket.Upgrade(rec, req)
+
+	// ASSERT.
+	assert.Equal(t, err, websocket.ErrHandshake, "", "\n\n"+
+		"UT Name:    Upgrade a request which isn't a WebSocket handshake.\n"+
+		"\033[32mExpected:   %v & status code %d\033[0m\n"+
+		"\033[31mActual:     %v & status code %d\033[0m\n\n", websocket.ErrHandshake, http.StatusBadRequest, err, rec.Code)
+
+	assert.Equal(t, rec.Code, http.StatusBadRequest, "", "\n\n"+
+		"UT Name:    Upgrade a request which isn't a WebSocket handshake.\n"+
+		"\033[32mExpected:   Status code %d\033[0m\n"+
+		"\033[31mActual:     Status code %d\033[0m\n\n", http.StatusBadRequest, rec.Code)
+}
+
+// UT: Upgrade a request from another origin.
+func TestUpgradeWithOptions_Origin(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		origin  string
+		allowed []string
+		want    int
+	}{
+		{origin: "", want: http.StatusSwitchingProtocols},
+		{origin: "http://example.com", want: http.StatusSwitchingProtocols},
+		{origin: "https://EXAMPLE.com", want: http.StatusSwitchingProtocols},
+		{origin: "https://evil.com", want: http.StatusForbidden},
+		{origin: "https://evil.com", allowed: []string{"https://other.com"}, want: http.StatusForbidden},
+		{origin: "https://app.com", allowed: []string{"https://app.com/"}, want: http.StatusSwitchingProtocols},
+		{origin: "null", want: http.StatusForbidden},
+	} {
+		// ARRANGE.
+		rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/", strings.NewReader(""))
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+
+		// ACT.
+		conn, err := websocket.UpgradeWithOptions(rec, req, websocket.Options{AllowedOrigins: tc.allowed})
+
+		if conn != nil {
+			conn.Close()
+		}
+
+		// ASSERT.
+		got := rec.status()
+
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Upgrade a request from another origin.\n"+
+			"Input:      Origin %q, allowed origins %q\n"+
+			"\033[32mExpected:   Status code %d\033[0m\n"+
+			"\033[31mActual:     Status code %d (%v)\033[0m\n\n", tc.origin, tc.allowed, tc.want, got, err)
+	}
+}
+
+// UT: Drop a WebSocket client which doesn't read its messages.
+func TestUpgradeWithOptions_WriteTimeout(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	result := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.UpgradeWithOptions(w, r, websocket.Options{WriteTimeout: 50 * time.Millisecond})
+
+		if err != nil {
+			result <- err
+
+			return
+		}
+
+		msg := make([]byte, 1<<20)
+
+		for {
+			if err := conn.WriteText(msg); err != nil {
+				<-conn.Done()
+				result <- err
+
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+
+	if err != nil {
+		t.Fatalf("net.Dial() = %v, want <nil>", err)
+	}
+
+	defer conn.Close()
+
+	// ACT.
+	io.WriteString(conn, "GET / HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	// ASSERT.
+	select {
+	case err := <-result:
+		var netErr net.Error
+
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("\n\n"+
+				"UT Name:    Drop a WebSocket client which doesn't read its messages.\n"+
+				"\033[32mExpected:   A timeout.\033[0m\n"+
+				"\033[31mActual:     %v\033[0m\n\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\n\n" +
+			"UT Name:    Drop a WebSocket client which doesn't read its messages.\n" +
+			"\033[32mExpected:   The write times out.\033[0m\n" +
+			"\033[31mActual:     The write is still blocked.\033[0m\n\n")
+	}
+}
+
+// UT: Close the connection of a WebSocket client which violates the protocol.
+func TestUpgrade_ProtocolError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// ARRANGE.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, err := websocket.Upgrade(w, r); err == nil {
+			<-conn.Done()
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name  string
+		frame []byte
+	}{
+		{name: "An unmasked frame", frame: []byte{0x81, 0x01, 'a'}},
+		{name: "A 64-bit length with its most significant bit set", frame: []byte{0x82, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+	} {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+
+		if err != nil {
+			t.Fatalf("net.Dial() = %v, want <nil>", err)
+		}
+
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		io.WriteString(conn, "GET / HTTP/1.1\r\n"+
+			"Host: localhost\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+			"Sec-WebSocket-Version: 13\r\n\r\n")
+
+		r := bufio.NewReader(conn)
+
+		if _, err := http.ReadResponse(r, nil); err != nil {
+			t.Fatalf("http.ReadResponse() = %v, want <nil>", err)
+		}
+
+		// ACT.
+		conn.Write(tc.frame)
+
+		got, err := io.ReadAll(r)
+
+		// ASSERT.
+		want := "\x88\x02\x03\xea" // NOTE: A close frame, with status code 1002 (protocol error).
+
+		assert.NoError(t, err, "ReadAll()")
+
+		assert.Equal(t, string(got), want, "", "\n\n"+
+			"UT Name:    Close the connection of a WebSocket client which violates the protocol.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q (after which the connection is closed)\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.name, want, got)
+	}
+}
+
+// A response recorder which can be hijacked (so a handshake can be completed), and which records the status code of
+// the handshake.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+// Hijack returns one end of an in-memory connection, of which the other end is discarded.
+func (rec *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	server, client := net.Pipe()
+	rec.hijacked = true
+
+	go io.Copy(io.Discard, client)
+
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+// Returns the status code of the handshake.
+func (rec *hijackRecorder) status() int {
+	if rec.hijacked {
+		return http.StatusSwitchingProtocols
+	}
+
+	return rec.Code
+}
@@ -0,0 +1,381 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xlsx contains functions for writing .NET test result(s) as an Excel workbook (in the Office Open XML
+// format), e.g. for the people who analyze the results in a spreadsheet.
+// More information regarding this format can be found @
+// https://ecma-international.org/publications-and-standards/standards/ecma-376/.
+package xlsx
+
+import (
+	"archive/zip"
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The number of tests on the sheet with the slowest tests.
+const slowestTests = 50
+
+// The maximum number of characters in a cell (longer texts, e.g. stack traces, are truncated).
+const maxCellLength = 32767
+
+// The parts of the workbook which don't depend on the test run, by their name.
+var staticParts = []struct{ name, content string }{
+	{name: "_rels/.rels", content: xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+		`Target="xl/workbook.xml"/></Relationships>`},
+	{name: "xl/styles.xml", content: xml.Header +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// A sheet is a single worksheet of the workbook.
+type sheet struct {
+	name   string    // The name of the sheet.
+	widths []float64 // The widths of the columns (in characters).
+	header []string  // The titles of the columns.
+	rows   [][]any   // The rows below the header, where each cell is a string, an int or a float64.
+	footer []any     // The row below the rows (e.g. with the totals), which is written in bold (if not nil).
+}
+
+// A locatedTest is a test, and the assembly and the groups it belongs to.
+type locatedTest struct {
+	assembly string // The name of the assembly the test belongs to.
+	group    string // The path of the groups the test belongs to (joined by dots).
+	xunit.TestCase
+}
+
+// Write writes testRun to w as an Excel workbook with 4 sheets: the statistics of each assembly ("Summary"), the
+// failed tests ("Failures"), the slowest tests ("Slowest"), and all the tests ("Full Results").
+// The group of a test is the path of the groups it belongs to (joined by dots), and durations are in seconds.
+func Write(w io.Writer, testRun xunit.TestRun) error {
+	tests := make([]locatedTest, 0)
+
+	for _, assembly := range testRun.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			tests = append(tests, locatedTest{assembly: assembly.Name, group: strings.Join(path, "."), TestCase: tc})
+		})
+	}
+
+	sheets := []sheet{summarySheet(testRun), failuresSheet(tests), slowestSheet(tests), resultsSheet(tests)}
+	zw := zip.NewWriter(w)
+
+	for _, part := range staticParts {
+		if err := writePart(zw, part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	if err := writePart(zw, "[Content_Types].xml", contentTypes(len(sheets))); err != nil {
+		return err
+	}
+
+	if err := writePart(zw, "xl/workbook.xml", workbook(sheets)); err != nil {
+		return err
+	}
+
+	if err := writePart(zw, "xl/_rels/workbook.xml.rels", workbookRels(len(sheets))); err != nil {
+		return err
+	}
+
+	for i, s := range sheets {
+		if err := writePart(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// Returns the sheet with the statistics of each assembly of testRun, and of the whole test run.
+func summarySheet(testRun xunit.TestRun) sheet {
+	s := sheet{
+		name:   "Summary",
+		widths: []float64{40, 10, 10, 10, 10, 10, 10, 14, 14},
+		header: []string{
+			"Assembly", "Total", "Passed", "Failed", "Skipped", "Not run", "Errors", "Pass rate (%)", "Duration (s)",
+		},
+	}
+
+	for _, a := range testRun.Assemblies {
+		run := xunit.TestRun{Assemblies: []xunit.Assembly{a}}
+		s.rows = append(s.rows, statsRow(a.Name, run.Stats()))
+	}
+
+	s.footer = statsRow("Total", testRun.Stats())
+
+	return s
+}
+
+// Returns the cells of a row of the summary, with the given name and statistics.
+func statsRow(name string, stats xunit.Stats) []any {
+	return []any{
+		name, stats.TotalCount, stats.PassedCount, stats.FailedCount, stats.SkippedCount, stats.NotRunCount,
+		stats.ErrorCount, math.Round(stats.PassRate*100) / 100, stats.TotalDuration.Seconds(),
+	}
+}
+
+// Returns the sheet with the failed tests of tests, in the order in which they appear in the test run.
+func failuresSheet(tests []locatedTest) sheet {
+	s := sheet{
+		name:   "Failures",
+		widths: []float64{30, 40, 50, 14, 30, 60, 80},
+		header: []string{"Assembly", "Group", "Test", "Duration (s)", "Exception", "Message", "Stack trace"},
+	}
+
+	for _, t := range tests {
+		if t.Result == "Fail" {
+			s.rows = append(s.rows, []any{
+				t.assembly, t.group, t.Name, t.Duration.Seconds(), t.Failure.ExceptionType, t.Failure.Message,
+				t.Failure.StackTrace,
+			})
+		}
+	}
+
+	return s
+}
+
+// Returns the sheet with the slowest tests of tests, ordered from the slowest to the fastest.
+func slowestSheet(tests []locatedTest) sheet {
+	s := sheet{
+		name:   "Slowest",
+		widths: []float64{30, 40, 50, 10, 14},
+		header: []string{"Assembly", "Group", "Test", "Result", "Duration (s)"},
+	}
+
+	slowest := slices.Clone(tests)
+
+	slices.SortStableFunc(slowest, func(a, b locatedTest) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+
+	for _, t := range slowest[:min(len(slowest), slowestTests)] {
+		s.rows = append(s.rows, []any{t.assembly, t.group, t.Name, t.Result, t.Duration.Seconds()})
+	}
+
+	return s
+}
+
+// Returns the sheet with all the tests of tests, in the order in which they appear in the test run.
+func resultsSheet(tests []locatedTest) sheet {
+	s := sheet{
+		name:   "Full Results",
+		widths: []float64{30, 40, 50, 10, 14, 50, 60},
+		header: []string{"Assembly", "Group", "Test", "Result", "Duration (s)", "Source file", "Message"},
+	}
+
+	for _, t := range tests {
+		message := t.Failure.Message
+
+		if t.Result != "Fail" {
+			message = t.Reason
+		}
+
+		s.rows = append(s.rows, []any{t.assembly, t.group, t.Name, t.Result, t.Duration.Seconds(), t.SourceFile, message})
+	}
+
+	return s
+}
+
+// Returns the worksheet part of s.
+// The header is written in bold, is frozen (so it stays visible while scrolling), and has a filter.
+func (s sheet) xml() string {
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sb.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	sb.WriteString(`<cols>`)
+
+	for i, width := range s.widths {
+		fmt.Fprintf(&sb, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+	}
+
+	sb.WriteString(`</cols><sheetData>`)
+
+	header := make([]any, 0, len(s.header))
+
+	for _, title := range s.header {
+		header = append(header, title)
+	}
+
+	writeRow(&sb, 1, header, true)
+
+	for i, row := range s.rows {
+		writeRow(&sb, i+2, row, false)
+	}
+
+	if s.footer != nil {
+		writeRow(&sb, len(s.rows)+2, s.footer, true)
+	}
+
+	fmt.Fprintf(&sb, `</sheetData><autoFilter ref="A1:%s%d"/></worksheet>`, column(len(s.header)-1), len(s.rows)+1)
+
+	return sb.String()
+}
+
+// Writes the row with the given (1-based) number and cells to sb, in bold if bold is true.
+func writeRow(sb *strings.Builder, number int, cells []any, bold bool) {
+	style := ""
+
+	if bold {
+		style = ` s="1"`
+	}
+
+	fmt.Fprintf(sb, `<row r="%d">`, number)
+
+	for i, cell := range cells {
+		ref := column(i) + strconv.Itoa(number)
+
+		switch v := cell.(type) {
+		case int:
+			fmt.Fprintf(sb, `<c r="%s"%s><v>%d</v></c>`, ref, style, v)
+		case float64:
+			fmt.Fprintf(sb, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			if v == "" {
+				continue
+			}
+
+			fmt.Fprintf(sb, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+			xml.EscapeText(sb, []byte(truncate(v)))
+			sb.WriteString(`</t></is></c>`)
+		}
+	}
+
+	sb.WriteString(`</row>`)
+}
+
+// Returns the name of the column with the given (0-based) index (e.g. "A" for 0, and "AA" for 26).
+func column(idx int) string {
+	name := ""
+
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+
+	return name
+}
+
+// Returns s, truncated to the maximum number of characters in a cell.
+func truncate(s string) string {
+	if runes := []rune(s); len(runes) > maxCellLength {
+		return string(runes[:maxCellLength])
+	}
+
+	return s
+}
+
+// Returns the content types part of a workbook with the given number of sheets.
+func contentTypes(sheets int) string {
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&sb, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	sb.WriteString(`</Types>`)
+
+	return sb.String()
+}
+
+// Returns the workbook part, which lists sheets.
+func workbook(sheets []sheet) string {
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+
+	for i, s := range sheets {
+		fmt.Fprintf(&sb, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, s.name, i+1, i+1)
+	}
+
+	sb.WriteString(`</sheets></workbook>`)
+
+	return sb.String()
+}
+
+// Returns the relationships of the workbook part with the given number of sheets (and its styles).
+func workbookRels(sheets int) string {
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&sb, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+
+	fmt.Fprintf(&sb, `<Relationship Id="rId%d" `+
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`,
+		sheets+1)
+	sb.WriteString(`</Relationships>`)
+
+	return sb.String()
+}
+
+// Writes a part with the given name and content to zw.
+func writePart(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, content)
+
+	return err
+}
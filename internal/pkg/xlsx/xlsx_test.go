@@ -0,0 +1,147 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xlsx" package.
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xlsx"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Write a test run as an Excel workbook.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"~/App.dll\" total=\"3\" passed=\"1\" failed=\"1\" skipped=\"1\" time=\"3.5\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.Calculator+Math.Adds\" type=\"NS.Calculator+Math\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.Calculator+Math.Divides\" type=\"NS.Calculator+Math\" result=\"Fail\" time=\"3\">\n" +
+		"        <failure exception-type=\"DivideByZeroException\">\n" +
+		"          <message>Expected: 1 &lt; 2</message>\n" +
+		"          <stack-trace>at Divides()</stack-trace>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Calculator+Math.Skips\" type=\"NS.Calculator+Math\" result=\"Skip\" time=\"0\">\n" +
+		"        <reason>Not yet</reason>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	var buf bytes.Buffer
+
+	// ACT.
+	err := xlsx.Write(&buf, testRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "Write()")
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	assert.NoError(t, err, "NewReader()")
+
+	parts := make(map[string]string)
+
+	for _, f := range zr.File {
+		rdr, _ := f.Open()
+		data, _ := io.ReadAll(rdr)
+		parts[f.Name] = string(data)
+
+		assert.NoError(t, xml.Unmarshal(data, new(struct{})), "", "\n\n"+
+			"UT Name:    Write a test run as an Excel workbook.\n"+
+			"\033[32mExpected:   A well-formed XML part\033[0m\n"+
+			"\033[31mActual:     %s: %s\033[0m\n\n", f.Name, data)
+	}
+
+	for _, tc := range []struct {
+		part string
+		want []string
+	}{
+		{
+			part: "[Content_Types].xml",
+			want: []string{`<Override PartName="/xl/worksheets/sheet4.xml"`},
+		},
+		{
+			part: "xl/workbook.xml",
+			want: []string{
+				`<sheet name="Summary" sheetId="1" r:id="rId1"/>`,
+				`<sheet name="Failures" sheetId="2" r:id="rId2"/>`,
+				`<sheet name="Slowest" sheetId="3" r:id="rId3"/>`,
+				`<sheet name="Full Results" sheetId="4" r:id="rId4"/>`,
+			},
+		},
+		{
+			part: "xl/worksheets/sheet1.xml",
+			want: []string{
+				`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Assembly</t></is></c>`,
+				`<c r="A2" t="inlineStr"><is><t xml:space="preserve">App.dll</t></is></c><c r="B2"><v>3</v></c>`,
+				`<c r="H3" s="1"><v>50</v></c><c r="I3" s="1"><v>3.5</v></c></row>`,
+				`<autoFilter ref="A1:I2"/>`,
+			},
+		},
+		{
+			part: "xl/worksheets/sheet2.xml",
+			want: []string{
+				`<t xml:space="preserve">Calculator.Math</t>`,
+				`<c r="D2"><v>3</v></c>` +
+					`<c r="E2" t="inlineStr"><is><t xml:space="preserve">DivideByZeroException</t></is></c>`,
+				`<t xml:space="preserve">Expected: 1 &lt; 2</t>`,
+				`<t xml:space="preserve">at Divides()</t>`,
+			},
+		},
+		{
+			part: "xl/worksheets/sheet3.xml",
+			want: []string{
+				`<c r="D2" t="inlineStr"><is><t xml:space="preserve">Fail</t></is></c><c r="E2"><v>3</v></c>`,
+				`<c r="D3" t="inlineStr"><is><t xml:space="preserve">Pass</t></is></c><c r="E3"><v>0.5</v></c>`,
+			},
+		},
+		{
+			part: "xl/worksheets/sheet4.xml",
+			want: []string{
+				`<c r="G4" t="inlineStr"><is><t xml:space="preserve">Not yet</t></is></c>`,
+				`<autoFilter ref="A1:G4"/>`,
+			},
+		},
+	} {
+		for _, want := range tc.want {
+			assert.Contains(t, parts[tc.part], want, "", "\n\n"+
+				"UT Name:    Write a test run as an Excel workbook.\n"+
+				"Input:      %s\n"+
+				"\033[32mExpected:   Part containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.part, want, parts[tc.part])
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/set"
+)
+
+// Diagnostics lists the deviations of a document from xUnit's v2+ XML format, which didn't prevent loading it (e.g. a
+// producer which doesn't write the duration of its tests).
+// The attributes are identified as "<element>/@<attribute>" (e.g. "test/@time"), and the elements as
+// "<parent>/<element>" (e.g. "test/attachments").
+type Diagnostics struct {
+	MissingAttributes map[string]int // The number of elements without an expected attribute, per attribute.
+	UnknownAttributes map[string]int // The number of elements with an unexpected attribute, per attribute.
+	SkippedElements   map[string]int // The number of unexpected elements which were skipped, per element.
+}
+
+// An element describes an element of xUnit's v2+ XML format.
+type element struct {
+	required set.Set[string] // The attributes which are expected on the element.
+	optional set.Set[string] // The attributes which may be present on the element.
+	children set.Set[string] // The elements which may be present in the element.
+}
+
+// The elements of xUnit's v2+ XML format, by name.
+var format = map[string]element{
+	"assemblies": {
+		optional: set.New("computer", "finish-rtf", "id", "schema-version", "start-rtf", "timestamp", "user"),
+		children: set.New("assembly"),
+	},
+	"assembly": {
+		required: set.New("name", "run-date", "run-time", "time", "total", "passed", "failed", "skipped"),
+		optional: set.New("config-file", "environment", "errors", "finish-rtf", "id", "not-run", "start-rtf",
+			"target-framework", "test-framework", "time-rtf"),
+		children: set.New("collection", "errors"),
+	},
+	"collection": {
+		required: set.New("name", "time", "total", "passed", "failed", "skipped"),
+		optional: set.New("id", "not-run", "time-rtf"),
+		children: set.New("test"),
+	},
+	"test": {
+		required: set.New("name", "type", "method", "time", "result"),
+		optional: set.New("id", "source-file", "source-line", "time-rtf"),
+		children: set.New("failure", "output", "reason", "traits", "warnings"),
+	},
+	"failure":     {optional: set.New("exception-type"), children: set.New("message", "stack-trace")},
+	"message":     {},
+	"stack-trace": {},
+	"output":      {},
+	"reason":      {},
+	"traits":      {children: set.New("trait")},
+	"trait":       {required: set.New("name", "value")},
+	"warnings":    {children: set.New("warning")},
+	"warning":     {},
+	"errors":      {children: set.New("error")},
+	"error":       {optional: set.New("name", "type"), children: set.New("failure")},
+}
+
+// Empty returns true if the document doesn't deviate from xUnit's v2+ XML format.
+func (d Diagnostics) Empty() bool {
+	return len(d.MissingAttributes)+len(d.UnknownAttributes)+len(d.SkippedElements) == 0
+}
+
+// Returns the sum of the counts of d and other.
+func (d Diagnostics) merge(other Diagnostics) Diagnostics {
+	sum := func(a, b map[string]int) map[string]int {
+		var res map[string]int
+
+		for _, m := range []map[string]int{a, b} {
+			for key, count := range m {
+				res = increment(res, key, count)
+			}
+		}
+
+		return res
+	}
+
+	return Diagnostics{
+		MissingAttributes: sum(d.MissingAttributes, other.MissingAttributes),
+		UnknownAttributes: sum(d.UnknownAttributes, other.UnknownAttributes),
+		SkippedElements:   sum(d.SkippedElements, other.SkippedElements),
+	}
+}
+
+// Returns the deviations of the document (which may be truncated) in rdr from xUnit's v2+ XML format.
+func diagnose(rdr io.Reader) Diagnostics {
+	var diag Diagnostics
+
+	dec := xml.NewDecoder(rdr)
+	path := []string{""}
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return diag
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			name, parent := tok.Name.Local, path[len(path)-1]
+			el, known := format[name]
+
+			if parent != "" {
+				known = known && format[parent].children.Has(name)
+			}
+
+			if !known {
+				diag.SkippedElements = increment(diag.SkippedElements, parent+"/"+name, 1)
+
+				if dec.Skip() != nil {
+					return diag
+				}
+
+				continue
+			}
+
+			present := set.New[string]()
+
+			for _, attr := range tok.Attr {
+				// NOTE: Namespace declarations aren't attributes of the format.
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+
+				present.Add(attr.Name.Local)
+
+				if !el.required.Has(attr.Name.Local) && !el.optional.Has(attr.Name.Local) {
+					diag.UnknownAttributes = increment(diag.UnknownAttributes, name+"/@"+attr.Name.Local, 1)
+				}
+			}
+
+			for attr := range el.required.Diff(present) {
+				diag.MissingAttributes = increment(diag.MissingAttributes, name+"/@"+attr, 1)
+			}
+
+			path = append(path, name)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// Returns m (allocated if it's nil), with n added to the count of key.
+func increment(m map[string]int, key string, n int) map[string]int {
+	if m == nil {
+		m = make(map[string]int)
+	}
+
+	m[key] += n
+
+	return m
+}
@@ -0,0 +1,126 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Report the deviations of a document from xUnit's v2+ XML format.
+func TestLoad_Diagnostics(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name      string
+		xmlData   string
+		want      xunit.Diagnostics
+		wantEmpty bool
+	}{
+		{
+			name: "A document which follows the format",
+			xmlData: "<assemblies xmlns:x=\"urn:x\" timestamp=\"07/10/2023 20:53:19\">\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"1\" " +
+				"passed=\"1\" failed=\"0\" skipped=\"0\">\n" +
+				"    <collection name=\"C\" time=\"1\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\">\n" +
+				"      <test name=\"T\" type=\"NS.C\" method=\"T\" time=\"1\" result=\"Pass\">\n" +
+				"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+				"      </test>\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			wantEmpty: true,
+		},
+		{
+			name: "A document which deviates from the format",
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"2\" " +
+				"passed=\"2\" failed=\"0\" skipped=\"0\" color=\"red\">\n" +
+				"    <collection name=\"C\" time=\"1\" total=\"2\" passed=\"2\" failed=\"0\" skipped=\"0\">\n" +
+				"      <test name=\"T1\" type=\"NS.C\" method=\"T1\" result=\"Pass\">\n" +
+				"        <attachments><attachment name=\"log\" /></attachments>\n" +
+				"      </test>\n" +
+				"      <test name=\"T2\" type=\"NS.C\" method=\"T2\" result=\"Pass\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"  <summary />\n" +
+				"</assemblies>",
+			want: xunit.Diagnostics{
+				MissingAttributes: map[string]int{"test/@time": 2},
+				UnknownAttributes: map[string]int{"assembly/@color": 1},
+				SkippedElements:   map[string]int{"test/attachments": 1, "assemblies/summary": 1},
+			},
+		},
+	} {
+		// ACT.
+		testRun, err := xunit.Load(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "Load()")
+
+		assert.Equal(t, testRun.Diagnostics.Empty(), tc.wantEmpty, "", "\n\n"+
+			"UT Name:    Report the deviations of a document from xUnit's v2+ XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Empty = %t\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.wantEmpty, testRun.Diagnostics)
+
+		assert.DeepEqual(t, testRun.Diagnostics, tc.want, "", "\n\n"+
+			"UT Name:    Report the deviations of a document from xUnit's v2+ XML format.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, testRun.Diagnostics)
+	}
+}
+
+// UT: Merge the diagnostics of multiple test runs.
+func TestMerge_Diagnostics(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	first := xunit.TestRun{Diagnostics: xunit.Diagnostics{MissingAttributes: map[string]int{"test/@time": 2}}}
+	second := xunit.TestRun{Diagnostics: xunit.Diagnostics{
+		MissingAttributes: map[string]int{"test/@time": 1, "test/@type": 1},
+		SkippedElements:   map[string]int{"test/attachments": 1},
+	}}
+
+	// ACT.
+	got := xunit.Merge(first, second).Diagnostics
+
+	// ASSERT.
+	want := xunit.Diagnostics{
+		MissingAttributes: map[string]int{"test/@time": 3, "test/@type": 1},
+		SkippedElements:   map[string]int{"test/attachments": 1},
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Merge the diagnostics of multiple test runs.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
@@ -0,0 +1,282 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/slices"
+)
+
+// Filter selects the tests of a test run which match an expression (see ParseFilter).
+type Filter struct {
+	expr   string         // The expression of the filter.
+	field  string         // The field of the tests which is compared ("trait", "result", "name" or "assembly").
+	trait  string         // The name of the trait which is compared (only if field is "trait").
+	op     string         // The operator ("=", "!=", "~" or "!~"), or an empty string if the trait must be present.
+	values []string       // The values which are compared by "=" and "!=".
+	re     *regexp.Regexp // The regular expression which is matched by "~" and "!~".
+}
+
+// ParseFilter returns the filter described by expr, which compares a field of the tests with a value:
+//   - "<field>=<values>" matches the tests of which the field is one of the comma-separated values,
+//   - "<field>~<regexp>" matches the tests of which (a part of) the field matches the regular expression,
+//   - and "!=" and "!~" match the tests which don't match "=" and "~".
+//
+// The fields are "result" (e.g. "result=Fail,Skip"), "name" (the name of the test, as written in the document or in
+// human-readable format, e.g. "name~Login.*"), "assembly" and "trait:<name>" (the value of a trait, e.g.
+// "trait:Category=Integration"). A trait without operator (e.g. "trait:Category") matches the tests with that trait.
+// The values (but not the regular expressions) are compared case-insensitively.
+func ParseFilter(expr string) (Filter, error) {
+	f := Filter{expr: expr}
+	field, value := expr, ""
+
+	if idx := strings.IndexAny(expr, "=~"); idx >= 0 {
+		field, f.op, value = expr[:idx], expr[idx:idx+1], expr[idx+1:]
+
+		if strings.HasSuffix(field, "!") {
+			field, f.op = field[:len(field)-1], "!"+f.op
+		}
+	}
+
+	field = strings.TrimSpace(field)
+
+	if name, ok := strings.CutPrefix(field, "trait:"); ok {
+		f.field, f.trait = "trait", strings.TrimSpace(name)
+
+		if f.trait == "" {
+			return Filter{}, fmt.Errorf("xunit: invalid filter %q: missing the name of the trait", expr)
+		}
+	} else {
+		f.field = strings.ToLower(field)
+	}
+
+	switch {
+	case f.field != "trait" && f.field != "result" && f.field != "name" && f.field != "assembly":
+		return Filter{}, fmt.Errorf("xunit: invalid filter %q: unknown field %q (expected trait:<name>, result, name "+
+			"or assembly)", expr, field)
+	case f.op == "" && f.field != "trait":
+		return Filter{}, fmt.Errorf("xunit: invalid filter %q: expected %s=<value> or %s~<regexp>", expr, field, field)
+	case f.op == "~" || f.op == "!~":
+		re, err := regexp.Compile(value)
+
+		if err != nil {
+			return Filter{}, fmt.Errorf("xunit: invalid filter %q: %w", expr, err)
+		}
+
+		f.re = re
+	case f.op != "":
+		for _, v := range strings.Split(value, ",") {
+			f.values = append(f.values, strings.TrimSpace(v))
+		}
+	}
+
+	if f.field == "result" && f.re == nil {
+		for _, v := range f.values {
+			if !results.Has(resultName(v)) {
+				return Filter{}, fmt.Errorf("xunit: invalid filter %q: unknown result %q (expected Pass, Fail, Skip "+
+					"or NotRun)", expr, v)
+			}
+		}
+	}
+
+	return f, nil
+}
+
+// String returns the expression of f.
+func (f Filter) String() string {
+	return f.expr
+}
+
+// Filter returns a copy of the test run which only contains the tests that match all the filters, without the
+// (sub)groups, the collections and the assemblies which don't contain any of them (except the assemblies with
+// environmental errors, which are kept so the errors are still reported). The counts (and the durations) of the
+// assemblies and their collections are those of the tests they contain.
+func (testRun *TestRun) Filter(filters ...Filter) TestRun {
+	res := *testRun
+
+	if len(filters) == 0 {
+		return res
+	}
+
+	res.Assemblies = make([]Assembly, 0, len(testRun.Assemblies))
+
+	for _, assembly := range testRun.Assemblies {
+		matches := make(map[testKey]bool)
+
+		keep := func(tc TestCase) bool {
+			match, ok := matches[tc.key()]
+
+			if !ok {
+				match = true
+
+				for _, f := range filters {
+					match = match && f.match(assembly.Name, tc)
+				}
+
+				matches[tc.key()] = match
+			}
+
+			return match
+		}
+
+		assembly.Tests = filterGroups(assembly.Tests, keep, true)
+		assembly.recount()
+
+		if assembly.TotalCount > 0 || assembly.ErrorCount > 0 {
+			res.Assemblies = append(res.Assemblies, assembly)
+		}
+	}
+
+	return res
+}
+
+// Returns true if tc (a test of assembly) matches f.
+func (f Filter) match(assembly string, tc TestCase) bool {
+	var candidates []string
+
+	switch f.field {
+	case "result":
+		candidates = []string{tc.Result}
+	case "name":
+		candidates = []string{tc.RawName, tc.Name}
+	case "assembly":
+		candidates = []string{assembly}
+	case "trait":
+		for _, t := range tc.Traits {
+			if strings.EqualFold(t.Name, f.trait) {
+				candidates = append(candidates, t.Value)
+			}
+		}
+	}
+
+	if f.op == "" {
+		return len(candidates) > 0
+	}
+
+	match := false
+
+	for _, candidate := range candidates {
+		if f.re != nil {
+			match = match || f.re.MatchString(candidate)
+
+			continue
+		}
+
+		for _, v := range f.values {
+			match = match || strings.EqualFold(candidate, v)
+		}
+	}
+
+	return match != strings.HasPrefix(f.op, "!")
+}
+
+// Returns the groups which contain the tests of groups for which keep returns true (and those tests only). The
+// unnamed group is kept when top is true, even if it doesn't contain any tests.
+func filterGroups(groups []*TestGroup, keep func(tc TestCase) bool, top bool) []*TestGroup {
+	resultSet := make([]*TestGroup, 0, len(groups))
+
+	for _, group := range groups {
+		filtered := &TestGroup{Name: group.Name, Tests: make([]TestCase, 0, len(group.Tests))}
+
+		for _, tc := range group.Tests {
+			if keep(tc) {
+				filtered.Tests = append(filtered.Tests, tc)
+			}
+		}
+
+		if len(group.Groups) > 0 {
+			filtered.Groups = filterGroups(group.Groups, keep, false)
+		}
+
+		if len(filtered.Tests) > 0 || len(filtered.Groups) > 0 || (top && group.Name == "") {
+			resultSet = append(resultSet, filtered)
+		}
+	}
+
+	return resultSet
+}
+
+// Sets the counts (and the duration) of the assembly, and of its collections, to those of the tests it contains. The
+// collections which don't contain any tests are removed.
+func (assembly *Assembly) recount() {
+	assembly.PassedCount, assembly.FailedCount, assembly.SkippedCount, assembly.NotRunCount = 0, 0, 0, 0
+	assembly.TotalCount, assembly.Duration = 0, 0
+
+	collections := make([]Collection, len(assembly.Collections))
+	byName := make(map[string]*Collection, len(collections))
+
+	for idx, c := range assembly.Collections {
+		collections[idx] = Collection{ID: c.ID, Name: c.Name}
+
+		if _, ok := byName[c.Name]; !ok {
+			byName[c.Name] = &collections[idx]
+		}
+	}
+
+	assembly.Walk(func(_ []string, tc TestCase) {
+		c := byName[tc.Collection]
+
+		if c == nil {
+			c = &Collection{}
+		}
+
+		switch tc.Result {
+		case "Pass":
+			assembly.PassedCount++
+			c.PassedCount++
+		case "Fail":
+			assembly.FailedCount++
+			c.FailedCount++
+		case "Skip":
+			assembly.SkippedCount++
+			c.SkippedCount++
+		case "NotRun":
+			assembly.NotRunCount++
+			c.NotRunCount++
+		}
+
+		assembly.TotalCount++
+		assembly.Duration += tc.Duration
+		c.TotalCount++
+		c.Duration += tc.Duration
+	})
+
+	assembly.Collections = slices.Filter(collections, func(c Collection) bool { return c.TotalCount > 0 })
+}
+
+// Returns the result r (e.g. "fail") as it's written in the document (e.g. "Fail"), or r if it isn't a known result.
+func resultName(r string) string {
+	for _, name := range []string{"Pass", "Fail", "Skip", "NotRun"} {
+		if strings.EqualFold(r, name) {
+			return name
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,316 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// The XML data used by the tests of the filters.
+const filterXMLData = "<assemblies>\n" +
+	"  <assembly name=\"App.dll\" total=\"4\" passed=\"2\" failed=\"1\" skipped=\"1\" time=\"2\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"NS.LoginTests.Succeeds\" result=\"Pass\" time=\"0.25\">\n" +
+	"        <traits><trait name=\"Category\" value=\"Integration\" /></traits>\n" +
+	"      </test>\n" +
+	"      <test name=\"NS.LoginTests.Fails\" result=\"Fail\" time=\"0.5\">\n" +
+	"        <traits><trait name=\"Category\" value=\"Integration\" /><trait name=\"Owner\" value=\"Ann\" /></traits>\n" +
+	"      </test>\n" +
+	"      <test name=\"NS.CalcTests.Adds\" result=\"Pass\" time=\"0.125\" />\n" +
+	"      <test name=\"NS.CalcTests.Divides\" result=\"Skip\" time=\"0\">\n" +
+	"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"  <assembly name=\"Lib.dll\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\" time=\"1\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"NS.LibTests.Works\" result=\"Pass\" time=\"1\" />\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// UT: Filter the tests of a test run.
+func TestTestRunFilter(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, err := xunit.Load(strings.NewReader(filterXMLData))
+
+	assert.NoError(t, err, "Load()")
+
+	for _, tc := range []struct {
+		exprs []string
+		want  []string // The raw names of the tests which match, in the order they're visited.
+	}{
+		{
+			exprs: []string{},
+			want: []string{"NS.CalcTests.Adds", "NS.LoginTests.Succeeds", "NS.LoginTests.Fails", "NS.CalcTests.Divides",
+				"NS.LibTests.Works"},
+		},
+		{
+			exprs: []string{"trait:Category=integration", "result=Fail"},
+			want:  []string{"NS.LoginTests.Fails"},
+		},
+		{
+			exprs: []string{"result=Pass,Skip", "name~^NS\\.Calc"},
+			want:  []string{"NS.CalcTests.Adds", "NS.CalcTests.Divides"},
+		},
+		{
+			exprs: []string{"trait:owner"},
+			want:  []string{"NS.LoginTests.Fails"},
+		},
+		{
+			exprs: []string{"trait:Category!=Integration", "assembly=App.dll"},
+			want:  []string{"NS.CalcTests.Adds", "NS.CalcTests.Divides"},
+		},
+		{
+			exprs: []string{"name!~Login"},
+			want:  []string{"NS.CalcTests.Adds", "NS.CalcTests.Divides", "NS.LibTests.Works"},
+		},
+	} {
+		filters := make([]xunit.Filter, 0, len(tc.exprs))
+
+		for _, expr := range tc.exprs {
+			f, err := xunit.ParseFilter(expr)
+
+			assert.NoError(t, err, "ParseFilter()")
+
+			filters = append(filters, f)
+		}
+
+		// ACT.
+		filtered := testRun.Filter(filters...)
+
+		// ASSERT.
+		got := make([]string, 0)
+
+		for _, assembly := range filtered.Assemblies {
+			assembly.Walk(func(_ []string, tc xunit.TestCase) {
+				got = append(got, tc.RawName)
+			})
+		}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Filter the tests of a test run.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.exprs, tc.want, got)
+
+		assert.Equal(t, filtered.Stats().TotalCount, len(tc.want), "", "\n\n"+
+			"UT Name:    Filter the tests of a test run.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %d tests\033[0m\n"+
+			"\033[31mActual:     %d tests\033[0m\n\n", tc.exprs, len(tc.want), filtered.Stats().TotalCount)
+	}
+}
+
+// UT: Recompute the statistics of a filtered test run.
+func TestTestRunFilter_Stats(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader(filterXMLData))
+	f, _ := xunit.ParseFilter("trait:Category~.")
+
+	// ACT.
+	filtered := testRun.Filter(f)
+	got := filtered.Stats()
+
+	// ASSERT.
+	want := xunit.Stats{
+		AssemblyCount: 1,
+		PassedCount:   1,
+		FailedCount:   1,
+		SkippedCount:  1,
+		TotalCount:    3,
+		PassRate:      50,
+		TotalDuration: 750 * time.Millisecond,
+	}
+
+	assert.Equal(t, got, want, "", "\n\n"+
+		"UT Name:    Recompute the statistics of a filtered test run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Filter the tests of a test run by trait, when they aren't grouped by trait.
+func TestTestRunFilter_GroupBy(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.LoadWithOptions(strings.NewReader(filterXMLData), xunit.Options{GroupBy: xunit.GroupByResult})
+	f, _ := xunit.ParseFilter("trait:Category=Integration")
+
+	// ACT.
+	filtered := testRun.Filter(f)
+
+	// ASSERT.
+	got := make([]string, 0)
+
+	for _, assembly := range filtered.Assemblies {
+		assembly.Walk(func(path []string, tc xunit.TestCase) {
+			got = append(got, path[0]+": "+tc.RawName)
+		})
+	}
+
+	want := []string{"Fail: NS.LoginTests.Fails", "Pass: NS.LoginTests.Succeeds"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Filter the tests of a test run by trait, when they aren't grouped by trait.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Recompute the statistics of the collections of a filtered test run.
+func TestTestRunFilter_Collections(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader(filterXMLData))
+	f, _ := xunit.ParseFilter("result=Pass")
+
+	// ACT.
+	filtered := testRun.Filter(f)
+
+	// ASSERT.
+	want := []xunit.Collection{{PassedCount: 2, TotalCount: 2, Duration: 375 * time.Millisecond}}
+	got := filtered.Assemblies[0].Collections
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Recompute the statistics of the collections of a filtered test run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Filter the tests of a test run by a trait whose name contains the separator of the names of the groups.
+func TestTestRunFilter_TraitName(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" total=\"2\" passed=\"2\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.LoginTests.Succeeds\" result=\"Pass\">\n" +
+		"        <traits><trait name=\"Team - Core\" value=\"Ann\" /></traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.LoginTests.Works\" result=\"Pass\">\n" +
+		"        <traits><trait name=\"Team\" value=\"Core - Ann\" /></traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	f, _ := xunit.ParseFilter("trait:Team - Core=Ann")
+
+	// ACT.
+	filtered := testRun.Filter(f)
+
+	// ASSERT.
+	got := make([]string, 0)
+
+	for _, assembly := range filtered.Assemblies {
+		assembly.Walk(func(_ []string, tc xunit.TestCase) {
+			got = append(got, tc.RawName)
+		})
+	}
+
+	want := []string{"NS.LoginTests.Succeeds"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Filter the tests of a test run by a trait whose name contains the separator of the names of the "+
+		"groups.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Keep the assemblies with environmental errors when filtering the tests of a test run.
+func TestTestRunFilter_Errors(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	testRun, _ := xunit.Load(strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\" total=\"1\" passed=\"1\" errors=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.LoginTests.Succeeds\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"    <errors>\n" +
+		"      <error type=\"fixture-cleanup\" name=\"NS.LoginTests\">\n" +
+		"        <failure exception-type=\"System.Exception\"><message>Boom</message></failure>\n" +
+		"      </error>\n" +
+		"    </errors>\n" +
+		"  </assembly>\n" +
+		"  <assembly name=\"Lib.dll\" total=\"1\" passed=\"1\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.LibTests.Works\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"))
+	f, _ := xunit.ParseFilter("result=Fail")
+
+	// ACT.
+	filtered := testRun.Filter(f)
+
+	// ASSERT.
+	got := make([]string, 0)
+
+	for _, assembly := range filtered.Assemblies {
+		got = append(got, assembly.Name)
+	}
+
+	want := []string{"App.dll"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Keep the assemblies with environmental errors when filtering the tests of a test run.\n"+
+		"\033[32mExpected:   The assemblies %q\033[0m\n"+
+		"\033[31mActual:     The assemblies %q\033[0m\n\n", want, got)
+
+	assert.Equal(t, filtered.Stats().ErrorCount, 1, "", "\n\n"+
+		"UT Name:    Keep the assemblies with environmental errors when filtering the tests of a test run.\n"+
+		"\033[32mExpected:   1 error\033[0m\n"+
+		"\033[31mActual:     %d errors\033[0m\n\n", filtered.Stats().ErrorCount)
+}
+
+// UT: Fail to parse an invalid filter.
+func TestParseFilter_Error(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, expr := range []string{"", "result", "status=Fail", "result=Failed", "name~(", "trait:=Unit"} {
+		// ACT.
+		_, err := xunit.ParseFilter(expr)
+
+		// ASSERT.
+		assert.Error(t, err, "", "\n\n"+
+			"UT Name:    Fail to parse an invalid filter.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   An error\033[0m\n"+
+			"\033[31mActual:     <nil>\033[0m\n\n", expr)
+	}
+}
@@ -0,0 +1,67 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+// GroupBy controls the top-level groups of the tests of an assembly, inside which the tests are grouped by their
+// classes (or by the delimiters in their names).
+type GroupBy int
+
+// The ways of grouping tests.
+const (
+	GroupByTrait      GroupBy = iota // A group per trait (e.g. "Category - Unit"), and one for the tests without traits.
+	GroupByNamespace                 // A group per namespace (e.g. "NS" for "NS.Class.Test").
+	GroupByCollection                // A group per test collection.
+	GroupByResult                    // A group per result (e.g. "Fail").
+	GroupByAssembly                  // No top-level groups: all the tests of the assembly are in the unnamed group.
+)
+
+// The names of the ways of grouping tests.
+var groupByNames = []string{
+	GroupByTrait:      "trait",
+	GroupByNamespace:  "namespace",
+	GroupByCollection: "collection",
+	GroupByResult:     "result",
+	GroupByAssembly:   "assembly",
+}
+
+// String returns the name of mode.
+func (mode GroupBy) String() string {
+	return enumString("GroupBy", groupByNames, int(mode))
+}
+
+// MarshalText returns the name of mode.
+func (mode GroupBy) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText sets mode to the way of grouping tests with the given name ("trait", "namespace", "collection",
+// "result" or "assembly").
+func (mode *GroupBy) UnmarshalText(text []byte) error {
+	m, err := parseEnum("grouping mode", groupByNames, text)
+	*mode = GroupBy(m)
+
+	return err
+}
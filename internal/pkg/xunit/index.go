@@ -0,0 +1,407 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// The size of the chunks of the document which are read to find the start of a test.
+const chunkSize = 4096
+
+// An Index is a TestRun without the details of its tests (the reasons they were skipped, their output and their
+// failures), which are loaded on demand, so huge test runs can be browsed without keeping all their details in memory.
+// An Index which keeps its document in a temporary file (see Options.MemoryBudget) must be closed.
+// An Index is safe for concurrent use.
+type Index struct {
+	TestRun TestRun // The test run, without the details of its tests.
+
+	mu   sync.RWMutex // Guards the document (which is released when the index is closed).
+	doc  io.ReaderAt  // The (escaped) document the index is constructed from.
+	size int64        // The size of doc.
+	file *os.File     // The temporary file doc is stored in (nil if doc is kept in memory).
+	opts Options      // How the document is loaded.
+}
+
+// LoadIndex returns an Index of the document in rdr, loaded as configured by opts.
+// The tests of the index only contain a handle of their details, which are loaded with Details. The document is
+// decoded in a single pass, which skips the details of the tests, so they aren't kept in memory.
+func LoadIndex(rdr io.Reader, opts Options) (*Index, error) {
+	return LoadIndexContext(context.Background(), rdr, opts)
+}
+
+// LoadIndexContext is like LoadIndex, but stops reading, decoding and grouping the tests of the document when ctx is
+// done, in which case it returns the error of ctx.
+func LoadIndexContext(ctx context.Context, rdr io.Reader, opts Options) (*Index, error) {
+	opts.index = true
+	doc := &spool{budget: opts.MemoryBudget}
+
+	esc := &escapingReader{rdr: &ctxReader{ctx: ctx, rdr: rdr}}
+	dec := xml.NewDecoder(io.TeeReader(esc, doc))
+	res, inRoot, err := decodeIndex(dec)
+
+	switch {
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	case doc.err != nil:
+		err = doc.err
+	case err != nil && esc.err != nil && esc.err != io.EOF:
+		err = &ParseError{Offset: doc.size, Line: doc.lineAt(doc.size), Err: esc.err}
+	case err != nil:
+		offset := dec.InputOffset()
+		err = &ParseError{Offset: offset, Line: doc.lineAt(offset), Element: elementAt(doc.reader(), offset), Err: err}
+	}
+
+	var testRun TestRun
+
+	switch {
+	case err == nil:
+		testRun, err = newTestRunContext(ctx, res, doc.reader(), opts)
+	case opts.Partial && inRoot && errors.As(err, new(*ParseError)):
+		testRun, err = newPartialTestRun(ctx, res, doc.reader(), opts, err)
+	}
+
+	if err != nil {
+		doc.remove()
+
+		return nil, err
+	}
+
+	opts.index = false
+
+	return &Index{TestRun: testRun, doc: doc.readerAt(), size: doc.size, file: doc.file, opts: opts}, nil
+}
+
+// A spool stores a document while it's read: in memory, until its size exceeds a budget, and in a temporary file from
+// then on, so the document is never kept in memory as a whole.
+type spool struct {
+	budget int64    // The maximum size of the document which is kept in memory (unlimited if 0).
+	buf    []byte   // The document (while it's kept in memory).
+	file   *os.File // The temporary file the document is stored in (nil while it's kept in memory).
+	size   int64    // The size of the document.
+	err    error    // The error which occurred while storing the document (if any).
+}
+
+// Write appends p to the document.
+func (s *spool) Write(p []byte) (int, error) {
+	if s.err == nil && s.file == nil && s.budget > 0 && s.size+int64(len(p)) > s.budget {
+		if s.file, s.err = os.CreateTemp("", "dtvisual-*.xml"); s.err == nil {
+			_, s.err = s.file.Write(s.buf)
+			s.buf = nil
+		}
+	}
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	if s.file == nil {
+		s.buf = append(s.buf, p...)
+		s.size += int64(len(p))
+
+		return len(p), nil
+	}
+
+	n, err := s.file.Write(p)
+	s.size, s.err = s.size+int64(n), err
+
+	return n, err
+}
+
+// Returns the document.
+func (s *spool) readerAt() io.ReaderAt {
+	if s.file != nil {
+		return s.file
+	}
+
+	return bytes.NewReader(s.buf)
+}
+
+// Returns a reader of the document, from its start.
+func (s *spool) reader() io.Reader {
+	return io.NewSectionReader(s.readerAt(), 0, s.size)
+}
+
+// Returns the (1-based) number of the line containing the byte at offset in the document.
+func (s *spool) lineAt(offset int64) int {
+	line := 1
+	buf := make([]byte, chunkSize)
+	rdr := io.NewSectionReader(s.readerAt(), 0, min(offset, s.size))
+
+	for {
+		n, err := rdr.Read(buf)
+		line += bytes.Count(buf[:n], []byte("\n"))
+
+		if err != nil {
+			return line
+		}
+	}
+}
+
+// Removes the temporary file the document is stored in (if any).
+func (s *spool) remove() {
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+	}
+}
+
+// Returns the result decoded from dec, without the details of its tests (which are skipped), and true if the document
+// contains the start of the root element, false otherwise.
+// If the document can't be decoded, the result contains the (complete) tests which precede the error.
+func decodeIndex(dec *xml.Decoder) (result, bool, error) {
+	var res result
+	var inRoot bool
+
+	var a *assembly
+	var c *collection
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return res, inRoot, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case !inRoot && tok.Name.Local != "assemblies":
+				return res, false, fmt.Errorf("expected element type <assemblies> but have <%s>", tok.Name.Local)
+			case !inRoot:
+				err = decodeAttrs(&res, tok)
+				inRoot = err == nil
+			case a == nil && tok.Name.Local == "assembly":
+				res.Assemblies = append(res.Assemblies, assembly{})
+				a = &res.Assemblies[len(res.Assemblies)-1]
+
+				err = decodeAttrs(a, tok)
+				a.offset = dec.InputOffset()
+			case a == nil:
+				res.Unknown = append(res.Unknown, unknown{XMLName: tok.Name})
+				err = dec.Skip()
+			case c == nil && tok.Name.Local == "collection":
+				a.Collections = append(a.Collections, collection{})
+				c = &a.Collections[len(a.Collections)-1]
+
+				err = decodeAttrs(c, tok)
+				c.offset = dec.InputOffset()
+			case c == nil && tok.Name.Local == "errors":
+				err = dec.DecodeElement(&a.ErrorSet, &tok)
+			case c == nil:
+				a.Unknown = append(a.Unknown, unknown{XMLName: tok.Name})
+				err = dec.Skip()
+			case tok.Name.Local == "test":
+				var t test
+
+				if t, err = decodeIndexTest(dec, tok); err == nil {
+					c.Tests = append(c.Tests, t)
+				}
+			default:
+				c.Unknown = append(c.Unknown, unknown{XMLName: tok.Name})
+				err = dec.Skip()
+			}
+		case xml.EndElement:
+			switch {
+			case c != nil:
+				c = nil
+			case a != nil:
+				a = nil
+			default:
+				return res, inRoot, nil
+			}
+		}
+
+		if err != nil {
+			return res, inRoot, err
+		}
+	}
+}
+
+// Returns the test decoded from the element start (read from dec), without its details (which are skipped).
+func decodeIndexTest(dec *xml.Decoder, start xml.StartElement) (test, error) {
+	var t test
+
+	if err := decodeAttrs(&t, start); err != nil {
+		return t, err
+	}
+
+	t.offset = dec.InputOffset()
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return t, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "traits":
+				err = dec.DecodeElement(&t.TraitSet, &tok)
+			case "warnings":
+				err = dec.DecodeElement(&t.WarningSet, &tok)
+			case "failure", "output", "reason":
+				err = dec.Skip()
+			default:
+				t.Unknown = append(t.Unknown, unknown{XMLName: tok.Name})
+				err = dec.Skip()
+			}
+
+			if err != nil {
+				return t, err
+			}
+		case xml.EndElement:
+			return t, nil
+		}
+	}
+}
+
+// Close removes the temporary file the document of the index is stored in (if any).
+// The details of the tests of the index can't be loaded once it's closed.
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.file == nil {
+		return nil
+	}
+
+	err := errors.Join(idx.file.Close(), os.Remove(idx.file.Name()))
+	idx.doc, idx.size, idx.file = bytes.NewReader(nil), 0, nil
+
+	return err
+}
+
+// Details returns tc (a test of the index), with its details.
+func (idx *Index) Details(tc TestCase) (TestCase, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if tc.Handle <= 0 || tc.Handle > idx.size {
+		return tc, errors.New("xunit: the test isn't part of the index")
+	}
+
+	start, err := idx.startOf(tc.Handle)
+
+	if err != nil {
+		return tc, err
+	}
+
+	var t test
+
+	if err := xml.NewDecoder(io.NewSectionReader(idx.doc, start, idx.size-start)).Decode(&t); err != nil {
+		return tc, &ParseError{Offset: start, Element: "test", Err: err}
+	}
+
+	details := t.testCase(&idx.opts, nil)
+	tc.Reason, tc.Output, tc.Failure = details.Reason, details.Output, details.Failure
+
+	return tc, nil
+}
+
+// WithDetails returns testRun (the test run of the index, or a test run derived from it, e.g. with Filter), with the
+// details of its tests. The groups of testRun are copied, so testRun itself doesn't change.
+func (idx *Index) WithDetails(testRun TestRun) (TestRun, error) {
+	// NOTE: The tests which belong to multiple groups (e.g. because they have multiple traits) are only loaded once.
+	loaded := make(map[int64]TestCase)
+
+	var withDetails func(groups []*TestGroup) ([]*TestGroup, error)
+
+	withDetails = func(groups []*TestGroup) ([]*TestGroup, error) {
+		var copies []*TestGroup
+
+		for _, group := range groups {
+			var err error
+
+			cp := &TestGroup{Name: group.Name, Tests: append([]TestCase(nil), group.Tests...)}
+
+			for tIdx, tc := range cp.Tests {
+				details, ok := loaded[tc.Handle]
+
+				if !ok {
+					if details, err = idx.Details(tc); err != nil {
+						return nil, err
+					}
+
+					loaded[tc.Handle] = details
+				}
+
+				cp.Tests[tIdx] = details
+			}
+
+			if cp.Groups, err = withDetails(group.Groups); err != nil {
+				return nil, err
+			}
+
+			copies = append(copies, cp)
+		}
+
+		return copies, nil
+	}
+
+	testRun.Assemblies = append([]Assembly(nil), testRun.Assemblies...)
+
+	for aIdx := range testRun.Assemblies {
+		groups, err := withDetails(testRun.Assemblies[aIdx].Tests)
+
+		if err != nil {
+			return TestRun{}, err
+		}
+
+		testRun.Assemblies[aIdx].Tests = groups
+	}
+
+	return testRun, nil
+}
+
+// Returns the offset of the start tag of the test with the given handle.
+// NOTE: The handle is the offset of the end of the start tag of the test, and a start tag doesn't contain a "<".
+func (idx *Index) startOf(handle int64) (int64, error) {
+	buf := make([]byte, chunkSize)
+
+	for end := handle; end > 0; end -= chunkSize {
+		chunk := buf[:min(chunkSize, end)]
+
+		if _, err := idx.doc.ReadAt(chunk, end-int64(len(chunk))); err != nil {
+			return 0, err
+		}
+
+		if i := bytes.LastIndexByte(chunk, '<'); i >= 0 {
+			return end - int64(len(chunk)) + int64(i), nil
+		}
+	}
+
+	return 0, errors.New("xunit: the test isn't part of the index")
+}
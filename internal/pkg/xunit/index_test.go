@@ -0,0 +1,387 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Load an index, which has the same tests as the test run of the document, without their details.
+func TestLoadIndex(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		opts  xunit.Options
+	}{
+		{
+			name: "A complete document",
+			input: "<assemblies schema-version=\"3\" computer=\"CI\">\n" +
+				"  <assembly name=\"App.dll\" total=\"3\" passed=\"1\" failed=\"1\" skipped=\"1\" time=\"1\">\n" +
+				"    <collection name=\"C\" total=\"3\">\n" +
+				"      <test name=\"A &#x1B;[1mbold&#x1B;[0m test.\" result=\"Pass\" time=\"0.5\">\n" +
+				"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+				"        <attachments />\n" +
+				"      </test>\n" +
+				"      <test name=\"NS.Class.Fails\" result=\"Fail\">\n" +
+				"        <output>Connecting...</output>\n" +
+				"        <failure><message>Expected: 1</message><unknown /></failure>\n" +
+				"      </test>\n" +
+				"      <test name=\"A skipped test.\" result=\"Skip\"><reason>Not ready.</reason></test>\n" +
+				"      <unknown />\n" +
+				"    </collection>\n" +
+				"    <errors><error name=\"Fixture\" type=\"assembly-cleanup\" /></errors>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+		},
+		{
+			name: "A truncated document",
+			input: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"A passing test.\" result=\"Pass\" />\n" +
+				"      <test name=\"A truncated test.\" result=\"Fail\"><output>Connec",
+			opts: xunit.Options{Partial: true},
+		},
+	} {
+		for _, rdr := range []func(s string) io.Reader{
+			func(s string) io.Reader { return strings.NewReader(s) },
+			func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		} {
+			// ARRANGE.
+			want, err := xunit.LoadWithOptions(strings.NewReader(tc.input), tc.opts)
+
+			assert.NoError(t, err, "LoadWithOptions()")
+
+			// ACT.
+			index, err := xunit.LoadIndex(rdr(tc.input), tc.opts)
+
+			// ASSERT.
+			assert.NoError(t, err, "LoadIndex()")
+
+			got := index.TestRun
+
+			for _, testRun := range []xunit.TestRun{want, got} {
+				for _, assembly := range testRun.Assemblies {
+					withoutDetails(assembly.Tests)
+				}
+			}
+
+			assert.DeepEqual(t, got, want, "", "\n\n"+
+				"UT Name:    Load an index, which has the same tests as the test run of the document (%s).\n"+
+				"\033[32mExpected:   %+v\033[0m\n"+
+				"\033[31mActual:     %+v\033[0m\n\n", tc.name, want, got)
+		}
+	}
+}
+
+// UT: Load the details of all the tests of an index.
+func TestIndex_WithDetails(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.Class.Fails\" result=\"Fail\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+		"        </traits>\n" +
+		"        <output>Connecting...</output>\n" +
+		"        <failure><message>Expected: 1</message></failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"A skipped test.\" result=\"Skip\"><reason>Not ready.</reason></test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+	want, _ := xunit.Load(strings.NewReader(xmlData))
+	index, err := xunit.LoadIndex(strings.NewReader(xmlData), xunit.Options{})
+
+	assert.NoError(t, err, "LoadIndex()")
+
+	// ACT.
+	got, err := index.WithDetails(index.TestRun)
+
+	// ASSERT.
+	assert.NoError(t, err, "WithDetails()")
+
+	for _, assembly := range got.Assemblies {
+		withoutHandles(assembly.Tests)
+	}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Load the details of all the tests of an index.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+
+	details := index.TestRun.Assemblies[0].Tests[0].Tests[0].Failure.Message
+
+	assert.Equal(t, details, "", "", "\n\n"+
+		"UT Name:    Load the details of all the tests of an index.\n"+
+		"\033[32mExpected:   The test run of the index without details\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", details)
+}
+
+// UT: Load an index of an invalid document.
+func TestLoadIndex_Error(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "xunit: line 1 (offset 0): EOF"},
+		{input: "<testsuites />", want: "xunit: line 1 (offset 14), in <testsuites>: expected element type <assemblies> " +
+			"but have <testsuites>"},
+		{input: "<assemblies>\n  <assembly name=\"App.dll\" total=\"x\">", want: "xunit: line 2 (offset 50), in " +
+			"<assembly>: strconv.ParseInt: parsing \"x\": invalid syntax"},
+	} {
+		// ARRANGE.
+		_, want := xunit.Load(strings.NewReader(tc.input))
+
+		// ACT.
+		_, err := xunit.LoadIndex(strings.NewReader(tc.input), xunit.Options{})
+
+		// ASSERT.
+		assert.EqualFn(t, err, want, func(got, want error) bool {
+			return got != nil && want != nil && got.Error() == want.Error()
+		}, "", "\n\n"+
+			"UT Name:    Load an index of an invalid document.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.input, want, err)
+	}
+}
+
+// Removes the details and the handles of the tests in groups (and their subgroups).
+func withoutDetails(groups []*xunit.TestGroup) {
+	for _, group := range groups {
+		for idx := range group.Tests {
+			tc := &group.Tests[idx]
+			tc.Reason, tc.Output, tc.Failure, tc.Handle = "", "", xunit.Failure{}, 0
+		}
+
+		withoutDetails(group.Groups)
+	}
+}
+
+// Removes the handles of the tests in groups (and their subgroups).
+func withoutHandles(groups []*xunit.TestGroup) {
+	for _, group := range groups {
+		for idx := range group.Tests {
+			group.Tests[idx].Handle = 0
+		}
+
+		withoutHandles(group.Groups)
+	}
+}
+
+// UT: Load the details of the tests of an index on demand.
+func TestIndex_Details(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A skipped test.\" result=\"Skip\"><reason>Not \x1b[1mready\x1b[0m.</reason></test>\n" +
+		"      <test name=\"A failed test.\" result=\"Fail\">\n" +
+		"        <output>Connecting...</output>\n" +
+		"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n" +
+		"          <message>Expected: 1</message>\n" +
+		"          <stack-trace>at Test()</stack-trace>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	// ACT.
+	index, err := xunit.LoadIndex(strings.NewReader(xmlData), xunit.Options{})
+
+	// ASSERT.
+	assert.NoError(t, err, "LoadIndex()")
+
+	tests := index.TestRun.Assemblies[0].Tests[0].Tests
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.Reason+tc.Output+tc.Failure.Message, "", "", "\n\n"+
+			"UT Name:    Load the details of the tests of an index on demand.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   No details\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.Name, tc)
+	}
+
+	for _, tc := range []struct {
+		test xunit.TestCase
+		want []string
+	}{
+		{test: tests[0], want: []string{"Not ready.", "", "", ""}},
+		{test: tests[1], want: []string{"", "Connecting...", "Expected: 1", "at Test()"}},
+	} {
+		// ACT.
+		details, err := index.Details(tc.test)
+
+		// ASSERT.
+		assert.NoError(t, err, "Details()")
+
+		got := []string{details.Reason, details.Output, details.Failure.Message, details.Failure.StackTrace}
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load the details of the tests of an index on demand.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.test.Name, tc.want, got)
+	}
+}
+
+// UT: Load the details of the tests of an index which keeps its document in a temporary file.
+func TestIndex_Details_MemoryBudget(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A failed test.\" result=\"Fail\">\n" +
+		"        <failure><stack-trace>at Test()</stack-trace></failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	index, err := xunit.LoadIndex(strings.NewReader(xmlData), xunit.Options{MemoryBudget: 16})
+
+	assert.NoError(t, err, "LoadIndex()")
+
+	tCase := index.TestRun.Assemblies[0].Tests[0].Tests[0]
+
+	// ACT.
+	details, err := index.Details(tCase)
+
+	// ASSERT.
+	assert.NoError(t, err, "Details()")
+
+	assert.Equal(t, details.Failure.StackTrace, "at Test()", "", "\n\n"+
+		"UT Name:    Load the details of the tests of an index which keeps its document in a temporary file.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", "at Test()", details.Failure.StackTrace)
+
+	// ACT.
+	assert.NoError(t, index.Close(), "Close()")
+
+	_, err = index.Details(tCase)
+
+	// ASSERT.
+	assert.Equal(t, err != nil, true, "", "\n\n"+
+		"UT Name:    Load the details of the tests of an index which keeps its document in a temporary file.\n"+
+		"\033[32mExpected:   An error once the index is closed\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+}
+
+// UT: Load an index which streams its document to a temporary file once it exceeds the memory budget.
+func TestLoadIndex_MemoryBudget(t *testing.T) {
+	// ARRANGE.
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A failed test.\" result=\"Fail\"><failure><message>Expected: 1</message></failure></test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	var files []int
+
+	rdr := readerFunc(func(p []byte) (int, error) {
+		entries, _ := os.ReadDir(dir)
+		files = append(files, len(entries))
+
+		if len(xmlData) == 0 {
+			return 0, io.EOF
+		}
+
+		n := copy(p[:min(len(p), 32)], xmlData)
+		xmlData = xmlData[n:]
+
+		return n, nil
+	})
+
+	// ACT.
+	index, err := xunit.LoadIndex(rdr, xunit.Options{MemoryBudget: 64})
+
+	// ASSERT.
+	assert.NoError(t, err, "LoadIndex()")
+
+	want := []int{0, 0, 0, 1, 1, 1, 1}
+
+	assert.DeepEqual(t, files[:len(want)], want, "", "\n\n"+
+		"UT Name:    Load an index which streams its document to a temporary file once it exceeds the memory budget.\n"+
+		"\033[32mExpected:   The number of temporary files before each read: %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, files)
+
+	// ACT.
+	assert.NoError(t, index.Close(), "Close()")
+
+	// ASSERT.
+	entries, _ := os.ReadDir(dir)
+
+	assert.Equal(t, len(entries), 0, "", "\n\n"+
+		"UT Name:    Load an index which streams its document to a temporary file once it exceeds the memory budget.\n"+
+		"\033[32mExpected:   No temporary files once the index is closed\033[0m\n"+
+		"\033[31mActual:     %d\033[0m\n\n", len(entries))
+}
+
+// UT: Load the details of a test which isn't part of an index.
+func TestIndex_Details_UnknownTest(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	index, err := xunit.LoadIndex(strings.NewReader("<assemblies />"), xunit.Options{})
+
+	assert.NoError(t, err, "LoadIndex()")
+
+	// ACT.
+	_, err = index.Details(xunit.TestCase{Name: "A test."})
+
+	// ASSERT.
+	assert.Equal(t, err != nil, true, "", "\n\n"+
+		"UT Name:    Load the details of a test which isn't part of an index.\n"+
+		"\033[32mExpected:   An error\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+}
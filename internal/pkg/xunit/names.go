@@ -0,0 +1,254 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/slices"
+)
+
+// Returns name (the name of a test), without the arguments it's run with (e.g. "NS.Class.Method" for
+// "NS.Class.Method(x: 1.5)").
+func methodPath(name string) string {
+	if idx := indexTopLevel(name, '('); idx >= 0 {
+		return name[:idx]
+	}
+
+	return name
+}
+
+// Returns the full name of the type which declares the method of name (the name of a test), e.g. "NS.Outer+Inner" for
+// "NS.Outer+Inner.Method(x: 1)".
+func typePath(name string) string {
+	path := methodPath(name)
+
+	if idx := lastIndexTopLevel(path, '.'); idx >= 0 {
+		return path[:idx]
+	}
+
+	return path
+}
+
+// Returns the readable name of the type with the given (reflection) name: the arity of a generic type is replaced by
+// its type parameters, and its type arguments by their (readable) short names (e.g. "Outer<T>" for "Outer`1", and
+// "Outer<String>" for "Outer`1[[System.String, mscorlib]]").
+func typeName(name string) string {
+	base, args, hasArgs := strings.Cut(name, "[")
+
+	base, arity, isGeneric := strings.Cut(base, "`")
+	n, _ := strconv.Atoi(arity)
+
+	if hasArgs {
+		args = strings.TrimSuffix(args, "]")
+
+		params := slices.Map(splitTopLevel(args, ','), func(arg string) string {
+			arg = strings.TrimSpace(arg)
+
+			// NOTE: Assembly-qualified type arguments are enclosed in brackets (e.g. "[System.String, mscorlib]").
+			if strings.HasPrefix(arg, "[") {
+				arg = strings.TrimSuffix(arg[1:], "]")
+				arg = splitTopLevel(arg, ',')[0]
+			}
+
+			arg = arg[lastIndexTopLevel(arg, '.')+1:]
+
+			return typeName(arg[lastIndexTopLevel(arg, '+')+1:])
+		})
+
+		return base + "<" + strings.Join(params, ", ") + ">"
+	}
+
+	if !isGeneric || n <= 0 {
+		return base
+	}
+
+	if n == 1 {
+		return base + "<T>"
+	}
+
+	params := make([]string, n)
+
+	for i := range params {
+		params[i] = "T" + strconv.Itoa(i+1)
+	}
+
+	return base + "<" + strings.Join(params, ", ") + ">"
+}
+
+// Returns the parts of s separated by sep, which are outside of brackets, parentheses and angle brackets.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+
+	for idx := indexTopLevel(s, sep); idx >= 0; idx = indexTopLevel(s, sep) {
+		parts = append(parts, s[:idx])
+		s = s[idx+1:]
+	}
+
+	return append(parts, s)
+}
+
+// Returns the index of the first c in s which is outside of brackets, parentheses and angle brackets, or -1 if there
+// is no such c.
+func indexTopLevel(s string, c byte) int {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		if depth == 0 && s[i] == c {
+			return i
+		}
+
+		depth += nesting(s[i])
+	}
+
+	return -1
+}
+
+// Returns the index of the last c in s which is outside of brackets, parentheses and angle brackets, or -1 if there
+// is no such c.
+func lastIndexTopLevel(s string, c byte) int {
+	depth := 0
+
+	for i := len(s) - 1; i >= 0; i-- {
+		if depth == 0 && s[i] == c {
+			return i
+		}
+
+		depth -= nesting(s[i])
+	}
+
+	return -1
+}
+
+// Returns 1 if b opens a bracket, a parenthesis or an angle bracket, -1 if it closes one, and 0 otherwise.
+func nesting(b byte) int {
+	switch b {
+	case '[', '(', '<':
+		return 1
+	case ']', ')', '>':
+		return -1
+	}
+
+	return 0
+}
+
+// Returns the parts of s separated by any of the delimiters (without their surrounding whitespace, and without empty
+// parts, except the last one).
+func splitAny(s string, delimiters []string) []string {
+	var parts []string
+
+	for {
+		idx, size := -1, 0
+
+		for _, delim := range delimiters {
+			if i := strings.Index(s, delim); delim != "" && i >= 0 && (idx < 0 || i < idx) {
+				idx, size = i, len(delim)
+			}
+		}
+
+		if idx < 0 {
+			return append(parts, s)
+		}
+
+		if part := strings.TrimSpace(s[:idx]); part != "" {
+			parts = append(parts, part)
+		}
+
+		s = s[idx+size:]
+	}
+}
+
+// Returns name (the name of a test), with the name of its method written as a sentence, followed by the arguments it's
+// run with (e.g. "Returns an error (x: 1)" for "NS.Class.ReturnsAnError(x: 1)").
+func humanizeTestName(name string) string {
+	path := methodPath(name)
+	method := path[lastIndexTopLevel(path, '.')+1:]
+
+	if args := name[len(path):]; args != "" {
+		return humanize(method) + " " + args
+	}
+
+	return humanize(method)
+}
+
+// Returns s (an identifier in snake case or in camel case) as a sentence (e.g. "Should return an error" for
+// "Should_Return_An_Error" or "ShouldReturnAnError"). Acronyms are kept in upper case (e.g. "Parses the HTTP request"
+// for "ParsesTheHTTPRequest").
+func humanize(s string) string {
+	var words []string
+
+	for _, part := range strings.Split(s, "_") {
+		for _, word := range splitCamelCase(part) {
+			if len([]rune(word)) < 2 || strings.ToUpper(word) != word {
+				word = strings.ToLower(word)
+			}
+
+			words = append(words, word)
+		}
+	}
+
+	sentence := []rune(strings.Join(words, " "))
+
+	if len(sentence) == 0 {
+		return s
+	}
+
+	sentence[0] = unicode.ToUpper(sentence[0])
+
+	return string(sentence)
+}
+
+// Returns the words of s, in camel case (e.g. "Parses", "The", "HTTP", "Request" and "2" for "ParsesTheHTTPRequest2").
+func splitCamelCase(s string) []string {
+	var words []string
+
+	runes := []rune(s)
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := cur
+
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if (unicode.IsLower(prev) && unicode.IsUpper(cur)) ||
+			(unicode.IsUpper(prev) && unicode.IsUpper(cur) && unicode.IsLower(next)) ||
+			(unicode.IsDigit(prev) != unicode.IsDigit(cur)) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
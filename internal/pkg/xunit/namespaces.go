@@ -0,0 +1,58 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+// Namespaces controls how the namespaces of nested tests (e.g. "NS" for "NS.Outer+Inner.Test") are shown in their
+// groups, which disambiguates classes with the same name in different namespaces.
+type Namespaces int
+
+// The ways of showing namespaces.
+const (
+	NamespacesStrip   Namespaces = iota // Remove the namespace (e.g. the groups "Outer" and "Inner").
+	NamespacesGroup                     // Add a group for the namespace (e.g. the groups "NS", "Outer" and "Inner").
+	NamespacesQualify                   // Qualify the outermost class (e.g. the groups "NS.Outer" and "Inner").
+)
+
+// The names of the ways of showing namespaces.
+var namespacesNames = []string{NamespacesStrip: "strip", NamespacesGroup: "group", NamespacesQualify: "qualify"}
+
+// String returns the name of mode.
+func (mode Namespaces) String() string {
+	return enumString("Namespaces", namespacesNames, int(mode))
+}
+
+// MarshalText returns the name of mode.
+func (mode Namespaces) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText sets mode to the way of showing namespaces with the given name ("strip", "group" or "qualify").
+func (mode *Namespaces) UnmarshalText(text []byte) error {
+	m, err := parseEnum("namespaces mode", namespacesNames, text)
+	*mode = Namespaces(m)
+
+	return err
+}
@@ -0,0 +1,222 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sanitize controls how the control characters (and the ANSI escape sequences) in the names, reasons and failures of
+// the tests are handled, since they break the rendering of the tests in terminals and HTML pages.
+type Sanitize int
+
+// The ways of handling control characters.
+const (
+	SanitizeStrip  Sanitize = iota // Remove ANSI escape sequences and control characters (except tabs and newlines).
+	SanitizeEscape                 // Replace control characters (except tabs and newlines) with escapes (e.g. "\x1b").
+	SanitizeNone                   // Keep control characters.
+)
+
+// The names of the ways of handling control characters.
+var sanitizeNames = []string{SanitizeStrip: "strip", SanitizeEscape: "escape", SanitizeNone: "none"}
+
+// The pattern of an ANSI escape sequence (e.g. "\x1b[31m", or "\x1b]0;title\x07").
+var ansiEscape = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// The pattern of a character reference (e.g. "&#x1B;" or "&#27;").
+var charRef = regexp.MustCompile(`&#(?:x([0-9a-fA-F]{1,6})|([0-9]{1,7}));`)
+
+// NOTE: XML doesn't allow control characters (other than tabs and newlines), not even as character references, but
+// some producers write them anyway (e.g. in the names of the tests). So they are replaced by the code points of the
+// Private Use Area of Unicode starting at placeholderBase before decoding a document, and restored when its tests are
+// built.
+const placeholderBase = 0xf700
+
+// String returns the name of mode.
+func (mode Sanitize) String() string {
+	return enumString("Sanitize", sanitizeNames, int(mode))
+}
+
+// MarshalText returns the name of mode.
+func (mode Sanitize) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText sets mode to the way of handling control characters with the given name ("strip", "escape" or
+// "none").
+func (mode *Sanitize) UnmarshalText(text []byte) error {
+	m, err := parseEnum("sanitize mode", sanitizeNames, text)
+	*mode = Sanitize(m)
+
+	return err
+}
+
+// Returns s, with its control characters handled according to mode.
+func (mode Sanitize) apply(s string) string {
+	if strings.IndexFunc(s, func(r rune) bool { return isControl(r) || isPlaceholder(r) }) < 0 {
+		return s
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if isPlaceholder(r) {
+			return r - placeholderBase
+		}
+
+		return r
+	}, s)
+
+	if mode == SanitizeNone {
+		return s
+	}
+
+	if mode == SanitizeStrip {
+		return strings.Map(func(r rune) rune {
+			if isControl(r) {
+				return -1
+			}
+
+			return r
+		}, ansiEscape.ReplaceAllString(s, ""))
+	}
+
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch {
+		case !isControl(r):
+			sb.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&sb, `\x%02x`, r)
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+
+	return sb.String()
+}
+
+// Returns true if r is a control character (other than a tab or a newline).
+func isControl(r rune) bool {
+	return r != '\t' && r != '\n' && (r < 0x20 || (r >= 0x7f && r <= 0x9f))
+}
+
+// Returns true if r is the placeholder of a control character which isn't allowed in a document.
+func isPlaceholder(r rune) bool {
+	return r >= placeholderBase && r < placeholderBase+0x20
+}
+
+// Returns true if XML doesn't allow the character r.
+func isIllegal(r rune) bool {
+	return r < 0x20 && r != '\t' && r != '\n' && r != '\r'
+}
+
+// Returns data (a document), with the control characters which XML doesn't allow (including the character references
+// to them) replaced by their placeholder.
+func escapeControls(data []byte) []byte {
+	if bytes.IndexFunc(data, isIllegal) < 0 && !bytes.Contains(data, []byte("&#")) {
+		return data
+	}
+
+	data = charRef.ReplaceAllFunc(data, func(ref []byte) []byte {
+		m := charRef.FindSubmatch(ref)
+		code, err := strconv.ParseInt(string(m[1]), 16, 32)
+
+		if len(m[1]) == 0 {
+			code, err = strconv.ParseInt(string(m[2]), 10, 32)
+		}
+
+		if err != nil || !isIllegal(rune(code)) {
+			return ref
+		}
+
+		return utf8.AppendRune(nil, placeholderBase+rune(code))
+	})
+
+	if bytes.IndexFunc(data, isIllegal) < 0 {
+		return data
+	}
+
+	escaped := make([]byte, 0, len(data))
+
+	for _, b := range data {
+		if b < utf8.RuneSelf && isIllegal(rune(b)) {
+			escaped = utf8.AppendRune(escaped, placeholderBase+rune(b))
+		} else {
+			escaped = append(escaped, b)
+		}
+	}
+
+	return escaped
+}
+
+// The length of the longest character reference matched by charRef (e.g. "&#x10FFFF;").
+const maxCharRefLen = 10
+
+// An escapingReader reads a document from rdr, with the control characters which XML doesn't allow replaced by their
+// placeholder (see escapeControls).
+type escapingReader struct {
+	rdr   io.Reader
+	chunk []byte // The buffer the document is read into.
+	buf   []byte // The escaped data which isn't read yet.
+	tail  []byte // The end of the last chunk, which may be the start of a character reference.
+	err   error  // The error returned by rdr (if any).
+}
+
+// Read reads the escaped document into p.
+func (r *escapingReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if r.chunk == nil {
+			r.chunk = make([]byte, chunkSize)
+		}
+
+		n, err := r.rdr.Read(r.chunk)
+		data := append(r.tail, r.chunk[:n]...)
+		r.tail, r.err = nil, err
+
+		// NOTE: A character reference which is split over 2 chunks is escaped along with the next chunk.
+		if idx := bytes.LastIndexByte(data, '&'); err == nil && idx >= 0 && len(data)-idx < maxCharRefLen &&
+			bytes.IndexByte(data[idx:], ';') < 0 {
+			data, r.tail = data[:idx], data[idx:]
+		}
+
+		r.buf = escapeControls(data)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
@@ -0,0 +1,123 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Handle the control characters in the names and failures of the tests.
+func TestLoadWithOptions_Sanitize(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A \x1b[1mbold\x1b[0m test.\" result=\"Fail\">\n" +
+		"        <failure>\n" +
+		"          <message>\x1b[31mExpected: 1\x1b[0m\n\tActual: 2\x7f&#7;</message>\n" +
+		"        </failure>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		mode        xunit.Sanitize
+		wantName    string
+		wantMessage string
+	}{
+		{
+			mode:        xunit.SanitizeStrip,
+			wantName:    "A bold test.",
+			wantMessage: "Expected: 1\n\tActual: 2",
+		},
+		{
+			mode:        xunit.SanitizeEscape,
+			wantName:    "A \\x1b[1mbold\\x1b[0m test.",
+			wantMessage: "\\x1b[31mExpected: 1\\x1b[0m\n\tActual: 2\\x7f\\x07",
+		},
+		{
+			mode:        xunit.SanitizeNone,
+			wantName:    "A \x1b[1mbold\x1b[0m test.",
+			wantMessage: "\x1b[31mExpected: 1\x1b[0m\n\tActual: 2\x7f\a",
+		},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Sanitize: tc.mode})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		tCase := testRun.Assemblies[0].Tests[0].Tests[0]
+		got, want := []string{tCase.Name, tCase.Failure.Message}, []string{tc.wantName, tc.wantMessage}
+
+		assert.DeepEqual(t, got, want, "", "\n\n"+
+			"UT Name:    Handle the control characters in the names and failures of the tests.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.mode, want, got)
+	}
+}
+
+// UT: Parse the name of a way of handling control characters.
+func TestSanitize_UnmarshalText(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		text    string
+		want    xunit.Sanitize
+		wantErr bool
+	}{
+		{text: "strip", want: xunit.SanitizeStrip},
+		{text: "escape", want: xunit.SanitizeEscape},
+		{text: "none", want: xunit.SanitizeNone},
+		{text: "remove", wantErr: true},
+	} {
+		// ACT.
+		var got xunit.Sanitize
+
+		err := got.UnmarshalText([]byte(tc.text))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Parse the name of a way of handling control characters.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.text, tc.wantErr, err)
+
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Parse the name of a way of handling control characters.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.text, tc.want, got)
+	}
+}
@@ -0,0 +1,63 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import "time"
+
+// Stats contains the aggregated statistics of a test run.
+type Stats struct {
+	AssemblyCount int           // The total number of assemblies in the test run.
+	ErrorCount    int           // The total number of environmental errors experienced in the test run.
+	PassedCount   int           // The total number of test cases which passed.
+	FailedCount   int           // The total number of test cases which failed.
+	SkippedCount  int           // The total number of test cases which were skipped.
+	NotRunCount   int           // The total number of test cases that weren't run.
+	TotalCount    int           // The total number of test cases.
+	PassRate      float64       // The percentage (0 - 100) of executed (passed or failed) test cases which passed.
+	TotalDuration time.Duration // The time spent running the tests of all the assemblies.
+}
+
+// Stats returns the aggregated statistics of all the assemblies in the test run.
+// When no test case was executed, the pass rate is 0.
+func (testRun *TestRun) Stats() Stats {
+	stats := Stats{AssemblyCount: len(testRun.Assemblies)}
+
+	for _, assembly := range testRun.Assemblies {
+		stats.ErrorCount += assembly.ErrorCount
+		stats.PassedCount += assembly.PassedCount
+		stats.FailedCount += assembly.FailedCount
+		stats.SkippedCount += assembly.SkippedCount
+		stats.NotRunCount += assembly.NotRunCount
+		stats.TotalCount += assembly.TotalCount
+		stats.TotalDuration += assembly.Duration
+	}
+
+	if executed := stats.PassedCount + stats.FailedCount; executed > 0 {
+		stats.PassRate = float64(stats.PassedCount) / float64(executed) * 100
+	}
+
+	return stats
+}
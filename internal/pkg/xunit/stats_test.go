@@ -0,0 +1,85 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Get the aggregated statistics of a test run.
+func TestTestRunStats(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		testRun xunit.TestRun
+		want    xunit.Stats
+	}{
+		{
+			testRun: xunit.TestRun{},
+			want:    xunit.Stats{},
+		},
+		{
+			testRun: xunit.TestRun{
+				Assemblies: []xunit.Assembly{
+					{
+						ErrorCount: 1, PassedCount: 5, FailedCount: 1, SkippedCount: 1, NotRunCount: 1, TotalCount: 8,
+						Duration: time.Second,
+					},
+					{
+						PassedCount: 4, FailedCount: 2, TotalCount: 6,
+						Duration: 2 * time.Second,
+					},
+				},
+			},
+			want: xunit.Stats{
+				AssemblyCount: 2,
+				ErrorCount:    1,
+				PassedCount:   9,
+				FailedCount:   3,
+				SkippedCount:  1,
+				NotRunCount:   1,
+				TotalCount:    14,
+				PassRate:      75,
+				TotalDuration: 3 * time.Second,
+			},
+		},
+	} {
+		// ACT.
+		got := tc.testRun.Stats()
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Get the aggregated statistics of a test run.\n"+
+			"Input:      %+v\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.testRun, tc.want, got)
+	}
+}
@@ -0,0 +1,326 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package xunit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/set"
+)
+
+// The results a test can have.
+var results = set.New("Pass", "Fail", "Skip", "NotRun")
+
+// Problem describes a problem of a document, which makes (a part of) the test results it contains unreliable, even if
+// the document can be loaded (e.g. an assembly which reports more tests than it contains).
+type Problem struct {
+	Line    int    // The (1-based) number of the line of the element with the problem (0 if it's about the document).
+	Element string // The element with the problem (e.g. `test "NS.Class.Test"`), empty if it's about the document.
+	Message string // The description of the problem.
+}
+
+// The number of tests of an element, per result.
+type counts struct {
+	total, passed, failed, skipped, notRun int
+}
+
+// Collects the problems of a document.
+type validator struct {
+	doc      []byte           // The (escaped) document.
+	ids      map[string]int64 // The offsets of the elements, by the kind of the element and its ID (e.g. "test/1").
+	problems []Problem        // The problems found so far.
+}
+
+// String returns the description of p, preceded by the element with the problem (if any).
+func (p Problem) String() string {
+	if p.Element == "" {
+		return p.Message
+	}
+
+	return p.Element + ": " + p.Message
+}
+
+// Validate reads a document from rdr, and returns its problems, in the order of the document:
+//   - the deviations from xUnit's v2+ XML format (e.g. missing attributes, or unknown results),
+//   - the counts of the assemblies and the collections which don't match the tests they contain,
+//   - the IDs which are used by more than one assembly, collection or test,
+//   - and the times (e.g. the `start-rtf` or `run-date` attributes) which can't be parsed.
+//
+// If the data can't be read, or isn't a valid document, the error is a *ParseError.
+func Validate(rdr io.Reader) ([]Problem, error) {
+	data, err := io.ReadAll(rdr)
+	data = escapeControls(data)
+
+	if err != nil {
+		return nil, readError(data, err)
+	}
+
+	res, err := decode(context.Background(), data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v := validator{doc: data, ids: make(map[string]int64)}
+
+	if _, warning := res.schemaVersion(); warning != "" {
+		v.add(-1, "", warning)
+	}
+
+	v.diagnostics(diagnose(bytes.NewReader(data)))
+	v.times(-1, "", res.StartRTF, res.FinishRTF, "")
+
+	for _, assembly := range res.Assemblies {
+		v.assembly(assembly)
+	}
+
+	return v.problems, nil
+}
+
+// Adds the problem described by msg to v, for element (at offset in the document, or -1 if it's unknown).
+func (v *validator) add(offset int64, element, msg string) {
+	p := Problem{Element: element, Message: msg}
+
+	if offset >= 0 {
+		p.Line = lineAt(v.doc, int(offset))
+	}
+
+	v.problems = append(v.problems, p)
+}
+
+// Adds the deviations of diag to v.
+func (v *validator) diagnostics(diag Diagnostics) {
+	for _, name := range maps.SortedKeys(diag.MissingAttributes) {
+		el, attr, _ := strings.Cut(name, "/@")
+		v.add(-1, "", fmt.Sprintf("%d <%s> element(s) don't have the required attribute %q",
+			diag.MissingAttributes[name], el, attr))
+	}
+
+	for _, name := range maps.SortedKeys(diag.UnknownAttributes) {
+		el, attr, _ := strings.Cut(name, "/@")
+		v.add(-1, "", fmt.Sprintf("%d <%s> element(s) have the unknown attribute %q",
+			diag.UnknownAttributes[name], el, attr))
+	}
+
+	for _, name := range maps.SortedKeys(diag.SkippedElements) {
+		parent, el, _ := strings.Cut(name, "/")
+		v.add(-1, "", fmt.Sprintf("%d unknown <%s> element(s) in <%s>", diag.SkippedElements[name], el, parent))
+	}
+}
+
+// Adds the problems of assembly, and of its collections, to v.
+func (v *validator) assembly(assembly assembly) {
+	element := fmt.Sprintf("assembly %q", assembly.name())
+	v.id(assembly.offset, element, "assembly", assembly.ID)
+	v.times(assembly.offset, element, assembly.StartRTF, assembly.FinishRTF, assembly.TimeRTF)
+
+	if assembly.RunDate != "" {
+		if _, err := time.Parse("2006-01-02", assembly.RunDate); err != nil {
+			v.add(assembly.offset, element, fmt.Sprintf("invalid run-date %q (expected yyyy-MM-dd)", assembly.RunDate))
+		}
+	}
+
+	if assembly.RunTime != "" {
+		if _, err := time.Parse("15:04:05", assembly.RunTime); err != nil {
+			v.add(assembly.offset, element, fmt.Sprintf("invalid run-time %q (expected HH:mm:ss)", assembly.RunTime))
+		}
+	}
+
+	var actual counts
+
+	for _, c := range assembly.Collections {
+		actual.add(c.Tests)
+	}
+
+	for _, mismatch := range countMismatches(assembly.counts(), actual) {
+		v.add(assembly.offset, element, mismatch)
+	}
+
+	for _, c := range assembly.Collections {
+		v.collection(c)
+	}
+}
+
+// Adds the problems of c, and of its tests, to v.
+func (v *validator) collection(c collection) {
+	element := fmt.Sprintf("collection %q", c.Name)
+	v.id(c.offset, element, "collection", c.ID)
+	v.times(c.offset, element, "", "", c.TimeRTF)
+
+	if c.Time != "" {
+		if _, err := strconv.ParseFloat(normalizeDecimal(c.Time), 64); err != nil {
+			v.add(c.offset, element, fmt.Sprintf("invalid time %q (expected a number of seconds)", c.Time))
+		}
+	}
+
+	var actual counts
+
+	actual.add(c.Tests)
+
+	for _, mismatch := range countMismatches(c.counts(), actual) {
+		v.add(c.offset, element, mismatch)
+	}
+
+	for _, t := range c.Tests {
+		element := fmt.Sprintf("test %q", t.Name)
+		v.id(t.offset, element, "test", t.ID)
+		v.times(t.offset, element, "", "", t.TimeRTF)
+
+		if !results.Has(t.Result) {
+			v.add(t.offset, element, fmt.Sprintf("unknown result %q (expected Pass, Fail, Skip or NotRun)", t.Result))
+		}
+
+		if _, err := strconv.Atoi(t.SourceLine); t.SourceLine != "" && err != nil {
+			v.add(t.offset, element, fmt.Sprintf("invalid source-line %q (expected a line number)", t.SourceLine))
+		}
+	}
+}
+
+// Adds a problem to v if id (the ID of element, at offset) is already used by another element of the same kind.
+func (v *validator) id(offset int64, element, kind, id string) {
+	if id == "" {
+		return
+	}
+
+	key := kind + "/" + id
+
+	if other, ok := v.ids[key]; ok {
+		v.add(offset, element, fmt.Sprintf("duplicate ID %q (already used by the %s on line %d)", id, kind,
+			lineAt(v.doc, int(other))))
+
+		return
+	}
+
+	v.ids[key] = offset
+}
+
+// Adds a problem to v for each time of element (at offset) which can't be parsed: its start and finish time (in
+// round-trip format), and its duration (in round-trip format). Empty times aren't checked.
+func (v *validator) times(offset int64, element, start, finish, duration string) {
+	var startTime, finishTime time.Time
+
+	for _, t := range []struct {
+		attr, value string
+		time        *time.Time
+	}{
+		{attr: "start-rtf", value: start, time: &startTime},
+		{attr: "finish-rtf", value: finish, time: &finishTime},
+	} {
+		if t.value == "" {
+			continue
+		}
+
+		if *t.time = parseRTF(t.value); t.time.IsZero() {
+			v.add(offset, element, fmt.Sprintf("invalid %s %q (expected a time in round-trip format, e.g. "+
+				"2023-07-10T20:53:19.1234567+02:00)", t.attr, t.value))
+		}
+	}
+
+	if !startTime.IsZero() && !finishTime.IsZero() && finishTime.Before(startTime) {
+		v.add(offset, element, fmt.Sprintf("finish-rtf %q is before start-rtf %q", finish, start))
+	}
+
+	if _, ok := parseTimeSpan(duration); duration != "" && !ok {
+		v.add(offset, element, fmt.Sprintf("invalid time-rtf %q (expected a duration in round-trip format, e.g. "+
+			"00:00:01.2345678)", duration))
+	}
+}
+
+// Returns the counts reported by the assembly.
+func (assembly *assembly) counts() counts {
+	return counts{
+		total:   assembly.Total,
+		passed:  assembly.PassedCount,
+		failed:  assembly.FailedCount,
+		skipped: assembly.SkippedCount,
+		notRun:  assembly.NotRunCount,
+	}
+}
+
+// Returns the counts reported by the collection.
+func (c *collection) counts() counts {
+	return counts{
+		total:   c.TotalCount,
+		passed:  c.PassedCount,
+		failed:  c.FailedCount,
+		skipped: c.SkippedCount,
+		notRun:  c.NotRunCount,
+	}
+}
+
+// Adds tests to c, by result.
+func (c *counts) add(tests []test) {
+	for _, t := range tests {
+		switch t.Result {
+		case "Pass":
+			c.passed++
+		case "Fail":
+			c.failed++
+		case "Skip":
+			c.skipped++
+		case "NotRun":
+			c.notRun++
+		}
+
+		c.total++
+	}
+}
+
+// Returns the descriptions of the reported counts which don't match the actual number of tests (e.g. "reports 2
+// failed tests, but contains 1"), if there are both.
+func countMismatches(reported, actual counts) []string {
+	// NOTE: Without tests, or without counts, there's nothing to compare (e.g. a document with only a summary).
+	if actual.total == 0 || reported.total+reported.passed+reported.failed+reported.skipped == 0 {
+		return nil
+	}
+
+	var mismatches []string
+
+	for _, c := range []struct {
+		name             string
+		reported, actual int
+	}{
+		{name: "passed", reported: reported.passed, actual: actual.passed},
+		{name: "failed", reported: reported.failed, actual: actual.failed},
+		{name: "skipped", reported: reported.skipped, actual: actual.skipped},
+		{name: "not run", reported: reported.notRun, actual: actual.notRun},
+		{name: "total", reported: reported.total, actual: actual.total},
+	} {
+		if c.reported != c.actual {
+			mismatches = append(mismatches, fmt.Sprintf("reports %d %s tests, but contains %d", c.reported, c.name,
+				c.actual))
+		}
+	}
+
+	return mismatches
+}
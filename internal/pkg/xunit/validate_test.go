@@ -0,0 +1,167 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunit" package.
+package xunit_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+)
+
+// UT: Report the problems of a document.
+func TestValidate(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		xmlData string
+		want    []xunit.Problem
+	}{
+		{
+			name: "A valid document",
+			xmlData: "<assemblies schema-version=\"3\" start-rtf=\"2023-07-10T20:53:19.0000000+02:00\" " +
+				"finish-rtf=\"2023-07-10T20:53:20.0000000+02:00\">\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" " +
+				"time-rtf=\"00:00:01.0000000\" total=\"2\" passed=\"1\" failed=\"1\" skipped=\"0\" id=\"a1\">\n" +
+				"    <collection name=\"C\" time=\"1,0\" total=\"2\" passed=\"1\" failed=\"1\" skipped=\"0\" id=\"c1\">\n" +
+				"      <test name=\"T1\" type=\"NS.C\" method=\"T1\" time=\"0.5\" result=\"Pass\" id=\"t1\" />\n" +
+				"      <test name=\"T2\" type=\"NS.C\" method=\"T2\" time=\"0.5\" result=\"Fail\" id=\"t2\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+		},
+		{
+			name: "A document which deviates from the format",
+			xmlData: "<assemblies schema-version=\"9\">\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"1\" " +
+				"passed=\"0\" failed=\"0\" skipped=\"0\" color=\"red\">\n" +
+				"    <collection name=\"C\" time=\"1\" total=\"1\" passed=\"0\" failed=\"0\" skipped=\"0\">\n" +
+				"      <test name=\"T\" type=\"NS.C\" method=\"T\" result=\"Passed\" source-line=\"x\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []xunit.Problem{
+				{Message: "unknown schema version \"9\", the document is parsed as version 3"},
+				{Message: "1 <test> element(s) don't have the required attribute \"time\""},
+				{Message: "1 <assembly> element(s) have the unknown attribute \"color\""},
+				{Line: 4, Element: "test \"T\"", Message: "unknown result \"Passed\" (expected Pass, Fail, Skip or NotRun)"},
+				{Line: 4, Element: "test \"T\"", Message: "invalid source-line \"x\" (expected a line number)"},
+			},
+		},
+		{
+			name: "A document with counts which don't match its tests",
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"3\" " +
+				"passed=\"2\" failed=\"1\" skipped=\"0\">\n" +
+				"    <collection name=\"C\" time=\"1\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\">\n" +
+				"      <test name=\"T1\" type=\"NS.C\" method=\"T1\" time=\"0.5\" result=\"Pass\" />\n" +
+				"      <test name=\"T2\" type=\"NS.C\" method=\"T2\" time=\"0.5\" result=\"Pass\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []xunit.Problem{
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "reports 1 failed tests, but contains 0"},
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "reports 3 total tests, but contains 2"},
+				{Line: 3, Element: "collection \"C\"", Message: "reports 1 passed tests, but contains 2"},
+				{Line: 3, Element: "collection \"C\"", Message: "reports 1 total tests, but contains 2"},
+			},
+		},
+		{
+			name: "A document with duplicate IDs",
+			xmlData: "<assemblies schema-version=\"3\">\n" +
+				"  <assembly name=\"App.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" time=\"1\" total=\"2\" " +
+				"passed=\"2\" failed=\"0\" skipped=\"0\" id=\"1\">\n" +
+				"    <collection name=\"C\" time=\"1\" total=\"2\" passed=\"2\" failed=\"0\" skipped=\"0\" id=\"1\">\n" +
+				"      <test name=\"T1\" type=\"NS.C\" method=\"T1\" time=\"0.5\" result=\"Pass\" id=\"1\" />\n" +
+				"      <test name=\"T2\" type=\"NS.C\" method=\"T2\" time=\"0.5\" result=\"Pass\" id=\"1\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []xunit.Problem{
+				{Line: 5, Element: "test \"T2\"", Message: "duplicate ID \"1\" (already used by the test on line 4)"},
+			},
+		},
+		{
+			name: "A document with times which can't be parsed",
+			xmlData: "<assemblies start-rtf=\"2023-07-10T20:53:20+02:00\" finish-rtf=\"2023-07-10T20:53:19+02:00\">\n" +
+				"  <assembly name=\"App.dll\" run-date=\"10/07/2023\" run-time=\"8:53 PM\" time=\"1\" total=\"1\" " +
+				"passed=\"1\" failed=\"0\" skipped=\"0\" start-rtf=\"yesterday\" time-rtf=\"1s\">\n" +
+				"    <collection name=\"C\" time=\"fast\" total=\"1\" passed=\"1\" failed=\"0\" skipped=\"0\">\n" +
+				"      <test name=\"T\" type=\"NS.C\" method=\"T\" time=\"0.5\" result=\"Pass\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []xunit.Problem{
+				{Message: "finish-rtf \"2023-07-10T20:53:19+02:00\" is before start-rtf \"2023-07-10T20:53:20+02:00\""},
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "invalid start-rtf \"yesterday\" (expected a " +
+					"time in round-trip format, e.g. 2023-07-10T20:53:19.1234567+02:00)"},
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "invalid time-rtf \"1s\" (expected a duration in " +
+					"round-trip format, e.g. 00:00:01.2345678)"},
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "invalid run-date \"10/07/2023\" (expected " +
+					"yyyy-MM-dd)"},
+				{Line: 2, Element: "assembly \"App.dll\"", Message: "invalid run-time \"8:53 PM\" (expected HH:mm:ss)"},
+				{Line: 3, Element: "collection \"C\"", Message: "invalid time \"fast\" (expected a number of seconds)"},
+			},
+		},
+	} {
+		// ACT.
+		got, err := xunit.Validate(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "Validate()")
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Report the problems of a document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Fail to validate a document which isn't valid XML.
+func TestValidate_ParseError(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ACT.
+	_, err := xunit.Validate(strings.NewReader("<assemblies>\n  <assembly name=\"App.dll\" total=\"x\">"))
+
+	// ASSERT.
+	var parseErr *xunit.ParseError
+
+	assert.Equal(t, errors.As(err, &parseErr), true, "", "\n\n"+
+		"UT Name:    Fail to validate a document which isn't valid XML.\n"+
+		"\033[32mExpected:   A *xunit.ParseError\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+
+	assert.Equal(t, parseErr.Line, 2, "", "\n\n"+
+		"UT Name:    Fail to validate a document which isn't valid XML.\n"+
+		"\033[32mExpected:   Line 2\033[0m\n"+
+		"\033[31mActual:     Line %d\033[0m\n\n", parseErr.Line)
+}
@@ -28,11 +28,23 @@
 package xunit
 
 import (
+	"bytes"
+	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kdeconinck/dtvisual/internal/pkg/maps"
+	"github.com/kdeconinck/dtvisual/internal/pkg/slices"
+)
+
+// The versions of the schema of xUnit's XML format which are known.
+const (
+	schemaV2     = 2 // The v2 format, which doesn't have a `schema-version` attribute.
+	schemaLatest = 3 // The latest revision, which adds the IDs and the times in round-trip format (RTF).
 )
 
 // A result is the top-level element of the document. It's the result of a `dotnet test` operation in xUnit's v2+ XML
@@ -47,6 +59,7 @@ type result struct {
 	Timestamp     string     `xml:"timestamp,attr"`
 	User          string     `xml:"user,attr"`
 	Assemblies    []assembly `xml:"assembly"`
+	Unknown       []unknown  `xml:",any"`
 }
 
 // An assembly contains information about the run of a single test assembly.
@@ -67,28 +80,56 @@ type assembly struct {
 	StartRTF        string       `xml:"start-rtf,attr"`
 	TargetFramework string       `xml:"target-framework,attr"`
 	TestFramework   string       `xml:"test-framework,attr"`
-	Time            float32      `xml:"time,attr"`
+	Time            decimal      `xml:"time,attr"`
 	TimeRTF         string       `xml:"time-rtf,attr"`
 	Total           int          `xml:"total,attr"`
 	Collections     []collection `xml:"collection"`
 	ErrorSet        errorSet     `xml:"errors"`
+	Unknown         []unknown    `xml:",any"`
 
 	// Calculated fields.
-	testMap map[string][]TestCase // A map that contains all the tests of the assembly, grouped by trait.
+	opts     Options         // How the document is loaded.
+	interned interner        // The strings which are shared by the tests of the document.
+	ctx      context.Context // The context whose end stops grouping the tests (nil if it can't be stopped).
+	offset   int64           // The offset of the end of the start tag of the assembly in the document.
+
+	stats []Collection // The statistics of the collections, which are computed while grouping the tests.
+}
+
+// UnmarshalXML decodes the assembly from the element start, and records its offset in the document.
+func (a *assembly) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainAssembly assembly
+
+	a.offset = d.InputOffset()
+
+	return d.DecodeElement((*plainAssembly)(a), &start)
 }
 
 // A collection contains information about the run of a single test collection.
 type collection struct {
-	ID           string `xml:"id,attr"`
-	Name         string `xml:"name,attr"`
-	FailedCount  int    `xml:"failed,attr"`
-	NotRunCount  int    `xml:"not-run,attr"`
-	PassedCount  int    `xml:"passed,attr"`
-	SkippedCount int    `xml:"skipped,attr"`
-	Time         string `xml:"time,attr"`
-	TimeRTF      string `xml:"time-rtf,attr"`
-	TotalCount   int    `xml:"total,attr"`
-	Tests        []test `xml:"test"`
+	ID           string    `xml:"id,attr"`
+	Name         string    `xml:"name,attr"`
+	FailedCount  int       `xml:"failed,attr"`
+	NotRunCount  int       `xml:"not-run,attr"`
+	PassedCount  int       `xml:"passed,attr"`
+	SkippedCount int       `xml:"skipped,attr"`
+	Time         string    `xml:"time,attr"`
+	TimeRTF      string    `xml:"time-rtf,attr"`
+	TotalCount   int       `xml:"total,attr"`
+	Tests        []test    `xml:"test"`
+	Unknown      []unknown `xml:",any"`
+
+	// Calculated fields.
+	offset int64 // The offset of the end of the start tag of the collection in the document.
+}
+
+// UnmarshalXML decodes the collection from the element start, and records its offset in the document.
+func (c *collection) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainCollection collection
+
+	c.offset = d.InputOffset()
+
+	return d.DecodeElement((*plainCollection)(c), &start)
 }
 
 // A test contains information about the run of a single test.
@@ -99,7 +140,7 @@ type test struct {
 	Result     string     `xml:"result,attr"`
 	SourceFile string     `xml:"source-file,attr"`
 	SourceLine string     `xml:"source-line,attr"`
-	Time       float32    `xml:"time,attr"`
+	Time       decimal    `xml:"time,attr"`
 	TimeRTF    string     `xml:"time-rtf,attr"`
 	Type       string     `xml:"type,attr"`
 	Failure    failure    `xml:"failure"`
@@ -107,6 +148,25 @@ type test struct {
 	Reason     string     `xml:"reason"`
 	TraitSet   traitSet   `xml:"traits"`
 	WarningSet warningSet `xml:"warnings"`
+	Unknown    []unknown  `xml:",any"`
+
+	// Calculated fields.
+	offset int64 // The offset of the end of the start tag of the test in the document.
+}
+
+// UnmarshalXML decodes the test from the element start, and records its offset in the document.
+func (t *test) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainTest test
+
+	t.offset = d.InputOffset()
+
+	return d.DecodeElement((*plainTest)(t), &start)
+}
+
+// A parsedTest is a test, with its TestCase representation and the names of its groups.
+type parsedTest struct {
+	tc         TestCase // The TestCase representation of the test.
+	groupNames []string // The names of the groups the test belongs to, inside the group of each of its traits.
 }
 
 // A failure contains information a test failure.
@@ -144,29 +204,104 @@ type err struct {
 	Type string `xml:"type,attr"`
 }
 
+// A decimal is a number written with the decimal separator of the culture of the machine which wrote the document
+// (e.g. "0.125", or "0,125").
+type decimal float32
+
+// UnmarshalXMLAttr sets d to the number in attr, regardless of its decimal separator.
+func (d *decimal) UnmarshalXMLAttr(attr xml.Attr) error {
+	value := normalizeDecimal(attr.Value)
+
+	if value == "" {
+		*d = 0
+
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(value, 32)
+
+	if err != nil {
+		return err
+	}
+
+	*d = decimal(v)
+
+	return nil
+}
+
+// Returns s, a number written with the separators of any culture (e.g. "1,234.5", "1.234,5" or "1 234,5"), with a dot
+// as the decimal separator and without group separators (e.g. "1234.5").
+func normalizeDecimal(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\u00a0' || r == '\u202f' || r == '\'' {
+			return -1
+		}
+
+		return r
+	}, strings.TrimSpace(s))
+
+	// NOTE: The last separator is the decimal separator, every other one separates groups of digits.
+	sep := strings.LastIndexAny(s, ".,")
+
+	if sep < 0 {
+		return s
+	}
+
+	return strings.NewReplacer(".", "", ",", "").Replace(s[:sep]) + "." + s[sep+1:]
+}
+
+// An unknown is an element which isn't part of xUnit's v2+ XML format.
+type unknown struct {
+	XMLName xml.Name
+}
+
 // TestRun contains the relevant information stored in xUnit's v2+ XML format.
 type TestRun struct {
-	Computer     string     // The name of the computer that produced xUnit's v2+ XML format.
-	User         string     // The name of the user that produced xUnit's v2+ XML format.
-	StartTimeRTF string     // The time the first assembly started running.
-	EndTimeRTF   string     // The time the last assembly finished running.
-	Timestamp    string     // The time the first assembly started running.
-	Assemblies   []Assembly // The assemblies that are part of this test run.
+	Computer      string      // The name of the computer that produced xUnit's v2+ XML format.
+	User          string      // The name of the user that produced xUnit's v2+ XML format.
+	SchemaVersion int         // The version of the schema the document is parsed as (2 for documents without a version).
+	StartTimeRTF  string      // The time the first assembly started running.
+	EndTimeRTF    string      // The time the last assembly finished running.
+	StartTime     time.Time   // The time the first assembly started running (zero if StartTimeRTF isn't valid).
+	EndTime       time.Time   // The time the last assembly finished running (zero if EndTimeRTF isn't valid).
+	Timestamp     string      // The time the first assembly started running.
+	Assemblies    []Assembly  // The assemblies that are part of this test run.
+	Warnings      []string    // The problems with the document which didn't prevent loading it (e.g. unknown elements).
+	Incomplete    bool        // True if the document is truncated (or malformed), so only a part of it was loaded.
+	Diagnostics   Diagnostics // The deviations of the document from xUnit's v2+ XML format.
 }
 
 // Assembly contains information about the run of a single test assembly.
 // This includes environmental information.
 type Assembly struct {
-	Name        string       // The full name of the assembly.
-	ErrorCount  int          // The total number of environmental errors experienced in the assembly.
-	PassedCount int          // The total number of test cases in the assembly which passed.
-	FailedCount int          // The total number of test cases in the assembly which failed.
-	NotRunCount int          // The total number of test cases that weren't run.
-	TotalCount  int          // The total number of test cases in the assembly.
-	RunDate     string       // The date when the test run started.
-	RunTime     string       // The time when the test run started.
-	Time        string       // The time spent running the tests in the assembly.
-	Tests       []*TestGroup // All the tests of the assembly, grouped by trait.
+	Name         string        // The full name of the assembly.
+	ErrorCount   int           // The total number of environmental errors experienced in the assembly.
+	PassedCount  int           // The total number of test cases in the assembly which passed.
+	FailedCount  int           // The total number of test cases in the assembly which failed.
+	SkippedCount int           // The total number of test cases in the assembly which were skipped.
+	NotRunCount  int           // The total number of test cases that weren't run.
+	TotalCount   int           // The total number of test cases in the assembly.
+	RunDate      string        // The date when the test run started.
+	RunTime      string        // The time when the test run started.
+	StartTime    time.Time     // The time the assembly started running (zero if unknown).
+	EndTime      time.Time     // The time the assembly finished running (zero if unknown).
+	Time         string        // The time spent running the tests in the assembly.
+	Duration     time.Duration // The time spent running the tests in the assembly.
+	Collections  []Collection  // The test collections of the assembly, in the order of the document.
+	Tests        []*TestGroup  // All the tests of the assembly, grouped by trait (unless Options.GroupBy is set).
+}
+
+// Collection contains the statistics of a test collection (the tests which xUnit runs sequentially, while the
+// collections themselves run in parallel).
+type Collection struct {
+	ID           string        // The unique identifier of the collection.
+	Name         string        // The name of the collection.
+	PassedCount  int           // The total number of test cases in the collection which passed.
+	FailedCount  int           // The total number of test cases in the collection which failed.
+	SkippedCount int           // The total number of test cases in the collection which were skipped.
+	NotRunCount  int           // The total number of test cases in the collection that weren't run.
+	TotalCount   int           // The total number of test cases in the collection.
+	Duration     time.Duration // The time spent running the tests in the collection.
 }
 
 // TestGroup is a group of tests.
@@ -178,59 +313,608 @@ type TestGroup struct {
 
 // TestCase contains information about a single test.
 type TestCase struct {
-	Name   string // The name of the test, in human-readable format.
-	Result string // The status of the test.
+	ID         string        // The unique identifier of the test.
+	Name       string        // The name of the test, in human-readable format.
+	RawName    string        // The name of the test, as written in the document (which identifies the test).
+	Result     string        // The status of the test.
+	Collection string        // The name of the test collection the test belongs to.
+	Duration   time.Duration // The time spent running the test.
+	SourceFile string        // The source file in which the test is defined (if known).
+	SourceLine int           // The line in the source file at which the test is defined (if known).
+	Reason     string        // The reason the test was skipped (only for skipped tests).
+	Output     string        // The output written by the test (if any).
+	Failure    Failure       // The details of the failure (only for failed tests).
+	Traits     []Trait       // The traits of the test, in the order of the document (whatever the tests are grouped by).
+
+	// The handle of the details of the test (its reason, output and failure) in the Index it's loaded from (0 if it
+	// isn't loaded from an Index).
+	Handle int64
+
+	// The (1-based) position of the test in its assembly, which identifies it, even if another test has the same
+	// fields (0 if it isn't loaded from a document).
+	Index int
+}
+
+// A testKey identifies a test in its assembly.
+type testKey struct {
+	index int    // The position of the test in its assembly (if it's known).
+	id    string // The ID of the test (if its position isn't known).
+	name  string // The name of the test (if neither its position nor its ID is known).
 }
 
-// Load returns a TestRun constructed from the data in rdr.
+// Returns the key which identifies tc in its assembly: its position, or else its ID, or else its name.
+func (tc *TestCase) key() testKey {
+	switch {
+	case tc.Index > 0:
+		return testKey{index: tc.Index}
+	case tc.ID != "":
+		return testKey{id: tc.ID}
+	}
+
+	return testKey{name: tc.RawName}
+}
+
+// Failure contains information about a test failure.
+type Failure struct {
+	ExceptionType string // The fully qualified type name of the exception which caused the failure.
+	Message       string // The message of the exception which caused the failure.
+	StackTrace    string // The stack trace of the exception which caused the failure.
+}
+
+// Trait is a trait of a test (e.g. `[Trait("Category", "Unit")]`).
+type Trait struct {
+	Name  string // The name of the trait.
+	Value string // The value of the trait.
+}
+
+// String returns the friendly name of the trait (e.g. "Category - Unit"), which is the name of its group.
+func (t Trait) String() string {
+	return t.Name + " - " + t.Value
+}
+
+// ParseError is returned when a document can't be read, or isn't a valid document in xUnit's v2+ XML format.
+type ParseError struct {
+	Offset  int64  // The offset (in bytes) in the document at which the error occurred.
+	Line    int    // The (1-based) number of the line at which the error occurred.
+	Element string // The name of the innermost element being parsed when the error occurred (if any).
+	Err     error  // The underlying error.
+}
+
+// Error returns the message of e.
+func (e *ParseError) Error() string {
+	if e.Element == "" {
+		return fmt.Sprintf("xunit: line %d (offset %d): %v", e.Line, e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("xunit: line %d (offset %d), in <%s>: %v", e.Line, e.Offset, e.Element, e.Err)
+}
+
+// Unwrap returns the underlying error of e.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Options controls how a document is loaded.
+type Options struct {
+	// If true, the (complete) tests which precede the error in a truncated (or malformed) document are loaded, instead
+	// of failing (see LoadPartial).
+	Partial bool
+
+	Sanitize Sanitize // How the control characters in the names, reasons and failures of the tests are handled.
+
+	// Returns true if name (the name of a test) is a display name, which is kept as it is, rather than the (fully
+	// qualified) name of a method, which is split into the groups of the test (DefaultDisplayName if nil).
+	DisplayName func(name string) bool
+
+	Namespaces Namespaces // How the namespaces of nested tests are shown in their groups.
+
+	GroupBy GroupBy // The top-level groups of the tests of each assembly.
+
+	// The delimiters (e.g. "::" or "/") which separate the groups in the names of the tests which aren't nested
+	// classes (e.g. the test "Parser/Errors/Reports the line" is part of the group "Errors" in the group "Parser").
+	Delimiters []string
+
+	// If true, the names of the methods of the tests which aren't display names are written as sentences (e.g.
+	// "Returns an error when the input is nil" for "NS.Class.Returns_An_Error_When_The_Input_Is_Nil").
+	Humanize bool
+
+	// The maximum number of levels of groups inside the group of a trait (unlimited if 0). The deepest levels of the
+	// tests which are nested deeper are combined into a single group (e.g. "Inner / Innermost").
+	MaxDepth int
+
+	// If true, each group which only contains a single group (and no tests) is merged with that group (e.g. "Outer /
+	// Inner" for the group "Inner" in the group "Outer").
+	Collapse bool
+
+	// The maximum size (in bytes) of a document which an Index keeps in memory to load the details of its tests
+	// (unlimited if 0). Larger documents are streamed to a temporary file as soon as they exceed it (so they're never
+	// kept in memory as a whole), which is removed when the Index is closed.
+	MemoryBudget int64
+
+	index bool // True if the details of the tests are loaded on demand (see LoadIndex).
+}
+
+// DefaultDisplayName returns true if name looks like a display name: it contains a space, and no plus sign (which
+// separates nested classes), apart from the arguments the test is run with (e.g. "NS.Class.Test(text: \"a b\")"
+// isn't a display name).
+func DefaultDisplayName(name string) bool {
+	path := methodPath(name)
+
+	return strings.Contains(path, " ") && !strings.Contains(path, "+")
+}
+
+// IsAssertion returns true if the failure is caused by a failed assertion (of xUnit, NUnit or MSTest), or if the type
+// of its exception isn't known, and false if it's caused by another exception (e.g. a NullReferenceException).
+func (f Failure) IsAssertion() bool {
+	return f.ExceptionType == "" || strings.HasPrefix(f.ExceptionType, "Xunit.Sdk.") ||
+		strings.Contains(f.ExceptionType, "Assert")
+}
+
+// Load returns a TestRun constructed from the data in rdr, with the control characters of its tests stripped.
+// If the data can't be read, or isn't a valid document, the error is a *ParseError.
 func Load(rdr io.Reader) (TestRun, error) {
-	data, err := unmarshal(rdr)
+	return LoadWithOptions(rdr, Options{})
+}
+
+// LoadPartial is like Load, but if the document is truncated (e.g. because the test runner crashed while writing it)
+// or malformed, it returns the (complete) assemblies and tests which precede the error, in a TestRun which is marked
+// as incomplete. The error is reported as a warning of the TestRun.
+// It only returns an error (a *ParseError) if the document doesn't even contain the start of the root element.
+func LoadPartial(rdr io.Reader) (TestRun, error) {
+	return LoadWithOptions(rdr, Options{Partial: true})
+}
+
+// LoadWithOptions is like Load, but loads the document as configured by opts.
+func LoadWithOptions(rdr io.Reader, opts Options) (TestRun, error) {
+	return LoadContext(context.Background(), rdr, opts)
+}
+
+// LoadContext is like LoadWithOptions, but stops reading, decoding and grouping the tests of the document when ctx is
+// done, in which case it returns the error of ctx.
+func LoadContext(ctx context.Context, rdr io.Reader, opts Options) (TestRun, error) {
+	testRun, _, err := load(ctx, rdr, opts)
+
+	return testRun, err
+}
+
+// Returns a TestRun constructed from the data in rdr, as configured by opts, and the (escaped) document it's
+// constructed from. It stops when ctx is done.
+func load(ctx context.Context, rdr io.Reader, opts Options) (TestRun, []byte, error) {
+	var res result
+
+	data, err := io.ReadAll(&ctxReader{ctx: ctx, rdr: rdr})
+	data = escapeControls(data)
 
 	if err != nil {
-		return TestRun{}, err
+		err = readError(data, err)
+	} else {
+		res, err = decode(ctx, data)
 	}
 
-	testRun := TestRun{
-		Computer:     data.Computer,
-		User:         data.User,
-		StartTimeRTF: data.StartRTF,
-		EndTimeRTF:   data.FinishRTF,
-		Timestamp:    data.Timestamp,
-		Assemblies:   make([]Assembly, 0, len(data.Assemblies)),
-	}
-
-	// Loop over each assembly.
-	for _, assembly := range data.Assemblies {
-		testRun.Assemblies = append(testRun.Assemblies, Assembly{
-			Name:        assembly.name(),
-			ErrorCount:  assembly.ErrorCount,
-			PassedCount: assembly.PassedCount,
-			FailedCount: assembly.FailedCount,
-			NotRunCount: assembly.NotRunCount,
-			TotalCount:  assembly.Total,
-			RunDate:     assembly.RunDate,
-			RunTime:     assembly.RunTime,
-			Time:        assembly.TimeRTF,
-			Tests:       assembly.groupTests(),
-		})
+	if ctx.Err() != nil {
+		return TestRun{}, nil, ctx.Err()
+	}
+
+	if err == nil {
+		testRun, err := newTestRunContext(ctx, res, bytes.NewReader(data), opts)
+
+		return testRun, data, err
+	}
+
+	if !opts.Partial {
+		return TestRun{}, nil, err
+	}
+
+	res, ok := recoverResult(data)
+
+	if !ok {
+		return TestRun{}, nil, err
+	}
+
+	testRun, err := newPartialTestRun(ctx, res, bytes.NewReader(data), opts, err)
+
+	if err != nil {
+		return TestRun{}, nil, err
+	}
+
+	return testRun, data, nil
+}
+
+// Returns an incomplete TestRun constructed from data, which is recovered from doc (which couldn't be decoded because
+// of err), as configured by opts. It stops when ctx is done, in which case it returns the error of ctx.
+func newPartialTestRun(ctx context.Context, data result, doc io.Reader, opts Options, err error) (TestRun, error) {
+	testRun, ctxErr := newTestRunContext(ctx, data, doc, opts)
+
+	if ctxErr != nil {
+		return TestRun{}, ctxErr
+	}
+
+	testRun.Incomplete = true
+	testRun.Warnings = append(testRun.Warnings, "the document is incomplete, only the tests before the error were "+
+		"loaded ("+err.Error()+")")
+
+	return testRun, nil
+}
+
+// Returns a TestRun constructed from data, which is decoded from doc, as configured by opts. It stops when ctx is done,
+// in which case it returns the error of ctx.
+func newTestRunContext(ctx context.Context, data result, doc io.Reader, opts Options) (TestRun, error) {
+	for idx := range data.Assemblies {
+		data.Assemblies[idx].ctx = ctx
+	}
+
+	testRun := newTestRun(data, doc, opts)
+
+	if ctx.Err() != nil {
+		return TestRun{}, ctx.Err()
 	}
 
 	return testRun, nil
 }
 
-// Returns a result, constructed from the data in rdr.
-func unmarshal(rdr io.Reader) (result, error) {
+// A ctxReader is a reader which fails with the error of its context once it's done.
+type ctxReader struct {
+	ctx context.Context
+	rdr io.Reader
+}
+
+// Read reads from the underlying reader, unless the context of r is done.
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.rdr.Read(p)
+}
+
+// Returns a TestRun constructed from data, which is decoded from doc, as configured by opts.
+func newTestRun(data result, doc io.Reader, opts Options) TestRun {
+	version, versionWarning := data.schemaVersion()
+	data.adapt(version)
+
+	// NOTE: The results, the source files, ... of the tests repeat thousands of times in large documents.
+	strs := make(interner)
+
+	testRun := TestRun{
+		Computer:      data.Computer,
+		User:          data.User,
+		SchemaVersion: version,
+		StartTimeRTF:  data.StartRTF,
+		EndTimeRTF:    data.FinishRTF,
+		StartTime:     parseRTF(data.StartRTF),
+		EndTime:       parseRTF(data.FinishRTF),
+		Timestamp:     data.Timestamp,
+		Assemblies: slices.Map(data.Assemblies, func(assembly assembly) Assembly {
+			assembly.opts, assembly.interned = opts, strs
+
+			// NOTE: The statistics of the collections are computed while the tests are grouped, so they must be
+			// grouped first.
+			tests := assembly.groupTests()
+
+			return Assembly{
+				Name:         assembly.name(),
+				ErrorCount:   assembly.ErrorCount,
+				PassedCount:  assembly.PassedCount,
+				FailedCount:  assembly.FailedCount,
+				SkippedCount: assembly.SkippedCount,
+				NotRunCount:  assembly.NotRunCount,
+				TotalCount:   assembly.Total,
+				RunDate:      assembly.RunDate,
+				RunTime:      assembly.RunTime,
+				StartTime:    assembly.startTime(),
+				EndTime:      parseRTF(assembly.FinishRTF),
+				Time:         assembly.TimeRTF,
+				Duration:     seconds(assembly.Time),
+				Collections:  assembly.stats,
+				Tests:        tests,
+			}
+		}),
+		Warnings:    data.warnings(),
+		Diagnostics: diagnose(doc),
+	}
+
+	if versionWarning != "" {
+		testRun.Warnings = append([]string{versionWarning}, testRun.Warnings...)
+	}
+
+	return testRun
+}
+
+// Walk calls fn for each test of the assembly, passing the names of the groups (from the outermost to the innermost)
+// the test belongs to. The unnamed group, which holds the tests without a trait, isn't part of the path.
+// Tests that belong to multiple groups (e.g. because they have multiple traits) are only visited once, with the path
+// of the first group they're found in.
+func (assembly *Assembly) Walk(fn func(path []string, tc TestCase)) {
+	seen := make(map[testKey]bool)
+
+	var walk func(path []string, groups []*TestGroup)
+
+	walk = func(path []string, groups []*TestGroup) {
+		for _, group := range groups {
+			gPath := path
+
+			if group.Name != "" {
+				gPath = append(path[:len(path):len(path)], group.Name)
+			}
+
+			for _, tc := range group.Tests {
+				if key := tc.key(); !seen[key] {
+					seen[key] = true
+
+					fn(gPath, tc)
+				}
+			}
+
+			walk(gPath, group.Groups)
+		}
+	}
+
+	walk(nil, assembly.Tests)
+}
+
+// Merge returns a single TestRun containing the assemblies (warnings and diagnostics) of all testRuns, in the given
+// order (which is incomplete if any of testRuns is).
+// The information about the test run itself (computer, user, ...) is taken from the first test run.
+func Merge(testRuns ...TestRun) TestRun {
+	if len(testRuns) == 0 {
+		return TestRun{}
+	}
+
+	testRun := testRuns[0]
+	testRun.Assemblies = append([]Assembly(nil), testRun.Assemblies...)
+	testRun.Warnings = append([]string(nil), testRun.Warnings...)
+
+	for _, other := range testRuns[1:] {
+		testRun.Assemblies = append(testRun.Assemblies, other.Assemblies...)
+		testRun.Warnings = append(testRun.Warnings, other.Warnings...)
+		testRun.Incomplete = testRun.Incomplete || other.Incomplete
+		testRun.Diagnostics = testRun.Diagnostics.merge(other.Diagnostics)
+	}
+
+	return testRun
+}
+
+// Returns the error err, which occurred after reading data, as a *ParseError.
+func readError(data []byte, err error) *ParseError {
+	return &ParseError{Offset: int64(len(data)), Line: lineAt(data, len(data)), Err: err}
+}
+
+// Returns a result, constructed from data. It stops when ctx is done.
+func decode(ctx context.Context, data []byte) (result, error) {
 	var res result
 
-	if bytes, err := io.ReadAll(rdr); err == nil {
-		if err := xml.Unmarshal(bytes, &res); err != nil {
-			return result{}, err
+	dec := xml.NewDecoder(&ctxReader{ctx: ctx, rdr: bytes.NewReader(data)})
+
+	if err := dec.Decode(&res); err != nil {
+		offset := dec.InputOffset()
+
+		return result{}, &ParseError{
+			Offset:  offset,
+			Line:    lineAt(data, int(offset)),
+			Element: elementAt(bytes.NewReader(data), offset),
+			Err:     err,
 		}
 	}
 
 	return res, nil
 }
 
+// Returns the result constructed from the complete assemblies, collections and tests at the start of data (which is
+// truncated or malformed), and false if data doesn't even contain the start of the root element.
+// Elements which aren't tests are skipped.
+func recoverResult(data []byte) (result, bool) {
+	var res result
+	var inRoot bool
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	assemblyIdx, collectionIdx := -1, -1
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return res, inRoot
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case !inRoot && tok.Name.Local == "assemblies":
+				err = decodeAttrs(&res, tok)
+				inRoot = err == nil
+			case inRoot && assemblyIdx < 0 && tok.Name.Local == "assembly":
+				var a assembly
+
+				err = decodeAttrs(&a, tok)
+				res.Assemblies = append(res.Assemblies, a)
+				assemblyIdx = len(res.Assemblies) - 1
+			case assemblyIdx >= 0 && collectionIdx < 0 && tok.Name.Local == "collection":
+				var c collection
+
+				err = decodeAttrs(&c, tok)
+				res.Assemblies[assemblyIdx].Collections = append(res.Assemblies[assemblyIdx].Collections, c)
+				collectionIdx = len(res.Assemblies[assemblyIdx].Collections) - 1
+			case collectionIdx >= 0 && tok.Name.Local == "test":
+				var t test
+
+				if err = dec.DecodeElement(&t, &tok); err == nil {
+					c := &res.Assemblies[assemblyIdx].Collections[collectionIdx]
+					c.Tests = append(c.Tests, t)
+				}
+			default:
+				err = dec.Skip()
+			}
+		case xml.EndElement:
+			switch {
+			case collectionIdx >= 0:
+				collectionIdx = -1
+			case assemblyIdx >= 0:
+				assemblyIdx = -1
+			}
+		}
+
+		if err != nil {
+			return res, inRoot
+		}
+	}
+}
+
+// Decodes the attributes of the element start into v.
+func decodeAttrs(v any, start xml.StartElement) error {
+	var buf bytes.Buffer
+
+	enc := xml.NewEncoder(&buf)
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if err := enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(buf.Bytes(), v)
+}
+
+// Returns the (1-based) number of the line containing the byte at offset in data.
+func lineAt(data []byte, offset int) int {
+	return 1 + bytes.Count(data[:min(offset, len(data))], []byte("\n"))
+}
+
+// Returns the name of the innermost element of the document in rdr which is open at offset (or an empty string if
+// there's none).
+func elementAt(rdr io.Reader, offset int64) string {
+	dec := xml.NewDecoder(rdr)
+
+	var open []string
+
+	for dec.InputOffset() < offset {
+		tok, err := dec.RawToken()
+
+		if err != nil {
+			break
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			open = append(open, tok.Name.Local)
+		case xml.EndElement:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		}
+	}
+
+	if len(open) == 0 {
+		return ""
+	}
+
+	return open[len(open)-1]
+}
+
+// Returns the version of the schema of the document, and a warning if that version isn't known (in which case the
+// document is parsed as the latest known version).
+func (res *result) schemaVersion() (int, string) {
+	if res.SchemaVersion == "" {
+		return schemaV2, ""
+	}
+
+	version, err := strconv.Atoi(strings.TrimSpace(res.SchemaVersion))
+
+	if err == nil && version >= schemaV2 && version <= schemaLatest {
+		return version, ""
+	}
+
+	return schemaLatest, fmt.Sprintf("unknown schema version %q, the document is parsed as version %d",
+		res.SchemaVersion, schemaLatest)
+}
+
+// Adapts the attributes of the document to the given version of the schema.
+func (res *result) adapt(version int) {
+	// NOTE: Version 2 doesn't have durations in round-trip format, so there's nothing to fall back to.
+	if version < 3 {
+		return
+	}
+
+	for aIdx := range res.Assemblies {
+		assembly := &res.Assemblies[aIdx]
+		assembly.Time = rtfSeconds(assembly.Time, assembly.TimeRTF)
+
+		for cIdx := range assembly.Collections {
+			for tIdx := range assembly.Collections[cIdx].Tests {
+				t := &assembly.Collections[cIdx].Tests[tIdx]
+				t.Time = rtfSeconds(t.Time, t.TimeRTF)
+			}
+		}
+	}
+}
+
+// Returns the warnings about the elements of the document which aren't part of xUnit's v2+ XML format (or nil if
+// there are no such elements). Each unknown element is reported once per parent element type.
+func (res *result) warnings() []string {
+	counts := make(map[string]int)
+
+	add := func(parent string, elements []unknown) {
+		for _, el := range elements {
+			counts["unknown element <"+el.XMLName.Local+"> in <"+parent+">"]++
+		}
+	}
+
+	add("assemblies", res.Unknown)
+
+	for _, assembly := range res.Assemblies {
+		add("assembly", assembly.Unknown)
+
+		for _, collection := range assembly.Collections {
+			add("collection", collection.Unknown)
+
+			for _, t := range collection.Tests {
+				add("test", t.Unknown)
+			}
+		}
+	}
+
+	var warnings []string
+
+	for _, msg := range maps.SortedKeys(counts) {
+		if counts[msg] > 1 {
+			msg += " ignored (" + strconv.Itoa(counts[msg]) + " times)"
+		} else {
+			msg += " ignored"
+		}
+
+		warnings = append(warnings, msg)
+	}
+
+	for _, assembly := range res.Assemblies {
+		warnings = append(warnings, assembly.countWarnings()...)
+	}
+
+	return warnings
+}
+
+// Returns the warnings about the counts of the assembly (e.g. the number of failed tests) which don't match the
+// number of tests (with that result) it contains, if the assembly has both.
+func (assembly *assembly) countWarnings() []string {
+	var actual counts
+
+	for _, collection := range assembly.Collections {
+		actual.add(collection.Tests)
+	}
+
+	var warnings []string
+
+	for _, mismatch := range countMismatches(assembly.counts(), actual) {
+		warnings = append(warnings, fmt.Sprintf("assembly %q %s", assembly.name(), mismatch))
+	}
+
+	return warnings
+}
+
 // Returns the name of the assembly.
 func (assembly *assembly) name() string {
 	if strings.Contains(assembly.FullName, "/") {
@@ -240,54 +924,71 @@ func (assembly *assembly) name() string {
 	return assembly.FullName[strings.LastIndex(assembly.FullName, "\\")+1:]
 }
 
-// Returns a map of tests, grouped per trait of the assembly.
+// Returns the tests of the assembly, grouped per trait of the assembly (or as configured by the GroupBy option).
 func (assembly *assembly) groupTests() []*TestGroup {
 	if !assembly.hasTests() {
 		return make([]*TestGroup, 0)
 	}
 
-	uniqueTraits := assembly.uniqueTraits()
-	resultSet := make([]*TestGroup, 0, len(uniqueTraits))
+	testMap := assembly.testsByGroup()
+	traits := maps.SortedKeysFunc(testMap, naturalCompare)
+	resultSet := make([]*TestGroup, 0, len(traits))
 
-	for idx, trait := range uniqueTraits {
-		cGroup := &TestGroup{Name: trait, Tests: make([]TestCase, 0, len(assembly.testMap[trait]))}
-		resultSet = append(resultSet, cGroup)
+	// NOTE: The subgroups of each group are indexed by name, so finding the group of a test doesn't depend on the
+	// number of groups.
+	subgroups := make(map[*TestGroup]map[string]*TestGroup)
 
-		for _, tc := range assembly.testMap[trait] {
-			if tc.hasDisplayName() || !tc.isNested() {
-				cGroup.Tests = append(cGroup.Tests, TestCase{Name: tc.Name, Result: tc.Result})
-			} else {
-				for idx, nn := range tc.nestedNames() {
-					var sGroup *TestGroup
+	for _, trait := range traits {
+		cGroup := &TestGroup{Name: trait, Tests: make([]TestCase, 0, len(testMap[trait]))}
+		resultSet = append(resultSet, cGroup)
 
-					for _, group := range cGroup.Groups {
-						if group.Name == nn {
-							sGroup = group
+		for _, pt := range testMap[trait] {
+			group := cGroup
 
-							break
-						}
-					}
+			for _, nn := range pt.groupNames {
+				sGroup, ok := subgroups[group][nn]
 
-					if sGroup == nil {
-						sGroup = &TestGroup{Name: nn}
-						cGroup.Groups = append(cGroup.Groups, sGroup)
-					}
+				if !ok {
+					sGroup = &TestGroup{Name: nn}
+					group.Groups = append(group.Groups, sGroup)
 
-					if idx == len(tc.nestedNames())-1 {
-						sGroup.Tests = append(sGroup.Tests, tc)
+					if subgroups[group] == nil {
+						subgroups[group] = make(map[string]*TestGroup)
 					}
 
-					cGroup = sGroup
+					subgroups[group][nn] = sGroup
 				}
 
-				cGroup = resultSet[idx]
+				group = sGroup
 			}
+
+			group.Tests = append(group.Tests, pt.tc)
+		}
+
+		if assembly.opts.Collapse {
+			collapseGroups(cGroup.Groups)
 		}
 	}
 
 	return resultSet
 }
 
+// Returns the time the assembly started running (from its `start-rtf` attribute, or else from its `run-date` and
+// `run-time` attributes), or the zero time if it's unknown.
+func (assembly *assembly) startTime() time.Time {
+	if t := parseRTF(assembly.StartRTF); !t.IsZero() || assembly.RunDate == "" {
+		return t
+	}
+
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", assembly.RunDate+" "+assembly.RunTime, time.Local)
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
 // Returns true if the assembly has tests, false otherwise.
 func (assembly *assembly) hasTests() bool {
 	for _, collection := range assembly.Collections {
@@ -299,59 +1000,420 @@ func (assembly *assembly) hasTests() bool {
 	return false
 }
 
-// Returns all all the unique trait(s).
-func (assembly *assembly) uniqueTraits() []string {
-	assembly.testMap = make(map[string][]TestCase)
-	assembly.testMap[""] = nil
+// Returns the tests of the assembly, by the name of each of their top-level groups (see groupKeys), in a single pass
+// over the tests. The unnamed group is always present.
+func (assembly *assembly) testsByGroup() map[string][]parsedTest {
+	testMap := map[string][]parsedTest{"": nil}
 
-	for _, collection := range assembly.Collections {
-		for _, t := range collection.Tests {
-			if len(t.TraitSet.Traits) == 0 {
-				assembly.testMap[""] = append(assembly.testMap[""], TestCase{Name: t.Name, Result: t.Result})
-			}
+	index := 0
 
-			for _, tTrait := range t.TraitSet.Traits {
-				traitName := tTrait.friendlyName()
+	for cIdx := range assembly.Collections {
+		// NOTE: The remaining tests don't matter once the context is done, since the TestRun is discarded.
+		if assembly.ctx != nil && assembly.ctx.Err() != nil {
+			return testMap
+		}
+
+		collection := &assembly.Collections[cIdx]
+		stats := collection.stats(&assembly.opts)
 
-				assembly.testMap[traitName] = append(assembly.testMap[traitName], TestCase{Name: t.Name, Result: t.Result})
+		for tIdx := range collection.Tests {
+			t := &collection.Tests[tIdx]
+			tc := t.testCase(&assembly.opts, assembly.interned)
+			index++
+			tc.Collection, tc.Index = stats.Name, index
+			pt := parsedTest{tc: tc, groupNames: limitDepth(assembly.groupNames(t, tc), assembly.opts.MaxDepth)}
+
+			for _, key := range assembly.groupKeys(collection, t, tc) {
+				testMap[key] = append(testMap[key], pt)
 			}
 		}
+
+		assembly.stats = append(assembly.stats, stats)
+	}
+
+	return testMap
+}
+
+// Returns the statistics of the collection, as configured by opts, without its tests. The duration is taken from its
+// `time` attribute, and is 0 if it's unknown.
+func (c *collection) stats(opts *Options) Collection {
+	stats := Collection{
+		ID:           c.ID,
+		Name:         opts.Sanitize.apply(c.Name),
+		PassedCount:  c.PassedCount,
+		FailedCount:  c.FailedCount,
+		SkippedCount: c.SkippedCount,
+		NotRunCount:  c.NotRunCount,
+		TotalCount:   c.TotalCount,
+	}
+
+	if v, err := strconv.ParseFloat(normalizeDecimal(c.Time), 64); err == nil {
+		stats.Duration = seconds(decimal(v))
+	}
+
+	return stats
+}
+
+// Returns the names of the top-level groups which the test t (of collection, with the TestCase representation tc)
+// belongs to, according to the GroupBy option. When grouped by trait, a test belongs to the group of each of its
+// traits, or to the unnamed group if it doesn't have any.
+func (assembly *assembly) groupKeys(collection *collection, t *test, tc TestCase) []string {
+	switch assembly.opts.GroupBy {
+	case GroupByNamespace:
+		return []string{assembly.opts.Sanitize.apply(t.namespace(&assembly.opts, tc))}
+	case GroupByCollection:
+		return []string{assembly.opts.Sanitize.apply(collection.Name)}
+	case GroupByResult:
+		return []string{tc.Result}
+	case GroupByAssembly:
+		return []string{""}
+	}
+
+	// NOTE: The traits of tc don't contain its display name (which is its name, rather than a group), nor the traits
+	// which are listed twice (which would make the test appear twice in its group).
+	if len(tc.Traits) == 0 {
+		return []string{""}
+	}
+
+	return slices.Map(tc.Traits, Trait.String)
+}
+
+// Returns the TestCase representation of the test, as configured by opts, with its repeated strings interned in strs.
+func (t *test) testCase(opts *Options, strs interner) TestCase {
+	sourceLine, _ := strconv.Atoi(t.SourceLine)
+	mode := opts.Sanitize
+	rawName := mode.apply(t.Name)
+	name := rawName
+
+	if opts.Humanize && !t.hasDisplayName(opts) {
+		name = humanizeTestName(rawName)
+	}
+
+	// NOTE: An explicit display name takes precedence over the name derived from the name in the document.
+	if idx := slices.IndexFunc(t.TraitSet.Traits, func(tTrait trait) bool { return tTrait.isDisplayName() }); idx >= 0 {
+		name = mode.apply(t.TraitSet.Traits[idx].Value)
+	}
+
+	tc := TestCase{
+		ID:         t.ID,
+		Name:       name,
+		RawName:    rawName,
+		Result:     strs.intern(t.Result),
+		Duration:   seconds(t.Time),
+		SourceFile: strs.intern(t.SourceFile),
+		SourceLine: sourceLine,
+		Traits:     t.traits(opts, strs),
+	}
+
+	// NOTE: The details of the tests of an Index are loaded on demand (see Index.Details).
+	if opts.index {
+		tc.Handle = t.offset
+
+		return tc
+	}
+
+	tc.Reason, tc.Output = mode.apply(t.Reason), mode.apply(t.Output)
+	tc.Failure = Failure{
+		ExceptionType: strs.intern(t.Failure.ExceptionType),
+		Message:       mode.apply(t.Failure.Message),
+		StackTrace:    mode.apply(t.Failure.StackTrace),
+	}
+
+	return tc
+}
+
+// An interner holds a single copy of the strings which are interned, so equal strings share their memory.
+type interner map[string]string
+
+// Returns the copy of s held by the interner (which holds s if it doesn't hold a copy yet), or s if the interner is
+// nil.
+func (strs interner) intern(s string) string {
+	if strs == nil {
+		return s
+	}
+
+	if interned, ok := strs[s]; ok {
+		return interned
+	}
+
+	strs[s] = s
+
+	return s
+}
+
+// Returns the traits of the test (except its display name), as configured by opts, with their names and values interned
+// in strs. A trait which is listed twice is only returned once.
+func (t *test) traits(opts *Options, strs interner) []Trait {
+	var traits []Trait
+
+	for _, tTrait := range t.TraitSet.Traits {
+		if tTrait.isDisplayName() {
+			continue
+		}
+
+		name, value := opts.Sanitize.apply(tTrait.Name), opts.Sanitize.apply(tTrait.Value)
+		trait := Trait{Name: strs.intern(name), Value: strs.intern(value)}
+
+		if !slices.ContainsFunc(traits, func(other Trait) bool { return other == trait }) {
+			traits = append(traits, trait)
+		}
+	}
+
+	return traits
+}
+
+// Returns true if the trait holds the display name of its test (e.g. `[Trait("DisplayName", "Adds two numbers")]`).
+func (t *trait) isDisplayName() bool {
+	return strings.EqualFold(t.Name, "DisplayName") && t.Value != ""
+}
+
+// Returns the names of the groups (from the outermost to the innermost) the test t (with the TestCase representation
+// tc) belongs to, inside the group of its trait.
+func (assembly *assembly) groupNames(t *test, tc TestCase) []string {
+	switch {
+	case isNestedType(t.Type):
+		// NOTE: The declaring type of a test is more reliable than its name, which may be a display name which
+		// doesn't contain its classes (or which contains a plus sign, such as "1+1 equals 2").
+		return nestedNames(t.Type, assembly.opts.Namespaces)
+	case t.Type == "" && !t.hasDisplayName(&assembly.opts) && tc.isNested():
+		return nestedNames(typePath(tc.RawName), assembly.opts.Namespaces)
 	}
 
-	return maps.SortedKeys(assembly.testMap)
+	parts := splitAny(tc.RawName, assembly.opts.Delimiters)
+
+	return parts[:len(parts)-1]
 }
 
-// Returns the friendly name of the trait.
-func (t *trait) friendlyName() string {
-	var b strings.Builder
+// Merges each of the groups (and their subgroups) which only contains a single group (and no tests) with that group.
+func collapseGroups(groups []*TestGroup) {
+	for _, group := range groups {
+		for len(group.Tests) == 0 && len(group.Groups) == 1 {
+			child := group.Groups[0]
+			group.Name, group.Tests, group.Groups = group.Name+" / "+child.Name, child.Tests, child.Groups
+		}
 
-	b.WriteString(t.Name)
-	b.WriteString(" - ")
-	b.WriteString(t.Value)
+		collapseGroups(group.Groups)
+	}
+}
 
-	return b.String()
+// Returns names (the names of nested groups), with the deepest names combined into a single name, so there are at
+// most depth names (if it isn't 0).
+func limitDepth(names []string, depth int) []string {
+	if depth <= 0 || len(names) <= depth {
+		return names
+	}
+
+	return append(names[:depth-1:depth-1], strings.Join(names[depth-1:], " / "))
 }
 
-// Returns true if test has a display name, false otherwise.
-// A test has a display name if it contains spaces and NO plus signs.
-func (tc *TestCase) hasDisplayName() bool {
-	return strings.Contains(tc.Name, " ") && !strings.Contains(tc.Name, "+")
+// Returns true if name is a display name, according to opts.
+func (opts *Options) isDisplayName(name string) bool {
+	if opts.DisplayName != nil {
+		return opts.DisplayName(name)
+	}
+
+	return DefaultDisplayName(name)
+}
+
+// Returns true if the name of the test is a display name, according to opts.
+// When the document contains the declaring type of the test, and opts doesn't detect display names, the name of the
+// test is a display name unless it's the name of a method of its declaring type.
+func (t *test) hasDisplayName(opts *Options) bool {
+	if opts.DisplayName == nil && t.Type != "" {
+		return typePath(t.Name) != t.Type
+	}
+
+	return opts.isDisplayName(opts.Sanitize.apply(t.Name))
+}
+
+// Returns the namespace of the test t (with the TestCase representation tc), as configured by opts (e.g. "NS" for
+// "NS.Outer+Inner.Test"), or an empty string if it's unknown.
+func (t *test) namespace(opts *Options, tc TestCase) string {
+	typ := t.Type
+
+	if typ == "" {
+		if t.hasDisplayName(opts) {
+			return ""
+		}
+
+		typ = typePath(tc.RawName)
+	}
+
+	outer := splitTopLevel(typ, '+')[0]
+
+	if sep := lastIndexTopLevel(outer, '.'); sep >= 0 {
+		return outer[:sep]
+	}
+
+	return ""
 }
 
 // Returns true if the test is nested, false otherwise.
-// A test is nested if the name of the test contains one or more plus signs.
+// A test is nested if the name of the test contains one or more plus signs (outside of the generic arguments of its
+// types, and of the arguments it's run with).
 func (tc *TestCase) isNested() bool {
-	return strings.Contains(tc.Name, "+")
+	return len(splitTopLevel(methodPath(tc.RawName), '+')) > 1
+}
+
+// Returns true if typ (the full name of a type) is the name of a nested class, false otherwise.
+func isNestedType(typ string) bool {
+	return len(splitTopLevel(typ, '+')) > 1
 }
 
-// Returns the name of each nested level.
-func (tc *TestCase) nestedNames() []string {
-	retVal := strings.Split(tc.Name, "+")
+// Returns the name of each nested level of typ (the full name of a type), with readable names for generic types (e.g.
+// "Outer<T>" for "Outer`1"), and the namespace shown according to namespaces.
+func nestedNames(typ string, namespaces Namespaces) []string {
+	retVal := splitTopLevel(typ, '+')
+	first, last := retVal[0], retVal[len(retVal)-1]
+	sep := lastIndexTopLevel(first, '.')
 
-	parts := make([]string, 0, len(retVal[1:len(retVal)-1]))
-	parts = append(parts, retVal[0][strings.LastIndex(retVal[0], ".")+1:])
-	parts = append(parts, retVal[1:len(retVal)-1]...)
-	parts = append(parts, strings.Split(retVal[len(retVal)-1], ".")[0])
+	parts := make([]string, 0, len(retVal)+1)
+
+	switch {
+	case sep < 0 || namespaces == NamespacesStrip:
+		parts = append(parts, typeName(first[sep+1:]))
+	case namespaces == NamespacesGroup:
+		parts = append(parts, first[:sep], typeName(first[sep+1:]))
+	default:
+		parts = append(parts, first[:sep+1]+typeName(first[sep+1:]))
+	}
+
+	parts = append(parts, slices.Map(retVal[1:len(retVal)-1], typeName)...)
+	parts = append(parts, typeName(last))
 
 	return parts
 }
+
+// Returns a negative number if a < b, a positive number if a > b, and 0 if they're equal, in natural order: the numbers
+// in a and b are compared by their value (e.g. "Scenario 2" < "Scenario 10").
+func naturalCompare(a, b string) int {
+	origA, origB := a, b
+
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, numB := a[:digitCount(a)], b[:digitCount(b)]
+			a, b = a[len(numA):], b[len(numB):]
+			numA, numB = strings.TrimLeft(numA, "0"), strings.TrimLeft(numB, "0")
+
+			if len(numA) != len(numB) {
+				return len(numA) - len(numB)
+			}
+
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	// NOTE: Numbers with leading zeros (e.g. "01" and "1") are equal, but their strings aren't.
+	return strings.Compare(origA, origB)
+}
+
+// Returns true if c is an (ASCII) digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// Returns the number of (ASCII) digits at the start of s.
+func digitCount(s string) int {
+	n := 0
+
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+
+	return n
+}
+
+// Returns the time represented by s, a .NET DateTime in round-trip format (e.g. "2023-07-10T20:53:19.1234567+02:00"),
+// or the zero time if s isn't in that format. Times without a time zone are interpreted as local times (which is how
+// xUnit writes them).
+func parseRTF(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// Returns s, or the number of seconds represented by rtf (a duration in round-trip format) if s is 0 (because the
+// `time` attribute is missing) and rtf is a valid duration.
+func rtfSeconds(s decimal, rtf string) decimal {
+	if s != 0 || rtf == "" {
+		return s
+	}
+
+	d, ok := parseTimeSpan(rtf)
+
+	if !ok {
+		return s
+	}
+
+	return decimal(d.Seconds())
+}
+
+// Returns the duration represented by s, a .NET TimeSpan in round-trip format ("[-][d.]hh:mm:ss[.fffffff]"), and
+// false if s isn't in that format.
+func parseTimeSpan(s string) (time.Duration, bool) {
+	s, negative := strings.CutPrefix(s, "-")
+	parts := strings.Split(s, ":")
+
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	var days, hours int
+	var err error
+
+	if d, h, ok := strings.Cut(parts[0], "."); ok {
+		if days, err = strconv.Atoi(d); err != nil {
+			return 0, false
+		}
+
+		parts[0] = h
+	}
+
+	if hours, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, false
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+
+	if err != nil {
+		return 0, false
+	}
+
+	secs, err := strconv.ParseFloat(parts[2], 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	d := time.Duration(days)*24*time.Hour + time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(secs*float64(time.Second))
+
+	if negative {
+		d = -d
+	}
+
+	return d, true
+}
+
+// Returns the duration represented by s seconds.
+func seconds(s decimal) time.Duration {
+	return time.Duration(float64(s) * float64(time.Second))
+}
@@ -27,14 +27,22 @@
 package xunit_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"html"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
 	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit/xunittest"
 )
 
 // UT: Load an XML file containing a .NET test result.
@@ -52,27 +60,30 @@ func TestLoad(t *testing.T) {
 		},
 		{
 			xmlData: "<assemblies computer=\"WIN11\" user=\"Kevin\" timestamp=\"07/10/2023 20:53:19\" start-rtf=\"2000-12-01\" finish-rtf=\"2001-12-01\" timestamp=\"2001-12-02\">\n" +
-				"  <assembly name=\"C:\\Parent\\Sub\\App.dll\" errors=\"1\" failed=\"2\" passed=\"3\" not-run=\"4\" total=\"5\" run-date=\"07/10/2023\" run-time=\"20:53:19\" time-rtf=\"2000-12-01\">\n" +
+				"  <assembly name=\"C:\\Parent\\Sub\\App.dll\" errors=\"1\" failed=\"2\" passed=\"3\" skipped=\"6\" not-run=\"4\" total=\"5\" time=\"1.5\" run-date=\"07/10/2023\" run-time=\"20:53:19\" time-rtf=\"2000-12-01\">\n" +
 				"  </assembly>\n" +
 				"</assemblies>",
 			want: xunit.TestRun{
-				Computer:     "WIN11",
-				User:         "Kevin",
-				StartTimeRTF: "2000-12-01",
-				EndTimeRTF:   "2001-12-01",
-				Timestamp:    "2001-12-02",
+				Computer:      "WIN11",
+				User:          "Kevin",
+				SchemaVersion: 2,
+				StartTimeRTF:  "2000-12-01",
+				EndTimeRTF:    "2001-12-01",
+				Timestamp:     "2001-12-02",
 				Assemblies: []xunit.Assembly{
 					{
-						Name:        "App.dll",
-						ErrorCount:  1,
-						PassedCount: 3,
-						FailedCount: 2,
-						NotRunCount: 4,
-						TotalCount:  5,
-						RunDate:     "07/10/2023",
-						RunTime:     "20:53:19",
-						Time:        "2000-12-01",
-						Tests:       make([]*xunit.TestGroup, 0),
+						Name:         "App.dll",
+						ErrorCount:   1,
+						PassedCount:  3,
+						FailedCount:  2,
+						SkippedCount: 6,
+						NotRunCount:  4,
+						TotalCount:   5,
+						RunDate:      "07/10/2023",
+						RunTime:      "20:53:19",
+						Time:         "2000-12-01",
+						Duration:     1500 * time.Millisecond,
+						Tests:        make([]*xunit.TestGroup, 0),
 					},
 				},
 			},
@@ -89,7 +100,11 @@ func TestLoad(t *testing.T) {
 				"      </test>\n" +
 
 				// NOTE: A NON nested test without a display name (it contains NO spaces, and NO `+` character).
-				"      <test name=\"NS1.Class.SubClass.TestClass.TestMethod\" result=\"Fail\">\n" +
+				"      <test id=\"1\" name=\"NS1.Class.SubClass.TestClass.TestMethod\" result=\"Fail\" time=\"0.25\" source-file=\"Tests.cs\" source-line=\"12\">\n" +
+				"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n" +
+				"          <message>Assert.Equal() Failure</message>\n" +
+				"          <stack-trace>at TestMethod() in Tests.cs:line 12</stack-trace>\n" +
+				"        </failure>\n" +
 				"        <traits />\n" +
 				"      </test>\n" +
 
@@ -121,11 +136,12 @@ func TestLoad(t *testing.T) {
 				"  </assembly>\n" +
 				"</assemblies>",
 			want: xunit.TestRun{
-				Computer:     "WIN11",
-				User:         "Kevin",
-				StartTimeRTF: "2000-12-01",
-				EndTimeRTF:   "2001-12-01",
-				Timestamp:    "2001-12-02",
+				Computer:      "WIN11",
+				User:          "Kevin",
+				SchemaVersion: 2,
+				StartTimeRTF:  "2000-12-01",
+				EndTimeRTF:    "2001-12-01",
+				Timestamp:     "2001-12-02",
 				Assemblies: []xunit.Assembly{
 					{
 						Name:        "app.dll",
@@ -137,17 +153,31 @@ func TestLoad(t *testing.T) {
 						RunDate:     "07/10/2023",
 						RunTime:     "20:53:19",
 						Time:        "2000-12-01",
+						Collections: []xunit.Collection{{}},
 						Tests: []*xunit.TestGroup{
 							{
 								Name: "",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name.",
-										Result: "Pass",
+										Name:    "A test with a display name.",
+										RawName: "A test with a display name.",
+										Result:  "Pass",
+										Index:   1,
 									},
 									{
-										Name:   "NS1.Class.SubClass.TestClass.TestMethod",
-										Result: "Fail",
+										ID:         "1",
+										Name:       "NS1.Class.SubClass.TestClass.TestMethod",
+										RawName:    "NS1.Class.SubClass.TestClass.TestMethod",
+										Result:     "Fail",
+										Index:      2,
+										Duration:   250 * time.Millisecond,
+										SourceFile: "Tests.cs",
+										SourceLine: 12,
+										Failure: xunit.Failure{
+											ExceptionType: "Xunit.Sdk.EqualException",
+											Message:       "Assert.Equal() Failure",
+											StackTrace:    "at TestMethod() in Tests.cs:line 12",
+										},
 									},
 								},
 								Groups: []*xunit.TestGroup{
@@ -167,8 +197,10 @@ func TestLoad(t *testing.T) {
 																Name: "SubScenario",
 																Tests: []xunit.TestCase{
 																	{
-																		Name:   "NS1.Class.SubClass.TestClass+Method+Scenario+SubScenario.Result",
-																		Result: "Pass",
+																		Name:    "NS1.Class.SubClass.TestClass+Method+Scenario+SubScenario.Result",
+																		RawName: "NS1.Class.SubClass.TestClass+Method+Scenario+SubScenario.Result",
+																		Result:  "Pass",
+																		Index:   3,
 																	},
 																},
 															},
@@ -182,8 +214,10 @@ func TestLoad(t *testing.T) {
 																Name: "SubScenario",
 																Tests: []xunit.TestCase{
 																	{
-																		Name:   "NS1.Class.SubClass.TestClass+Method+Scenario2+SubScenario.Result",
-																		Result: "Pass",
+																		Name:    "NS1.Class.SubClass.TestClass+Method+Scenario2+SubScenario.Result",
+																		RawName: "NS1.Class.SubClass.TestClass+Method+Scenario2+SubScenario.Result",
+																		Result:  "Pass",
+																		Index:   4,
 																	},
 																},
 															},
@@ -199,12 +233,18 @@ func TestLoad(t *testing.T) {
 								Name: "Category - Unit",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name (with a trait).",
-										Result: "Pass",
+										Name:    "A test with a display name (with a trait).",
+										RawName: "A test with a display name (with a trait).",
+										Result:  "Pass",
+										Traits:  []xunit.Trait{{Name: "Category", Value: "Unit"}},
+										Index:   5,
 									},
 									{
-										Name:   "A test with a display name (with multiple traits).",
-										Result: "Pass",
+										Name:    "A test with a display name (with multiple traits).",
+										RawName: "A test with a display name (with multiple traits).",
+										Result:  "Pass",
+										Traits:  []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
+										Index:   6,
 									},
 								},
 							},
@@ -212,14 +252,38 @@ func TestLoad(t *testing.T) {
 								Name: "Timing - Slow",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name (with multiple traits).",
-										Result: "Pass",
+										Name:    "A test with a display name (with multiple traits).",
+										RawName: "A test with a display name (with multiple traits).",
+										Result:  "Pass",
+										Traits:  []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
+										Index:   6,
 									},
 								},
 							},
 						},
 					},
 				},
+				Warnings: []string{
+					"assembly \"app.dll\" reports 3 passed tests, but contains 5",
+					"assembly \"app.dll\" reports 2 failed tests, but contains 1",
+					"assembly \"app.dll\" reports 4 not run tests, but contains 0",
+					"assembly \"app.dll\" reports 5 total tests, but contains 6",
+				},
+				Diagnostics: xunit.Diagnostics{
+					MissingAttributes: map[string]int{
+						"assembly/@skipped":   1,
+						"assembly/@time":      1,
+						"collection/@failed":  1,
+						"collection/@name":    1,
+						"collection/@passed":  1,
+						"collection/@skipped": 1,
+						"collection/@time":    1,
+						"collection/@total":   1,
+						"test/@method":        6,
+						"test/@time":          5,
+						"test/@type":          6,
+					},
+				},
 			},
 		},
 	} {
@@ -253,7 +317,7 @@ func TestLoad(t *testing.T) {
 		}
 
 		if !tc.wantErr {
-			assert.Nil(t, err, "", "\n\n"+
+			assert.NoError(t, err, "", "\n\n"+
 				"UT Name:    Parse an invalid XML file containing a .NET test result in xUnit's v2+ XML format.\n"+
 				"XML Input:  %s\n"+
 				"\033[32mExpected:   Error, <nil>\033[0m\n"+
@@ -271,6 +335,531 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+// UT: Parse an XML file containing a test with a duplicate trait.
+func TestLoad_DuplicateTrait(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	rdr := strings.NewReader("<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A test with a duplicate trait.\" result=\"Pass\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>")
+
+	// ACT.
+	got, err := xunit.Load(rdr)
+
+	// ASSERT.
+	assert.NoError(t, err, "Load()")
+	assert.Len(t, got.Assemblies[0].Tests, 2, "Load().Assemblies[0].Tests")
+	assert.Len(t, got.Assemblies[0].Tests[1].Tests, 1, "Load().Assemblies[0].Tests[1].Tests")
+}
+
+// UT: Parse an XML file containing traits with numbers (which are sorted in natural order).
+func TestLoad_NaturalOrder(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n"
+
+	for _, scenario := range []string{"10", "2", "1", "02", "Setup"} {
+		xmlData += "      <test name=\"A test.\" result=\"Pass\">\n" +
+			"        <traits><trait name=\"Scenario\" value=\"" + scenario + "\" /></traits>\n" +
+			"      </test>\n"
+	}
+
+	xmlData += "    </collection>\n  </assembly>\n</assemblies>"
+
+	// ACT.
+	got, err := xunit.Load(strings.NewReader(xmlData))
+
+	// ASSERT.
+	assert.NoError(t, err, "Load()")
+
+	names := make([]string, 0, len(got.Assemblies[0].Tests))
+
+	for _, group := range got.Assemblies[0].Tests {
+		names = append(names, group.Name)
+	}
+
+	want := []string{"", "Scenario - 1", "Scenario - 02", "Scenario - 2", "Scenario - 10", "Scenario - Setup"}
+
+	assert.DeepEqual(t, names, want, "", "\n\n"+
+		"UT Name:    Parse an XML file containing traits with numbers (which are sorted in natural order).\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, names)
+}
+
+// UT: Report where a document which can't be read (or parsed) is invalid.
+func TestLoad_ParseError(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		rdr     io.Reader
+		wantErr string
+	}{
+		{
+			name: "A truncated document",
+			rdr: strings.NewReader("<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n" +
+				"      <test name=\"A"),
+			wantErr: "xunit: line 4 (offset 77), in <collection>: XML syntax error on line 4: unexpected EOF",
+		},
+		{
+			name: "An invalid attribute",
+			rdr: strings.NewReader("<assemblies>\n  <assembly name=\"App.dll\" failed=\"abc\">\n  </assembly>\n" +
+				"</assemblies>"),
+			wantErr: "xunit: line 2 (offset 53), in <assembly>: strconv.ParseInt: parsing \"abc\": invalid syntax",
+		},
+		{
+			name:    "A read error",
+			rdr:     io.MultiReader(strings.NewReader("<assemblies>\n"), iotest.ErrReader(errors.New("disk failure"))),
+			wantErr: "xunit: line 2 (offset 13): disk failure",
+		},
+	} {
+		// ACT.
+		_, err := xunit.Load(tc.rdr)
+
+		// ASSERT.
+		var parseErr *xunit.ParseError
+
+		assert.Equal(t, errors.As(err, &parseErr), true, "", "\n\n"+
+			"UT Name:    Report where a document which can't be read (or parsed) is invalid.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   A *xunit.ParseError\033[0m\n"+
+			"\033[31mActual:     %T\033[0m\n\n", tc.name, err)
+
+		assert.Equal(t, err.Error(), tc.wantErr, "", "\n\n"+
+			"UT Name:    Report where a document which can't be read (or parsed) is invalid.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.wantErr, err)
+	}
+}
+
+// UT: Load the tests which precede the error in a truncated (or malformed) document.
+func TestLoadPartial(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name           string
+		xmlData        string
+		wantTests      []string
+		wantIncomplete bool
+		wantWarning    string
+		wantErr        bool
+	}{
+		{
+			name: "A valid document",
+			xmlData: "<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\" />\n    </collection>\n  </assembly>\n</assemblies>",
+			wantTests: []string{"Test 1"},
+		},
+		{
+			name: "A document truncated in a test",
+			xmlData: "<assemblies computer=\"WIN11\">\n  <assembly name=\"App.dll\">\n    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\" />\n      <test name=\"Test 2\" result=\"Fail\">\n" +
+				"        <failure>",
+			wantTests:      []string{"Test 1"},
+			wantIncomplete: true,
+			wantWarning: "the document is incomplete, only the tests before the error were loaded (xunit: line 6 " +
+				"(offset 176), in <failure>: XML syntax error on line 6: unexpected EOF)",
+		},
+		{
+			name: "A document truncated after an assembly",
+			xmlData: "<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\" />\n    </collection>\n  </assembly>\n" +
+				"  <assembly name=\"Lib.dll\">\n    <collection>\n      <test name=\"Test 2\" result=\"Pass\" />\n" +
+				"      <test name=\"Test",
+			wantTests:      []string{"Test 1", "Test 2"},
+			wantIncomplete: true,
+		},
+		{
+			name:    "A document without a root element",
+			xmlData: "<assem",
+			wantErr: true,
+		},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadPartial(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Load the tests which precede the error in a truncated (or malformed) document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantErr, err)
+
+		var names []string
+
+		for _, assembly := range testRun.Assemblies {
+			assembly.Walk(func(_ []string, tc xunit.TestCase) { names = append(names, tc.Name) })
+		}
+
+		assert.DeepEqual(t, names, tc.wantTests, "", "\n\n"+
+			"UT Name:    Load the tests which precede the error in a truncated (or malformed) document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.wantTests, names)
+
+		assert.Equal(t, testRun.Incomplete, tc.wantIncomplete, "", "\n\n"+
+			"UT Name:    Load the tests which precede the error in a truncated (or malformed) document.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   Incomplete = %t\033[0m\n"+
+			"\033[31mActual:     Incomplete = %t\033[0m\n\n", tc.name, tc.wantIncomplete, testRun.Incomplete)
+
+		if tc.wantWarning != "" {
+			assert.Contains(t, testRun.Warnings, tc.wantWarning, "", "\n\n"+
+				"UT Name:    Load the tests which precede the error in a truncated (or malformed) document.\n"+
+				"Input:      %s\n"+
+				"\033[32mExpected:   Warnings containing %q\033[0m\n"+
+				"\033[31mActual:     %q\033[0m\n\n", tc.name, tc.wantWarning, testRun.Warnings)
+		}
+	}
+}
+
+// UT: Parse a document according to the version of its schema.
+func TestLoad_SchemaVersion(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		version      string
+		wantVersion  int
+		wantDuration []time.Duration
+		wantWarnings []string
+	}{
+		{version: "", wantVersion: 2, wantDuration: []time.Duration{0, 0}},
+		{version: "schema-version=\"2\"", wantVersion: 2, wantDuration: []time.Duration{0, 0}},
+		{
+			version:      "schema-version=\"3\"",
+			wantVersion:  3,
+			wantDuration: []time.Duration{90*time.Minute + 1250*time.Millisecond, 24 * time.Hour},
+		},
+		{
+			version:      "schema-version=\"4\"",
+			wantVersion:  3,
+			wantDuration: []time.Duration{90*time.Minute + 1250*time.Millisecond, 24 * time.Hour},
+			wantWarnings: []string{"unknown schema version \"4\", the document is parsed as version 3"},
+		},
+	} {
+		// ARRANGE.
+		xmlData := "<assemblies " + tc.version + ">\n" +
+			"  <assembly name=\"App.dll\" time-rtf=\"01:30:01.2500000\">\n" +
+			"    <collection>\n" +
+			"      <test name=\"Test 1\" result=\"Pass\" time-rtf=\"1.00:00:00\" />\n" +
+			"    </collection>\n" +
+			"  </assembly>\n" +
+			"</assemblies>"
+
+		// ACT.
+		testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "Load()")
+
+		durations := []time.Duration{testRun.Assemblies[0].Duration, testRun.Assemblies[0].Tests[0].Tests[0].Duration}
+		got := []any{testRun.SchemaVersion, durations, testRun.Warnings}
+		want := []any{tc.wantVersion, tc.wantDuration, tc.wantWarnings}
+
+		assert.DeepEqual(t, got, want, "", "\n\n"+
+			"UT Name:    Parse a document according to the version of its schema.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.version, want, got)
+	}
+}
+
+// UT: Parse the times at which a test run (and its assemblies) started and finished.
+func TestLoad_Times(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies start-rtf=\"2023-07-10T20:53:19.1234567+02:00\" finish-rtf=\"2023-07-10T20:55:00Z\">\n" +
+		"  <assembly name=\"App.dll\" start-rtf=\"2023-07-10T20:53:19.5\" finish-rtf=\"invalid\" />\n" +
+		"  <assembly name=\"Lib.dll\" run-date=\"2023-07-10\" run-time=\"20:54:00\" />\n" +
+		"  <assembly name=\"Other.dll\" run-date=\"07/10/2023\" run-time=\"20:54:00\" />\n" +
+		"</assemblies>"
+
+	// ACT.
+	testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+	// ASSERT.
+	assert.NoError(t, err, "Load()")
+
+	for _, tc := range []struct {
+		name      string
+		got, want time.Time
+	}{
+		{name: "StartTime", got: testRun.StartTime, want: time.Date(2023, 7, 10, 18, 53, 19, 123456700, time.UTC)},
+		{name: "EndTime", got: testRun.EndTime, want: time.Date(2023, 7, 10, 20, 55, 0, 0, time.UTC)},
+		{
+			name: "Assemblies[0].StartTime",
+			got:  testRun.Assemblies[0].StartTime,
+			want: time.Date(2023, 7, 10, 20, 53, 19, 500000000, time.Local),
+		},
+		{name: "Assemblies[0].EndTime", got: testRun.Assemblies[0].EndTime},
+		{
+			name: "Assemblies[1].StartTime",
+			got:  testRun.Assemblies[1].StartTime,
+			want: time.Date(2023, 7, 10, 20, 54, 0, 0, time.Local),
+		},
+		{name: "Assemblies[2].StartTime", got: testRun.Assemblies[2].StartTime},
+	} {
+		assert.Equal(t, tc.got.Equal(tc.want), true, "", "\n\n"+
+			"UT Name:    Parse the times at which a test run (and its assemblies) started and finished.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, tc.want, tc.got)
+	}
+}
+
+// UT: Parse the durations of a document written with the decimal separator of any culture.
+func TestLoad_DecimalSeparator(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		time    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{time: "", want: 0},
+		{time: "0.125", want: 125 * time.Millisecond},
+		{time: "0,125", want: 125 * time.Millisecond},
+		{time: " 2,5 ", want: 2500 * time.Millisecond},
+		{time: "1,234.5", want: 1234500 * time.Millisecond},
+		{time: "1.234,5", want: 1234500 * time.Millisecond},
+		{time: "1\u00a0234,5", want: 1234500 * time.Millisecond},
+		{time: "abc", wantErr: true},
+	} {
+		// ARRANGE.
+		xmlData := "<assemblies>\n  <assembly name=\"App.dll\" time=\"" + tc.time + "\" />\n</assemblies>"
+
+		// ACT.
+		testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+		// ASSERT.
+		assert.Equal(t, err != nil, tc.wantErr, "", "\n\n"+
+			"UT Name:    Parse the durations of a document written with the decimal separator of any culture.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   Error = %t\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.time, tc.wantErr, err)
+
+		if tc.wantErr {
+			continue
+		}
+
+		got := testRun.Assemblies[0].Duration.Round(time.Millisecond)
+
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Parse the durations of a document written with the decimal separator of any culture.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.time, tc.want, got)
+	}
+}
+
+// UT: Report the counts of an assembly which don't match the tests it contains.
+func TestLoadWarnings_Counts(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    []string
+	}{
+		{
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\" total=\"2\" passed=\"1\" failed=\"1\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\" />\n" +
+				"      <test name=\"Test 2\" result=\"Fail\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+		},
+		{
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\" total=\"3\" passed=\"2\" failed=\"1\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\" />\n" +
+				"      <test name=\"Test 2\" result=\"Skip\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []string{
+				"assembly \"App.dll\" reports 2 passed tests, but contains 1",
+				"assembly \"App.dll\" reports 1 failed tests, but contains 0",
+				"assembly \"App.dll\" reports 0 skipped tests, but contains 1",
+				"assembly \"App.dll\" reports 3 total tests, but contains 2",
+			},
+		},
+	} {
+		// ACT.
+		run, err := xunit.Load(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "Load()")
+
+		assert.DeepEqual(t, run.Warnings, tc.want, "", "\n\n"+
+			"UT Name:    Report the counts of an assembly which don't match the tests it contains.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.xmlData, tc.want, run.Warnings)
+	}
+}
+
+// UT: Walk over the tests of an assembly.
+func TestAssemblyWalk(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	type visit struct {
+		Path []string
+		Name string
+	}
+
+	for _, tc := range []struct {
+		xmlData string
+		want    []visit
+	}{
+		{
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\">\n" +
+				"    <collection>\n" +
+				"      <test id=\"1\" name=\"A test with a display name.\" result=\"Pass\" />\n" +
+				"      <test id=\"2\" name=\"NS.TestClass+Method+Scenario.Result\" result=\"Pass\" />\n" +
+				"      <test id=\"3\" name=\"A test with multiple traits.\" result=\"Pass\">\n" +
+				"        <traits>\n" +
+				"          <trait name=\"Category\" value=\"Unit\" />\n" +
+				"          <trait name=\"Timing\" value=\"Slow\" />\n" +
+				"        </traits>\n" +
+				"      </test>\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []visit{
+				{Path: nil, Name: "A test with a display name."},
+				{Path: []string{"TestClass", "Method", "Scenario"}, Name: "NS.TestClass+Method+Scenario.Result"},
+				{Path: []string{"Category - Unit"}, Name: "A test with multiple traits."},
+			},
+		},
+		{
+			// NOTE: The rows of a theory without IDs, which have the same fields, are distinct tests.
+			xmlData: "<assemblies>\n" +
+				"  <assembly name=\"App.dll\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"A theory (x: 1).\" result=\"Pass\" />\n" +
+				"      <test name=\"A theory (x: 1).\" result=\"Pass\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []visit{
+				{Path: nil, Name: "A theory (x: 1)."},
+				{Path: nil, Name: "A theory (x: 1)."},
+			},
+		},
+	} {
+		// ARRANGE.
+		run, _ := xunit.Load(strings.NewReader(tc.xmlData))
+		got := make([]visit, 0)
+
+		// ACT.
+		run.Assemblies[0].Walk(func(path []string, tc xunit.TestCase) {
+			got = append(got, visit{Path: path, Name: tc.Name})
+		})
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got, want []visit) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Walk over the tests of an assembly.\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.want, got)
+	}
+}
+
+// UT: Report the elements which aren't part of xUnit's v2+ XML format.
+func TestLoadWarnings(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    []string
+	}{
+		{
+			xmlData: "<assemblies><assembly name=\"App.dll\"><collection /></assembly></assemblies>",
+			want:    nil,
+		},
+		{
+			xmlData: "<assemblies>\n" +
+				"  <metadata />\n" +
+				"  <assembly name=\"App.dll\">\n" +
+				"    <collection>\n" +
+				"      <test name=\"Test 1\" result=\"Pass\"><attachments /></test>\n" +
+				"      <test name=\"Test 2\" result=\"Pass\"><attachments /></test>\n" +
+				"      <property />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: []string{
+				"unknown element <attachments> in <test> ignored (2 times)",
+				"unknown element <metadata> in <assemblies> ignored",
+				"unknown element <property> in <collection> ignored",
+			},
+		},
+	} {
+		// ACT.
+		run, err := xunit.Load(strings.NewReader(tc.xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "", "\n\n"+
+			"UT Name:    Report the elements which aren't part of xUnit's v2+ XML format.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.xmlData, nil, err)
+
+		assert.EqualFn(t, run.Warnings, tc.want, func(got, want []string) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Report the elements which aren't part of xUnit's v2+ XML format.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.xmlData, tc.want, run.Warnings)
+	}
+}
+
+// UT: Merge multiple test runs.
+func TestMerge(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	first := xunit.TestRun{Computer: "WIN11", Assemblies: []xunit.Assembly{{Name: "App.dll"}}, Warnings: []string{"W1"}}
+	second := xunit.TestRun{Computer: "LINUX", Assemblies: []xunit.Assembly{{Name: "Lib.dll"}}, Warnings: []string{"W2"}}
+
+	// ACT.
+	got := xunit.Merge(first, second)
+
+	// ASSERT.
+	want := xunit.TestRun{
+		Computer:   "WIN11",
+		Assemblies: []xunit.Assembly{{Name: "App.dll"}, {Name: "Lib.dll"}},
+		Warnings:   []string{"W1", "W2"},
+	}
+
+	assert.EqualFn(t, got, want, func(got, want xunit.TestRun) bool {
+		return reflect.DeepEqual(got, want) && len(first.Assemblies) == 1 && len(first.Warnings) == 1
+	}, "", "\n\n"+
+		"UT Name:    Merge multiple test runs.\n"+
+		"Input:      %+v, %+v\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", first, second, want, got)
+}
+
 // Benchmark: Load an XML file containing a .NET test result.
 func BenchmarkLoad_MultipleAssemblies(b *testing.B) {
 	xmlData := "<assemblies>\n"
@@ -342,3 +931,609 @@ func benchmarkLoad(xmlData string, b *testing.B) {
 		}
 	}
 }
+
+// UT: Load a document with a custom detection of the display names of the tests.
+func TestLoadWithOptions_DisplayName(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Result\" result=\"Pass\" />\n" +
+		"      <test name=\"Scenario: add 1+1\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		name        string
+		displayName func(name string) bool
+		want        []string
+	}{
+		{
+			name: "The default detection",
+			want: []string{"TestClass/Method/NS.TestClass+Method.Result", "Scenario: add 1/1/Scenario: add 1+1"},
+		},
+		{
+			name:        "A custom detection",
+			displayName: func(name string) bool { return strings.HasPrefix(name, "Scenario: ") },
+			want:        []string{"Scenario: add 1+1", "TestClass/Method/NS.TestClass+Method.Result"},
+		},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{DisplayName: tc.displayName})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(path []string, tCase xunit.TestCase) {
+			got = append(got, strings.Join(append(path, tCase.Name), "/"))
+		})
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a document with a custom detection of the display names of the tests.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Group the tests with a display name by the nested classes which declare them.
+func TestLoad_NestedDisplayNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Adds two numbers\" type=\"NS.Calculator+Add\" method=\"Test\" result=\"Pass\" />\n" +
+		"      <test name=\"Divides two numbers\" type=\"NS.Calculator\" method=\"Test\" result=\"Pass\" />\n" +
+		"      <test name=\"1+1 equals 2\" type=\"NS.Calculator\" method=\"Test\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	// ACT.
+	testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+	// ASSERT.
+	assert.NoError(t, err, "Load()")
+
+	var got []string
+
+	testRun.Assemblies[0].Walk(func(path []string, tCase xunit.TestCase) {
+		got = append(got, strings.Join(append(path, tCase.Name), "/"))
+	})
+
+	want := []string{"Divides two numbers", "1+1 equals 2", "Calculator/Add/Adds two numbers"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Group the tests with a display name by the nested classes which declare them.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Group the tests of nested generic classes by the readable names of their types.
+func TestLoad_GenericNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name string
+		want []string
+	}{
+		{name: "NS.Outer`1+Inner.Test", want: []string{"Outer<T>", "Inner"}},
+		{name: "NS.Outer`2+Inner[System.String].Test", want: []string{"Outer<T1, T2>", "Inner<String>"}},
+		{
+			name: "NS.Outer`1[[System.Collections.Generic.List`1[[System.Int32, mscorlib]], mscorlib]]+Inner.Test",
+			want: []string{"Outer<List<Int32>>", "Inner"},
+		},
+		{name: "NS.Outer+Inner.Test(value: 1.5, text: \"a+b\")", want: []string{"Outer", "Inner"}},
+		{name: "NS.Class.Test(text: \"a+b\")", want: nil},
+	} {
+		// ARRANGE.
+		xmlData := "<assemblies>\n" +
+			"  <assembly name=\"App.dll\">\n" +
+			"    <collection>\n" +
+			"      <test name=\"" + html.EscapeString(tc.name) + "\" result=\"Pass\" />\n" +
+			"    </collection>\n" +
+			"  </assembly>\n" +
+			"</assemblies>"
+
+		// ACT.
+		testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+		// ASSERT.
+		assert.NoError(t, err, "Load()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(path []string, _ xunit.TestCase) { got = path })
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Group the tests of nested generic classes by the readable names of their types.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Load a document, showing the namespaces of nested tests in their groups.
+func TestLoadWithOptions_Namespaces(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS1.Sub.Outer+Inner.Test\" result=\"Pass\" />\n" +
+		"      <test name=\"Outer+Inner.Test\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		namespaces xunit.Namespaces
+		want       []string
+	}{
+		{namespaces: xunit.NamespacesStrip, want: []string{"Outer/Inner", "Outer/Inner"}},
+		{namespaces: xunit.NamespacesGroup, want: []string{"NS1.Sub/Outer/Inner", "Outer/Inner"}},
+		{namespaces: xunit.NamespacesQualify, want: []string{"NS1.Sub.Outer/Inner", "Outer/Inner"}},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Namespaces: tc.namespaces})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(path []string, _ xunit.TestCase) {
+			got = append(got, strings.Join(path, "/"))
+		})
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a document, showing the namespaces of nested tests in their groups.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.namespaces, tc.want, got)
+	}
+}
+
+// UT: Load a document, with the statistics of its test collections.
+func TestLoad_Collections(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection id=\"c1\" name=\"Login\" total=\"2\" passed=\"1\" failed=\"1\" skipped=\"0\" time=\"1,5\">\n" +
+		"      <test name=\"NS.Login.Succeeds\" result=\"Pass\" time=\"0.5\" />\n" +
+		"      <test name=\"NS.Login.Fails\" result=\"Fail\" time=\"1\" />\n" +
+		"    </collection>\n" +
+		"    <collection name=\"Calc\" total=\"1\" skipped=\"1\">\n" +
+		"      <test name=\"NS.Calc.Adds\" result=\"Skip\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	// ACT.
+	testRun, err := xunit.Load(strings.NewReader(xmlData))
+
+	// ASSERT.
+	assert.NoError(t, err, "Load()")
+
+	want := []xunit.Collection{
+		{ID: "c1", Name: "Login", PassedCount: 1, FailedCount: 1, TotalCount: 2, Duration: 1500 * time.Millisecond},
+		{Name: "Calc", SkippedCount: 1, TotalCount: 1},
+	}
+	got := testRun.Assemblies[0].Collections
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Load a document, with the statistics of its test collections.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Load a document, grouping the tests by trait, namespace, collection, result or assembly.
+func TestLoadWithOptions_GroupBy(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection name=\"Login\">\n" +
+		"      <test name=\"NS.Outer+Inner.Succeeds\" result=\"Pass\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.Outer+Inner.Fails\" result=\"Fail\" />\n" +
+		"    </collection>\n" +
+		"    <collection name=\"Calc\">\n" +
+		"      <test name=\"Other.Calc+Inner.Adds\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		groupBy xunit.GroupBy
+		want    []string // The path of each test, in the order they're visited.
+	}{
+		{groupBy: xunit.GroupByTrait, want: []string{"Outer/Inner", "Calc/Inner", "Category - Unit/Outer/Inner"}},
+		{groupBy: xunit.GroupByNamespace, want: []string{"NS/Outer/Inner", "NS/Outer/Inner", "Other/Calc/Inner"}},
+		{groupBy: xunit.GroupByCollection, want: []string{"Calc/Calc/Inner", "Login/Outer/Inner", "Login/Outer/Inner"}},
+		{groupBy: xunit.GroupByResult, want: []string{"Fail/Outer/Inner", "Pass/Outer/Inner", "Pass/Calc/Inner"}},
+		{groupBy: xunit.GroupByAssembly, want: []string{"Outer/Inner", "Outer/Inner", "Calc/Inner"}},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{GroupBy: tc.groupBy})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		var gotTraits []xunit.Trait
+
+		testRun.Assemblies[0].Walk(func(path []string, test xunit.TestCase) {
+			got = append(got, strings.Join(path, "/"))
+			gotTraits = append(gotTraits, test.Traits...)
+		})
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a document, grouping the tests by trait, namespace, collection, result or assembly.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.groupBy, tc.want, got)
+
+		// NOTE: The traits of the tests are recorded, whatever the tests are grouped by.
+		wantTraits := []xunit.Trait{{Name: "Category", Value: "Unit"}}
+
+		assert.DeepEqual(t, gotTraits, wantTraits, "", "\n\n"+
+			"UT Name:    Load a document, grouping the tests by trait, namespace, collection, result or assembly.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   The traits %v\033[0m\n"+
+			"\033[31mActual:     The traits %v\033[0m\n\n", tc.groupBy, wantTraits, gotTraits)
+	}
+}
+
+// UT: Load a document, grouping the tests by the delimiters in their names.
+func TestLoadWithOptions_Delimiters(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"Parser :: Errors :: Reports the line\" result=\"Pass\" />\n" +
+		"      <test name=\"Parser/Reads a file\" result=\"Pass\" />\n" +
+		"      <test name=\"NS.Class+Method.Result\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		delimiters []string
+		want       []string
+	}{
+		{
+			want: []string{
+				"Parser :: Errors :: Reports the line", "Parser/Reads a file", "Class/Method/NS.Class+Method.Result",
+			},
+		},
+		{
+			delimiters: []string{"::", "/"},
+			want: []string{
+				"Parser/Parser/Reads a file", "Parser/Errors/Parser :: Errors :: Reports the line",
+				"Class/Method/NS.Class+Method.Result",
+			},
+		},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Delimiters: tc.delimiters})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(path []string, tCase xunit.TestCase) {
+			got = append(got, strings.Join(append(path, tCase.Name), "/"))
+		})
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a document, grouping the tests by the delimiters in their names.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.delimiters, tc.want, got)
+	}
+}
+
+// UT: Load a document, writing the names of the methods of the tests as sentences.
+func TestLoadWithOptions_Humanize(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{name: "NS.Class.Should_Return_Error_When_Input_Is_Null", want: "Should return error when input is null"},
+		{name: "NS.Class.ReturnsErrorWhenInputIsNull", want: "Returns error when input is null"},
+		{name: "NS.Class+Nested.ParsesTheHTTPRequest2", want: "Parses the HTTP request 2"},
+		{name: "NS.Class.Adds_Numbers(a: 1.5, b: 2)", want: "Adds numbers (a: 1.5, b: 2)"},
+		{name: "Returns an error.", want: "Returns an error."},
+	} {
+		// ARRANGE.
+		xmlData := "<assemblies>\n" +
+			"  <assembly name=\"App.dll\">\n" +
+			"    <collection>\n" +
+			"      <test name=\"" + tc.name + "\" result=\"Pass\" />\n" +
+			"    </collection>\n" +
+			"  </assembly>\n" +
+			"</assemblies>"
+
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Humanize: true})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(_ []string, tCase xunit.TestCase) {
+			got = append(got, tCase.Name, tCase.RawName)
+		})
+
+		want := []string{tc.want, tc.name}
+
+		assert.DeepEqual(t, got, want, "", "\n\n"+
+			"UT Name:    Load a document, writing the names of the methods of the tests as sentences.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.name, want, got)
+	}
+}
+
+// UT: Load a document, limiting the number of levels of groups.
+func TestLoadWithOptions_MaxDepth(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.A+B+C+D.Test\" result=\"Pass\" />\n" +
+		"      <test name=\"NS.A+B.Test\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		maxDepth int
+		want     []string
+	}{
+		{maxDepth: 0, want: []string{"A", "B", "|", "A", "B", "C", "D"}},
+		{maxDepth: 2, want: []string{"A", "B / C / D", "|", "A", "B"}},
+		{maxDepth: 1, want: []string{"A / B / C / D", "|", "A / B"}},
+	} {
+		// ACT.
+		testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{MaxDepth: tc.maxDepth})
+
+		// ASSERT.
+		assert.NoError(t, err, "LoadWithOptions()")
+
+		var got []string
+
+		testRun.Assemblies[0].Walk(func(path []string, _ xunit.TestCase) {
+			if len(got) > 0 {
+				got = append(got, "|")
+			}
+
+			got = append(got, path...)
+		})
+
+		assert.DeepEqual(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Load a document, limiting the number of levels of groups.\n"+
+			"Input:      %d\n"+
+			"\033[32mExpected:   %q\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", tc.maxDepth, tc.want, got)
+	}
+}
+
+// UT: Load a document, merging the groups which only contain a single group.
+func TestLoadWithOptions_Collapse(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.A+B+C.Test\" result=\"Pass\" />\n" +
+		"      <test name=\"NS.A+B+D+E.Test\" result=\"Pass\" />\n" +
+		"      <test name=\"NS.A+B+D.Test\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	// ACT.
+	testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Collapse: true})
+
+	// ASSERT.
+	assert.NoError(t, err, "LoadWithOptions()")
+
+	var got []string
+
+	testRun.Assemblies[0].Walk(func(path []string, tCase xunit.TestCase) {
+		got = append(got, strings.Join(append(path, tCase.Name), "/"))
+	})
+
+	want := []string{"A / B/C/NS.A+B+C.Test", "A / B/D/NS.A+B+D.Test", "A / B/D/E/NS.A+B+D+E.Test"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Load a document, merging the groups which only contain a single group.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Load a document, using the explicit display names of the tests as their name.
+func TestLoad_DisplayNameTrait(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.Calculator+Add.ReturnsTheSum\" result=\"Pass\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"DisplayName\" value=\"Adds two numbers\" />\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	// ACT.
+	testRun, err := xunit.LoadWithOptions(strings.NewReader(xmlData), xunit.Options{Humanize: true})
+
+	// ASSERT.
+	assert.NoError(t, err, "LoadWithOptions()")
+
+	var got []string
+
+	testRun.Assemblies[0].Walk(func(path []string, tCase xunit.TestCase) {
+		got = append(got, strings.Join(path, "/"), tCase.Name, tCase.RawName)
+	})
+
+	want := []string{"Category - Unit/Calculator/Add", "Adds two numbers", "NS.Calculator+Add.ReturnsTheSum"}
+
+	assert.DeepEqual(t, got, want, "", "\n\n"+
+		"UT Name:    Load a document, using the explicit display names of the tests as their name.\n"+
+		"\033[32mExpected:   %q\033[0m\n"+
+		"\033[31mActual:     %q\033[0m\n\n", want, got)
+}
+
+// UT: Stop loading a document when the context is done.
+func TestLoadContext(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	xmlData := "<assemblies>\n" +
+		"  <assembly name=\"App.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"A test.\" result=\"Pass\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	for _, tc := range []struct {
+		name    string
+		partial bool
+	}{
+		{name: "A complete document"},
+		{name: "A partial document", partial: true},
+	} {
+		// ARRANGE.
+		ctx, cancel := context.WithCancel(context.Background())
+		rdr := iotest.OneByteReader(strings.NewReader(xmlData))
+
+		// NOTE: The context is done while the document is being read.
+		cancelAfterRead := readerFunc(func(p []byte) (int, error) {
+			defer cancel()
+
+			return rdr.Read(p)
+		})
+
+		// ACT.
+		_, err := xunit.LoadContext(ctx, cancelAfterRead, xunit.Options{Partial: tc.partial})
+
+		// ASSERT.
+		assert.Equal(t, errors.Is(err, context.Canceled), true, "", "\n\n"+
+			"UT Name:    Stop loading a document when the context is done.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.name, context.Canceled, err)
+	}
+}
+
+// A readerFunc is an io.Reader which calls itself to read.
+type readerFunc func(p []byte) (int, error)
+
+// Read calls fn.
+func (fn readerFunc) Read(p []byte) (int, error) {
+	return fn(p)
+}
+
+// The numbers of tests of the generated documents used by the benchmarks.
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+// Benchmark: Load a document.
+func BenchmarkLoad(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(strconv.Itoa(n)+" tests", func(b *testing.B) {
+			data := xunittest.Generate(n)
+
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = xunit.Load(bytes.NewReader(data))
+			}
+		})
+	}
+}
+
+// UT: Check whether a failure is caused by a failed assertion.
+func TestFailureIsAssertion(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		exceptionType string
+		want          bool
+	}{
+		{exceptionType: "", want: true},
+		{exceptionType: "Xunit.Sdk.EqualException", want: true},
+		{exceptionType: "NUnit.Framework.AssertionException", want: true},
+		{exceptionType: "Microsoft.VisualStudio.TestTools.UnitTesting.AssertFailedException", want: true},
+		{exceptionType: "System.NullReferenceException", want: false},
+	} {
+		// ACT.
+		got := xunit.Failure{ExceptionType: tc.exceptionType}.IsAssertion()
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Check whether a failure is caused by a failed assertion.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %t\033[0m\n"+
+			"\033[31mActual:     %t\033[0m\n\n", tc.exceptionType, tc.want, got)
+	}
+}
+
+// Benchmark: Load a document, with all the options which affect the grouping of the tests.
+func BenchmarkLoadWithOptions(b *testing.B) {
+	opts := xunit.Options{Namespaces: xunit.NamespacesGroup, Humanize: true, MaxDepth: 2, Collapse: true}
+
+	for _, n := range benchmarkSizes {
+		b.Run(strconv.Itoa(n)+" tests", func(b *testing.B) {
+			data := xunittest.Generate(n)
+
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = xunit.LoadWithOptions(bytes.NewReader(data), opts)
+			}
+		})
+	}
+}
+
+// Benchmark: Load an index of a document.
+func BenchmarkLoadIndex(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(strconv.Itoa(n)+" tests", func(b *testing.B) {
+			data := xunittest.Generate(n)
+
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = xunit.LoadIndex(bytes.NewReader(data), xunit.Options{})
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunittest provides utilities for testing (and benchmarking) the code which processes .NET test results.
+package xunittest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The number of tests in each collection, and the number of collections in each assembly, of the generated documents.
+const (
+	testsPerCollection     = 100
+	collectionsPerAssembly = 100
+)
+
+// The counts of the tests of an element of a generated document.
+type counts struct {
+	passed, failed, skipped int
+}
+
+// Generate returns a synthetic document in xUnit's v2+ XML format containing n tests.
+// The document is deterministic and resembles a real test run: the tests are spread over assemblies and collections,
+// belong to nested classes, have traits, and some of them are skipped or failed (with a message and a stack trace).
+func Generate(n int) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("<assemblies timestamp=\"07/10/2023 20:53:19\">\n")
+
+	for aIdx := 0; aIdx*testsPerCollection*collectionsPerAssembly < n; aIdx++ {
+		var collections strings.Builder
+		var aCounts counts
+
+		aTests := min(n-aIdx*testsPerCollection*collectionsPerAssembly, testsPerCollection*collectionsPerAssembly)
+
+		for cIdx := 0; cIdx*testsPerCollection < aTests; cIdx++ {
+			var tests strings.Builder
+			var cCounts counts
+
+			for tIdx := 0; tIdx < min(aTests-cIdx*testsPerCollection, testsPerCollection); tIdx++ {
+				writeTest(&tests, &cCounts, aIdx, cIdx, tIdx)
+			}
+
+			fmt.Fprintf(&collections, "    <collection name=\"Test collection for Project%d.Class%d\" time=\"1\" "+
+				"%s>\n%s    </collection>\n", aIdx, cIdx, cCounts.attrs(), tests.String())
+
+			aCounts = counts{
+				passed:  aCounts.passed + cCounts.passed,
+				failed:  aCounts.failed + cCounts.failed,
+				skipped: aCounts.skipped + cCounts.skipped,
+			}
+		}
+
+		fmt.Fprintf(&sb, "  <assembly name=\"/src/Project%d.Tests.dll\" run-date=\"2023-07-10\" run-time=\"20:53:19\" "+
+			"time=\"%d\" %s errors=\"0\">\n%s  </assembly>\n", aIdx, aTests/10, aCounts.attrs(), collections.String())
+	}
+
+	sb.WriteString("</assemblies>\n")
+
+	return []byte(sb.String())
+}
+
+// Returns the attributes containing the counts c.
+func (c counts) attrs() string {
+	return fmt.Sprintf("total=\"%d\" passed=\"%d\" failed=\"%d\" skipped=\"%d\"", c.passed+c.failed+c.skipped,
+		c.passed, c.failed, c.skipped)
+}
+
+// Writes the test with the index tIdx in the collection cIdx of the assembly aIdx to sb, and adds it to c.
+func writeTest(sb *strings.Builder, c *counts, aIdx, cIdx, tIdx int) {
+	typ := fmt.Sprintf("Project%d.Tests.Class%d+Scenario%d", aIdx, cIdx, tIdx%10)
+	method := fmt.Sprintf("Should_Return_Result_%d", tIdx)
+
+	fmt.Fprintf(sb, "      <test name=\"%s.%s\" type=\"%s\" method=\"%s\" time=\"0.%03d\"", typ, method, typ, method,
+		tIdx)
+
+	switch tIdx % 20 {
+	case 7:
+		c.skipped++
+
+		sb.WriteString(" result=\"Skip\">\n        <reason>Not implemented yet.</reason>\n")
+	case 13:
+		c.failed++
+
+		fmt.Fprintf(sb, " result=\"Fail\">\n"+
+			"        <failure exception-type=\"Xunit.Sdk.EqualException\">\n"+
+			"          <message>Assert.Equal() Failure\nExpected: %d\nActual:   0</message>\n"+
+			"          <stack-trace>   at %s.%s() in /src/Class%d.cs:line %d</stack-trace>\n"+
+			"        </failure>\n", tIdx, typ, method, cIdx, 10+tIdx)
+	default:
+		c.passed++
+
+		sb.WriteString(" result=\"Pass\">\n")
+	}
+
+	fmt.Fprintf(sb, "        <traits>\n          <trait name=\"Category\" value=\"Category%d\" />\n        </traits>\n"+
+		"      </test>\n", tIdx%5)
+}
@@ -0,0 +1,67 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "xunittest" package.
+package xunittest_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kdeconinck/dtvisual/internal/pkg/assert"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit"
+	"github.com/kdeconinck/dtvisual/internal/pkg/xunit/xunittest"
+)
+
+// UT: Generate a synthetic document with a given number of tests.
+func TestGenerate(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, n := range []int{1, 250, 12345} {
+		// ACT.
+		testRun, err := xunit.Load(bytes.NewReader(xunittest.Generate(n)))
+
+		// ASSERT.
+		assert.NoError(t, err, "Load()")
+
+		got := 0
+
+		for _, assembly := range testRun.Assemblies {
+			got += assembly.TotalCount
+		}
+
+		assert.Equal(t, got, n, "", "\n\n"+
+			"UT Name:    Generate a synthetic document with a given number of tests.\n"+
+			"Input:      %d\n"+
+			"\033[32mExpected:   %d tests\033[0m\n"+
+			"\033[31mActual:     %d tests\033[0m\n\n", n, n, got)
+
+		assert.Equal(t, len(testRun.Warnings), 0, "", "\n\n"+
+			"UT Name:    Generate a synthetic document with a given number of tests.\n"+
+			"Input:      %d\n"+
+			"\033[32mExpected:   No warnings\033[0m\n"+
+			"\033[31mActual:     %q\033[0m\n\n", n, testRun.Warnings)
+	}
+}